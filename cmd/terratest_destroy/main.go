@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/entrypoint"
+	test_structure "github.com/gruntwork-io/terratest/modules/test-structure"
+	"github.com/urfave/cli"
+)
+
+const CustomUsageText = `Usage: terratest_destroy [OPTIONS] <REGION> <UNIQUE_ID>
+
+This tool destroys the resources a Terratest test run left behind. Tests that call test_structure.RegisterFixture save their TerraformOptions under their region and unique ID, and log the command to run if they leak resources. This tool loads those TerraformOptions and runs terraform destroy with them. If the test saved its Terraform state, destroy runs in a fresh copy of the saved module with that state restored, so it works even after the original module folder is gone.
+
+Arguments:
+
+  REGION       The AWS region the test deployed to. E.g.: us-east-1.
+  UNIQUE_ID    The unique ID of the test run, as printed in the test logs. E.g.: a1B2c3.
+
+
+Environment variables:
+
+  TERRATEST_FIXTURES_DIR    The folder in which the tests saved their artifacts. Defaults to a terratest-fixtures folder in the system temp folder.
+
+
+Options:
+
+  --help            Show this help text and exit.
+
+Example:
+
+  terratest_destroy us-east-1 a1B2c3
+`
+
+func run(cliContext *cli.Context) error {
+	region := cliContext.Args().First()
+	if region == "" {
+		return fmt.Errorf("You must specify an AWS region as the first argument")
+	}
+
+	uniqueId := cliContext.Args().Get(1)
+	if uniqueId == "" {
+		return fmt.Errorf("You must specify the unique ID of the test run as the second argument")
+	}
+
+	// Create mock testing.T implementation so we can re-use Terratest methods
+	t := MockTestingT{MockName: "terratest_destroy"}
+
+	return test_structure.DestroyByUniqueIdE(t, region, uniqueId)
+}
+
+func main() {
+	app := entrypoint.NewApp()
+	cli.AppHelpTemplate = CustomUsageText
+	entrypoint.HelpTextLineWidth = 120
+
+	app.Name = "terratest_destroy"
+	app.Author = "Gruntwork <www.gruntwork.io>"
+	app.Description = `This tool destroys the resources a Terratest test run left behind, given the region and unique ID printed in the test logs.`
+	app.Action = run
+
+	entrypoint.RunApp(app)
+}
+
+// MockTestingT is a mock implementation of testing.TestingT. All the functions are essentially no-ops. This allows us
+// to use Terratest methods outside of a testing context (e.g., in a CLI tool).
+type MockTestingT struct {
+	MockName string
+}
+
+func (t MockTestingT) Fail()                                     {}
+func (t MockTestingT) FailNow()                                  {}
+func (t MockTestingT) Fatal(args ...interface{})                 {}
+func (t MockTestingT) Fatalf(format string, args ...interface{}) {}
+func (t MockTestingT) Error(args ...interface{})                 {}
+func (t MockTestingT) Errorf(format string, args ...interface{}) {}
+func (t MockTestingT) Name() string {
+	return t.MockName
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/entrypoint"
+	"github.com/gruntwork-io/terratest/modules/reaper"
+	"github.com/urfave/cli"
+)
+
+const CustomUsageText = `Usage: terratest_reaper [OPTIONS] <REGION> <REGION...>
+
+This tool deletes the AWS resources that test runs left behind, e.g. because the CI job running them was killed. It deletes the EC2 Instances, Key Pairs, Security Groups, Subnets, and VPCs that are tagged with created-by = terratest and a UniqueId, and that were created longer ago than --older-than. Run it on a schedule to keep leaked resources from piling up.
+
+Arguments:
+
+  REGION    One or more AWS regions to delete leaked resources in. E.g.: us-east-1.
+
+
+Options:
+
+  --older-than DURATION    Only delete resources created at least this long ago. Default: 6h.
+  --dry-run                Only print the leaked resources, without deleting them.
+  --help                   Show this help text and exit.
+
+Example:
+
+  terratest_reaper --older-than 12h us-east-1 eu-west-1
+`
+
+func run(cliContext *cli.Context) error {
+	regions := cliContext.Args()
+	if len(regions) == 0 {
+		return fmt.Errorf("You must specify at least one AWS region")
+	}
+
+	olderThan, err := time.ParseDuration(cliContext.String("older-than"))
+	if err != nil {
+		return fmt.Errorf("Invalid value for --older-than: %s", err)
+	}
+
+	// Create mock testing.T implementation so we can re-use Terratest methods
+	t := MockTestingT{MockName: "terratest_reaper"}
+
+	_, err = reaper.ReapE(t, reaper.Options{Regions: regions, OlderThan: olderThan, DryRun: cliContext.Bool("dry-run")})
+	return err
+}
+
+func main() {
+	app := entrypoint.NewApp()
+	cli.AppHelpTemplate = CustomUsageText
+	entrypoint.HelpTextLineWidth = 120
+
+	app.Name = "terratest_reaper"
+	app.Author = "Gruntwork <www.gruntwork.io>"
+	app.Description = `This tool deletes the AWS resources tagged as created by Terratest test runs that are older than a given age.`
+	app.Flags = []cli.Flag{
+		cli.StringFlag{Name: "older-than", Value: "6h"},
+		cli.BoolFlag{Name: "dry-run"},
+	}
+	app.Action = run
+
+	entrypoint.RunApp(app)
+}
+
+// MockTestingT is a mock implementation of testing.TestingT. All the functions are essentially no-ops. This allows us
+// to use Terratest methods outside of a testing context (e.g., in a CLI tool).
+type MockTestingT struct {
+	MockName string
+}
+
+func (t MockTestingT) Fail()                                     {}
+func (t MockTestingT) FailNow()                                  {}
+func (t MockTestingT) Fatal(args ...interface{})                 {}
+func (t MockTestingT) Fatalf(format string, args ...interface{}) {}
+func (t MockTestingT) Error(args ...interface{})                 {}
+func (t MockTestingT) Errorf(format string, args ...interface{}) {}
+func (t MockTestingT) Name() string {
+	return t.MockName
+}
@@ -7,8 +7,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
 )
 
 // These are commonly used AMI account IDs.
@@ -105,7 +108,12 @@ func GetMostRecentAmiIdE(t testing.TestingT, region string, ownerId string, filt
 	if err != nil {
 		return "", err
 	}
+	return GetMostRecentAmiIdWithClientE(t, ec2Client, region, ownerId, filters)
+}
 
+// GetMostRecentAmiIdWithClientE gets the ID of the most recent AMI in the given region that has the given owner and
+// matches the given filters, with the ability to provide the EC2 client.
+func GetMostRecentAmiIdWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, region string, ownerId string, filters map[string][]string) (string, error) {
 	ec2Filters := []*ec2.Filter{}
 	for name, values := range filters {
 		ec2Filters = append(ec2Filters, &ec2.Filter{Name: aws.String(name), Values: aws.StringSlice(values)})
@@ -191,6 +199,28 @@ func GetUbuntu1604AmiE(t testing.TestingT, region string) (string, error) {
 	return GetMostRecentAmiIdE(t, region, CanonicalAccountId, filters)
 }
 
+// GetUbuntu2004Ami gets the ID of the most recent Ubuntu 20.04 HVM x86_64 EBS GP2 AMI in the given region.
+func GetUbuntu2004Ami(t testing.TestingT, region string) string {
+	amiID, err := GetUbuntu2004AmiE(t, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amiID
+}
+
+// GetUbuntu2004AmiE gets the ID of the most recent Ubuntu 20.04 HVM x86_64 EBS GP2 AMI in the given region.
+func GetUbuntu2004AmiE(t testing.TestingT, region string) (string, error) {
+	filters := map[string][]string{
+		"name":                             {"ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-*"},
+		"virtualization-type":              {"hvm"},
+		"architecture":                     {"x86_64"},
+		"root-device-type":                 {"ebs"},
+		"block-device-mapping.volume-type": {"gp2"},
+	}
+
+	return GetMostRecentAmiIdE(t, region, CanonicalAccountId, filters)
+}
+
 // GetCentos7Ami returns a CentOS 7 public AMI from the given region.
 // WARNING: you may have to accept the terms & conditions of this AMI in AWS MarketPlace for your AWS Account before
 // you can successfully launch the AMI.
@@ -239,6 +269,28 @@ func GetAmazonLinuxAmiE(t testing.TestingT, region string) (string, error) {
 	return GetMostRecentAmiIdE(t, region, AmazonAccountId, filters)
 }
 
+// GetAmazonLinux2Ami returns the most recent Amazon Linux 2 HVM x86_64 EBS GP2 public AMI for the given region.
+func GetAmazonLinux2Ami(t testing.TestingT, region string) string {
+	amiID, err := GetAmazonLinux2AmiE(t, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amiID
+}
+
+// GetAmazonLinux2AmiE returns the most recent Amazon Linux 2 HVM x86_64 EBS GP2 public AMI for the given region.
+func GetAmazonLinux2AmiE(t testing.TestingT, region string) (string, error) {
+	filters := map[string][]string{
+		"name":                             {"amzn2-ami-hvm-*-x86_64-gp2"},
+		"virtualization-type":              {"hvm"},
+		"architecture":                     {"x86_64"},
+		"root-device-type":                 {"ebs"},
+		"block-device-mapping.volume-type": {"gp2"},
+	}
+
+	return GetMostRecentAmiIdE(t, region, AmazonAccountId, filters)
+}
+
 // GetEcsOptimizedAmazonLinuxAmi returns an Amazon ECS-Optimized Amazon Linux AMI for the given region. This AMI is useful for running an ECS cluster.
 func GetEcsOptimizedAmazonLinuxAmi(t testing.TestingT, region string) string {
 	amiID, err := GetEcsOptimizedAmazonLinuxAmiE(t, region)
@@ -261,6 +313,83 @@ func GetEcsOptimizedAmazonLinuxAmiE(t testing.TestingT, region string) (string,
 	return GetMostRecentAmiIdE(t, region, AmazonAccountId, filters)
 }
 
+// CopyAmiToRegion copies the given AMI from srcRegion to destRegion, waits for the copy to be available, and returns
+// its ID. This is useful to check that an AMI built in one region works when launched in the (randomly selected)
+// region of a test. The copy is not deleted; use DeleteAmiAndAllSnapshots to clean it up.
+func CopyAmiToRegion(t testing.TestingT, srcRegion string, destRegion string, amiID string) string {
+	copyID, err := CopyAmiToRegionE(t, srcRegion, destRegion, amiID)
+	require.NoError(t, err)
+	return copyID
+}
+
+// CopyAmiToRegionE copies the given AMI from srcRegion to destRegion, waits for the copy to be available, and returns
+// its ID.
+func CopyAmiToRegionE(t testing.TestingT, srcRegion string, destRegion string, amiID string) (string, error) {
+	srcClient, err := NewEc2ClientE(t, srcRegion)
+	if err != nil {
+		return "", err
+	}
+	destClient, err := NewEc2ClientE(t, destRegion)
+	if err != nil {
+		return "", err
+	}
+
+	// Copying an AMI typically takes several minutes, and more for large volumes
+	return CopyAmiToRegionWithClientE(t, srcClient, destClient, srcRegion, amiID, 60, 30*time.Second)
+}
+
+// CopyAmiToRegionWithClientE copies the given AMI from srcRegion to the region of destClient, waits up to maxRetries
+// times sleepBetweenRetries for the copy to be available, and returns its ID. This function expects authenticated EC2
+// clients from the AWS SDK Go library for both regions.
+func CopyAmiToRegionWithClientE(t testing.TestingT, srcClient ec2iface.EC2API, destClient ec2iface.EC2API, srcRegion string, amiID string, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	images, err := srcClient.DescribeImages(&ec2.DescribeImagesInput{ImageIds: aws.StringSlice([]string{amiID})})
+	if err != nil {
+		return "", err
+	}
+	if len(images.Images) == 0 {
+		return "", NewNotFoundError("AMI", amiID, srcRegion)
+	}
+
+	logger.Logf(t, "Copying AMI %s from %s", amiID, srcRegion)
+	copyOutput, err := destClient.CopyImage(&ec2.CopyImageInput{
+		Name:          images.Images[0].Name,
+		Description:   aws.String(fmt.Sprintf("Copy of %s from %s", amiID, srcRegion)),
+		SourceImageId: aws.String(amiID),
+		SourceRegion:  aws.String(srcRegion),
+	})
+	if err != nil {
+		return "", err
+	}
+	copyID := aws.StringValue(copyOutput.ImageId)
+
+	err = retry.WaitUntilE(t, fmt.Sprintf("AMI %s is available", copyID), maxRetries+1, sleepBetweenRetries, func() (bool, error) {
+		copies, err := destClient.DescribeImages(&ec2.DescribeImagesInput{ImageIds: aws.StringSlice([]string{copyID})})
+		if err != nil {
+			return false, err
+		}
+		if len(copies.Images) == 0 {
+			// The copy may not be visible right away due to eventual consistency
+			return false, nil
+		}
+
+		image := copies.Images[0]
+		state := aws.StringValue(image.State)
+		switch state {
+		case ec2.ImageStateAvailable:
+			return true, nil
+		case ec2.ImageStateFailed, ec2.ImageStateInvalid, ec2.ImageStateError:
+			reason := ""
+			if image.StateReason != nil {
+				reason = aws.StringValue(image.StateReason.Message)
+			}
+			return false, retry.FatalError{Underlying: fmt.Errorf("copy %s of AMI %s is %s: %s", copyID, amiID, state, reason)}
+		default:
+			return false, nil
+		}
+	})
+	return copyID, err
+}
+
 // NoImagesFound is an error that occurs if no images were found.
 type NoImagesFound struct {
 	Region  string
@@ -2,8 +2,13 @@ package aws
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetUbuntu1404AmiReturnsSomeAmi(t *testing.T) {
@@ -20,6 +25,13 @@ func TestGetUbuntu1604AmiReturnsSomeAmi(t *testing.T) {
 	assert.Regexp(t, "^ami-[[:alnum:]]+$", amiID)
 }
 
+func TestGetUbuntu2004AmiReturnsSomeAmi(t *testing.T) {
+	t.Parallel()
+
+	amiID := GetUbuntu2004Ami(t, "eu-central-1")
+	assert.Regexp(t, "^ami-[[:alnum:]]+$", amiID)
+}
+
 func TestGetCentos7AmiReturnsSomeAmi(t *testing.T) {
 	t.Parallel()
 
@@ -34,9 +46,96 @@ func TestGetAmazonLinuxAmiReturnsSomeAmi(t *testing.T) {
 	assert.Regexp(t, "^ami-[[:alnum:]]+$", amiID)
 }
 
+func TestGetAmazonLinux2AmiReturnsSomeAmi(t *testing.T) {
+	t.Parallel()
+
+	amiID := GetAmazonLinux2Ami(t, "us-west-2")
+	assert.Regexp(t, "^ami-[[:alnum:]]+$", amiID)
+}
+
 func TestGetEcsOptimizedAmazonLinuxAmiEReturnsSomeAmi(t *testing.T) {
 	t.Parallel()
 
 	amiID := GetEcsOptimizedAmazonLinuxAmi(t, "us-east-2")
 	assert.Regexp(t, "^ami-[[:alnum:]]+$", amiID)
 }
+
+// fakeEc2ImageSearch returns the given images for any search, and records the inputs it was called with.
+type fakeEc2ImageSearch struct {
+	ec2iface.EC2API
+	images []*ec2.Image
+	inputs []*ec2.DescribeImagesInput
+}
+
+func (client *fakeEc2ImageSearch) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	client.inputs = append(client.inputs, input)
+	return &ec2.DescribeImagesOutput{Images: client.images}, nil
+}
+
+func TestGetMostRecentAmiIdWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeEc2ImageSearch{images: []*ec2.Image{
+		{ImageId: aws.String("ami-old"), CreationDate: aws.String("2021-01-01T00:00:00.000Z")},
+		{ImageId: aws.String("ami-new"), CreationDate: aws.String("2023-06-01T00:00:00.000Z")},
+		{ImageId: aws.String("ami-mid"), CreationDate: aws.String("2022-03-01T00:00:00.000Z")},
+	}}
+	filters := map[string][]string{"name": {"my-image-*"}}
+
+	amiID, err := GetMostRecentAmiIdWithClientE(t, client, "us-east-1", CanonicalAccountId, filters)
+	require.NoError(t, err)
+	assert.Equal(t, "ami-new", amiID)
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, []string{CanonicalAccountId}, aws.StringValueSlice(client.inputs[0].Owners))
+	assert.Equal(t, "name", aws.StringValue(client.inputs[0].Filters[0].Name))
+
+	_, err = GetMostRecentAmiIdWithClientE(t, &fakeEc2ImageSearch{}, "us-east-1", CanonicalAccountId, filters)
+	assert.Equal(t, NoImagesFound{Region: "us-east-1", OwnerId: CanonicalAccountId, Filters: filters}, err)
+}
+
+// fakeEc2Images serves the given images, which become available after the given number of DescribeImages calls.
+type fakeEc2Images struct {
+	ec2iface.EC2API
+	images           map[string]*ec2.Image
+	describesToReady int
+	copyInputs       []*ec2.CopyImageInput
+}
+
+func (client *fakeEc2Images) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	out := &ec2.DescribeImagesOutput{}
+	for _, id := range aws.StringValueSlice(input.ImageIds) {
+		if image, exists := client.images[id]; exists {
+			if client.describesToReady--; client.describesToReady <= 0 {
+				image.State = aws.String(ec2.ImageStateAvailable)
+			}
+			out.Images = append(out.Images, image)
+		}
+	}
+	return out, nil
+}
+
+func (client *fakeEc2Images) CopyImage(input *ec2.CopyImageInput) (*ec2.CopyImageOutput, error) {
+	client.copyInputs = append(client.copyInputs, input)
+	client.images["ami-copy"] = &ec2.Image{ImageId: aws.String("ami-copy"), Name: input.Name, State: aws.String(ec2.ImageStatePending)}
+	return &ec2.CopyImageOutput{ImageId: aws.String("ami-copy")}, nil
+}
+
+func TestCopyAmiToRegionWithClient(t *testing.T) {
+	t.Parallel()
+
+	srcClient := &fakeEc2Images{images: map[string]*ec2.Image{
+		"ami-source": {ImageId: aws.String("ami-source"), Name: aws.String("my-image"), State: aws.String(ec2.ImageStateAvailable)},
+	}}
+	destClient := &fakeEc2Images{images: map[string]*ec2.Image{}, describesToReady: 3}
+
+	copyID, err := CopyAmiToRegionWithClientE(t, srcClient, destClient, "us-east-1", "ami-source", 5, time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ami-copy", copyID)
+	require.Len(t, destClient.copyInputs, 1)
+	assert.Equal(t, "my-image", aws.StringValue(destClient.copyInputs[0].Name))
+	assert.Equal(t, "us-east-1", aws.StringValue(destClient.copyInputs[0].SourceRegion))
+
+	_, err = CopyAmiToRegionWithClientE(t, srcClient, destClient, "us-east-1", "ami-missing", 5, time.Millisecond)
+	assert.Equal(t, NewNotFoundError("AMI", "ami-missing", "us-east-1"), err)
+}
@@ -0,0 +1,221 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// AsgRollingUpdateOptions configures VerifyAsgRollingUpdate.
+type AsgRollingUpdateOptions struct {
+	AsgName             string
+	TargetGroupArn      string // The ALB/NLB target group the ASG registers its Instances with
+	AwsRegion           string
+	MinHealthyTargets   int           // The minimum number of healthy targets that must be kept throughout the update
+	MaxRetries          int           // The number of times to check if the update has completed
+	SleepBetweenRetries time.Duration // Also how often the ASG and target group are polled, so keep it shorter than the deregistration delay
+}
+
+// AsgRollingUpdateReport describes what was observed during a rolling update of an ASG.
+type AsgRollingUpdateReport struct {
+	OldInstanceIds        []string // The Instances in the ASG before the update
+	NewInstanceIds        []string // The Instances in the ASG after the update
+	UndrainedInstanceIds  []string // Old Instances that were never seen draining or deregistered from the target group
+	MinHealthyTargetsSeen int      // The lowest number of healthy targets seen during the update
+}
+
+// asgRollingUpdateSnapshot is the state of the ASG and its target group at one point during a rolling update.
+type asgRollingUpdateSnapshot struct {
+	desiredCapacity int64
+	instances       map[string]string // Instance ID to lifecycle state
+	targetHealth    map[string]string // Target ID to health state
+}
+
+// VerifyAsgRollingUpdate calls triggerUpdate to start a rolling update of the ASG (e.g., by running terraform apply with
+// a new launch template version), then tracks the Instances of the ASG and the health of its target group until all
+// old Instances have been replaced by healthy new ones. It fails the test if any old Instance was terminated without
+// being drained first or if the number of healthy targets dropped below the minimum.
+func VerifyAsgRollingUpdate(t testing.TestingT, options AsgRollingUpdateOptions, triggerUpdate func() error) AsgRollingUpdateReport {
+	report, err := VerifyAsgRollingUpdateE(t, options, triggerUpdate)
+	require.NoError(t, err)
+	return report
+}
+
+// VerifyAsgRollingUpdateE calls triggerUpdate to start a rolling update of the ASG (e.g., by running terraform apply
+// with a new launch template version), then tracks the Instances of the ASG and the health of its target group until
+// all old Instances have been replaced by healthy new ones. It returns an error if any old Instance was terminated
+// without being drained first or if the number of healthy targets dropped below the minimum.
+func VerifyAsgRollingUpdateE(t testing.TestingT, options AsgRollingUpdateOptions, triggerUpdate func() error) (AsgRollingUpdateReport, error) {
+	asgClient, err := NewAsgClientE(t, options.AwsRegion)
+	if err != nil {
+		return AsgRollingUpdateReport{}, err
+	}
+
+	oldInstanceIds, err := GetInstanceIdsForAsgE(t, options.AsgName, options.AwsRegion)
+	if err != nil {
+		return AsgRollingUpdateReport{}, err
+	}
+
+	var mutex sync.Mutex
+	var snapshots []asgRollingUpdateSnapshot
+	takeSnapshot := func() (asgRollingUpdateSnapshot, error) {
+		snapshot, err := getAsgRollingUpdateSnapshotE(t, asgClient, options)
+		if err != nil {
+			return snapshot, err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		snapshots = append(snapshots, snapshot)
+		return snapshot, nil
+	}
+
+	if _, err := takeSnapshot(); err != nil {
+		return AsgRollingUpdateReport{}, err
+	}
+
+	poller := retry.DoInBackgroundUntilStopped(t, fmt.Sprintf("Tracking rolling update of ASG %s", options.AsgName), options.SleepBetweenRetries, func() {
+		if _, err := takeSnapshot(); err != nil {
+			logger.Logf(t, "Failed to get the state of ASG %s: %s", options.AsgName, err)
+		}
+	})
+
+	err = triggerUpdate()
+	if err == nil {
+		_, err = retry.DoWithRetryE(
+			t,
+			fmt.Sprintf("Waiting for ASG %s to replace Instances %v.", options.AsgName, oldInstanceIds),
+			options.MaxRetries,
+			options.SleepBetweenRetries,
+			func() (string, error) {
+				snapshot, err := takeSnapshot()
+				if err != nil {
+					return "", err
+				}
+				return "", checkAsgRollingUpdateComplete(options.AsgName, oldInstanceIds, snapshot)
+			},
+		)
+	}
+	poller.Done()
+	if err != nil {
+		return AsgRollingUpdateReport{}, err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	report := analyzeAsgRollingUpdate(oldInstanceIds, snapshots)
+	logger.Logf(t, "ASG %s replaced Instances %v with %v", options.AsgName, report.OldInstanceIds, report.NewInstanceIds)
+
+	errorsOccurred := new(multierror.Error)
+	if len(report.UndrainedInstanceIds) > 0 {
+		errorsOccurred = multierror.Append(errorsOccurred, InstancesNotDrainedError{AsgName: options.AsgName, InstanceIds: report.UndrainedInstanceIds})
+	}
+	if report.MinHealthyTargetsSeen < options.MinHealthyTargets {
+		errorsOccurred = multierror.Append(errorsOccurred, TooFewHealthyTargetsError{TargetGroupArn: options.TargetGroupArn, MinHealthyTargets: options.MinHealthyTargets, HealthyTargets: report.MinHealthyTargetsSeen})
+	}
+	return report, errorsOccurred.ErrorOrNil()
+}
+
+func getAsgRollingUpdateSnapshotE(t testing.TestingT, asgClient *autoscaling.AutoScaling, options AsgRollingUpdateOptions) (asgRollingUpdateSnapshot, error) {
+	input := autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: []*string{aws.String(options.AsgName)}}
+	output, err := asgClient.DescribeAutoScalingGroups(&input)
+	if err != nil {
+		return asgRollingUpdateSnapshot{}, err
+	}
+	if len(output.AutoScalingGroups) == 0 {
+		return asgRollingUpdateSnapshot{}, NewNotFoundError("ASG", options.AsgName, options.AwsRegion)
+	}
+	group := output.AutoScalingGroups[0]
+
+	snapshot := asgRollingUpdateSnapshot{
+		desiredCapacity: aws.Int64Value(group.DesiredCapacity),
+		instances:       map[string]string{},
+	}
+	for _, instance := range group.Instances {
+		snapshot.instances[aws.StringValue(instance.InstanceId)] = aws.StringValue(instance.LifecycleState)
+	}
+
+	targetHealth, err := GetTargetHealthForTargetGroupE(t, options.AwsRegion, options.TargetGroupArn)
+	if err != nil {
+		return asgRollingUpdateSnapshot{}, err
+	}
+	snapshot.targetHealth = targetHealth
+
+	return snapshot, nil
+}
+
+// checkAsgRollingUpdateComplete returns an error unless none of the old Instances are left in the ASG and enough new
+// Instances are InService and healthy in the target group to meet the desired capacity.
+func checkAsgRollingUpdateComplete(asgName string, oldInstanceIds []string, snapshot asgRollingUpdateSnapshot) error {
+	remaining := []string{}
+	healthyCount := int64(0)
+	for instanceId, lifecycleState := range snapshot.instances {
+		if collections.ListContains(oldInstanceIds, instanceId) {
+			remaining = append(remaining, instanceId)
+		} else if lifecycleState == autoscaling.LifecycleStateInService && snapshot.targetHealth[instanceId] == elbv2.TargetHealthStateEnumHealthy {
+			healthyCount++
+		}
+	}
+	sort.Strings(remaining)
+
+	if len(remaining) > 0 || healthyCount < snapshot.desiredCapacity {
+		return AsgRollingUpdateNotCompleteError{AsgName: asgName, RemainingOldInstanceIds: remaining, HealthyNewInstances: healthyCount, DesiredCapacity: snapshot.desiredCapacity}
+	}
+	return nil
+}
+
+// analyzeAsgRollingUpdate builds a report from the snapshots taken during a rolling update. An old Instance counts as
+// drained if it was seen draining in the target group, or deregistered from it while still in the ASG.
+func analyzeAsgRollingUpdate(oldInstanceIds []string, snapshots []asgRollingUpdateSnapshot) AsgRollingUpdateReport {
+	report := AsgRollingUpdateReport{OldInstanceIds: oldInstanceIds, NewInstanceIds: []string{}, UndrainedInstanceIds: []string{}}
+	if len(snapshots) == 0 {
+		return report
+	}
+
+	drained := map[string]bool{}
+	report.MinHealthyTargetsSeen = -1
+	for _, snapshot := range snapshots {
+		healthyCount := 0
+		for _, state := range snapshot.targetHealth {
+			if state == elbv2.TargetHealthStateEnumHealthy {
+				healthyCount++
+			}
+		}
+		if report.MinHealthyTargetsSeen == -1 || healthyCount < report.MinHealthyTargetsSeen {
+			report.MinHealthyTargetsSeen = healthyCount
+		}
+
+		for _, instanceId := range oldInstanceIds {
+			state, inTargetGroup := snapshot.targetHealth[instanceId]
+			_, inAsg := snapshot.instances[instanceId]
+			if state == elbv2.TargetHealthStateEnumDraining || (inAsg && !inTargetGroup) {
+				drained[instanceId] = true
+			}
+		}
+	}
+
+	for _, instanceId := range oldInstanceIds {
+		if !drained[instanceId] {
+			report.UndrainedInstanceIds = append(report.UndrainedInstanceIds, instanceId)
+		}
+	}
+	for instanceId := range snapshots[len(snapshots)-1].instances {
+		if !collections.ListContains(oldInstanceIds, instanceId) {
+			report.NewInstanceIds = append(report.NewInstanceIds, instanceId)
+		}
+	}
+	sort.Strings(report.NewInstanceIds)
+
+	return report
+}
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAsgRollingUpdateComplete(t *testing.T) {
+	t.Parallel()
+
+	oldInstanceIds := []string{"i-old1", "i-old2"}
+
+	inProgress := asgRollingUpdateSnapshot{
+		desiredCapacity: 2,
+		instances:       map[string]string{"i-old2": "Terminating", "i-new1": "InService", "i-new2": "Pending"},
+		targetHealth:    map[string]string{"i-old2": "draining", "i-new1": "healthy", "i-new2": "initial"},
+	}
+	assert.Equal(t, AsgRollingUpdateNotCompleteError{AsgName: "asg", RemainingOldInstanceIds: []string{"i-old2"}, HealthyNewInstances: 1, DesiredCapacity: 2}, checkAsgRollingUpdateComplete("asg", oldInstanceIds, inProgress))
+
+	complete := asgRollingUpdateSnapshot{
+		desiredCapacity: 2,
+		instances:       map[string]string{"i-new1": "InService", "i-new2": "InService"},
+		targetHealth:    map[string]string{"i-new1": "healthy", "i-new2": "healthy"},
+	}
+	assert.NoError(t, checkAsgRollingUpdateComplete("asg", oldInstanceIds, complete))
+}
+
+func TestAnalyzeAsgRollingUpdate(t *testing.T) {
+	t.Parallel()
+
+	oldInstanceIds := []string{"i-old1", "i-old2", "i-old3"}
+	snapshots := []asgRollingUpdateSnapshot{
+		{
+			instances:    map[string]string{"i-old1": "InService", "i-old2": "InService", "i-old3": "InService"},
+			targetHealth: map[string]string{"i-old1": "healthy", "i-old2": "healthy", "i-old3": "healthy"},
+		},
+		{
+			// i-old1 is draining, i-old2 has been deregistered but is still in the ASG
+			instances:    map[string]string{"i-old1": "Terminating", "i-old2": "Terminating", "i-old3": "InService", "i-new1": "InService"},
+			targetHealth: map[string]string{"i-old1": "draining", "i-old3": "healthy", "i-new1": "initial"},
+		},
+		{
+			// i-old3 disappeared from both the ASG and the target group without being seen draining
+			instances:    map[string]string{"i-new1": "InService", "i-new2": "InService", "i-new3": "InService"},
+			targetHealth: map[string]string{"i-new1": "healthy", "i-new2": "healthy", "i-new3": "healthy"},
+		},
+	}
+
+	report := analyzeAsgRollingUpdate(oldInstanceIds, snapshots)
+	assert.Equal(t, AsgRollingUpdateReport{
+		OldInstanceIds:        oldInstanceIds,
+		NewInstanceIds:        []string{"i-new1", "i-new2", "i-new3"},
+		UndrainedInstanceIds:  []string{"i-old3"},
+		MinHealthyTargetsSeen: 1,
+	}, report)
+}
@@ -1,14 +1,17 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/stretchr/testify/require"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
@@ -34,6 +37,12 @@ func GetCapacityInfoForAsgE(t testing.TestingT, asgName string, awsRegion string
 		return AsgCapacityInfo{}, err
 	}
 
+	return GetCapacityInfoForAsgWithClientE(t, asgClient, asgName)
+}
+
+// GetCapacityInfoForAsgWithClientE returns the capacity info for the queried asg as a struct, AsgCapacityInfo, with the ability to provide
+// the Auto Scaling client.
+func GetCapacityInfoForAsgWithClientE(t testing.TestingT, asgClient autoscalingiface.AutoScalingAPI, asgName string) (AsgCapacityInfo, error) {
 	input := autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: []*string{aws.String(asgName)}}
 	output, err := asgClient.DescribeAutoScalingGroups(&input)
 	if err != nil {
@@ -41,7 +50,7 @@ func GetCapacityInfoForAsgE(t testing.TestingT, asgName string, awsRegion string
 	}
 	groups := output.AutoScalingGroups
 	if len(groups) == 0 {
-		return AsgCapacityInfo{}, NewNotFoundError("ASG", asgName, awsRegion)
+		return AsgCapacityInfo{}, NewNotFoundError("ASG", asgName, getAsgClientRegion(asgClient))
 	}
 	capacityInfo := AsgCapacityInfo{
 		MinCapacity:     *groups[0].MinSize,
@@ -68,6 +77,11 @@ func GetInstanceIdsForAsgE(t testing.TestingT, asgName string, awsRegion string)
 		return nil, err
 	}
 
+	return GetInstanceIdsForAsgWithClientE(t, asgClient, asgName)
+}
+
+// GetInstanceIdsForAsgWithClientE gets the IDs of EC2 Instances in the given ASG, with the ability to provide the Auto Scaling client.
+func GetInstanceIdsForAsgWithClientE(t testing.TestingT, asgClient autoscalingiface.AutoScalingAPI, asgName string) ([]string, error) {
 	input := autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: []*string{aws.String(asgName)}}
 	output, err := asgClient.DescribeAutoScalingGroups(&input)
 	if err != nil {
@@ -104,7 +118,20 @@ func WaitForCapacityE(
 	maxRetries int,
 	sleepBetweenRetries time.Duration,
 ) error {
-	msg, err := retry.DoWithRetryE(
+	return WaitForCapacityContextE(context.Background(), t, asgName, region, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForCapacityContextE works like WaitForCapacityE, but stops waiting as soon as the given context is done.
+func WaitForCapacityContextE(
+	ctx context.Context,
+	t testing.TestingT,
+	asgName string,
+	region string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	msg, err := retry.DoWithRetryContextE(
+		ctx,
 		t,
 		fmt.Sprintf("Waiting for ASG %s to reach desired capacity.", asgName),
 		maxRetries,
@@ -124,6 +151,213 @@ func WaitForCapacityE(
 	return err
 }
 
+// WaitForInstancesInService waits for the ASG to have as many InService Instances as its desired capacity, and returns
+// their IDs. Unlike WaitForCapacity, this doesn't count Instances that are still launching, so they are ready to be
+// registered with load balancers once this returns.
+func WaitForInstancesInService(
+	t testing.TestingT,
+	asgName string,
+	region string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) []string {
+	instanceIDs, err := WaitForInstancesInServiceE(t, asgName, region, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return instanceIDs
+}
+
+// WaitForInstancesInServiceE waits for the ASG to have as many InService Instances as its desired capacity, and returns
+// their IDs.
+func WaitForInstancesInServiceE(
+	t testing.TestingT,
+	asgName string,
+	region string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) ([]string, error) {
+	asgClient, err := NewAsgClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return WaitForInstancesInServiceWithClientE(t, asgClient, asgName, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForInstancesInServiceWithClientE waits for the ASG to have as many InService Instances as its desired capacity,
+// and returns their IDs, with the ability to provide the Auto Scaling client.
+func WaitForInstancesInServiceWithClientE(
+	t testing.TestingT,
+	asgClient autoscalingiface.AutoScalingAPI,
+	asgName string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) ([]string, error) {
+	out, err := retry.DoWithRetryInterfaceE(
+		t,
+		fmt.Sprintf("Waiting for the Instances of ASG %s to be InService.", asgName),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (interface{}, error) {
+			input := autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: []*string{aws.String(asgName)}}
+			output, err := asgClient.DescribeAutoScalingGroups(&input)
+			if err != nil {
+				return nil, err
+			}
+			if len(output.AutoScalingGroups) == 0 {
+				return nil, NewNotFoundError("ASG", asgName, getAsgClientRegion(asgClient))
+			}
+
+			group := output.AutoScalingGroups[0]
+			instanceIDs := inServiceInstanceIds(group)
+			if int64(len(instanceIDs)) < aws.Int64Value(group.DesiredCapacity) {
+				return nil, NewAsgCapacityNotMetError(asgName, aws.Int64Value(group.DesiredCapacity), int64(len(instanceIDs)))
+			}
+			return instanceIDs, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logf(t, "ASG %s has %d Instances InService", asgName, len(out.([]string)))
+	return out.([]string), nil
+}
+
+// inServiceInstanceIds returns the IDs of the Instances in the given ASG that are InService.
+func inServiceInstanceIds(group *autoscaling.Group) []string {
+	instanceIDs := []string{}
+	for _, instance := range group.Instances {
+		if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateInService {
+			instanceIDs = append(instanceIDs, aws.StringValue(instance.InstanceId))
+		}
+	}
+	return instanceIDs
+}
+
+// TerminateRandomInstanceInAsg terminates a random EC2 Instance in the given ASG to simulate an instance failure, and
+// returns the ID of the terminated Instance.
+func TerminateRandomInstanceInAsg(t testing.TestingT, asgName string, awsRegion string) string {
+	instanceID, err := TerminateRandomInstanceInAsgE(t, asgName, awsRegion)
+	require.NoError(t, err)
+	return instanceID
+}
+
+// TerminateRandomInstanceInAsgE terminates a random EC2 Instance in the given ASG to simulate an instance failure, and
+// returns the ID of the terminated Instance.
+func TerminateRandomInstanceInAsgE(t testing.TestingT, asgName string, awsRegion string) (string, error) {
+	instanceIDs, err := GetInstanceIdsForAsgE(t, asgName, awsRegion)
+	if err != nil {
+		return "", err
+	}
+	if len(instanceIDs) == 0 {
+		return "", NewNotFoundError("Instances in ASG", asgName, awsRegion)
+	}
+
+	instanceID := random.RandomString(instanceIDs)
+	return instanceID, TerminateInstanceE(t, awsRegion, instanceID)
+}
+
+// WaitForAsgToRecover waits for the ASG to replace the given Instance (e.g., one terminated with
+// TerminateRandomInstanceInAsg or stopped with StopInstance) and for all its Instances to be InService and Healthy at
+// the desired capacity.
+func WaitForAsgToRecover(
+	t testing.TestingT,
+	asgName string,
+	awsRegion string,
+	removedInstanceID string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) {
+	err := WaitForAsgToRecoverE(t, asgName, awsRegion, removedInstanceID, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForAsgToRecoverE waits for the ASG to replace the given Instance (e.g., one terminated with
+// TerminateRandomInstanceInAsg or stopped with StopInstance) and for all its Instances to be InService and Healthy at
+// the desired capacity.
+func WaitForAsgToRecoverE(
+	t testing.TestingT,
+	asgName string,
+	awsRegion string,
+	removedInstanceID string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	return WaitForAsgToRecoverContextE(context.Background(), t, asgName, awsRegion, removedInstanceID, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForAsgToRecoverContextE works like WaitForAsgToRecoverE, but stops waiting, and aborts the pending API call, as
+// soon as the given context is done.
+func WaitForAsgToRecoverContextE(
+	ctx context.Context,
+	t testing.TestingT,
+	asgName string,
+	awsRegion string,
+	removedInstanceID string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	asgClient, err := NewAsgClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	msg, err := retry.DoWithRetryContextE(
+		ctx,
+		t,
+		fmt.Sprintf("Waiting for ASG %s to replace Instance %s.", asgName, removedInstanceID),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			input := autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: []*string{aws.String(asgName)}}
+			output, err := asgClient.DescribeAutoScalingGroupsWithContext(ctx, &input)
+			if err != nil {
+				return "", err
+			}
+			if len(output.AutoScalingGroups) == 0 {
+				return "", NewNotFoundError("ASG", asgName, awsRegion)
+			}
+			if err := checkAsgRecovered(output.AutoScalingGroups[0], removedInstanceID); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("ASG %s has replaced Instance %s", asgName, removedInstanceID), nil
+		},
+	)
+	logger.Log(t, msg)
+	return err
+}
+
+// checkAsgRecovered returns an error if the given ASG still contains the removed Instance or has fewer InService and
+// Healthy Instances than its desired capacity.
+func checkAsgRecovered(group *autoscaling.Group, removedInstanceID string) error {
+	asgName := aws.StringValue(group.AutoScalingGroupName)
+	desiredCapacity := aws.Int64Value(group.DesiredCapacity)
+
+	healthyCount := int64(0)
+	for _, instance := range group.Instances {
+		if aws.StringValue(instance.InstanceId) == removedInstanceID {
+			return AsgNotRecoveredError{AsgName: asgName, RemovedInstanceId: removedInstanceID, StillInAsg: true, DesiredCapacity: desiredCapacity}
+		}
+		if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateInService && aws.StringValue(instance.HealthStatus) == "Healthy" {
+			healthyCount++
+		}
+	}
+
+	if healthyCount < desiredCapacity {
+		return AsgNotRecoveredError{AsgName: asgName, RemovedInstanceId: removedInstanceID, DesiredCapacity: desiredCapacity, HealthyCount: healthyCount}
+	}
+	return nil
+}
+
+// getAsgClientRegion returns the region the given client is configured for, or an empty string if it's not an SDK
+// client (e.g., a fake in a test).
+func getAsgClientRegion(asgClient autoscalingiface.AutoScalingAPI) string {
+	if sdkClient, isSdkClient := asgClient.(*autoscaling.AutoScaling); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
 // NewAsgClient creates an Auto Scaling Group client.
 func NewAsgClient(t testing.TestingT, region string) *autoscaling.AutoScaling {
 	client, err := NewAsgClientE(t, region)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +15,71 @@ import (
 	"github.com/gruntwork-io/terratest/modules/random"
 )
 
+// fakeAsg returns the given Auto Scaling Groups for any request.
+type fakeAsg struct {
+	autoscalingiface.AutoScalingAPI
+	groups []*autoscaling.Group
+}
+
+func (client fakeAsg) DescribeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	return &autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: client.groups}, nil
+}
+
+func TestAsgHelpersWithClient(t *testing.T) {
+	t.Parallel()
+
+	group := &autoscaling.Group{
+		MinSize:         aws.Int64(1),
+		MaxSize:         aws.Int64(3),
+		DesiredCapacity: aws.Int64(2),
+		Instances:       []*autoscaling.Instance{{InstanceId: aws.String("i-1")}, {InstanceId: aws.String("i-2")}},
+	}
+	client := fakeAsg{groups: []*autoscaling.Group{group}}
+
+	capacityInfo, err := GetCapacityInfoForAsgWithClientE(t, client, "terratest-asg")
+	require.NoError(t, err)
+	assert.Equal(t, AsgCapacityInfo{MinCapacity: 1, MaxCapacity: 3, DesiredCapacity: 2, CurrentCapacity: 2}, capacityInfo)
+
+	ids, err := GetInstanceIdsForAsgWithClientE(t, client, "terratest-asg")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-1", "i-2"}, ids)
+
+	_, err = GetCapacityInfoForAsgWithClientE(t, fakeAsg{}, "terratest-asg")
+	assert.IsType(t, NotFoundError{}, err)
+}
+
+// fakeLaunchingAsg returns an ASG whose Instances become InService one DescribeAutoScalingGroups call after another.
+type fakeLaunchingAsg struct {
+	autoscalingiface.AutoScalingAPI
+	describes int
+}
+
+func (client *fakeLaunchingAsg) DescribeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	client.describes++
+	group := &autoscaling.Group{AutoScalingGroupName: aws.String("terratest-asg"), DesiredCapacity: aws.Int64(2)}
+	for i := 1; i <= 2; i++ {
+		state := autoscaling.LifecycleStatePending
+		if i < client.describes {
+			state = autoscaling.LifecycleStateInService
+		}
+		group.Instances = append(group.Instances, &autoscaling.Instance{InstanceId: aws.String(fmt.Sprintf("i-%d", i)), LifecycleState: aws.String(state)})
+	}
+	return &autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []*autoscaling.Group{group}}, nil
+}
+
+func TestWaitForInstancesInServiceWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeLaunchingAsg{}
+	ids, err := WaitForInstancesInServiceWithClientE(t, client, "terratest-asg", 5, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-1", "i-2"}, ids)
+	assert.Equal(t, 3, client.describes)
+
+	_, err = WaitForInstancesInServiceWithClientE(t, &fakeLaunchingAsg{}, "terratest-asg", 1, time.Millisecond)
+	assert.Error(t, err)
+}
+
 func TestGetCapacityInfoForAsg(t *testing.T) {
 	t.Parallel()
 
@@ -47,6 +113,45 @@ func TestGetInstanceIdsForAsg(t *testing.T) {
 	assert.Equal(t, len(instanceIds), 1)
 }
 
+func TestTerminateRandomInstanceInAsgRecovers(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	asgName := fmt.Sprintf("%s-%s", t.Name(), uniqueID)
+	region := GetRandomStableRegion(t, []string{}, []string{})
+
+	defer deleteAutoScalingGroup(t, asgName, region)
+	createTestAutoScalingGroup(t, asgName, region, 2)
+	WaitForCapacity(t, asgName, region, 40, 15*time.Second)
+
+	terminatedInstanceID := TerminateRandomInstanceInAsg(t, asgName, region)
+	WaitForAsgToRecover(t, asgName, region, terminatedInstanceID, 40, 15*time.Second)
+
+	instanceIds := GetInstanceIdsForAsg(t, asgName, region)
+	assert.Equal(t, 2, len(instanceIds))
+	assert.NotContains(t, instanceIds, terminatedInstanceID)
+}
+
+func TestCheckAsgRecovered(t *testing.T) {
+	t.Parallel()
+
+	instance := func(id string, lifecycleState string, healthStatus string) *autoscaling.Instance {
+		return &autoscaling.Instance{InstanceId: aws.String(id), LifecycleState: aws.String(lifecycleState), HealthStatus: aws.String(healthStatus)}
+	}
+	group := func(instances ...*autoscaling.Instance) *autoscaling.Group {
+		return &autoscaling.Group{AutoScalingGroupName: aws.String("asg"), DesiredCapacity: aws.Int64(2), Instances: instances}
+	}
+
+	err := checkAsgRecovered(group(instance("i-1", "Terminating", "Unhealthy"), instance("i-2", "InService", "Healthy")), "i-1")
+	assert.Equal(t, AsgNotRecoveredError{AsgName: "asg", RemovedInstanceId: "i-1", StillInAsg: true, DesiredCapacity: 2}, err)
+
+	err = checkAsgRecovered(group(instance("i-2", "InService", "Healthy"), instance("i-3", "Pending", "Healthy")), "i-1")
+	assert.Equal(t, AsgNotRecoveredError{AsgName: "asg", RemovedInstanceId: "i-1", DesiredCapacity: 2, HealthyCount: 1}, err)
+
+	err = checkAsgRecovered(group(instance("i-2", "InService", "Healthy"), instance("i-3", "InService", "Healthy")), "i-1")
+	assert.NoError(t, err)
+}
+
 // The following functions were adapted from the tests for cloud-nuke
 
 func createTestAutoScalingGroup(t *testing.T, name string, region string, desiredCount int64) {
@@ -3,10 +3,12 @@ package aws
 import (
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
@@ -15,9 +17,43 @@ import (
 )
 
 const (
-	AuthAssumeRoleEnvVar = "TERRATEST_IAM_ROLE" // OS environment variable name through which Assume Role ARN may be passed for authentication
+	AuthAssumeRoleEnvVar          = "TERRATEST_IAM_ROLE"            // OS environment variable name through which Assume Role ARN may be passed for authentication
+	AuthAssumeRoleMfaSerialEnvVar = "TERRATEST_IAM_ROLE_MFA_SERIAL" // OS environment variable name through which the serial number (ARN) of the MFA device required to assume the role in TERRATEST_IAM_ROLE may be passed
+	AuthMfaTokenEnvVar            = "TERRATEST_MFA_TOKEN"           // OS environment variable name through which a one-time MFA token code may be passed, instead of prompting for it
 )
 
+var (
+	mfaTokenProviderMutex sync.Mutex
+	mfaTokenProvider      func() (string, error)
+)
+
+// SetMfaTokenProvider sets the function that's called for an MFA token code whenever a session needs to assume a role
+// that requires MFA, either because the profile in the AWS config file sets mfa_serial, or because
+// TERRATEST_IAM_ROLE_MFA_SERIAL is set. Use it to supply codes from e.g. a password manager CLI. Pass nil, the
+// default, to use the code in the TERRATEST_MFA_TOKEN environment variable if set, or to prompt for a code on stdin
+// otherwise (which requires running go test on a single package with -count=1, so stdin is attached to the test).
+func SetMfaTokenProvider(provider func() (string, error)) {
+	mfaTokenProviderMutex.Lock()
+	defer mfaTokenProviderMutex.Unlock()
+	mfaTokenProvider = provider
+}
+
+// getMfaToken returns an MFA token code from the provider set with SetMfaTokenProvider, the TERRATEST_MFA_TOKEN
+// environment variable, or stdin, in that order.
+func getMfaToken() (string, error) {
+	mfaTokenProviderMutex.Lock()
+	provider := mfaTokenProvider
+	mfaTokenProviderMutex.Unlock()
+
+	if provider != nil {
+		return provider()
+	}
+	if token, ok := os.LookupEnv(AuthMfaTokenEnvVar); ok {
+		return token, nil
+	}
+	return stscreds.StdinTokenProvider()
+}
+
 // NewAuthenticatedSession creates an AWS session following to standard AWS authentication workflow.
 // If AuthAssumeIamRoleEnvVar environment variable is set, assumes IAM role specified in it.
 func NewAuthenticatedSession(region string) (*session.Session, error) {
@@ -30,17 +66,20 @@ func NewAuthenticatedSession(region string) (*session.Session, error) {
 
 // NewAuthenticatedSessionFromDefaultCredentials gets an AWS Session, checking that the user has credentials properly configured in their environment.
 func NewAuthenticatedSessionFromDefaultCredentials(region string) (*session.Session, error) {
-	awsConfig := aws.NewConfig().WithRegion(region)
+	awsConfig := newConfig(region)
 
 	sessionOptions := session.Options{
 		Config:            *awsConfig,
 		SharedConfigState: session.SharedConfigEnable,
+		// Used if the profile assumes a role with mfa_serial set
+		AssumeRoleTokenProvider: getMfaToken,
 	}
 
 	sess, err := session.NewSessionWithOptions(sessionOptions)
 	if err != nil {
 		return nil, err
 	}
+	addRateLimitHandler(sess)
 
 	if _, err = sess.Config.Credentials.Get(); err != nil {
 		return nil, CredentialsError{UnderlyingErr: err}
@@ -68,26 +107,58 @@ func NewAuthenticatedSessionFromRole(region string, roleARN string) (*session.Se
 // CreateAwsSessionFromRole returns a new AWS session after assuming the role
 // whose ARN is provided in roleARN.
 func CreateAwsSessionFromRole(region string, roleARN string) (*session.Session, error) {
-	sess, err := session.NewSession(aws.NewConfig().WithRegion(region))
+	// Enable the shared config, so the credentials used to assume the role can come from e.g. a credential_process
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:                  *newConfig(region),
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: getMfaToken,
+	})
 	if err != nil {
 		return nil, err
 	}
+	addRateLimitHandler(sess)
 	sess = AssumeRole(sess, roleARN)
 	return sess, err
 }
 
 // AssumeRole mutates the provided session by obtaining new credentials by
-// assuming the role provided in roleARN.
+// assuming the role provided in roleARN. The credentials are refreshed shortly
+// before they expire, so they can be used for the whole length of a long test.
+// If TERRATEST_IAM_ROLE_MFA_SERIAL is set, the role is assumed with MFA (see
+// SetMfaTokenProvider).
 func AssumeRole(sess *session.Session, roleARN string) *session.Session {
-	sess.Config.Credentials = stscreds.NewCredentials(sess, roleARN)
+	sess.Config.Credentials = stscreds.NewCredentials(sess, roleARN, func(provider *stscreds.AssumeRoleProvider) {
+		provider.ExpiryWindow = credentialsExpiryWindow
+		if mfaSerial, ok := os.LookupEnv(AuthAssumeRoleMfaSerialEnvVar); ok {
+			provider.SerialNumber = aws.String(mfaSerial)
+			provider.TokenProvider = getMfaToken
+		}
+	})
 	return sess
 }
 
+// CreateAwsSessionWithCredentialProcess creates a new AWS session using the credentials returned by the given command,
+// which must follow the same protocol as the credential_process setting of the AWS config file. This is useful if
+// your credentials come from an external tool, but you can't (or don't want to) configure it in a profile.
+func CreateAwsSessionWithCredentialProcess(region string, command string) (*session.Session, error) {
+	creds := processcreds.NewCredentials(command)
+	sess, err := session.NewSession(newConfig(region).WithCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = sess.Config.Credentials.Get(); err != nil {
+		return nil, CredentialsError{UnderlyingErr: err}
+	}
+
+	return addRateLimitHandler(sess), nil
+}
+
 // CreateAwsSessionWithCreds creates a new AWS session using explicit credentials. This is useful if you want to create an IAM User dynamically and
 // create an AWS session authenticated as the new IAM User.
 func CreateAwsSessionWithCreds(region string, accessKeyID string, secretAccessKey string) (*session.Session, error) {
 	creds := CreateAwsCredentials(accessKeyID, secretAccessKey)
-	return session.NewSession(aws.NewConfig().WithRegion(region).WithCredentials(creds))
+	return session.NewSession(newConfig(region).WithCredentials(creds))
 }
 
 // CreateAwsSessionWithMfa creates a new AWS session authenticated using an MFA token retrieved using the given STS client and MFA Device.
@@ -110,7 +181,7 @@ func CreateAwsSessionWithMfa(region string, stsClient *sts.STS, mfaDevice *iam.V
 	sessionToken := *output.Credentials.SessionToken
 
 	creds := CreateAwsCredentialsWithSessionToken(accessKeyID, secretAccessKey, sessionToken)
-	return session.NewSession(aws.NewConfig().WithRegion(region).WithCredentials(creds))
+	return session.NewSession(newConfig(region).WithCredentials(creds))
 }
 
 // CreateAwsCredentials creates an AWS Credentials configuration with specific AWS credentials.
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAwsSessionWithCredentialProcess(t *testing.T) {
+	t.Parallel()
+
+	sess, err := CreateAwsSessionWithCredentialProcess("us-east-1", `echo '{"Version": 1, "AccessKeyId": "AKIAEXAMPLE", "SecretAccessKey": "secret", "SessionToken": "token"}'`)
+	require.NoError(t, err)
+
+	creds, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "AKIAEXAMPLE", creds.AccessKeyID)
+	assert.Equal(t, "secret", creds.SecretAccessKey)
+	assert.Equal(t, "token", creds.SessionToken)
+}
+
+func TestCreateAwsSessionWithFailingCredentialProcess(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateAwsSessionWithCredentialProcess("us-east-1", "exit 1")
+	require.Error(t, err)
+	assert.IsType(t, CredentialsError{}, err)
+}
+
+func TestGetMfaToken(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The MFA token provider and environment variables are process-wide.
+
+	os.Setenv(AuthMfaTokenEnvVar, "123456")
+	defer os.Unsetenv(AuthMfaTokenEnvVar)
+
+	token, err := getMfaToken()
+	require.NoError(t, err)
+	assert.Equal(t, "123456", token)
+
+	SetMfaTokenProvider(func() (string, error) { return "654321", nil })
+	defer SetMfaTokenProvider(nil)
+
+	token, err = getMfaToken()
+	require.NoError(t, err)
+	assert.Equal(t, "654321", token)
+}
@@ -1,2 +1,11 @@
 // Package aws allows to interact with resources on Amazon Web Services.
+//
+// Most helpers create their own SDK client from a session for the given region. The config of those sessions can be
+// customized with SetConfigCustomizer, e.g. to point the clients at a local endpoint. Helpers whose name ends in
+// WithClientE instead take the client as an interface from the SDK's *iface package (e.g. ec2iface.EC2API), so that
+// tests can pass a client of their own or a fake. Those variants exist for the EC2, ASG, IAM, S3, and SSM helpers that
+// read state or tag, stop, and delete resources; the other helpers always create their clients from a session.
+//
+// This package is built on version 1 of the AWS SDK for Go, and its helpers accept and return the types of that
+// version. Clients from aws-sdk-go-v2 are not supported.
 package aws
@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/gruntwork-io/terratest/modules/metrics"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// cloudWatchMaxMetricsPerRequest is the maximum number of metrics that can be sent in one PutMetricData request.
+const cloudWatchMaxMetricsPerRequest = 20
+
+// CloudWatchMetricsSink is a metrics.Sink that sends metrics to CloudWatch. The tags of the metrics are sent as
+// dimensions.
+type CloudWatchMetricsSink struct {
+	Client    *cloudwatch.CloudWatch
+	Namespace string // The CloudWatch namespace to send the metrics to, e.g., Terratest
+}
+
+// NewCloudWatchMetricsSink returns a CloudWatchMetricsSink that sends metrics to the given namespace in the given region.
+func NewCloudWatchMetricsSink(t testing.TestingT, region string, namespace string) *CloudWatchMetricsSink {
+	sink, err := NewCloudWatchMetricsSinkE(t, region, namespace)
+	require.NoError(t, err)
+	return sink
+}
+
+// NewCloudWatchMetricsSinkE returns a CloudWatchMetricsSink that sends metrics to the given namespace in the given
+// region.
+func NewCloudWatchMetricsSinkE(t testing.TestingT, region string, namespace string) (*CloudWatchMetricsSink, error) {
+	client, err := NewCloudWatchClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudWatchMetricsSink{Client: client, Namespace: namespace}, nil
+}
+
+// Send sends the given metrics to CloudWatch, in as few requests as possible.
+func (sink *CloudWatchMetricsSink) Send(metricsToSend []metrics.Metric) error {
+	data := toCloudWatchMetricData(metricsToSend)
+	for start := 0; start < len(data); start += cloudWatchMaxMetricsPerRequest {
+		end := start + cloudWatchMaxMetricsPerRequest
+		if end > len(data) {
+			end = len(data)
+		}
+
+		_, err := sink.Client.PutMetricData(&cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(sink.Namespace),
+			MetricData: data[start:end],
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toCloudWatchMetricData(metricsToSend []metrics.Metric) []*cloudwatch.MetricDatum {
+	data := []*cloudwatch.MetricDatum{}
+	for _, metric := range metricsToSend {
+		names := []string{}
+		for name := range metric.Tags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		dimensions := []*cloudwatch.Dimension{}
+		for _, name := range names {
+			dimensions = append(dimensions, &cloudwatch.Dimension{Name: aws.String(name), Value: aws.String(metric.Tags[name])})
+		}
+
+		// The units of the metrics package are named after the CloudWatch units
+		data = append(data, &cloudwatch.MetricDatum{
+			MetricName: aws.String(metric.Name),
+			Value:      aws.Float64(metric.Value),
+			Unit:       aws.String(metric.Unit),
+			Timestamp:  aws.Time(metric.Timestamp),
+			Dimensions: dimensions,
+		})
+	}
+	return data
+}
+
+// NewCloudWatchClient creates a new CloudWatch client.
+func NewCloudWatchClient(t testing.TestingT, region string) *cloudwatch.CloudWatch {
+	client, err := NewCloudWatchClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewCloudWatchClientE creates a new CloudWatch client.
+func NewCloudWatchClientE(t testing.TestingT, region string) (*cloudwatch.CloudWatch, error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+	return cloudwatch.New(sess), nil
+}
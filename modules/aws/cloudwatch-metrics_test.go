@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCloudWatchMetricData(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Now()
+	data := toCloudWatchMetricData([]metrics.Metric{{
+		Name:      "test.duration",
+		Value:     12.5,
+		Unit:      metrics.UnitSeconds,
+		Tags:      map[string]string{"test": "TestVpc", "module": "vpc"},
+		Timestamp: timestamp,
+	}})
+
+	require.Len(t, data, 1)
+	assert.Equal(t, "test.duration", *data[0].MetricName)
+	assert.Equal(t, 12.5, *data[0].Value)
+	assert.Equal(t, "Seconds", *data[0].Unit)
+	assert.Equal(t, timestamp, *data[0].Timestamp)
+	require.Len(t, data[0].Dimensions, 2)
+	assert.Equal(t, "module", *data[0].Dimensions[0].Name)
+	assert.Equal(t, "vpc", *data[0].Dimensions[0].Value)
+	assert.Equal(t, "test", *data[0].Dimensions[1].Name)
+}
@@ -1,8 +1,15 @@
 package aws
 
 import (
+	"fmt"
+	"regexp"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -22,21 +29,88 @@ func GetCloudWatchLogEntriesE(t testing.TestingT, awsRegion string, logStreamNam
 		return nil, err
 	}
 
-	output, err := client.GetLogEvents(&cloudwatchlogs.GetLogEventsInput{
+	return GetCloudWatchLogEntriesWithClientE(t, client, logStreamName, logGroupName)
+}
+
+// GetCloudWatchLogEntriesWithClientE returns all the CloudWatch log messages for the given log stream and log group,
+// oldest first, with the ability to provide the CloudWatch Logs client.
+func GetCloudWatchLogEntriesWithClientE(t testing.TestingT, client cloudwatchlogsiface.CloudWatchLogsAPI, logStreamName string, logGroupName string) ([]string, error) {
+	input := &cloudwatchlogs.GetLogEventsInput{
 		LogGroupName:  aws.String(logGroupName),
 		LogStreamName: aws.String(logStreamName),
-	})
+		StartFromHead: aws.Bool(true),
+	}
 
+	entries := []string{}
+	err := client.GetLogEventsPages(input, func(page *cloudwatchlogs.GetLogEventsOutput, lastPage bool) bool {
+		for _, event := range page.Events {
+			entries = append(entries, aws.StringValue(event.Message))
+		}
+		return true
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	entries := []string{}
-	for _, event := range output.Events {
-		entries = append(entries, *event.Message)
+	return entries, nil
+}
+
+// WaitForCloudWatchLogEntryMatching waits until the given log stream and log group contain a log message that matches
+// the given regular expression, retrying up to maxRetries times with sleepBetweenRetries in between, and returns the
+// first matching message.
+func WaitForCloudWatchLogEntryMatching(t testing.TestingT, awsRegion string, logStreamName string, logGroupName string, pattern string, maxRetries int, sleepBetweenRetries time.Duration) string {
+	entry, err := WaitForCloudWatchLogEntryMatchingE(t, awsRegion, logStreamName, logGroupName, pattern, maxRetries, sleepBetweenRetries)
+	if err != nil {
+		t.Fatal(err)
 	}
+	return entry
+}
 
-	return entries, nil
+// WaitForCloudWatchLogEntryMatchingE waits until the given log stream and log group contain a log message that matches
+// the given regular expression, retrying up to maxRetries times with sleepBetweenRetries in between, and returns the
+// first matching message.
+func WaitForCloudWatchLogEntryMatchingE(t testing.TestingT, awsRegion string, logStreamName string, logGroupName string, pattern string, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	client, err := NewCloudWatchLogsClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	return WaitForCloudWatchLogEntryMatchingWithClientE(t, client, logStreamName, logGroupName, pattern, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForCloudWatchLogEntryMatchingWithClientE waits until the given log stream and log group contain a log message
+// that matches the given regular expression, with the ability to provide the CloudWatch Logs client, and returns the
+// first matching message. The log stream doesn't need to exist yet when this is called.
+func WaitForCloudWatchLogEntryMatchingWithClientE(t testing.TestingT, client cloudwatchlogsiface.CloudWatchLogsAPI, logStreamName string, logGroupName string, pattern string, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := retry.DoWithRetryE(
+		t,
+		fmt.Sprintf("Waiting for a log message matching %s in log stream %s of log group %s.", pattern, logStreamName, logGroupName),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			entries, err := GetCloudWatchLogEntriesWithClientE(t, client, logStreamName, logGroupName)
+			if err != nil {
+				return "", err
+			}
+			for _, entry := range entries {
+				if re.MatchString(entry) {
+					return entry, nil
+				}
+			}
+			return "", fmt.Errorf("none of the %d log messages in log stream %s match %s", len(entries), logStreamName, pattern)
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Logf(t, "Found log message matching %s: %s", pattern, entry)
+	return entry, nil
 }
 
 // NewCloudWatchLogsClient creates a new CloudWatch Logs client.
@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCloudWatchLogs returns the given pages of log messages, and one more page of log messages on each call.
+type fakeCloudWatchLogs struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	pages [][]string
+	next  []string
+}
+
+func (client *fakeCloudWatchLogs) GetLogEventsPages(input *cloudwatchlogs.GetLogEventsInput, fn func(*cloudwatchlogs.GetLogEventsOutput, bool) bool) error {
+	for i, page := range client.pages {
+		output := &cloudwatchlogs.GetLogEventsOutput{}
+		for _, message := range page {
+			output.Events = append(output.Events, &cloudwatchlogs.OutputLogEvent{Message: aws.String(message)})
+		}
+		if !fn(output, i == len(client.pages)-1) {
+			break
+		}
+	}
+	client.pages = append(client.pages, client.next)
+	return nil
+}
+
+func TestGetCloudWatchLogEntriesWithClientReadsAllPages(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeCloudWatchLogs{pages: [][]string{{"first", "second"}, {"third"}}}
+	entries, err := GetCloudWatchLogEntriesWithClientE(t, client, "stream", "group")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, entries)
+}
+
+func TestWaitForCloudWatchLogEntryMatchingWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeCloudWatchLogs{pages: [][]string{{"booting"}}, next: []string{"server listening on port 8080"}}
+	entry, err := WaitForCloudWatchLogEntryMatchingWithClientE(t, client, "stream", "group", `listening on port \d+`, 2, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "server listening on port 8080", entry)
+
+	_, err = WaitForCloudWatchLogEntryMatchingWithClientE(t, client, "stream", "group", "(", 2, time.Millisecond)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultCredentialsMinValidity is how long the credentials returned by NewRefreshingCredentialsEnvVars are at least
+// valid for by default. It should be longer than any single Terraform command in the test.
+const DefaultCredentialsMinValidity = 15 * time.Minute
+
+// credentialsExpiryWindow is how long before they expire assumed role credentials are refreshed, so that requests
+// that are already in flight, and retries of them, don't fail with ExpiredToken.
+const credentialsExpiryWindow = 5 * time.Minute
+
+// NewRefreshingCredentialsEnvVars returns a function that returns the AWS credentials of the current environment (see
+// NewAuthenticatedSession) as the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN environment variables.
+// Temporary credentials, such as those of an assumed role or SSO, are refreshed whenever they'd expire within
+// minValidity. Pass the function as the EnvVarsFunc of terraform.Options so that each Terraform command, including a
+// destroy at the end of a test that runs for longer than the credentials are valid, gets fresh credentials:
+//
+//	terraformOptions := &terraform.Options{
+//		TerraformDir: "../examples/terraform-aws-example",
+//		EnvVarsFunc:  aws.NewRefreshingCredentialsEnvVars(t, region, aws.DefaultCredentialsMinValidity),
+//	}
+//
+// This will fail the test if there is an error creating the AWS session.
+func NewRefreshingCredentialsEnvVars(t testing.TestingT, region string, minValidity time.Duration) func() (map[string]string, error) {
+	envVarsFunc, err := NewRefreshingCredentialsEnvVarsE(t, region, minValidity)
+	require.NoError(t, err)
+	return envVarsFunc
+}
+
+// NewRefreshingCredentialsEnvVarsE returns a function that returns the AWS credentials of the current environment (see
+// NewAuthenticatedSession) as the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN environment variables.
+// Temporary credentials, such as those of an assumed role or SSO, are refreshed whenever they'd expire within
+// minValidity. See NewRefreshingCredentialsEnvVars for an example.
+func NewRefreshingCredentialsEnvVarsE(t testing.TestingT, region string, minValidity time.Duration) (func() (map[string]string, error), error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (map[string]string, error) {
+		return getCredentialsEnvVars(sess.Config.Credentials, minValidity)
+	}, nil
+}
+
+// getCredentialsEnvVars returns the given credentials as environment variables, refreshing them first if they'd expire
+// within minValidity.
+func getCredentialsEnvVars(creds *credentials.Credentials, minValidity time.Duration) (map[string]string, error) {
+	// Credentials that never expire, such as static keys, return an error here, and never need refreshing
+	if expiresAt, err := creds.ExpiresAt(); err == nil && time.Until(expiresAt) < minValidity {
+		creds.Expire()
+	}
+
+	value, err := creds.Get()
+	if err != nil {
+		return nil, CredentialsError{UnderlyingErr: err}
+	}
+
+	envVars := map[string]string{
+		"AWS_ACCESS_KEY_ID":     value.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY": value.SecretAccessKey,
+	}
+	if value.SessionToken != "" {
+		envVars["AWS_SESSION_TOKEN"] = value.SessionToken
+	}
+	return envVars, nil
+}
@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExpiringProvider returns new temporary credentials, valid for validity, every time they're retrieved.
+type fakeExpiringProvider struct {
+	credentials.Expiry
+	validity   time.Duration
+	retrievals int
+}
+
+func (provider *fakeExpiringProvider) Retrieve() (credentials.Value, error) {
+	provider.retrievals++
+	provider.SetExpiration(time.Now().Add(provider.validity), 0)
+	return credentials.Value{
+		AccessKeyID:     fmt.Sprintf("ASIA%d", provider.retrievals),
+		SecretAccessKey: "secret",
+		SessionToken:    fmt.Sprintf("token%d", provider.retrievals),
+	}, nil
+}
+
+func TestGetCredentialsEnvVarsRefreshesCredentialsThatExpireSoon(t *testing.T) {
+	t.Parallel()
+
+	provider := &fakeExpiringProvider{validity: 10 * time.Minute}
+	creds := credentials.NewCredentials(provider)
+
+	envVars, err := getCredentialsEnvVars(creds, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"AWS_ACCESS_KEY_ID": "ASIA1", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_SESSION_TOKEN": "token1"}, envVars)
+
+	// Still valid for long enough, so the same credentials are returned
+	envVars, err = getCredentialsEnvVars(creds, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA1", envVars["AWS_ACCESS_KEY_ID"])
+
+	// Not valid for long enough, so they're refreshed
+	envVars, err = getCredentialsEnvVars(creds, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA2", envVars["AWS_ACCESS_KEY_ID"])
+	assert.Equal(t, "token2", envVars["AWS_SESSION_TOKEN"])
+}
+
+func TestGetCredentialsEnvVarsStaticCredentials(t *testing.T) {
+	t.Parallel()
+
+	envVars, err := getCredentialsEnvVars(CreateAwsCredentials("AKIAEXAMPLE", "secret"), DefaultCredentialsMinValidity)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"AWS_ACCESS_KEY_ID": "AKIAEXAMPLE", "AWS_SECRET_ACCESS_KEY": "secret"}, envVars)
+}
@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	// Deleted resources can still be visible for a little while due to eventual consistency
+	defaultDestroyCheckMaxRetries          = 12
+	defaultDestroyCheckSleepBetweenRetries = 5 * time.Second
+)
+
+// DestroyedResource identifies a resource that should no longer exist, e.g. after terraform destroy: Address is only
+// used to name the resource in errors, Type is its Terraform resource type (e.g. aws_instance), and ID is its ID in AWS.
+type DestroyedResource struct {
+	Address string
+	Type    string
+	ID      string
+}
+
+// AssertResourcesDestroyed checks with AWS that none of the given resources exists in the given region anymore,
+// failing the test if any of them does. This catches providers that report a successful destroy but silently leave
+// orphans behind. The resource types that are checked are aws_instance (terminated instances count as destroyed),
+// aws_security_group, and aws_s3_bucket; resources of other types are skipped. To check the resources of a Terraform
+// state, see test_structure.DestroyAndAssertAwsResourcesDestroyed.
+func AssertResourcesDestroyed(t testing.TestingT, region string, resources []DestroyedResource) {
+	require.NoError(t, AssertResourcesDestroyedE(t, region, resources))
+}
+
+// AssertResourcesDestroyedE checks with AWS that none of the given resources exists in the given region anymore,
+// returning a ResourcesNotDestroyedError if any of them does.
+func AssertResourcesDestroyedE(t testing.TestingT, region string, resources []DestroyedResource) error {
+	ec2Client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return err
+	}
+	s3Client, err := NewS3ClientE(t, region)
+	if err != nil {
+		return err
+	}
+	return AssertResourcesDestroyedWithClientsE(t, ec2Client, s3Client, resources, defaultDestroyCheckMaxRetries, defaultDestroyCheckSleepBetweenRetries)
+}
+
+// AssertResourcesDestroyedWithClientsE checks that none of the given resources exists anymore, retrying up to
+// maxRetries times to give AWS time to catch up, and returns a ResourcesNotDestroyedError if any of them does. This
+// function expects authenticated EC2 and S3 clients from the AWS SDK Go library.
+func AssertResourcesDestroyedWithClientsE(t testing.TestingT, ec2Client ec2iface.EC2API, s3Client s3iface.S3API, resources []DestroyedResource, maxRetries int, sleepBetweenRetries time.Duration) error {
+	checked := []DestroyedResource{}
+	for _, resource := range resources {
+		if _, isChecked := destroyedResourceCheckers[resource.Type]; !isChecked {
+			logger.Logf(t, "Not checking that %s was destroyed: resources of type %s are not supported", resource.Address, resource.Type)
+			continue
+		}
+		checked = append(checked, resource)
+	}
+	sort.Slice(checked, func(i, j int) bool { return checked[i].Address < checked[j].Address })
+
+	var survivors []string
+	err := retry.WaitUntilE(t, fmt.Sprintf("%d resources are destroyed", len(checked)), maxRetries+1, sleepBetweenRetries, func() (bool, error) {
+		survivors = []string{}
+		for _, resource := range checked {
+			exists, err := destroyedResourceCheckers[resource.Type](t, ec2Client, s3Client, resource.ID)
+			if err != nil {
+				return false, retry.FatalError{Underlying: err}
+			}
+			if exists {
+				survivors = append(survivors, fmt.Sprintf("%s (%s)", resource.Address, resource.ID))
+			}
+		}
+		return len(survivors) == 0, nil
+	})
+
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case retry.FatalError:
+		return err.Underlying
+	case retry.ConditionNotMet:
+		return ResourcesNotDestroyedError{Resources: survivors}
+	default:
+		return err
+	}
+}
+
+// destroyedResourceCheckers returns whether the resource with the given ID still exists, for each supported type.
+var destroyedResourceCheckers = map[string]func(t testing.TestingT, ec2Client ec2iface.EC2API, s3Client s3iface.S3API, id string) (bool, error){
+	"aws_instance": func(t testing.TestingT, ec2Client ec2iface.EC2API, s3Client s3iface.S3API, id string) (bool, error) {
+		out, err := ec2Client.DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{id})})
+		if isAwsErrorCode(err, "InvalidInstanceID.NotFound") {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				state := aws.StringValue(instance.State.Name)
+				if state != ec2.InstanceStateNameTerminated && state != ec2.InstanceStateNameShuttingDown {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	},
+	"aws_security_group": func(t testing.TestingT, ec2Client ec2iface.EC2API, s3Client s3iface.S3API, id string) (bool, error) {
+		out, err := ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{GroupIds: aws.StringSlice([]string{id})})
+		if isAwsErrorCode(err, "InvalidGroup.NotFound") {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return len(out.SecurityGroups) > 0, nil
+	},
+	"aws_s3_bucket": func(t testing.TestingT, ec2Client ec2iface.EC2API, s3Client s3iface.S3API, id string) (bool, error) {
+		_, err := s3Client.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(id)})
+		if isAwsErrorCode(err, "NotFound") || isAwsErrorCode(err, s3.ErrCodeNoSuchBucket) {
+			return false, nil
+		}
+		// HeadBucket responses have no body, so the status code is all there is to go on
+		if requestErr, isRequestErr := err.(awserr.RequestFailure); isRequestErr {
+			switch requestErr.StatusCode() {
+			case http.StatusForbidden:
+				// The bucket exists, but these credentials can no longer see it
+				return true, nil
+			case http.StatusMovedPermanently:
+				// The bucket exists in another region, so the name has been taken by another bucket since the destroy
+				logger.Logf(t, "Not checking that S3 bucket %s was destroyed: a bucket with that name exists in another region", id)
+				return false, nil
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	},
+}
+
+// isAwsErrorCode returns true if the given error is an AWS error with the given code.
+func isAwsErrorCode(err error, code string) bool {
+	awsErr, isAwsErr := err.(awserr.Error)
+	return isAwsErr && awsErr.Code() == code
+}
@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEc2Instances returns the given instance states, and not found for any other instance or security group.
+type fakeEc2Instances struct {
+	ec2iface.EC2API
+	states map[string]string
+}
+
+func (client fakeEc2Instances) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	id := aws.StringValue(input.InstanceIds[0])
+	state, exists := client.states[id]
+	if !exists {
+		return nil, awserr.New("InvalidInstanceID.NotFound", "not found", nil)
+	}
+	instance := &ec2.Instance{InstanceId: aws.String(id), State: &ec2.InstanceState{Name: aws.String(state)}}
+	return &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{instance}}}}, nil
+}
+
+func (client fakeEc2Instances) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return nil, awserr.New("InvalidGroup.NotFound", "not found", nil)
+}
+
+// fakeS3Buckets answers HeadBucket with the given status code for each bucket, and not found for any other bucket.
+type fakeS3Buckets struct {
+	s3iface.S3API
+	statusCodes map[string]int
+}
+
+func (client fakeS3Buckets) HeadBucket(input *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	switch statusCode := client.statusCodes[aws.StringValue(input.Bucket)]; statusCode {
+	case http.StatusOK:
+		return &s3.HeadBucketOutput{}, nil
+	case 0:
+		return nil, awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), http.StatusNotFound, "")
+	default:
+		return nil, awserr.NewRequestFailure(awserr.New(http.StatusText(statusCode), http.StatusText(statusCode), nil), statusCode, "")
+	}
+}
+
+func TestAssertResourcesDestroyedWithClients(t *testing.T) {
+	t.Parallel()
+
+	ec2Client := fakeEc2Instances{states: map[string]string{"i-terminated": ec2.InstanceStateNameTerminated, "i-running": ec2.InstanceStateNameRunning}}
+	s3Client := fakeS3Buckets{statusCodes: map[string]int{
+		"orphan-bucket":    http.StatusOK,
+		"forbidden-bucket": http.StatusForbidden,
+		"moved-bucket":     http.StatusMovedPermanently,
+	}}
+
+	destroyed := []DestroyedResource{
+		{Address: "aws_instance.web", Type: "aws_instance", ID: "i-terminated"},
+		{Address: "aws_instance.gone", Type: "aws_instance", ID: "i-gone"},
+		{Address: "aws_security_group.web", Type: "aws_security_group", ID: "sg-0abc"},
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", ID: "deleted-bucket"},
+		{Address: "aws_s3_bucket.moved", Type: "aws_s3_bucket", ID: "moved-bucket"},
+		{Address: "aws_iam_role.unsupported", Type: "aws_iam_role", ID: "role"},
+	}
+	require.NoError(t, AssertResourcesDestroyedWithClientsE(t, ec2Client, s3Client, destroyed, 0, time.Millisecond))
+
+	survived := []DestroyedResource{
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", ID: "orphan-bucket"},
+		{Address: "aws_instance.web", Type: "aws_instance", ID: "i-running"},
+		{Address: "aws_s3_bucket.private", Type: "aws_s3_bucket", ID: "forbidden-bucket"},
+	}
+	err := AssertResourcesDestroyedWithClientsE(t, ec2Client, s3Client, survived, 1, time.Millisecond)
+	assert.Equal(t, ResourcesNotDestroyedError{Resources: []string{"aws_instance.web (i-running)", "aws_s3_bucket.logs (orphan-bucket)", "aws_s3_bucket.private (forbidden-bucket)"}}, err)
+}
@@ -1,8 +1,12 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -62,6 +66,90 @@ func GetDynamoDBTableE(t testing.TestingT, region string, tableName string) (*dy
 	return out.Table, err
 }
 
+// PutDynamoDBItem writes the given item, e.g. map[string]interface{}{"LockID": "test", "Count": 1} or a struct, to the
+// specified dynamoDB table, replacing any existing item with the same key. This will fail the test if there are any
+// errors.
+func PutDynamoDBItem(t testing.TestingT, region string, tableName string, item interface{}) {
+	require.NoError(t, PutDynamoDBItemE(t, region, tableName, item))
+}
+
+// PutDynamoDBItemE writes the given item, e.g. map[string]interface{}{"LockID": "test", "Count": 1} or a struct, to the
+// specified dynamoDB table, replacing any existing item with the same key.
+func PutDynamoDBItemE(t testing.TestingT, region string, tableName string, item interface{}) error {
+	client, err := NewDynamoDBClientE(t, region)
+	if err != nil {
+		return err
+	}
+	return PutDynamoDBItemWithClientE(t, client, tableName, item)
+}
+
+// PutDynamoDBItemWithClientE writes the given item to the specified dynamoDB table, with the ability to provide the
+// DynamoDB client.
+func PutDynamoDBItemWithClientE(t testing.TestingT, client dynamodbiface.DynamoDBAPI, tableName string, item interface{}) error {
+	attributes, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      attributes,
+	})
+	return err
+}
+
+// GetDynamoDBItem reads the item with the given key, e.g. map[string]interface{}{"LockID": "test"}, from the specified
+// dynamoDB table, using a strongly consistent read. This will fail the test if there are any errors, including if the
+// item doesn't exist.
+func GetDynamoDBItem(t testing.TestingT, region string, tableName string, key interface{}) map[string]interface{} {
+	item, err := GetDynamoDBItemE(t, region, tableName, key)
+	require.NoError(t, err)
+	return item
+}
+
+// GetDynamoDBItemE reads the item with the given key, e.g. map[string]interface{}{"LockID": "test"}, from the specified
+// dynamoDB table, using a strongly consistent read. Returns a NotFoundError if the item doesn't exist.
+func GetDynamoDBItemE(t testing.TestingT, region string, tableName string, key interface{}) (map[string]interface{}, error) {
+	client, err := NewDynamoDBClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return GetDynamoDBItemWithClientE(t, client, tableName, key)
+}
+
+// GetDynamoDBItemWithClientE reads the item with the given key from the specified dynamoDB table, with the ability to
+// provide the DynamoDB client. Numbers in the item are returned as float64.
+func GetDynamoDBItemWithClientE(t testing.TestingT, client dynamodbiface.DynamoDBAPI, tableName string, key interface{}) (map[string]interface{}, error) {
+	keyAttributes, err := dynamodbattribute.MarshalMap(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(tableName),
+		Key:            keyAttributes,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Item) == 0 {
+		return nil, NewNotFoundError("DynamoDB item", fmt.Sprintf("%v", key), getDynamoDBClientRegion(client))
+	}
+
+	item := map[string]interface{}{}
+	err = dynamodbattribute.UnmarshalMap(out.Item, &item)
+	return item, err
+}
+
+// getDynamoDBClientRegion returns the region of the given DynamoDB client, or an empty string if it isn't an SDK client.
+func getDynamoDBClientRegion(client dynamodbiface.DynamoDBAPI) string {
+	if sdkClient, isSdkClient := client.(*dynamodb.DynamoDB); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
 // NewDynamoDBClient creates a DynamoDB client.
 func NewDynamoDBClient(t testing.TestingT, region string) *dynamodb.DynamoDB {
 	client, err := NewDynamoDBClientE(t, region)
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDynamoDBTable stores the items it is given, keyed by their LockID.
+type fakeDynamoDBTable struct {
+	dynamodbiface.DynamoDBAPI
+	items map[string]map[string]*dynamodb.AttributeValue
+}
+
+func (client *fakeDynamoDBTable) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	client.items[aws.StringValue(input.Item["LockID"].S)] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (client *fakeDynamoDBTable) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: client.items[aws.StringValue(input.Key["LockID"].S)]}, nil
+}
+
+func TestPutAndGetDynamoDBItemWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeDynamoDBTable{items: map[string]map[string]*dynamodb.AttributeValue{}}
+	err := PutDynamoDBItemWithClientE(t, client, "locks", map[string]interface{}{"LockID": "test", "Count": 2, "Owner": "terratest"})
+	require.NoError(t, err)
+
+	item, err := GetDynamoDBItemWithClientE(t, client, "locks", map[string]string{"LockID": "test"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"LockID": "test", "Count": float64(2), "Owner": "terratest"}, item)
+
+	_, err = GetDynamoDBItemWithClientE(t, client, "locks", map[string]string{"LockID": "missing"})
+	assert.IsType(t, NotFoundError{}, err)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
@@ -42,7 +43,17 @@ func GetPrivateIpsOfEc2Instances(t testing.TestingT, instanceIDs []string, awsRe
 
 // GetPrivateIpsOfEc2InstancesE gets the private IP address of the given EC2 Instance in the given region. Returns a map of instance ID to IP address.
 func GetPrivateIpsOfEc2InstancesE(t testing.TestingT, instanceIDs []string, awsRegion string) (map[string]string, error) {
-	ec2Client := NewEc2Client(t, awsRegion)
+	ec2Client, err := NewEc2ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetPrivateIpsOfEc2InstancesWithClientE(t, ec2Client, instanceIDs)
+}
+
+// GetPrivateIpsOfEc2InstancesWithClientE gets the private IP address of the given EC2 Instances, with the ability to provide the EC2
+// client. Returns a map of instance ID to IP address.
+func GetPrivateIpsOfEc2InstancesWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, instanceIDs []string) (map[string]string, error) {
 	// TODO: implement pagination for cases that extend beyond limit (1000 instances)
 	input := ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice(instanceIDs)}
 	output, err := ec2Client.DescribeInstances(&input)
@@ -97,6 +108,13 @@ func GetPrivateHostnamesOfEc2InstancesE(t testing.TestingT, instanceIDs []string
 	if err != nil {
 		return nil, err
 	}
+
+	return GetPrivateHostnamesOfEc2InstancesWithClientE(t, ec2Client, instanceIDs)
+}
+
+// GetPrivateHostnamesOfEc2InstancesWithClientE gets the private hostname of the given EC2 Instances, with the ability to provide the EC2
+// client. Returns a map of instance ID to hostname.
+func GetPrivateHostnamesOfEc2InstancesWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, instanceIDs []string) (map[string]string, error) {
 	// TODO: implement pagination for cases that extend beyond limit (1000 instances)
 	input := ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice(instanceIDs)}
 	output, err := ec2Client.DescribeInstances(&input)
@@ -147,7 +165,17 @@ func GetPublicIpsOfEc2Instances(t testing.TestingT, instanceIDs []string, awsReg
 
 // GetPublicIpsOfEc2InstancesE gets the public IP address of the given EC2 Instance in the given region. Returns a map of instance ID to IP address.
 func GetPublicIpsOfEc2InstancesE(t testing.TestingT, instanceIDs []string, awsRegion string) (map[string]string, error) {
-	ec2Client := NewEc2Client(t, awsRegion)
+	ec2Client, err := NewEc2ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetPublicIpsOfEc2InstancesWithClientE(t, ec2Client, instanceIDs)
+}
+
+// GetPublicIpsOfEc2InstancesWithClientE gets the public IP address of the given EC2 Instances, with the ability to provide the EC2
+// client. Returns a map of instance ID to IP address.
+func GetPublicIpsOfEc2InstancesWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, instanceIDs []string) (map[string]string, error) {
 	// TODO: implement pagination for cases that extend beyond limit (1000 instances)
 	input := ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice(instanceIDs)}
 	output, err := ec2Client.DescribeInstances(&input)
@@ -166,6 +194,92 @@ func GetPublicIpsOfEc2InstancesE(t testing.TestingT, instanceIDs []string, awsRe
 	return ips, nil
 }
 
+// GetEc2InstanceState gets the state (e.g. pending, running, or terminated) of the given EC2 Instance in the given region.
+func GetEc2InstanceState(t testing.TestingT, instanceID string, awsRegion string) string {
+	state, err := GetEc2InstanceStateE(t, instanceID, awsRegion)
+	require.NoError(t, err)
+	return state
+}
+
+// GetEc2InstanceStateE gets the state (e.g. pending, running, or terminated) of the given EC2 Instance in the given region.
+func GetEc2InstanceStateE(t testing.TestingT, instanceID string, awsRegion string) (string, error) {
+	ec2Client, err := NewEc2ClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	return GetEc2InstanceStateWithClientE(t, ec2Client, instanceID)
+}
+
+// GetEc2InstanceStateWithClientE gets the state (e.g. pending, running, or terminated) of the given EC2 Instance, with the ability to
+// provide the EC2 client.
+func GetEc2InstanceStateWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, instanceID string) (string, error) {
+	instance, err := getEc2InstanceWithClientE(ec2Client, instanceID)
+	if err != nil {
+		return "", err
+	}
+	if instance.State == nil {
+		return "", nil
+	}
+
+	return aws.StringValue(instance.State.Name), nil
+}
+
+// GetEc2InstanceType gets the instance type (e.g. t3.micro) of the given EC2 Instance in the given region.
+func GetEc2InstanceType(t testing.TestingT, instanceID string, awsRegion string) string {
+	instanceType, err := GetEc2InstanceTypeE(t, instanceID, awsRegion)
+	require.NoError(t, err)
+	return instanceType
+}
+
+// GetEc2InstanceTypeE gets the instance type (e.g. t3.micro) of the given EC2 Instance in the given region.
+func GetEc2InstanceTypeE(t testing.TestingT, instanceID string, awsRegion string) (string, error) {
+	ec2Client, err := NewEc2ClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	return GetEc2InstanceTypeWithClientE(t, ec2Client, instanceID)
+}
+
+// GetEc2InstanceTypeWithClientE gets the instance type (e.g. t3.micro) of the given EC2 Instance, with the ability to provide the EC2
+// client.
+func GetEc2InstanceTypeWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, instanceID string) (string, error) {
+	instance, err := getEc2InstanceWithClientE(ec2Client, instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(instance.InstanceType), nil
+}
+
+// getEc2InstanceWithClientE describes the given EC2 Instance, returning a NotFoundError if it does not exist.
+func getEc2InstanceWithClientE(ec2Client ec2iface.EC2API, instanceID string) (*ec2.Instance, error) {
+	input := ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{instanceID})}
+	output, err := ec2Client.DescribeInstances(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if aws.StringValue(instance.InstanceId) == instanceID {
+				return instance, nil
+			}
+		}
+	}
+
+	return nil, NewNotFoundError("EC2 Instance", instanceID, getEc2ClientRegion(ec2Client))
+}
+
+// getEc2ClientRegion returns the region of the given EC2 client, or an empty string if it isn't an SDK client.
+func getEc2ClientRegion(ec2Client ec2iface.EC2API) string {
+	if sdkClient, isSdkClient := ec2Client.(*ec2.EC2); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
 // GetEc2InstanceIdsByTag returns all the IDs of EC2 instances in the given region with the given tag.
 func GetEc2InstanceIdsByTag(t testing.TestingT, region string, tagName string, tagValue string) []string {
 	out, err := GetEc2InstanceIdsByTagE(t, region, tagName, tagValue)
@@ -197,6 +311,12 @@ func GetEc2InstanceIdsByFiltersE(t testing.TestingT, region string, ec2Filters m
 		return nil, err
 	}
 
+	return GetEc2InstanceIdsByFiltersWithClientE(t, client, ec2Filters)
+}
+
+// GetEc2InstanceIdsByFiltersWithClientE returns all the IDs of EC2 instances which match to EC2 filter list, with the ability to provide
+// the EC2 client.
+func GetEc2InstanceIdsByFiltersWithClientE(t testing.TestingT, client ec2iface.EC2API, ec2Filters map[string][]string) ([]string, error) {
 	ec2FilterList := []*ec2.Filter{}
 
 	for name, values := range ec2Filters {
@@ -234,6 +354,11 @@ func GetTagsForEc2InstanceE(t testing.TestingT, region string, instanceID string
 		return nil, err
 	}
 
+	return GetTagsForEc2InstanceWithClientE(t, client, instanceID)
+}
+
+// GetTagsForEc2InstanceWithClientE returns all the tags for the given EC2 Instance, with the ability to provide the EC2 client.
+func GetTagsForEc2InstanceWithClientE(t testing.TestingT, client ec2iface.EC2API, instanceID string) (map[string]string, error) {
 	input := ec2.DescribeTagsInput{
 		Filters: []*ec2.Filter{
 			{
@@ -268,14 +393,19 @@ func DeleteAmi(t testing.TestingT, region string, imageID string) {
 
 // DeleteAmiE deletes the given AMI in the given region.
 func DeleteAmiE(t testing.TestingT, region string, imageID string) error {
-	logger.Logf(t, "Deregistering AMI %s", imageID)
-
 	client, err := NewEc2ClientE(t, region)
 	if err != nil {
 		return err
 	}
 
-	_, err = client.DeregisterImage(&ec2.DeregisterImageInput{ImageId: aws.String(imageID)})
+	return DeleteAmiWithClientE(t, client, imageID)
+}
+
+// DeleteAmiWithClientE deletes the given AMI, with the ability to provide the EC2 client.
+func DeleteAmiWithClientE(t testing.TestingT, client ec2iface.EC2API, imageID string) error {
+	logger.Logf(t, "Deregistering AMI %s", imageID)
+
+	_, err := client.DeregisterImage(&ec2.DeregisterImageInput{ImageId: aws.String(imageID)})
 	return err
 }
 
@@ -291,6 +421,12 @@ func AddTagsToResourceE(t testing.TestingT, region string, resource string, tags
 		return err
 	}
 
+	return AddTagsToResourceWithClientE(t, client, resource, tags)
+}
+
+// AddTagsToResourceWithClientE adds the tags to the given taggable AWS resource such as EC2, AMI or VPC, with the ability to provide the
+// EC2 client.
+func AddTagsToResourceWithClientE(t testing.TestingT, client ec2iface.EC2API, resource string, tags map[string]string) error {
 	var awsTags []*ec2.Tag
 	for key, value := range tags {
 		awsTags = append(awsTags, &ec2.Tag{
@@ -299,7 +435,7 @@ func AddTagsToResourceE(t testing.TestingT, region string, resource string, tags
 		})
 	}
 
-	_, err = client.CreateTags(&ec2.CreateTagsInput{
+	_, err := client.CreateTags(&ec2.CreateTagsInput{
 		Resources: []*string{aws.String(resource)},
 		Tags:      awsTags,
 	})
@@ -314,14 +450,47 @@ func TerminateInstance(t testing.TestingT, region string, instanceID string) {
 
 // TerminateInstanceE terminates the EC2 instance with the given ID in the given region.
 func TerminateInstanceE(t testing.TestingT, region string, instanceID string) error {
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	return TerminateInstanceWithClientE(t, client, instanceID)
+}
+
+// TerminateInstanceWithClientE terminates the EC2 instance with the given ID, with the ability to provide the EC2 client.
+func TerminateInstanceWithClientE(t testing.TestingT, client ec2iface.EC2API, instanceID string) error {
 	logger.Logf(t, "Terminating Instance %s", instanceID)
 
+	_, err := client.TerminateInstances(&ec2.TerminateInstancesInput{
+		InstanceIds: []*string{
+			aws.String(instanceID),
+		},
+	})
+
+	return err
+}
+
+// StopInstance stops the EC2 instance with the given ID in the given region.
+func StopInstance(t testing.TestingT, region string, instanceID string) {
+	require.NoError(t, StopInstanceE(t, region, instanceID))
+}
+
+// StopInstanceE stops the EC2 instance with the given ID in the given region.
+func StopInstanceE(t testing.TestingT, region string, instanceID string) error {
 	client, err := NewEc2ClientE(t, region)
 	if err != nil {
 		return err
 	}
 
-	_, err = client.TerminateInstances(&ec2.TerminateInstancesInput{
+	return StopInstanceWithClientE(t, client, instanceID)
+}
+
+// StopInstanceWithClientE stops the EC2 instance with the given ID, with the ability to provide the EC2 client.
+func StopInstanceWithClientE(t testing.TestingT, client ec2iface.EC2API, instanceID string) error {
+	logger.Logf(t, "Stopping Instance %s", instanceID)
+
+	_, err := client.StopInstances(&ec2.StopInstancesInput{
 		InstanceIds: []*string{
 			aws.String(instanceID),
 		},
@@ -422,7 +591,7 @@ func GetRecommendedInstanceTypeE(t testing.TestingT, region string, instanceType
 // AZs. If you have code that needs to run on a "small" instance across all AZs in many different regions, you can
 // use this function to automatically figure out which instance type you should use.
 // This function expects an authenticated EC2 client from the AWS SDK Go library.
-func GetRecommendedInstanceTypeWithClientE(t testing.TestingT, ec2Client *ec2.EC2, instanceTypeOptions []string) (string, error) {
+func GetRecommendedInstanceTypeWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, instanceTypeOptions []string) (string, error) {
 	availabilityZones, err := getAllAvailabilityZonesE(ec2Client)
 	if err != nil {
 		return "", err
@@ -480,7 +649,7 @@ func hasOffering(instanceTypeOfferings []*ec2.InstanceTypeOffering, availability
 
 // getInstanceTypeOfferingsE returns the instance types from the given list that are available in the region configured
 // in the given EC2 client
-func getInstanceTypeOfferingsE(client *ec2.EC2, instanceTypeOptions []string) ([]*ec2.InstanceTypeOffering, error) {
+func getInstanceTypeOfferingsE(client ec2iface.EC2API, instanceTypeOptions []string) ([]*ec2.InstanceTypeOffering, error) {
 	input := ec2.DescribeInstanceTypeOfferingsInput{
 		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
 		Filters: []*ec2.Filter{
@@ -500,7 +669,7 @@ func getInstanceTypeOfferingsE(client *ec2.EC2, instanceTypeOptions []string) ([
 }
 
 // getAllAvailabilityZonesE returns all the available AZs in the region configured in the given EC2 client
-func getAllAvailabilityZonesE(client *ec2.EC2) ([]string, error) {
+func getAllAvailabilityZonesE(client ec2iface.EC2API) ([]string, error) {
 	input := ec2.DescribeAvailabilityZonesInput{
 		Filters: []*ec2.Filter{
 			{
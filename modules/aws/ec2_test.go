@@ -7,12 +7,72 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// fakeEc2 returns a single running EC2 Instance and records the instances it was asked to terminate.
+type fakeEc2 struct {
+	ec2iface.EC2API
+	terminated []string
+}
+
+func (client *fakeEc2) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	instance := &ec2.Instance{
+		InstanceId:       aws.String("i-0123456789"),
+		PrivateIpAddress: aws.String("10.0.0.1"),
+		InstanceType:     aws.String("t3.micro"),
+		State:            &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+		Tags:             []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("terratest")}},
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{instance}}}}, nil
+}
+
+func (client *fakeEc2) DescribeTags(input *ec2.DescribeTagsInput) (*ec2.DescribeTagsOutput, error) {
+	tag := &ec2.TagDescription{Key: aws.String("Name"), Value: aws.String("terratest")}
+	return &ec2.DescribeTagsOutput{Tags: []*ec2.TagDescription{tag}}, nil
+}
+
+func (client *fakeEc2) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	client.terminated = append(client.terminated, aws.StringValueSlice(input.InstanceIds)...)
+	return &ec2.TerminateInstancesOutput{}, nil
+}
+
+func TestEc2HelpersWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeEc2{}
+
+	ips, err := GetPrivateIpsOfEc2InstancesWithClientE(t, client, []string{"i-0123456789"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"i-0123456789": "10.0.0.1"}, ips)
+
+	ids, err := GetEc2InstanceIdsByFiltersWithClientE(t, client, map[string][]string{"tag:Name": {"terratest"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-0123456789"}, ids)
+
+	tags, err := GetTagsForEc2InstanceWithClientE(t, client, "i-0123456789")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Name": "terratest"}, tags)
+
+	state, err := GetEc2InstanceStateWithClientE(t, client, "i-0123456789")
+	require.NoError(t, err)
+	assert.Equal(t, ec2.InstanceStateNameRunning, state)
+
+	instanceType, err := GetEc2InstanceTypeWithClientE(t, client, "i-0123456789")
+	require.NoError(t, err)
+	assert.Equal(t, "t3.micro", instanceType)
+
+	_, err = GetEc2InstanceTypeWithClientE(t, client, "i-missing")
+	assert.Equal(t, NewNotFoundError("EC2 Instance", "i-missing", ""), err)
+
+	require.NoError(t, TerminateInstanceWithClientE(t, client, "i-0123456789"))
+	assert.Equal(t, []string{"i-0123456789"}, client.terminated)
+}
+
 func TestGetEc2InstanceIdsByTag(t *testing.T) {
 	t.Parallel()
 
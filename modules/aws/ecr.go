@@ -10,17 +10,21 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// CreateECRRepo creates a new ECR Repository. This will fail the test and stop execution if there is an error.
+// CreateECRRepo creates a new ECR Repository tagged with the tags of GetTestTags. This will fail the test and stop execution if there is an error.
 func CreateECRRepo(t testing.TestingT, region string, name string) *ecr.Repository {
 	repo, err := CreateECRRepoE(t, region, name)
 	require.NoError(t, err)
 	return repo
 }
 
-// CreateECRRepoE creates a new ECR Repository.
+// CreateECRRepoE creates a new ECR Repository tagged with the tags of GetTestTags.
 func CreateECRRepoE(t testing.TestingT, region string, name string) (*ecr.Repository, error) {
 	client := NewECRClient(t, region)
-	resp, err := client.CreateRepository(&ecr.CreateRepositoryInput{RepositoryName: aws.String(name)})
+	input := &ecr.CreateRepositoryInput{RepositoryName: aws.String(name)}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		input.Tags = append(input.Tags, &ecr.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	resp, err := client.CreateRepository(input)
 	if err != nil {
 		return nil, err
 	}
@@ -2,9 +2,13 @@ package aws
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -66,19 +70,23 @@ func GetDefaultEcsCluster(t testing.TestingT, region string) *ecs.Cluster {
 	return GetEcsCluster(t, region, "default")
 }
 
-// CreateEcsCluster creates ECS cluster in the given region under the given name.
+// CreateEcsCluster creates ECS cluster in the given region under the given name, tagged with the tags of GetTestTags.
 func CreateEcsCluster(t testing.TestingT, region string, name string) *ecs.Cluster {
 	cluster, err := CreateEcsClusterE(t, region, name)
 	require.NoError(t, err)
 	return cluster
 }
 
-// CreateEcsClusterE creates ECS cluster in the given region under the given name.
+// CreateEcsClusterE creates ECS cluster in the given region under the given name, tagged with the tags of GetTestTags.
 func CreateEcsClusterE(t testing.TestingT, region string, name string) (*ecs.Cluster, error) {
 	client := NewEcsClient(t, region)
-	cluster, err := client.CreateCluster(&ecs.CreateClusterInput{
+	input := &ecs.CreateClusterInput{
 		ClusterName: aws.String(name),
-	})
+	}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		input.Tags = append(input.Tags, &ecs.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	cluster, err := client.CreateCluster(input)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +116,16 @@ func GetEcsService(t testing.TestingT, region string, clusterName string, servic
 
 // GetEcsServiceE fetches information about specified ECS service.
 func GetEcsServiceE(t testing.TestingT, region string, clusterName string, serviceName string) (*ecs.Service, error) {
-	output, err := NewEcsClient(t, region).DescribeServices(&ecs.DescribeServicesInput{
+	client, err := NewEcsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return GetEcsServiceWithClientE(t, client, clusterName, serviceName)
+}
+
+// GetEcsServiceWithClientE fetches information about specified ECS service, with the ability to provide the ECS client.
+func GetEcsServiceWithClientE(t testing.TestingT, client ecsiface.ECSAPI, clusterName string, serviceName string) (*ecs.Service, error) {
+	output, err := client.DescribeServices(&ecs.DescribeServicesInput{
 		Cluster: aws.String(clusterName),
 		Services: []*string{
 			aws.String(serviceName),
@@ -122,11 +139,152 @@ func GetEcsServiceE(t testing.TestingT, region string, clusterName string, servi
 	if numServices != 1 {
 		return nil, fmt.Errorf(
 			"Expected to find 1 ECS service named '%s' in cluster '%s' in region '%v', but found '%d'",
-			serviceName, clusterName, region, numServices)
+			serviceName, clusterName, getEcsClientRegion(client), numServices)
 	}
 	return output.Services[0], nil
 }
 
+// GetEcsRunningTaskCount returns the number of tasks of the specified ECS service that are in the RUNNING state.
+func GetEcsRunningTaskCount(t testing.TestingT, region string, clusterName string, serviceName string) int64 {
+	count, err := GetEcsRunningTaskCountE(t, region, clusterName, serviceName)
+	require.NoError(t, err)
+	return count
+}
+
+// GetEcsRunningTaskCountE returns the number of tasks of the specified ECS service that are in the RUNNING state.
+func GetEcsRunningTaskCountE(t testing.TestingT, region string, clusterName string, serviceName string) (int64, error) {
+	service, err := GetEcsServiceE(t, region, clusterName, serviceName)
+	if err != nil {
+		return 0, err
+	}
+	return aws.Int64Value(service.RunningCount), nil
+}
+
+// WaitForEcsServiceStable waits until the specified ECS service is stable: it has a single deployment, and as many
+// running tasks as desired and no pending ones. It retries up to maxRetries times with sleepBetweenRetries in between,
+// and returns the service.
+func WaitForEcsServiceStable(t testing.TestingT, region string, clusterName string, serviceName string, maxRetries int, sleepBetweenRetries time.Duration) *ecs.Service {
+	service, err := WaitForEcsServiceStableE(t, region, clusterName, serviceName, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return service
+}
+
+// WaitForEcsServiceStableE waits until the specified ECS service is stable: it has a single deployment, and as many
+// running tasks as desired and no pending ones. It retries up to maxRetries times with sleepBetweenRetries in between,
+// and returns the service.
+func WaitForEcsServiceStableE(t testing.TestingT, region string, clusterName string, serviceName string, maxRetries int, sleepBetweenRetries time.Duration) (*ecs.Service, error) {
+	client, err := NewEcsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return WaitForEcsServiceStableWithClientE(t, client, clusterName, serviceName, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForEcsServiceStableWithClientE waits until the specified ECS service is stable, with the ability to provide the
+// ECS client, and returns the service.
+func WaitForEcsServiceStableWithClientE(t testing.TestingT, client ecsiface.ECSAPI, clusterName string, serviceName string, maxRetries int, sleepBetweenRetries time.Duration) (*ecs.Service, error) {
+	service, err := retry.DoWithRetryInterfaceE(
+		t,
+		fmt.Sprintf("Waiting for ECS service %s in cluster %s to be stable.", serviceName, clusterName),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (interface{}, error) {
+			service, err := GetEcsServiceWithClientE(t, client, clusterName, serviceName)
+			if err != nil {
+				return nil, err
+			}
+			if !isEcsServiceStable(service) {
+				return nil, EcsServiceNotStableError{
+					Name:        serviceName,
+					Deployments: len(service.Deployments),
+					Desired:     aws.Int64Value(service.DesiredCount),
+					Running:     aws.Int64Value(service.RunningCount),
+					Pending:     aws.Int64Value(service.PendingCount),
+				}
+			}
+			return service, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logf(t, "ECS service %s in cluster %s is stable", serviceName, clusterName)
+	return service.(*ecs.Service), nil
+}
+
+// isEcsServiceStable returns true if the given ECS service has finished deploying: it has a single deployment, and as
+// many running tasks as desired and no pending ones.
+func isEcsServiceStable(service *ecs.Service) bool {
+	return len(service.Deployments) == 1 &&
+		aws.Int64Value(service.RunningCount) == aws.Int64Value(service.DesiredCount) &&
+		aws.Int64Value(service.PendingCount) == 0
+}
+
+// GetEcsRunningTaskIps returns the private IP addresses of the running tasks of the specified ECS service. Only tasks
+// that use the awsvpc network mode have their own IP address.
+func GetEcsRunningTaskIps(t testing.TestingT, region string, clusterName string, serviceName string) []string {
+	ips, err := GetEcsRunningTaskIpsE(t, region, clusterName, serviceName)
+	require.NoError(t, err)
+	return ips
+}
+
+// GetEcsRunningTaskIpsE returns the private IP addresses of the running tasks of the specified ECS service. Only tasks
+// that use the awsvpc network mode have their own IP address.
+func GetEcsRunningTaskIpsE(t testing.TestingT, region string, clusterName string, serviceName string) ([]string, error) {
+	client, err := NewEcsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return GetEcsRunningTaskIpsWithClientE(t, client, clusterName, serviceName)
+}
+
+// GetEcsRunningTaskIpsWithClientE returns the private IP addresses of the running tasks of the specified ECS service,
+// with the ability to provide the ECS client.
+func GetEcsRunningTaskIpsWithClientE(t testing.TestingT, client ecsiface.ECSAPI, clusterName string, serviceName string) ([]string, error) {
+	var taskArns []*string
+	err := client.ListTasksPages(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterName),
+		ServiceName:   aws.String(serviceName),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	}, func(page *ecs.ListTasksOutput, lastPage bool) bool {
+		taskArns = append(taskArns, page.TaskArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []string{}
+	// DescribeTasks accepts at most 100 tasks per call
+	for start := 0; start < len(taskArns); start += 100 {
+		end := start + 100
+		if end > len(taskArns) {
+			end = len(taskArns)
+		}
+		output, err := client.DescribeTasks(&ecs.DescribeTasksInput{
+			Cluster: aws.String(clusterName),
+			Tasks:   taskArns[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range output.Tasks {
+			if aws.StringValue(task.LastStatus) != ecs.DesiredStatusRunning {
+				continue
+			}
+			for _, attachment := range task.Attachments {
+				for _, detail := range attachment.Details {
+					if aws.StringValue(detail.Name) == "privateIPv4Address" {
+						ips = append(ips, aws.StringValue(detail.Value))
+					}
+				}
+			}
+		}
+	}
+	return ips, nil
+}
+
 // GetEcsTaskDefinition fetches information about specified ECS task definition.
 func GetEcsTaskDefinition(t testing.TestingT, region string, taskDefinition string) *ecs.TaskDefinition {
 	task, err := GetEcsTaskDefinitionE(t, region, taskDefinition)
@@ -136,7 +294,18 @@ func GetEcsTaskDefinition(t testing.TestingT, region string, taskDefinition stri
 
 // GetEcsTaskDefinitionE fetches information about specified ECS task definition.
 func GetEcsTaskDefinitionE(t testing.TestingT, region string, taskDefinition string) (*ecs.TaskDefinition, error) {
-	output, err := NewEcsClient(t, region).DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+	client, err := NewEcsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return GetEcsTaskDefinitionWithClientE(t, client, taskDefinition)
+}
+
+// GetEcsTaskDefinitionWithClientE fetches information about specified ECS task definition, with the ability to provide
+// the ECS client. The task definition can be given as its family, family:revision, or full ARN, e.g. the
+// TaskDefinition of an ECS service.
+func GetEcsTaskDefinitionWithClientE(t testing.TestingT, client ecsiface.ECSAPI, taskDefinition string) (*ecs.TaskDefinition, error) {
+	output, err := client.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
 		TaskDefinition: aws.String(taskDefinition),
 	})
 	if err != nil {
@@ -145,6 +314,14 @@ func GetEcsTaskDefinitionE(t testing.TestingT, region string, taskDefinition str
 	return output.TaskDefinition, nil
 }
 
+// getEcsClientRegion returns the region of the given ECS client, or an empty string if it isn't an SDK client.
+func getEcsClientRegion(client ecsiface.ECSAPI) string {
+	if sdkClient, isSdkClient := client.(*ecs.ECS); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
 // NewEcsClient creates en ECS client.
 func NewEcsClient(t testing.TestingT, region string) *ecs.ECS {
 	client, err := NewEcsClientE(t, region)
@@ -2,13 +2,62 @@ package aws
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// fakeEcsService returns the given services in order on each DescribeServices call, repeating the last one, and the
+// given tasks for any cluster and service.
+type fakeEcsService struct {
+	ecsiface.ECSAPI
+	services []*ecs.Service
+	tasks    []*ecs.Task
+	calls    int
+}
+
+func (client *fakeEcsService) DescribeServices(input *ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error) {
+	service := client.services[len(client.services)-1]
+	if client.calls < len(client.services) {
+		service = client.services[client.calls]
+	}
+	client.calls++
+	return &ecs.DescribeServicesOutput{Services: []*ecs.Service{service}}, nil
+}
+
+func (client *fakeEcsService) ListTasksPages(input *ecs.ListTasksInput, fn func(*ecs.ListTasksOutput, bool) bool) error {
+	var taskArns []*string
+	for _, task := range client.tasks {
+		taskArns = append(taskArns, task.TaskArn)
+	}
+	fn(&ecs.ListTasksOutput{TaskArns: taskArns}, true)
+	return nil
+}
+
+func (client *fakeEcsService) DescribeTasks(input *ecs.DescribeTasksInput) (*ecs.DescribeTasksOutput, error) {
+	return &ecs.DescribeTasksOutput{Tasks: client.tasks}, nil
+}
+
+func fakeEcsTask(arn string, lastStatus string, ip string) *ecs.Task {
+	return &ecs.Task{
+		TaskArn:    aws.String(arn),
+		LastStatus: aws.String(lastStatus),
+		Attachments: []*ecs.Attachment{{
+			Type: aws.String("ElasticNetworkInterface"),
+			Details: []*ecs.KeyValuePair{
+				{Name: aws.String("subnetId"), Value: aws.String("subnet-123")},
+				{Name: aws.String("privateIPv4Address"), Value: aws.String(ip)},
+			},
+		}},
+	}
+}
+
 func TestEcsCluster(t *testing.T) {
 	t.Parallel()
 
@@ -60,3 +109,45 @@ func TestEcsClusterWithInclude(t *testing.T) {
 	assert.NotEmpty(t, c3.Statistics)
 	assert.Empty(t, c3.Tags)
 }
+
+func TestWaitForEcsServiceStableWithClient(t *testing.T) {
+	t.Parallel()
+
+	deployment := &ecs.Deployment{Id: aws.String("ecs-svc/1")}
+	client := &fakeEcsService{services: []*ecs.Service{
+		{ServiceName: aws.String("web"), Deployments: []*ecs.Deployment{deployment, deployment}, DesiredCount: aws.Int64(2), RunningCount: aws.Int64(2)},
+		{ServiceName: aws.String("web"), Deployments: []*ecs.Deployment{deployment}, DesiredCount: aws.Int64(2), RunningCount: aws.Int64(1), PendingCount: aws.Int64(1)},
+		{ServiceName: aws.String("web"), Deployments: []*ecs.Deployment{deployment}, DesiredCount: aws.Int64(2), RunningCount: aws.Int64(2), PendingCount: aws.Int64(0)},
+	}}
+
+	service, err := WaitForEcsServiceStableWithClientE(t, client, "cluster", "web", 5, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), aws.Int64Value(service.RunningCount))
+	assert.Equal(t, 3, client.calls)
+}
+
+func TestWaitForEcsServiceStableWithClientTimesOut(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeEcsService{services: []*ecs.Service{
+		{ServiceName: aws.String("web"), Deployments: []*ecs.Deployment{{}}, DesiredCount: aws.Int64(2), RunningCount: aws.Int64(0), PendingCount: aws.Int64(2)},
+	}}
+
+	_, err := WaitForEcsServiceStableWithClientE(t, client, "cluster", "web", 2, time.Millisecond)
+	assert.IsType(t, retry.MaxRetriesExceeded{}, err)
+	assert.Equal(t, 3, client.calls)
+}
+
+func TestGetEcsRunningTaskIpsWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeEcsService{tasks: []*ecs.Task{
+		fakeEcsTask("arn:aws:ecs:us-east-1:123456789012:task/cluster/1", ecs.DesiredStatusRunning, "10.0.1.10"),
+		fakeEcsTask("arn:aws:ecs:us-east-1:123456789012:task/cluster/2", ecs.DesiredStatusPending, "10.0.1.11"),
+		fakeEcsTask("arn:aws:ecs:us-east-1:123456789012:task/cluster/3", ecs.DesiredStatusRunning, "10.0.2.10"),
+	}}
+
+	ips, err := GetEcsRunningTaskIpsWithClientE(t, client, "cluster", "web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.1.10", "10.0.2.10"}, ips)
+}
@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// GetEksCluster fetches information about the EKS cluster with the given name, such as its endpoint and certificate
+// authority data. This will fail the test if there is an error.
+func GetEksCluster(t testing.TestingT, region string, clusterName string) *eks.Cluster {
+	cluster, err := GetEksClusterE(t, region, clusterName)
+	require.NoError(t, err)
+	return cluster
+}
+
+// GetEksClusterE fetches information about the EKS cluster with the given name, such as its endpoint and certificate
+// authority data.
+func GetEksClusterE(t testing.TestingT, region string, clusterName string) (*eks.Cluster, error) {
+	client, err := NewEksClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return GetEksClusterWithClientE(t, client, clusterName)
+}
+
+// GetEksClusterWithClientE fetches information about the EKS cluster with the given name, with the ability to provide
+// the EKS client.
+func GetEksClusterWithClientE(t testing.TestingT, client eksiface.EKSAPI, clusterName string) (*eks.Cluster, error) {
+	output, err := client.DescribeCluster(&eks.DescribeClusterInput{Name: aws.String(clusterName)})
+	if err != nil {
+		return nil, err
+	}
+	return output.Cluster, nil
+}
+
+// NewEksClient creates an EKS client.
+func NewEksClient(t testing.TestingT, region string) *eks.EKS {
+	client, err := NewEksClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewEksClientE creates an EKS client.
+func NewEksClientE(t testing.TestingT, region string) (*eks.EKS, error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+	return eks.New(sess), nil
+}
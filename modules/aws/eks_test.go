@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEks returns the given cluster for any name.
+type fakeEks struct {
+	eksiface.EKSAPI
+	cluster *eks.Cluster
+}
+
+func (client *fakeEks) DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+	return &eks.DescribeClusterOutput{Cluster: client.cluster}, nil
+}
+
+func TestGetEksClusterWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeEks{cluster: &eks.Cluster{
+		Name:                 aws.String("terratest"),
+		Endpoint:             aws.String("https://ABC123.gr7.us-east-1.eks.amazonaws.com"),
+		CertificateAuthority: &eks.Certificate{Data: aws.String("Y2VydA==")},
+	}}
+
+	cluster, err := GetEksClusterWithClientE(t, client, "terratest")
+	require.NoError(t, err)
+	assert.Equal(t, "https://ABC123.gr7.us-east-1.eks.amazonaws.com", aws.StringValue(cluster.Endpoint))
+}
@@ -0,0 +1,288 @@
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// GetTargetHealthForTargetGroup returns the health state (e.g. healthy, unhealthy, draining) of each target in the
+// given ALB/NLB target group, keyed by target ID.
+func GetTargetHealthForTargetGroup(t testing.TestingT, awsRegion string, targetGroupArn string) map[string]string {
+	targetHealth, err := GetTargetHealthForTargetGroupE(t, awsRegion, targetGroupArn)
+	require.NoError(t, err)
+	return targetHealth
+}
+
+// GetTargetHealthForTargetGroupE returns the health state (e.g. healthy, unhealthy, draining) of each target in the
+// given ALB/NLB target group, keyed by target ID.
+func GetTargetHealthForTargetGroupE(t testing.TestingT, awsRegion string, targetGroupArn string) (map[string]string, error) {
+	client, err := NewElbV2ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetTargetHealthForTargetGroupWithClientE(t, client, targetGroupArn)
+}
+
+// GetTargetHealthForTargetGroupWithClientE returns the health state (e.g. healthy, unhealthy, draining) of each target
+// in the given ALB/NLB target group, keyed by target ID, with the ability to provide the ELBv2 client.
+func GetTargetHealthForTargetGroupWithClientE(t testing.TestingT, client elbv2iface.ELBV2API, targetGroupArn string) (map[string]string, error) {
+	output, err := client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(targetGroupArn)})
+	if err != nil {
+		return nil, err
+	}
+
+	targetHealth := map[string]string{}
+	for _, description := range output.TargetHealthDescriptions {
+		targetHealth[aws.StringValue(description.Target.Id)] = aws.StringValue(description.TargetHealth.State)
+	}
+	return targetHealth, nil
+}
+
+// WaitForTargetGroupHealthy waits until the given ALB/NLB target group has at least one registered target, and all of
+// its targets are healthy, retrying up to maxRetries times with sleepBetweenRetries in between. It returns the IDs of
+// the healthy targets.
+func WaitForTargetGroupHealthy(t testing.TestingT, awsRegion string, targetGroupArn string, maxRetries int, sleepBetweenRetries time.Duration) []string {
+	targetIds, err := WaitForTargetGroupHealthyE(t, awsRegion, targetGroupArn, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return targetIds
+}
+
+// WaitForTargetGroupHealthyE waits until the given ALB/NLB target group has at least one registered target, and all
+// of its targets are healthy, retrying up to maxRetries times with sleepBetweenRetries in between. It returns the IDs
+// of the healthy targets.
+func WaitForTargetGroupHealthyE(t testing.TestingT, awsRegion string, targetGroupArn string, maxRetries int, sleepBetweenRetries time.Duration) ([]string, error) {
+	client, err := NewElbV2ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return WaitForTargetGroupHealthyWithClientE(t, client, targetGroupArn, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForTargetGroupHealthyWithClientE waits until the given ALB/NLB target group has at least one registered target,
+// and all of its targets are healthy, with the ability to provide the ELBv2 client. It returns the IDs of the healthy
+// targets.
+func WaitForTargetGroupHealthyWithClientE(t testing.TestingT, client elbv2iface.ELBV2API, targetGroupArn string, maxRetries int, sleepBetweenRetries time.Duration) ([]string, error) {
+	return waitForHealthyTargets(t, targetGroupArn, elbv2.TargetHealthStateEnumHealthy, maxRetries, sleepBetweenRetries, func() (map[string]string, error) {
+		return GetTargetHealthForTargetGroupWithClientE(t, client, targetGroupArn)
+	})
+}
+
+// GetLoadBalancerV2 returns the ALB/NLB with the given name.
+func GetLoadBalancerV2(t testing.TestingT, awsRegion string, name string) *elbv2.LoadBalancer {
+	loadBalancer, err := GetLoadBalancerV2E(t, awsRegion, name)
+	require.NoError(t, err)
+	return loadBalancer
+}
+
+// GetLoadBalancerV2E returns the ALB/NLB with the given name.
+func GetLoadBalancerV2E(t testing.TestingT, awsRegion string, name string) (*elbv2.LoadBalancer, error) {
+	client, err := NewElbV2ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetLoadBalancerV2WithClientE(t, client, name)
+}
+
+// GetLoadBalancerV2WithClientE returns the ALB/NLB with the given name, with the ability to provide the ELBv2 client.
+func GetLoadBalancerV2WithClientE(t testing.TestingT, client elbv2iface.ELBV2API, name string) (*elbv2.LoadBalancer, error) {
+	output, err := client.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{name})})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.LoadBalancers) == 0 {
+		return nil, NewNotFoundError("Load Balancer", name, getElbV2ClientRegion(client))
+	}
+	return output.LoadBalancers[0], nil
+}
+
+// GetLoadBalancerV2DnsName returns the DNS name of the ALB/NLB with the given name.
+func GetLoadBalancerV2DnsName(t testing.TestingT, awsRegion string, name string) string {
+	dnsName, err := GetLoadBalancerV2DnsNameE(t, awsRegion, name)
+	require.NoError(t, err)
+	return dnsName
+}
+
+// GetLoadBalancerV2DnsNameE returns the DNS name of the ALB/NLB with the given name.
+func GetLoadBalancerV2DnsNameE(t testing.TestingT, awsRegion string, name string) (string, error) {
+	loadBalancer, err := GetLoadBalancerV2E(t, awsRegion, name)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(loadBalancer.DNSName), nil
+}
+
+// GetElbDnsName returns the DNS name of the Classic Load Balancer with the given name.
+func GetElbDnsName(t testing.TestingT, awsRegion string, name string) string {
+	dnsName, err := GetElbDnsNameE(t, awsRegion, name)
+	require.NoError(t, err)
+	return dnsName
+}
+
+// GetElbDnsNameE returns the DNS name of the Classic Load Balancer with the given name.
+func GetElbDnsNameE(t testing.TestingT, awsRegion string, name string) (string, error) {
+	client, err := NewElbClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	return GetElbDnsNameWithClientE(t, client, name)
+}
+
+// GetElbDnsNameWithClientE returns the DNS name of the Classic Load Balancer with the given name, with the ability to
+// provide the ELB client.
+func GetElbDnsNameWithClientE(t testing.TestingT, client elbiface.ELBAPI, name string) (string, error) {
+	output, err := client.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{LoadBalancerNames: aws.StringSlice([]string{name})})
+	if err != nil {
+		return "", err
+	}
+	if len(output.LoadBalancerDescriptions) == 0 {
+		return "", NewNotFoundError("Load Balancer", name, getElbClientRegion(client))
+	}
+	return aws.StringValue(output.LoadBalancerDescriptions[0].DNSName), nil
+}
+
+// WaitForElbInstancesInService waits until the given Classic Load Balancer has at least one registered Instance, and
+// all of its Instances are InService, retrying up to maxRetries times with sleepBetweenRetries in between. It returns
+// the IDs of the Instances.
+func WaitForElbInstancesInService(t testing.TestingT, awsRegion string, name string, maxRetries int, sleepBetweenRetries time.Duration) []string {
+	instanceIds, err := WaitForElbInstancesInServiceE(t, awsRegion, name, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return instanceIds
+}
+
+// WaitForElbInstancesInServiceE waits until the given Classic Load Balancer has at least one registered Instance, and
+// all of its Instances are InService, retrying up to maxRetries times with sleepBetweenRetries in between. It returns
+// the IDs of the Instances.
+func WaitForElbInstancesInServiceE(t testing.TestingT, awsRegion string, name string, maxRetries int, sleepBetweenRetries time.Duration) ([]string, error) {
+	client, err := NewElbClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return WaitForElbInstancesInServiceWithClientE(t, client, name, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForElbInstancesInServiceWithClientE waits until the given Classic Load Balancer has at least one registered
+// Instance, and all of its Instances are InService, with the ability to provide the ELB client. It returns the IDs of
+// the Instances.
+func WaitForElbInstancesInServiceWithClientE(t testing.TestingT, client elbiface.ELBAPI, name string, maxRetries int, sleepBetweenRetries time.Duration) ([]string, error) {
+	return waitForHealthyTargets(t, name, "InService", maxRetries, sleepBetweenRetries, func() (map[string]string, error) {
+		output, err := client.DescribeInstanceHealth(&elb.DescribeInstanceHealthInput{LoadBalancerName: aws.String(name)})
+		if err != nil {
+			return nil, err
+		}
+
+		states := map[string]string{}
+		for _, state := range output.InstanceStates {
+			states[aws.StringValue(state.InstanceId)] = aws.StringValue(state.State)
+		}
+		return states, nil
+	})
+}
+
+// waitForHealthyTargets retries getStates, which returns the state of each target of the given load balancer or target
+// group keyed by target ID, until there is at least one target and all of them are in the healthy state. It returns
+// the IDs of the targets.
+func waitForHealthyTargets(
+	t testing.TestingT,
+	name string,
+	healthyState string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+	getStates func() (map[string]string, error),
+) ([]string, error) {
+	out, err := retry.DoWithRetryInterfaceE(
+		t,
+		fmt.Sprintf("Waiting for the targets of %s to be healthy.", name),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (interface{}, error) {
+			states, err := getStates()
+			if err != nil {
+				return nil, err
+			}
+
+			targetIds := []string{}
+			for targetId, state := range states {
+				if state != healthyState {
+					return nil, UnhealthyTargetsError{Name: name, States: states}
+				}
+				targetIds = append(targetIds, targetId)
+			}
+			if len(targetIds) == 0 {
+				return nil, UnhealthyTargetsError{Name: name, States: states}
+			}
+			return targetIds, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logf(t, "All %d targets of %s are healthy", len(out.([]string)), name)
+	return out.([]string), nil
+}
+
+// NewElbClient creates a new ELB (Classic Load Balancer) client.
+func NewElbClient(t testing.TestingT, region string) *elb.ELB {
+	client, err := NewElbClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewElbClientE creates a new ELB (Classic Load Balancer) client.
+func NewElbClientE(t testing.TestingT, region string) (*elb.ELB, error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return elb.New(sess), nil
+}
+
+// getElbClientRegion returns the region of the given ELB client, or an empty string if it isn't an SDK client.
+func getElbClientRegion(client elbiface.ELBAPI) string {
+	if sdkClient, isSdkClient := client.(*elb.ELB); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
+// getElbV2ClientRegion returns the region of the given ELBv2 client, or an empty string if it isn't an SDK client.
+func getElbV2ClientRegion(client elbv2iface.ELBV2API) string {
+	if sdkClient, isSdkClient := client.(*elbv2.ELBV2); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
+// NewElbV2Client creates a new ELBv2 (ALB/NLB) client.
+func NewElbV2Client(t testing.TestingT, region string) *elbv2.ELBV2 {
+	client, err := NewElbV2ClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewElbV2ClientE creates a new ELBv2 (ALB/NLB) client.
+func NewElbV2ClientE(t testing.TestingT, region string) (*elbv2.ELBV2, error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return elbv2.New(sess), nil
+}
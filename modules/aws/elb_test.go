@@ -0,0 +1,119 @@
+package aws
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeElbV2 returns the given load balancers, and the next of the given target health states on each call.
+type fakeElbV2 struct {
+	elbv2iface.ELBV2API
+	loadBalancers []*elbv2.LoadBalancer
+	targetHealth  []map[string]string
+}
+
+func (client *fakeElbV2) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	return &elbv2.DescribeLoadBalancersOutput{LoadBalancers: client.loadBalancers}, nil
+}
+
+func (client *fakeElbV2) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	states := client.targetHealth[0]
+	if len(client.targetHealth) > 1 {
+		client.targetHealth = client.targetHealth[1:]
+	}
+
+	output := &elbv2.DescribeTargetHealthOutput{}
+	for id, state := range states {
+		output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, &elbv2.TargetHealthDescription{
+			Target:       &elbv2.TargetDescription{Id: aws.String(id)},
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+		})
+	}
+	return output, nil
+}
+
+// fakeElb returns the given load balancers and Instance states.
+type fakeElb struct {
+	elbiface.ELBAPI
+	loadBalancers  []*elb.LoadBalancerDescription
+	instanceStates []*elb.InstanceState
+}
+
+func (client *fakeElb) DescribeLoadBalancers(input *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	return &elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: client.loadBalancers}, nil
+}
+
+func (client *fakeElb) DescribeInstanceHealth(input *elb.DescribeInstanceHealthInput) (*elb.DescribeInstanceHealthOutput, error) {
+	return &elb.DescribeInstanceHealthOutput{InstanceStates: client.instanceStates}, nil
+}
+
+func TestGetLoadBalancerV2WithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeElbV2{loadBalancers: []*elbv2.LoadBalancer{{LoadBalancerName: aws.String("alb"), DNSName: aws.String("alb.example.com")}}}
+	loadBalancer, err := GetLoadBalancerV2WithClientE(t, client, "alb")
+	require.NoError(t, err)
+	assert.Equal(t, "alb.example.com", aws.StringValue(loadBalancer.DNSName))
+
+	_, err = GetLoadBalancerV2WithClientE(t, &fakeElbV2{}, "alb")
+	assert.IsType(t, NotFoundError{}, err)
+}
+
+func TestWaitForTargetGroupHealthyWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeElbV2{targetHealth: []map[string]string{
+		{},
+		{"i-1": elbv2.TargetHealthStateEnumInitial, "i-2": elbv2.TargetHealthStateEnumHealthy},
+		{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-2": elbv2.TargetHealthStateEnumHealthy},
+	}}
+	targetIds, err := WaitForTargetGroupHealthyWithClientE(t, client, "arn:target-group", 3, time.Millisecond)
+	require.NoError(t, err)
+
+	sort.Strings(targetIds)
+	assert.Equal(t, []string{"i-1", "i-2"}, targetIds)
+}
+
+func TestWaitForTargetGroupHealthyWithClientNoTargets(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeElbV2{targetHealth: []map[string]string{{}}}
+	_, err := WaitForTargetGroupHealthyWithClientE(t, client, "arn:target-group", 1, time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestGetElbDnsNameWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeElb{loadBalancers: []*elb.LoadBalancerDescription{{DNSName: aws.String("elb.example.com")}}}
+	dnsName, err := GetElbDnsNameWithClientE(t, client, "elb")
+	require.NoError(t, err)
+	assert.Equal(t, "elb.example.com", dnsName)
+}
+
+func TestWaitForElbInstancesInServiceWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeElb{instanceStates: []*elb.InstanceState{
+		{InstanceId: aws.String("i-1"), State: aws.String("InService")},
+		{InstanceId: aws.String("i-2"), State: aws.String("OutOfService")},
+	}}
+	_, err := WaitForElbInstancesInServiceWithClientE(t, client, "elb", 1, time.Millisecond)
+	assert.Error(t, err)
+
+	client.instanceStates[1].State = aws.String("InService")
+	instanceIds, err := WaitForElbInstancesInServiceWithClientE(t, client, "elb", 1, time.Millisecond)
+	require.NoError(t, err)
+
+	sort.Strings(instanceIds)
+	assert.Equal(t, []string{"i-1", "i-2"}, instanceIds)
+}
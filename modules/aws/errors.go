@@ -2,6 +2,8 @@ package aws
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // IpForEc2InstanceNotFound is an error that occurs when the IP for an EC2 instance is not found.
@@ -61,6 +63,72 @@ func NewAsgCapacityNotMetError(asgName string, desiredCapacity int64, currentCap
 	return AsgCapacityNotMetError{asgName, desiredCapacity, currentCapacity}
 }
 
+// AsgNotRecoveredError is returned when an ASG has not yet replaced a removed Instance with a healthy one.
+type AsgNotRecoveredError struct {
+	AsgName           string
+	RemovedInstanceId string
+	StillInAsg        bool
+	DesiredCapacity   int64
+	HealthyCount      int64
+}
+
+func (err AsgNotRecoveredError) Error() string {
+	if err.StillInAsg {
+		return fmt.Sprintf("ASG %s has not yet removed Instance %s", err.AsgName, err.RemovedInstanceId)
+	}
+	return fmt.Sprintf(
+		"ASG %s has removed Instance %s but only has %d of %d desired Instances InService and Healthy",
+		err.AsgName,
+		err.RemovedInstanceId,
+		err.HealthyCount,
+		err.DesiredCapacity,
+	)
+}
+
+// AsgRollingUpdateNotCompleteError is returned when an ASG has not yet replaced all its old Instances with healthy new ones.
+type AsgRollingUpdateNotCompleteError struct {
+	AsgName                 string
+	RemainingOldInstanceIds []string
+	HealthyNewInstances     int64
+	DesiredCapacity         int64
+}
+
+func (err AsgRollingUpdateNotCompleteError) Error() string {
+	return fmt.Sprintf(
+		"ASG %s still has old Instances %v and %d of %d desired new Instances healthy",
+		err.AsgName,
+		err.RemainingOldInstanceIds,
+		err.HealthyNewInstances,
+		err.DesiredCapacity,
+	)
+}
+
+// InstancesNotDrainedError is returned when Instances were removed from an ASG without being drained from its target group first.
+type InstancesNotDrainedError struct {
+	AsgName     string
+	InstanceIds []string
+}
+
+func (err InstancesNotDrainedError) Error() string {
+	return fmt.Sprintf("ASG %s removed Instances %v without draining them from the target group first", err.AsgName, err.InstanceIds)
+}
+
+// TooFewHealthyTargetsError is returned when the number of healthy targets in a target group dropped below the minimum.
+type TooFewHealthyTargetsError struct {
+	TargetGroupArn    string
+	MinHealthyTargets int
+	HealthyTargets    int
+}
+
+func (err TooFewHealthyTargetsError) Error() string {
+	return fmt.Sprintf(
+		"Target group %s dropped to %d healthy targets, below the minimum of %d",
+		err.TargetGroupArn,
+		err.HealthyTargets,
+		err.MinHealthyTargets,
+	)
+}
+
 // BucketVersioningNotEnabledError is returned when an S3 bucket that should have versioning does not have it applied
 type BucketVersioningNotEnabledError struct {
 	s3BucketName     string
@@ -114,6 +182,43 @@ func (err NoInstanceTypeError) Error() string {
 	)
 }
 
+// NoRegionForInstanceTypeError is returned when the given instance type is not offered in any of the given regions
+type NoRegionForInstanceTypeError struct {
+	InstanceType string
+	Regions      []string
+}
+
+func (err NoRegionForInstanceTypeError) Error() string {
+	return fmt.Sprintf(
+		"Instance type %s is not offered in any AZ of the regions %v.",
+		err.InstanceType,
+		err.Regions,
+	)
+}
+
+// NoRegionToPickFromError is returned when every one of the approved regions is also forbidden
+type NoRegionToPickFromError struct {
+	ApprovedRegions  []string
+	ForbiddenRegions []string
+}
+
+func (err NoRegionToPickFromError) Error() string {
+	return fmt.Sprintf(
+		"There is no region to pick from: all the approved regions (%v) are forbidden (%v).",
+		err.ApprovedRegions,
+		err.ForbiddenRegions,
+	)
+}
+
+// NoAvailabilityZoneError is returned when there is no available AZ in the given region
+type NoAvailabilityZoneError struct {
+	Region string
+}
+
+func (err NoAvailabilityZoneError) Error() string {
+	return fmt.Sprintf("There is no available AZ in region %s.", err.Region)
+}
+
 // NoRdsInstanceTypeError is returned when none of the given instance types are avaiable for the region, database engine, and database engine combination given
 type NoRdsInstanceTypeError struct {
 	InstanceTypeOptions   []string
@@ -129,3 +234,83 @@ func (err NoRdsInstanceTypeError) Error() string {
 		err.DatabaseEngineVersion,
 	)
 }
+
+// LeakedResourcesError is returned when tagged resources created during a test still exist after teardown.
+type LeakedResourcesError struct {
+	Tags         map[string]string
+	ResourceArns map[string][]string // Region to the ARNs of the leaked resources
+}
+
+func (err LeakedResourcesError) Error() string {
+	regions := []string{}
+	for region := range err.ResourceArns {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	message := fmt.Sprintf("Resources tagged with %v were leaked:", err.Tags)
+	for _, region := range regions {
+		for _, arn := range err.ResourceArns[region] {
+			message += fmt.Sprintf("\n  + %s (%s)", arn, region)
+		}
+	}
+	return message
+}
+
+// InvalidCidrBlock is returned when a CIDR block can't be parsed.
+type InvalidCidrBlock struct {
+	CidrBlock string
+}
+
+func (err InvalidCidrBlock) Error() string {
+	return fmt.Sprintf("Invalid CIDR block: %s", err.CidrBlock)
+}
+
+// ResourcesNotDestroyedError is returned when resources that were in the Terraform state still exist after destroy.
+type ResourcesNotDestroyedError struct {
+	Resources []string
+}
+
+func (err ResourcesNotDestroyedError) Error() string {
+	return fmt.Sprintf("Resources still exist after destroy:\n  + %s", strings.Join(err.Resources, "\n  + "))
+}
+
+// UnhealthyTargetsError is returned when a load balancer or target group has no registered targets, or some of them
+// aren't healthy yet.
+type UnhealthyTargetsError struct {
+	Name   string            // The name of the load balancer or the ARN of the target group
+	States map[string]string // The state of each target, keyed by target ID
+}
+
+func (err UnhealthyTargetsError) Error() string {
+	if len(err.States) == 0 {
+		return fmt.Sprintf("%s has no registered targets.", err.Name)
+	}
+	return fmt.Sprintf("Not all the targets of %s are healthy: %v", err.Name, err.States)
+}
+
+// RdsInstanceNotAvailableError is returned when an RDS Instance isn't available yet.
+type RdsInstanceNotAvailableError struct {
+	ID     string
+	Status string
+}
+
+func (err RdsInstanceNotAvailableError) Error() string {
+	return fmt.Sprintf("RDS Instance %s is not available yet: its status is %s.", err.ID, err.Status)
+}
+
+// EcsServiceNotStableError is returned when an ECS service is still deploying: it has more than one deployment, or
+// not as many running tasks as desired.
+type EcsServiceNotStableError struct {
+	Name        string
+	Deployments int
+	Desired     int64
+	Running     int64
+	Pending     int64
+}
+
+func (err EcsServiceNotStableError) Error() string {
+	return fmt.Sprintf(
+		"ECS service %s is not stable yet: it has %d deployments, and %d running and %d pending tasks out of %d desired.",
+		err.Name, err.Deployments, err.Running, err.Pending, err.Desired)
+}
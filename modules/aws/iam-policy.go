@@ -0,0 +1,180 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// IamPolicyDocument is an IAM policy document, such as the assume role policy of an IAM role or a version of a managed
+// IAM policy.
+type IamPolicyDocument struct {
+	Version   string
+	Statement []IamPolicyStatement
+}
+
+// IamPolicyStatement is a single statement of an IAM policy document.
+type IamPolicyStatement struct {
+	Sid         string                 `json:",omitempty"`
+	Effect      string                 // Allow or Deny
+	Principal   IamPolicyPrincipal     `json:",omitempty"`
+	Action      IamPolicyValues        `json:",omitempty"`
+	NotAction   IamPolicyValues        `json:",omitempty"`
+	Resource    IamPolicyValues        `json:",omitempty"`
+	NotResource IamPolicyValues        `json:",omitempty"`
+	Condition   map[string]interface{} `json:",omitempty"`
+}
+
+// IamPolicyValues is a list of values in an IAM policy document, which may be written as either a single string or a
+// list of strings.
+type IamPolicyValues []string
+
+// UnmarshalJSON accepts both a single string and a list of strings.
+func (values *IamPolicyValues) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*values = IamPolicyValues{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*values = list
+	return nil
+}
+
+// IamPolicyPrincipal is the principal of an IAM policy statement, keyed by principal type (e.g. AWS or Service). The
+// principal "*" is represented as {"*": ["*"]}.
+type IamPolicyPrincipal map[string]IamPolicyValues
+
+// UnmarshalJSON accepts both "*" and a map of principal type to principals.
+func (principal *IamPolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		*principal = IamPolicyPrincipal{wildcard: {wildcard}}
+		return nil
+	}
+
+	var principals map[string]IamPolicyValues
+	if err := json.Unmarshal(data, &principals); err != nil {
+		return err
+	}
+	*principal = principals
+	return nil
+}
+
+// ParseIamPolicyDocument parses the given IAM policy document. The IAM API returns policy documents URL encoded, so
+// those are decoded first.
+func ParseIamPolicyDocument(t testing.TestingT, document string) *IamPolicyDocument {
+	policy, err := ParseIamPolicyDocumentE(t, document)
+	require.NoError(t, err)
+	return policy
+}
+
+// ParseIamPolicyDocumentE parses the given IAM policy document. The IAM API returns policy documents URL encoded, so
+// those are decoded first.
+func ParseIamPolicyDocumentE(t testing.TestingT, document string) (*IamPolicyDocument, error) {
+	if !strings.HasPrefix(strings.TrimSpace(document), "{") {
+		decoded, err := url.QueryUnescape(document)
+		if err != nil {
+			return nil, err
+		}
+		document = decoded
+	}
+
+	var policy IamPolicyDocument
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// IsActionAllowed returns true if the policy allows the given action (e.g. s3:GetObject) on the given resource ARN,
+// i.e. if a statement allows it and no statement explicitly denies it. Wildcards in the policy are supported, but
+// Conditions are not evaluated: a statement with Conditions is assumed to apply.
+func (policy *IamPolicyDocument) IsActionAllowed(action string, resource string) bool {
+	allowed := false
+	for _, statement := range policy.Statement {
+		if !statement.appliesTo(action, resource) {
+			continue
+		}
+		if strings.EqualFold(statement.Effect, "Deny") {
+			return false
+		}
+		if strings.EqualFold(statement.Effect, "Allow") {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// appliesTo returns true if the statement's actions and resources match the given action and resource.
+func (statement IamPolicyStatement) appliesTo(action string, resource string) bool {
+	actionMatches := matchesAnyIamPattern(statement.Action, action, false)
+	if statement.NotAction != nil {
+		actionMatches = !matchesAnyIamPattern(statement.NotAction, action, false)
+	}
+
+	// Statements without a Resource, such as the ones in assume role policies, apply to any resource
+	resourceMatches := statement.Resource == nil || matchesAnyIamPattern(statement.Resource, resource, true)
+	if statement.NotResource != nil {
+		resourceMatches = !matchesAnyIamPattern(statement.NotResource, resource, true)
+	}
+
+	return actionMatches && resourceMatches
+}
+
+// matchesAnyIamPattern returns true if the value matches any of the given patterns, which may contain the * and ?
+// wildcards.
+func matchesAnyIamPattern(patterns []string, value string, caseSensitive bool) bool {
+	for _, pattern := range patterns {
+		expression := strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern))
+		if !caseSensitive {
+			expression = "(?i)" + expression
+		}
+		if regexp.MustCompile("^" + expression + "$").MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertIamPolicyAllowsAction checks that the given policy allows the given action on the given resource ARN. See
+// IamPolicyDocument.IsActionAllowed for the limitations of the check.
+func AssertIamPolicyAllowsAction(t testing.TestingT, policy *IamPolicyDocument, action string, resource string) {
+	require.NoError(t, AssertIamPolicyAllowsActionE(t, policy, action, resource))
+}
+
+// AssertIamPolicyAllowsActionE checks that the given policy allows the given action on the given resource ARN. See
+// IamPolicyDocument.IsActionAllowed for the limitations of the check.
+func AssertIamPolicyAllowsActionE(t testing.TestingT, policy *IamPolicyDocument, action string, resource string) error {
+	if !policy.IsActionAllowed(action, resource) {
+		return fmt.Errorf("IAM policy does not allow %s on %s", action, resource)
+	}
+	return nil
+}
+
+// AssertIamPolicyDeniesAction checks that the given policy doesn't allow the given action on the given resource ARN,
+// either because no statement allows it or because a statement explicitly denies it. See
+// IamPolicyDocument.IsActionAllowed for the limitations of the check.
+func AssertIamPolicyDeniesAction(t testing.TestingT, policy *IamPolicyDocument, action string, resource string) {
+	require.NoError(t, AssertIamPolicyDeniesActionE(t, policy, action, resource))
+}
+
+// AssertIamPolicyDeniesActionE checks that the given policy doesn't allow the given action on the given resource ARN,
+// either because no statement allows it or because a statement explicitly denies it. See
+// IamPolicyDocument.IsActionAllowed for the limitations of the check.
+func AssertIamPolicyDeniesActionE(t testing.TestingT, policy *IamPolicyDocument, action string, resource string) error {
+	if policy.IsActionAllowed(action, resource) {
+		return fmt.Errorf("IAM policy allows %s on %s", action, resource)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIamPolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {"Effect": "Allow", "Action": ["s3:Get*", "s3:ListBucket"], "Resource": "arn:aws:s3:::my-bucket*"},
+    {"Effect": "Deny", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::my-bucket/secret/*"},
+    {"Effect": "Allow", "NotAction": "iam:*", "Resource": "arn:aws:ec2:*"}
+  ]
+}`
+
+func TestIamPolicyDocumentIsActionAllowed(t *testing.T) {
+	t.Parallel()
+
+	policy := ParseIamPolicyDocument(t, testIamPolicy)
+
+	assert.True(t, policy.IsActionAllowed("s3:GetObject", "arn:aws:s3:::my-bucket/public/file"))
+	assert.True(t, policy.IsActionAllowed("S3:getobject", "arn:aws:s3:::my-bucket/public/file"))
+	assert.True(t, policy.IsActionAllowed("s3:ListBucket", "arn:aws:s3:::my-bucket"))
+	assert.True(t, policy.IsActionAllowed("ec2:RunInstances", "arn:aws:ec2:*"))
+	assert.False(t, policy.IsActionAllowed("s3:GetObject", "arn:aws:s3:::my-bucket/secret/file"))
+	assert.False(t, policy.IsActionAllowed("s3:PutObject", "arn:aws:s3:::my-bucket/public/file"))
+	assert.False(t, policy.IsActionAllowed("s3:GetObject", "arn:aws:s3:::other-bucket/file"))
+	assert.False(t, policy.IsActionAllowed("iam:CreateUser", "arn:aws:ec2:*"))
+
+	AssertIamPolicyAllowsAction(t, policy, "s3:GetBucketPolicy", "arn:aws:s3:::my-bucket")
+	AssertIamPolicyDeniesAction(t, policy, "s3:DeleteBucket", "arn:aws:s3:::my-bucket")
+}
+
+func TestParseIamAssumeRolePolicy(t *testing.T) {
+	t.Parallel()
+
+	// The IAM API returns policy documents URL encoded
+	document := url.QueryEscape(`{
+	  "Version": "2012-10-17",
+	  "Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}]
+	}`)
+	policy := ParseIamPolicyDocument(t, document)
+
+	assert.Equal(t, IamPolicyPrincipal{"Service": {"ec2.amazonaws.com"}}, policy.Statement[0].Principal)
+	assert.True(t, policy.IsActionAllowed("sts:AssumeRole", ""))
+
+	policy = ParseIamPolicyDocument(t, `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "sts:AssumeRole"}]}`)
+	assert.Equal(t, IamPolicyPrincipal{"*": {"*"}}, policy.Statement[0].Principal)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
@@ -25,6 +26,11 @@ func GetIamCurrentUserNameE(t testing.TestingT) (string, error) {
 		return "", err
 	}
 
+	return GetIamCurrentUserNameWithClientE(t, iamClient)
+}
+
+// GetIamCurrentUserNameWithClientE gets the username for the current IAM user, with the ability to provide the IAM client.
+func GetIamCurrentUserNameWithClientE(t testing.TestingT, iamClient iamiface.IAMAPI) (string, error) {
 	resp, err := iamClient.GetUser(&iam.GetUserInput{})
 	if err != nil {
 		return "", err
@@ -49,6 +55,11 @@ func GetIamCurrentUserArnE(t testing.TestingT) (string, error) {
 		return "", err
 	}
 
+	return GetIamCurrentUserArnWithClientE(t, iamClient)
+}
+
+// GetIamCurrentUserArnWithClientE gets the ARN for the current IAM user, with the ability to provide the IAM client.
+func GetIamCurrentUserArnWithClientE(t testing.TestingT, iamClient iamiface.IAMAPI) (string, error) {
 	resp, err := iamClient.GetUser(&iam.GetUserInput{})
 	if err != nil {
 		return "", err
@@ -58,7 +69,7 @@ func GetIamCurrentUserArnE(t testing.TestingT) (string, error) {
 }
 
 // CreateMfaDevice creates an MFA device using the given IAM client.
-func CreateMfaDevice(t testing.TestingT, iamClient *iam.IAM, deviceName string) *iam.VirtualMFADevice {
+func CreateMfaDevice(t testing.TestingT, iamClient iamiface.IAMAPI, deviceName string) *iam.VirtualMFADevice {
 	mfaDevice, err := CreateMfaDeviceE(t, iamClient, deviceName)
 	if err != nil {
 		t.Fatal(err)
@@ -67,7 +78,7 @@ func CreateMfaDevice(t testing.TestingT, iamClient *iam.IAM, deviceName string)
 }
 
 // CreateMfaDeviceE creates an MFA device using the given IAM client.
-func CreateMfaDeviceE(t testing.TestingT, iamClient *iam.IAM, deviceName string) (*iam.VirtualMFADevice, error) {
+func CreateMfaDeviceE(t testing.TestingT, iamClient iamiface.IAMAPI, deviceName string) (*iam.VirtualMFADevice, error) {
 	logger.Logf(t, "Creating an MFA device called %s", deviceName)
 
 	output, err := iamClient.CreateVirtualMFADevice(&iam.CreateVirtualMFADeviceInput{
@@ -86,7 +97,7 @@ func CreateMfaDeviceE(t testing.TestingT, iamClient *iam.IAM, deviceName string)
 
 // EnableMfaDevice enables a newly created MFA Device by supplying the first two one-time passwords, so that it can be used for future
 // logins by the given IAM User.
-func EnableMfaDevice(t testing.TestingT, iamClient *iam.IAM, mfaDevice *iam.VirtualMFADevice) {
+func EnableMfaDevice(t testing.TestingT, iamClient iamiface.IAMAPI, mfaDevice *iam.VirtualMFADevice) {
 	err := EnableMfaDeviceE(t, iamClient, mfaDevice)
 	if err != nil {
 		t.Fatal(err)
@@ -95,7 +106,7 @@ func EnableMfaDevice(t testing.TestingT, iamClient *iam.IAM, mfaDevice *iam.Virt
 
 // EnableMfaDeviceE enables a newly created MFA Device by supplying the first two one-time passwords, so that it can be used for future
 // logins by the given IAM User.
-func EnableMfaDeviceE(t testing.TestingT, iamClient *iam.IAM, mfaDevice *iam.VirtualMFADevice) error {
+func EnableMfaDeviceE(t testing.TestingT, iamClient iamiface.IAMAPI, mfaDevice *iam.VirtualMFADevice) error {
 	logger.Logf(t, "Enabling MFA device %s", aws.StringValue(mfaDevice.SerialNumber))
 
 	iamUserName, err := GetIamCurrentUserArnE(t)
@@ -133,6 +144,143 @@ func EnableMfaDeviceE(t testing.TestingT, iamClient *iam.IAM, mfaDevice *iam.Vir
 	return nil
 }
 
+// GetIamRoleAssumeRolePolicy gets the assume role policy of the given IAM role, i.e. the policy that controls who can
+// assume it.
+func GetIamRoleAssumeRolePolicy(t testing.TestingT, roleName string) *IamPolicyDocument {
+	policy, err := GetIamRoleAssumeRolePolicyE(t, roleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+// GetIamRoleAssumeRolePolicyE gets the assume role policy of the given IAM role, i.e. the policy that controls who can
+// assume it.
+func GetIamRoleAssumeRolePolicyE(t testing.TestingT, roleName string) (*IamPolicyDocument, error) {
+	iamClient, err := NewIamClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetIamRoleAssumeRolePolicyWithClientE(t, iamClient, roleName)
+}
+
+// GetIamRoleAssumeRolePolicyWithClientE gets the assume role policy of the given IAM role, with the ability to provide
+// the IAM client.
+func GetIamRoleAssumeRolePolicyWithClientE(t testing.TestingT, iamClient iamiface.IAMAPI, roleName string) (*IamPolicyDocument, error) {
+	resp, err := iamClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseIamPolicyDocumentE(t, aws.StringValue(resp.Role.AssumeRolePolicyDocument))
+}
+
+// GetIamRoleAttachedPolicyArns gets the ARNs of the managed IAM policies attached to the given IAM role.
+func GetIamRoleAttachedPolicyArns(t testing.TestingT, roleName string) []string {
+	arns, err := GetIamRoleAttachedPolicyArnsE(t, roleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return arns
+}
+
+// GetIamRoleAttachedPolicyArnsE gets the ARNs of the managed IAM policies attached to the given IAM role.
+func GetIamRoleAttachedPolicyArnsE(t testing.TestingT, roleName string) ([]string, error) {
+	iamClient, err := NewIamClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetIamRoleAttachedPolicyArnsWithClientE(t, iamClient, roleName)
+}
+
+// GetIamRoleAttachedPolicyArnsWithClientE gets the ARNs of the managed IAM policies attached to the given IAM role,
+// with the ability to provide the IAM client.
+func GetIamRoleAttachedPolicyArnsWithClientE(t testing.TestingT, iamClient iamiface.IAMAPI, roleName string) ([]string, error) {
+	arns := []string{}
+	input := &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)}
+	err := iamClient.ListAttachedRolePoliciesPages(input, func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
+		for _, policy := range page.AttachedPolicies {
+			arns = append(arns, aws.StringValue(policy.PolicyArn))
+		}
+		return true
+	})
+	return arns, err
+}
+
+// GetIamPolicyDocument gets the document of the default version of the given managed IAM policy.
+func GetIamPolicyDocument(t testing.TestingT, policyArn string) *IamPolicyDocument {
+	policy, err := GetIamPolicyDocumentE(t, policyArn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+// GetIamPolicyDocumentE gets the document of the default version of the given managed IAM policy.
+func GetIamPolicyDocumentE(t testing.TestingT, policyArn string) (*IamPolicyDocument, error) {
+	iamClient, err := NewIamClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetIamPolicyDocumentWithClientE(t, iamClient, policyArn)
+}
+
+// GetIamPolicyDocumentWithClientE gets the document of the default version of the given managed IAM policy, with the
+// ability to provide the IAM client.
+func GetIamPolicyDocumentWithClientE(t testing.TestingT, iamClient iamiface.IAMAPI, policyArn string) (*IamPolicyDocument, error) {
+	policy, err := iamClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: aws.String(policyArn)})
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: policy.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseIamPolicyDocumentE(t, aws.StringValue(version.PolicyVersion.Document))
+}
+
+// GetIamInstanceProfileRoleNames gets the names of the IAM roles in the given IAM instance profile.
+func GetIamInstanceProfileRoleNames(t testing.TestingT, instanceProfileName string) []string {
+	roleNames, err := GetIamInstanceProfileRoleNamesE(t, instanceProfileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return roleNames
+}
+
+// GetIamInstanceProfileRoleNamesE gets the names of the IAM roles in the given IAM instance profile.
+func GetIamInstanceProfileRoleNamesE(t testing.TestingT, instanceProfileName string) ([]string, error) {
+	iamClient, err := NewIamClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetIamInstanceProfileRoleNamesWithClientE(t, iamClient, instanceProfileName)
+}
+
+// GetIamInstanceProfileRoleNamesWithClientE gets the names of the IAM roles in the given IAM instance profile, with the
+// ability to provide the IAM client.
+func GetIamInstanceProfileRoleNamesWithClientE(t testing.TestingT, iamClient iamiface.IAMAPI, instanceProfileName string) ([]string, error) {
+	resp, err := iamClient.GetInstanceProfile(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(instanceProfileName)})
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames := []string{}
+	for _, role := range resp.InstanceProfile.Roles {
+		roleNames = append(roleNames, aws.StringValue(role.RoleName))
+	}
+	return roleNames, nil
+}
+
 // NewIamClient creates a new IAM client.
 func NewIamClient(t testing.TestingT, region string) *iam.IAM {
 	client, err := NewIamClientE(t, region)
@@ -34,7 +34,8 @@ func CreateAndImportEC2KeyPairE(t testing.TestingT, region string, name string)
 	return ImportEC2KeyPairE(t, region, name, keyPair)
 }
 
-// ImportEC2KeyPair creates a Key Pair in EC2 by importing an existing public key.
+// ImportEC2KeyPair creates a Key Pair in EC2 by importing an existing public key. The Key Pair is tagged with the tags of
+// GetTestTags.
 func ImportEC2KeyPair(t testing.TestingT, region string, name string, keyPair *ssh.KeyPair) *Ec2Keypair {
 	ec2KeyPair, err := ImportEC2KeyPairE(t, region, name, keyPair)
 	if err != nil {
@@ -43,7 +44,8 @@ func ImportEC2KeyPair(t testing.TestingT, region string, name string, keyPair *s
 	return ec2KeyPair
 }
 
-// ImportEC2KeyPairE creates a Key Pair in EC2 by importing an existing public key.
+// ImportEC2KeyPairE creates a Key Pair in EC2 by importing an existing public key. The Key Pair is tagged with the tags of
+// GetTestTags.
 func ImportEC2KeyPairE(t testing.TestingT, region string, name string, keyPair *ssh.KeyPair) (*Ec2Keypair, error) {
 	logger.Logf(t, "Creating new Key Pair in EC2 region %s named %s", region, name)
 
@@ -55,6 +57,7 @@ func ImportEC2KeyPairE(t testing.TestingT, region string, name string, keyPair *
 	params := &ec2.ImportKeyPairInput{
 		KeyName:           aws.String(name),
 		PublicKeyMaterial: []byte(keyPair.PublicKey),
+		TagSpecifications: getEc2TestTagSpecifications(t, ec2.ResourceTypeKeyPair),
 	}
 
 	_, err = client.ImportKeyPair(params)
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKms "encrypts" data keys by prefixing them with the key ID.
+type fakeKms struct {
+	kmsiface.KMSAPI
+}
+
+func (client fakeKms) GenerateDataKey(input *kms.GenerateDataKeyInput) (*kms.GenerateDataKeyOutput, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &kms.GenerateDataKeyOutput{Plaintext: key, CiphertextBlob: append([]byte(*input.KeyId), key...)}, nil
+}
+
+func (client fakeKms) Decrypt(input *kms.DecryptInput) (*kms.DecryptOutput, error) {
+	if !bytes.HasPrefix(input.CiphertextBlob, []byte(*input.KeyId)) {
+		return nil, errors.New("wrong key")
+	}
+	return &kms.DecryptOutput{Plaintext: input.CiphertextBlob[len(*input.KeyId):]}, nil
+}
+
+func TestKmsEncryptor(t *testing.T) {
+	t.Parallel()
+
+	encryptor := &KmsEncryptor{Client: fakeKms{}, KeyID: "alias/terratest"}
+	plaintext := bytes.Repeat([]byte("secret "), 1000)
+
+	ciphertext, err := encryptor.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "secret")
+
+	decrypted, err := encryptor.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	ciphertext[len(ciphertext)-1] ^= 1
+	_, err = encryptor.Decrypt(ciphertext)
+	assert.Error(t, err)
+
+	_, err = (&KmsEncryptor{Client: fakeKms{}, KeyID: "alias/other"}).Decrypt(ciphertext)
+	assert.Error(t, err)
+}
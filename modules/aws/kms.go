@@ -1,8 +1,12 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -35,6 +39,148 @@ func GetCmkArnE(t testing.TestingT, region string, cmkID string) (string, error)
 	return *result.KeyMetadata.Arn, nil
 }
 
+// CreateTestCmk creates a new symmetric KMS Customer Master Key (CMK) in the given region, tagged with the tags of
+// GetTestTags, and returns its ID. CMKs can't be deleted right away, so call ScheduleCmkDeletion to clean it up.
+func CreateTestCmk(t testing.TestingT, region string) string {
+	out, err := CreateTestCmkE(t, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// CreateTestCmkE creates a new symmetric KMS Customer Master Key (CMK) in the given region, tagged with the tags of
+// GetTestTags, and returns its ID. CMKs can't be deleted right away, so call ScheduleCmkDeletion to clean it up.
+func CreateTestCmkE(t testing.TestingT, region string) (string, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	return CreateTestCmkWithClientE(t, kmsClient)
+}
+
+// CreateTestCmkWithClientE creates a new symmetric KMS Customer Master Key (CMK), tagged with the tags of GetTestTags,
+// and returns its ID, with the ability to provide the KMS client.
+func CreateTestCmkWithClientE(t testing.TestingT, kmsClient kmsiface.KMSAPI) (string, error) {
+	input := &kms.CreateKeyInput{
+		Description: aws.String(fmt.Sprintf("Created by terratest for %s (%s)", t.Name(), testRunUniqueId)),
+	}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		input.Tags = append(input.Tags, &kms.Tag{TagKey: aws.String(key), TagValue: aws.String(value)})
+	}
+
+	result, err := kmsClient.CreateKey(input)
+	if err != nil {
+		return "", err
+	}
+
+	keyID := aws.StringValue(result.KeyMetadata.KeyId)
+	logger.Logf(t, "Created KMS CMK %s", keyID)
+	return keyID, nil
+}
+
+// ScheduleCmkDeletion schedules the KMS Customer Master Key (CMK) with the given ID in the given region to be deleted
+// after the minimum waiting period of 7 days. The CMK is disabled right away.
+func ScheduleCmkDeletion(t testing.TestingT, region string, cmkID string) {
+	err := ScheduleCmkDeletionE(t, region, cmkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ScheduleCmkDeletionE schedules the KMS Customer Master Key (CMK) with the given ID in the given region to be deleted
+// after the minimum waiting period of 7 days. The CMK is disabled right away.
+func ScheduleCmkDeletionE(t testing.TestingT, region string, cmkID string) error {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	return ScheduleCmkDeletionWithClientE(t, kmsClient, cmkID)
+}
+
+// ScheduleCmkDeletionWithClientE schedules the KMS Customer Master Key (CMK) with the given ID to be deleted after the
+// minimum waiting period of 7 days, with the ability to provide the KMS client.
+func ScheduleCmkDeletionWithClientE(t testing.TestingT, kmsClient kmsiface.KMSAPI, cmkID string) error {
+	logger.Logf(t, "Scheduling deletion of KMS CMK %s", cmkID)
+
+	_, err := kmsClient.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(cmkID),
+		PendingWindowInDays: aws.Int64(7),
+	})
+	return err
+}
+
+// KmsEncrypt encrypts the given plaintext, which can be at most 4 KB, with the KMS Customer Master Key (CMK) with the
+// given ID in the given region. Use KmsEncryptor to encrypt larger data.
+func KmsEncrypt(t testing.TestingT, region string, cmkID string, plaintext []byte) []byte {
+	out, err := KmsEncryptE(t, region, cmkID, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// KmsEncryptE encrypts the given plaintext, which can be at most 4 KB, with the KMS Customer Master Key (CMK) with the
+// given ID in the given region. Use KmsEncryptor to encrypt larger data.
+func KmsEncryptE(t testing.TestingT, region string, cmkID string, plaintext []byte) ([]byte, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return KmsEncryptWithClientE(t, kmsClient, cmkID, plaintext)
+}
+
+// KmsEncryptWithClientE encrypts the given plaintext with the KMS Customer Master Key (CMK) with the given ID, with the
+// ability to provide the KMS client.
+func KmsEncryptWithClientE(t testing.TestingT, kmsClient kmsiface.KMSAPI, cmkID string, plaintext []byte) ([]byte, error) {
+	result, err := kmsClient.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(cmkID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.CiphertextBlob, nil
+}
+
+// KmsDecrypt decrypts the given ciphertext with the KMS Customer Master Key (CMK) with the given ID in the given
+// region. Use it to check that data written by a template, e.g. to an S3 object or an SSM parameter, is encrypted
+// with the expected CMK.
+func KmsDecrypt(t testing.TestingT, region string, cmkID string, ciphertext []byte) []byte {
+	out, err := KmsDecryptE(t, region, cmkID, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// KmsDecryptE decrypts the given ciphertext with the KMS Customer Master Key (CMK) with the given ID in the given
+// region.
+func KmsDecryptE(t testing.TestingT, region string, cmkID string, ciphertext []byte) ([]byte, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return KmsDecryptWithClientE(t, kmsClient, cmkID, ciphertext)
+}
+
+// KmsDecryptWithClientE decrypts the given ciphertext with the KMS Customer Master Key (CMK) with the given ID, with
+// the ability to provide the KMS client. Decryption fails if the ciphertext was encrypted with a different CMK.
+func KmsDecryptWithClientE(t testing.TestingT, kmsClient kmsiface.KMSAPI, cmkID string, ciphertext []byte) ([]byte, error) {
+	result, err := kmsClient.Decrypt(&kms.DecryptInput{
+		KeyId:          aws.String(cmkID),
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Plaintext, nil
+}
+
 // NewKmsClient creates a KMS client.
 func NewKmsClient(t testing.TestingT, region string) *kms.KMS {
 	client, err := NewKmsClientE(t, region)
@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (client fakeKms) Encrypt(input *kms.EncryptInput) (*kms.EncryptOutput, error) {
+	return &kms.EncryptOutput{CiphertextBlob: append([]byte(*input.KeyId), input.Plaintext...)}, nil
+}
+
+// fakeKmsKeys records the CMKs it was asked to create and schedule for deletion.
+type fakeKmsKeys struct {
+	kmsiface.KMSAPI
+	created   *kms.CreateKeyInput
+	scheduled *kms.ScheduleKeyDeletionInput
+}
+
+func (client *fakeKmsKeys) CreateKey(input *kms.CreateKeyInput) (*kms.CreateKeyOutput, error) {
+	client.created = input
+	return &kms.CreateKeyOutput{KeyMetadata: &kms.KeyMetadata{KeyId: aws.String("1234abcd")}}, nil
+}
+
+func (client *fakeKmsKeys) ScheduleKeyDeletion(input *kms.ScheduleKeyDeletionInput) (*kms.ScheduleKeyDeletionOutput, error) {
+	client.scheduled = input
+	return &kms.ScheduleKeyDeletionOutput{}, nil
+}
+
+func TestCreateTestCmkWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeKmsKeys{}
+	keyID, err := CreateTestCmkWithClientE(t, client)
+	require.NoError(t, err)
+	assert.Equal(t, "1234abcd", keyID)
+
+	tags := map[string]string{}
+	for _, tag := range client.created.Tags {
+		tags[aws.StringValue(tag.TagKey)] = aws.StringValue(tag.TagValue)
+	}
+	assert.Equal(t, CreatedByTagValue, tags[CreatedByTagKey])
+	assert.Equal(t, t.Name(), tags[TestNameTagKey])
+
+	require.NoError(t, ScheduleCmkDeletionWithClientE(t, client, keyID))
+	assert.Equal(t, keyID, aws.StringValue(client.scheduled.KeyId))
+	assert.Equal(t, int64(7), aws.Int64Value(client.scheduled.PendingWindowInDays))
+}
+
+func TestKmsEncryptAndDecryptWithClient(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := KmsEncryptWithClientE(t, fakeKms{}, "alias/terratest", []byte("secret"))
+	require.NoError(t, err)
+
+	plaintext, err := KmsDecryptWithClientE(t, fakeKms{}, "alias/terratest", ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	_, err = KmsDecryptWithClientE(t, fakeKms{}, "alias/other", ciphertext)
+	assert.Error(t, err)
+}
@@ -1,11 +1,17 @@
 package aws
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +53,10 @@ type LambdaOptions struct {
 
 	// Lambda function input; will be converted to JSON.
 	Payload interface{}
+
+	// If true, return the last 4 KB of the execution log of the function
+	// in LambdaOutput.LogResult.
+	IncludeLogs bool
 }
 
 // LambdaOutput contains the output from InvokeFunctionWithParams().  The
@@ -60,6 +70,10 @@ type LambdaOutput struct {
 	// For RequestResponse invocation type, the status code is 200.
 	// For the DryRun invocation type, the status code is 204.
 	StatusCode *int64
+
+	// The last 4 KB of the execution log, if LambdaOptions.IncludeLogs
+	// was set.
+	LogResult string
 }
 
 // InvokeFunction invokes a lambda function.
@@ -90,7 +104,6 @@ func InvokeFunctionE(t testing.TestingT, region, functionName string, payload in
 	}
 
 	out, err := lambdaClient.Invoke(invokeInput)
-	require.NoError(t, err)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +135,13 @@ func InvokeFunctionWithParamsE(t testing.TestingT, region, functionName string,
 		return nil, err
 	}
 
+	return InvokeFunctionWithParamsWithClientE(t, lambdaClient, functionName, input)
+}
+
+// InvokeFunctionWithParamsWithClientE invokes a lambda function using
+// parameters supplied in the LambdaOptions struct, with the ability to
+// provide the Lambda client.
+func InvokeFunctionWithParamsWithClientE(t testing.TestingT, lambdaClient lambdaiface.LambdaAPI, functionName string, input *LambdaOptions) (*LambdaOutput, error) {
 	// Verify the InvocationType is one of the allowed values and report
 	// an error if it's not.  By default the InvocationType will be
 	// "RequestResponse".
@@ -143,6 +163,10 @@ func InvokeFunctionWithParamsE(t testing.TestingT, region, functionName string,
 		invokeInput.Payload = payloadJson
 	}
 
+	if input.IncludeLogs {
+		invokeInput.LogType = aws.String(lambda.LogTypeTail)
+	}
+
 	out, err := lambdaClient.Invoke(invokeInput)
 	if err != nil {
 		return nil, err
@@ -156,6 +180,14 @@ func InvokeFunctionWithParamsE(t testing.TestingT, region, functionName string,
 		StatusCode: out.StatusCode,
 	}
 
+	if out.LogResult != nil {
+		logResult, err := base64.StdEncoding.DecodeString(*out.LogResult)
+		if err != nil {
+			return nil, err
+		}
+		lambdaOutput.LogResult = string(logResult)
+	}
+
 	if out.FunctionError != nil {
 		return &lambdaOutput, errors.New(*out.FunctionError)
 	}
@@ -163,6 +195,58 @@ func InvokeFunctionWithParamsE(t testing.TestingT, region, functionName string,
 	return &lambdaOutput, nil
 }
 
+// WaitForFunctionActive waits until the given lambda function is Active and
+// is not being updated, so that it can be invoked, retrying up to maxRetries
+// times with sleepBetweenRetries in between.
+func WaitForFunctionActive(t testing.TestingT, region, functionName string, maxRetries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitForFunctionActiveE(t, region, functionName, maxRetries, sleepBetweenRetries))
+}
+
+// WaitForFunctionActiveE waits until the given lambda function is Active and
+// is not being updated, so that it can be invoked, retrying up to maxRetries
+// times with sleepBetweenRetries in between.
+func WaitForFunctionActiveE(t testing.TestingT, region, functionName string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	lambdaClient, err := NewLambdaClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	return WaitForFunctionActiveWithClientE(t, lambdaClient, functionName, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForFunctionActiveWithClientE waits until the given lambda function is
+// Active and is not being updated, with the ability to provide the Lambda
+// client. It gives up right away if the function failed to be created.
+func WaitForFunctionActiveWithClientE(t testing.TestingT, lambdaClient lambdaiface.LambdaAPI, functionName string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	_, err := retry.DoWithRetryE(
+		t,
+		fmt.Sprintf("Waiting for lambda function %s to be active.", functionName),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			config, err := lambdaClient.GetFunctionConfiguration(&lambda.GetFunctionConfigurationInput{FunctionName: &functionName})
+			if err != nil {
+				return "", err
+			}
+
+			state := aws.StringValue(config.State)
+			if state == lambda.StateFailed {
+				return "", retry.FatalError{Underlying: fmt.Errorf("lambda function %s failed: %s", functionName, aws.StringValue(config.StateReason))}
+			}
+			if state != lambda.StateActive || aws.StringValue(config.LastUpdateStatus) == lambda.LastUpdateStatusInProgress {
+				return "", fmt.Errorf("lambda function %s is %s, last update %s", functionName, state, aws.StringValue(config.LastUpdateStatus))
+			}
+			return state, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	logger.Logf(t, "Lambda function %s is active", functionName)
+	return nil
+}
+
 type FunctionError struct {
 	Message    string
 	StatusCode int64
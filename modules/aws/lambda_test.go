@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLambda returns the next of the given function configurations on each call, and records the last invocation.
+type fakeLambda struct {
+	lambdaiface.LambdaAPI
+	configurations []*lambda.FunctionConfiguration
+	invoked        *lambda.InvokeInput
+}
+
+func (client *fakeLambda) GetFunctionConfiguration(input *lambda.GetFunctionConfigurationInput) (*lambda.FunctionConfiguration, error) {
+	config := client.configurations[0]
+	if len(client.configurations) > 1 {
+		client.configurations = client.configurations[1:]
+	}
+	return config, nil
+}
+
+func (client *fakeLambda) Invoke(input *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+	client.invoked = input
+	return &lambda.InvokeOutput{
+		Payload:    []byte(`"Hi!"`),
+		StatusCode: aws.Int64(200),
+		LogResult:  aws.String(base64.StdEncoding.EncodeToString([]byte("START RequestId: 123"))),
+	}, nil
+}
+
+func TestInvokeFunctionWithParamsWithClientIncludesLogs(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeLambda{}
+	out, err := InvokeFunctionWithParamsWithClientE(t, client, "my-function", &LambdaOptions{Payload: "Hello", IncludeLogs: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, lambda.LogTypeTail, aws.StringValue(client.invoked.LogType))
+	assert.Equal(t, `"Hello"`, string(client.invoked.Payload))
+	assert.Equal(t, `"Hi!"`, string(out.Payload))
+	assert.Equal(t, "START RequestId: 123", out.LogResult)
+}
+
+func TestWaitForFunctionActiveWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeLambda{configurations: []*lambda.FunctionConfiguration{
+		{State: aws.String(lambda.StatePending)},
+		{State: aws.String(lambda.StateActive), LastUpdateStatus: aws.String(lambda.LastUpdateStatusInProgress)},
+		{State: aws.String(lambda.StateActive), LastUpdateStatus: aws.String(lambda.LastUpdateStatusSuccessful)},
+	}}
+	assert.NoError(t, WaitForFunctionActiveWithClientE(t, client, "my-function", 3, time.Millisecond))
+
+	client = &fakeLambda{configurations: []*lambda.FunctionConfiguration{{State: aws.String(lambda.StateFailed)}}}
+	assert.Error(t, WaitForFunctionActiveWithClientE(t, client, "my-function", 3, time.Millisecond))
+}
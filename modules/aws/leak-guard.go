@@ -0,0 +1,282 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// LeakGuard records the resources with the given tags in the given regions when it's created, so that after teardown
+// Check can fail the test if any tagged resource created in the meantime still exists. Tag the resources your tests
+// create (e.g., with a CreatedBy = terratest tag via the default_tags of the AWS provider) and wrap the tests in one
+// parent test:
+//
+//	guard := aws.NewLeakGuard(t, []string{"us-east-1"}, map[string]string{"CreatedBy": "terratest"})
+//	t.Run("group", func(t *testing.T) {
+//	    t.Run("TestVpc", testVpc)
+//	    t.Run("TestAsg", testAsg)
+//	})
+//	guard.Check(t)
+//
+// Deleted resources can show up in the Resource Groups Tagging API for a while. Terminated EC2 Instances stay there for
+// about an hour, so Check looks up the state of the Instances it finds and ignores the terminated ones. For other
+// resources, Check retries up to MaxRetries times before reporting them as leaked.
+type LeakGuard struct {
+	Regions             []string
+	Tags                map[string]string // Resources must have all these tags. An empty value matches any value of the tag.
+	MaxRetries          int
+	SleepBetweenRetries time.Duration
+
+	before map[string][]string // Region to the ARNs of the tagged resources that existed when the guard was created
+}
+
+const (
+	defaultLeakGuardMaxRetries          = 30
+	defaultLeakGuardSleepBetweenRetries = 10 * time.Second
+
+	// The number of instance IDs to look up per DescribeInstances call
+	maxInstanceIdFilterValues = 200
+)
+
+// NewLeakGuard records the resources with the given tags in the given regions and returns a LeakGuard to check for
+// leaked resources against after teardown. This will fail the test if there is an error.
+func NewLeakGuard(t testing.TestingT, regions []string, tags map[string]string) *LeakGuard {
+	guard, err := NewLeakGuardE(t, regions, tags)
+	require.NoError(t, err)
+	return guard
+}
+
+// NewLeakGuardE records the resources with the given tags in the given regions and returns a LeakGuard to check for
+// leaked resources against after teardown.
+func NewLeakGuardE(t testing.TestingT, regions []string, tags map[string]string) (*LeakGuard, error) {
+	guard := &LeakGuard{
+		Regions:             regions,
+		Tags:                tags,
+		MaxRetries:          defaultLeakGuardMaxRetries,
+		SleepBetweenRetries: defaultLeakGuardSleepBetweenRetries,
+		before:              map[string][]string{},
+	}
+
+	for _, region := range regions {
+		arns, err := GetTaggedResourceArnsE(t, region, tags)
+		if err != nil {
+			return nil, err
+		}
+		guard.before[region] = arns
+	}
+
+	return guard, nil
+}
+
+// Check fails the test if any resource with the tags of the guard exists now that did not exist when the guard was
+// created.
+func (guard *LeakGuard) Check(t testing.TestingT) {
+	require.NoError(t, guard.CheckE(t))
+}
+
+// CheckE returns a LeakedResourcesError if any resource with the tags of the guard exists now that did not exist when
+// the guard was created.
+func (guard *LeakGuard) CheckE(t testing.TestingT) error {
+	var leakedErr error
+	_, err := retry.DoWithRetryE(
+		t,
+		fmt.Sprintf("Checking for resources tagged with %v leaked in %v", guard.Tags, guard.Regions),
+		guard.MaxRetries,
+		guard.SleepBetweenRetries,
+		func() (string, error) {
+			leaked := map[string][]string{}
+			for _, region := range guard.Regions {
+				after, err := GetTaggedResourceArnsE(t, region, guard.Tags)
+				if err != nil {
+					return "", retry.FatalError{Underlying: err}
+				}
+				arns, err := removeTerminatedInstanceArnsE(t, region, newArns(guard.before[region], after))
+				if err != nil {
+					return "", retry.FatalError{Underlying: err}
+				}
+				if len(arns) > 0 {
+					leaked[region] = arns
+				}
+			}
+
+			if len(leaked) > 0 {
+				leakedErr = LeakedResourcesError{Tags: guard.Tags, ResourceArns: leaked}
+				return "", leakedErr
+			}
+			return "", nil
+		},
+	)
+
+	switch err := err.(type) {
+	case nil:
+		logger.Logf(t, "No resources tagged with %v were leaked in %v", guard.Tags, guard.Regions)
+		return nil
+	case retry.FatalError:
+		return err.Underlying
+	case retry.MaxRetriesExceeded:
+		return leakedErr
+	default:
+		return err
+	}
+}
+
+// GetTaggedResourceArns returns the sorted ARNs of all resources in the given region that have all the given tags. An
+// empty tag value matches any value of the tag.
+func GetTaggedResourceArns(t testing.TestingT, region string, tags map[string]string) []string {
+	arns, err := GetTaggedResourceArnsE(t, region, tags)
+	require.NoError(t, err)
+	return arns
+}
+
+// GetTaggedResourceArnsE returns the sorted ARNs of all resources in the given region that have all the given tags. An
+// empty tag value matches any value of the tag.
+func GetTaggedResourceArnsE(t testing.TestingT, region string, tags map[string]string) ([]string, error) {
+	client, err := NewResourceGroupsTaggingApiClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &resourcegroupstaggingapi.GetResourcesInput{}
+	for key, value := range tags {
+		filter := &resourcegroupstaggingapi.TagFilter{Key: aws.String(key)}
+		if value != "" {
+			filter.Values = aws.StringSlice([]string{value})
+		}
+		input.TagFilters = append(input.TagFilters, filter)
+	}
+
+	arns := []string{}
+	err = client.GetResourcesPages(input, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+		for _, mapping := range page.ResourceTagMappingList {
+			arns = append(arns, aws.StringValue(mapping.ResourceARN))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(arns)
+	return arns, nil
+}
+
+// newArns returns the ARNs in after that are not in before.
+func newArns(before []string, after []string) []string {
+	existing := map[string]bool{}
+	for _, arn := range before {
+		existing[arn] = true
+	}
+
+	added := []string{}
+	for _, arn := range after {
+		if !existing[arn] {
+			added = append(added, arn)
+		}
+	}
+	return added
+}
+
+// removeTerminatedInstanceArnsE returns the given ARNs without the ones of EC2 Instances in the given region that are
+// terminated (or shutting down) or no longer exist at all, since the Resource Groups Tagging API keeps returning
+// terminated Instances for about an hour.
+func removeTerminatedInstanceArnsE(t testing.TestingT, region string, arns []string) ([]string, error) {
+	hasInstances := false
+	for _, arn := range arns {
+		if _, isInstance := instanceIdFromArn(arn); isInstance {
+			hasInstances = true
+		}
+	}
+	if !hasInstances {
+		return arns, nil
+	}
+
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return removeTerminatedInstanceArnsWithClientE(client, arns)
+}
+
+// removeTerminatedInstanceArnsWithClientE returns the given ARNs without the ones of EC2 Instances that are terminated
+// (or shutting down) or no longer exist at all, looking up the Instances with the given EC2 client.
+func removeTerminatedInstanceArnsWithClientE(client ec2iface.EC2API, arns []string) ([]string, error) {
+	instanceIds := []string{}
+	for _, arn := range arns {
+		if instanceId, isInstance := instanceIdFromArn(arn); isInstance {
+			instanceIds = append(instanceIds, instanceId)
+		}
+	}
+
+	// Filter on the instance IDs rather than passing them as InstanceIds, which fails if any of them no longer exists
+	liveInstances := map[string]bool{}
+	for start := 0; start < len(instanceIds); start += maxInstanceIdFilterValues {
+		end := start + maxInstanceIdFilterValues
+		if end > len(instanceIds) {
+			end = len(instanceIds)
+		}
+		input := &ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("instance-id"), Values: aws.StringSlice(instanceIds[start:end])}},
+		}
+		err := client.DescribeInstancesPages(input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					state := aws.StringValue(instance.State.Name)
+					if state != ec2.InstanceStateNameTerminated && state != ec2.InstanceStateNameShuttingDown {
+						liveInstances[aws.StringValue(instance.InstanceId)] = true
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remaining := []string{}
+	for _, arn := range arns {
+		if instanceId, isInstance := instanceIdFromArn(arn); isInstance && !liveInstances[instanceId] {
+			continue
+		}
+		remaining = append(remaining, arn)
+	}
+	return remaining, nil
+}
+
+// instanceIdFromArn returns the instance ID in the given ARN and true if it's the ARN of an EC2 Instance (e.g.,
+// arn:aws:ec2:us-east-1:123456789012:instance/i-0abc), or false otherwise.
+func instanceIdFromArn(arn string) (string, bool) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[2] != "ec2" || !strings.HasPrefix(parts[5], "instance/") {
+		return "", false
+	}
+	return strings.TrimPrefix(parts[5], "instance/"), true
+}
+
+// NewResourceGroupsTaggingApiClient creates a new Resource Groups Tagging API client.
+func NewResourceGroupsTaggingApiClient(t testing.TestingT, region string) *resourcegroupstaggingapi.ResourceGroupsTaggingAPI {
+	client, err := NewResourceGroupsTaggingApiClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewResourceGroupsTaggingApiClientE creates a new Resource Groups Tagging API client.
+func NewResourceGroupsTaggingApiClientE(t testing.TestingT, region string) (*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourcegroupstaggingapi.New(sess), nil
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArns(t *testing.T) {
+	t.Parallel()
+
+	before := []string{"arn:aws:s3:::existing-bucket", "arn:aws:sqs:us-east-1:123456789012:existing-queue"}
+	after := []string{"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc", "arn:aws:s3:::existing-bucket", "arn:aws:sqs:us-east-1:123456789012:existing-queue"}
+
+	assert.Equal(t, []string{"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc"}, newArns(before, after))
+	assert.Empty(t, newArns(after, before))
+}
+
+func TestLeakedResourcesError(t *testing.T) {
+	t.Parallel()
+
+	err := LeakedResourcesError{
+		Tags: map[string]string{"CreatedBy": "terratest"},
+		ResourceArns: map[string][]string{
+			"us-west-2": {"arn:aws:ec2:us-west-2:123456789012:vpc/vpc-0def"},
+			"us-east-1": {"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc", "arn:aws:s3:::leaked-bucket"},
+		},
+	}
+
+	assert.Equal(t, "Resources tagged with map[CreatedBy:terratest] were leaked:\n"+
+		"  + arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc (us-east-1)\n"+
+		"  + arn:aws:s3:::leaked-bucket (us-east-1)\n"+
+		"  + arn:aws:ec2:us-west-2:123456789012:vpc/vpc-0def (us-west-2)", err.Error())
+}
+
+// fakeEc2InstanceFilter returns the instances with the given states that match the instance-id filter.
+type fakeEc2InstanceFilter struct {
+	ec2iface.EC2API
+	states map[string]string
+}
+
+func (client fakeEc2InstanceFilter) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	reservation := &ec2.Reservation{}
+	for _, id := range aws.StringValueSlice(input.Filters[0].Values) {
+		if state, exists := client.states[id]; exists {
+			reservation.Instances = append(reservation.Instances, &ec2.Instance{InstanceId: aws.String(id), State: &ec2.InstanceState{Name: aws.String(state)}})
+		}
+	}
+	fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{reservation}}, true)
+	return nil
+}
+
+func TestRemoveTerminatedInstanceArns(t *testing.T) {
+	t.Parallel()
+
+	client := fakeEc2InstanceFilter{states: map[string]string{
+		"i-running":    ec2.InstanceStateNameRunning,
+		"i-terminated": ec2.InstanceStateNameTerminated,
+	}}
+	arns := []string{
+		"arn:aws:ec2:us-east-1:123456789012:instance/i-gone",
+		"arn:aws:ec2:us-east-1:123456789012:instance/i-running",
+		"arn:aws:ec2:us-east-1:123456789012:instance/i-terminated",
+		"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc",
+	}
+
+	remaining, err := removeTerminatedInstanceArnsWithClientE(client, arns)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"arn:aws:ec2:us-east-1:123456789012:instance/i-running",
+		"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc",
+	}, remaining)
+}
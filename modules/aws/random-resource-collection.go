@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/ssh"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+const defaultKeyPairBitSize = 2048
+
+// RandomResourceCollection is a collection of resources that most tests need: a randomly chosen region, a unique ID to
+// namespace the resources of the test, an EC2 Key Pair, and an AMI to launch in that region.
+type RandomResourceCollection struct {
+	AwsRegion string      // The randomly chosen AWS region
+	UniqueId  string      // A unique ID to use in the names of the resources of the test
+	KeyPair   *Ec2Keypair // The EC2 Key Pair imported into AwsRegion, or nil if SkipKeyPair was set
+	AmiId     string      // The ID of the most recent Ubuntu 20.04 AMI in AwsRegion, or empty if SkipAmi was set
+}
+
+// RandomResourceCollectionOptions customizes the resources created by CreateRandomResourceCollection.
+type RandomResourceCollectionOptions struct {
+	ApprovedRegions  []string // If set, only pick the region from these regions
+	ForbiddenRegions []string // Never pick the region from these regions
+	KeyPairBitSize   int      // The size of the RSA key of the Key Pair. Defaults to 2048.
+	SkipKeyPair      bool     // Whether to skip creating a Key Pair, e.g. for tests that don't use SSH
+	SkipAmi          bool     // Whether to skip looking up an AMI, e.g. for tests that don't launch EC2 Instances
+}
+
+// CreateRandomResourceCollection picks a random region and creates the resources of a RandomResourceCollection in it,
+// customized with the given options, which may be nil to use the defaults. Call Destroy on the collection to clean up.
+func CreateRandomResourceCollection(t testing.TestingT, options *RandomResourceCollectionOptions) *RandomResourceCollection {
+	collection, err := CreateRandomResourceCollectionE(t, options)
+	require.NoError(t, err)
+	return collection
+}
+
+// CreateRandomResourceCollectionE picks a random region and creates the resources of a RandomResourceCollection in it,
+// customized with the given options, which may be nil to use the defaults. Call Destroy on the collection to clean up.
+func CreateRandomResourceCollectionE(t testing.TestingT, options *RandomResourceCollectionOptions) (*RandomResourceCollection, error) {
+	if options == nil {
+		options = &RandomResourceCollectionOptions{}
+	}
+
+	region, err := GetRandomStableRegionE(t, options.ApprovedRegions, options.ForbiddenRegions)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &RandomResourceCollection{AwsRegion: region, UniqueId: random.UniqueId()}
+
+	if !options.SkipAmi {
+		amiID, err := GetUbuntu2004AmiE(t, region)
+		if err != nil {
+			return nil, err
+		}
+		collection.AmiId = amiID
+	}
+
+	if !options.SkipKeyPair {
+		keyPair, err := ssh.GenerateRSAKeyPairE(t, options.keyPairBitSize())
+		if err != nil {
+			return nil, err
+		}
+		ec2KeyPair, err := ImportEC2KeyPairE(t, region, fmt.Sprintf("terratest-%s", collection.UniqueId), keyPair)
+		if err != nil {
+			return nil, err
+		}
+		collection.KeyPair = ec2KeyPair
+	}
+
+	return collection, nil
+}
+
+// Destroy deletes the resources of the collection that exist in AWS.
+func (collection *RandomResourceCollection) Destroy(t testing.TestingT) {
+	require.NoError(t, collection.DestroyE(t))
+}
+
+// DestroyE deletes the resources of the collection that exist in AWS.
+func (collection *RandomResourceCollection) DestroyE(t testing.TestingT) error {
+	if collection.KeyPair == nil {
+		return nil
+	}
+	return DeleteEC2KeyPairE(t, collection.KeyPair)
+}
+
+func (options *RandomResourceCollectionOptions) keyPairBitSize() int {
+	if options.KeyPairBitSize > 0 {
+		return options.KeyPairBitSize
+	}
+	return defaultKeyPairBitSize
+}
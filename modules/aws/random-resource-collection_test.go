@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRandomResourceCollection(t *testing.T) {
+	t.Parallel()
+
+	collection := CreateRandomResourceCollection(t, &RandomResourceCollectionOptions{
+		ApprovedRegions:  []string{"us-east-1", "us-east-2", "eu-west-1"},
+		ForbiddenRegions: []string{"us-east-1"},
+		KeyPairBitSize:   4096,
+	})
+	defer collection.Destroy(t)
+
+	assert.Contains(t, []string{"us-east-2", "eu-west-1"}, collection.AwsRegion)
+	assert.Regexp(t, "^ami-[[:alnum:]]+$", collection.AmiId)
+	require.NotNil(t, collection.KeyPair)
+	assert.True(t, keyPairExists(t, collection.KeyPair))
+	assert.Contains(t, collection.KeyPair.Name, collection.UniqueId)
+}
+
+func TestCreateRandomResourceCollectionWithEverythingSkipped(t *testing.T) {
+	t.Parallel()
+
+	collection := CreateRandomResourceCollection(t, &RandomResourceCollectionOptions{
+		ApprovedRegions: []string{"eu-west-1"},
+		SkipKeyPair:     true,
+		SkipAmi:         true,
+	})
+
+	assert.Equal(t, "eu-west-1", collection.AwsRegion)
+	assert.Len(t, collection.UniqueId, 6)
+	assert.Nil(t, collection.KeyPair)
+	assert.Empty(t, collection.AmiId)
+	assert.NoError(t, collection.DestroyE(t))
+}
+
+func TestRandomResourceCollectionOptionsKeyPairBitSize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 2048, (&RandomResourceCollectionOptions{}).keyPairBitSize())
+	assert.Equal(t, 4096, (&RandomResourceCollectionOptions{KeyPairBitSize: 4096}).keyPairBitSize())
+}
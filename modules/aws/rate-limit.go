@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"golang.org/x/time/rate"
+)
+
+var (
+	rateLimitersMutex sync.Mutex
+	rateLimiters      = map[string]*rate.Limiter{}
+)
+
+// SetRateLimit caps the number of requests per second that the AWS clients created by this package send to the given
+// service, across all the tests running in this process. The service is the name the AWS SDK uses for it, such as
+// "ec2", "s3", or "autoscaling". Up to burst requests can be sent at once before the cap kicks in. Requests over the cap
+// wait for their turn rather than failing, which keeps large parallel validation loops from exhausting account-level
+// API limits such as those of EC2 DescribeInstances. This is typically called once from TestMain:
+//
+//	aws.SetRateLimit("ec2", 10, 5)
+func SetRateLimit(service string, requestsPerSecond float64, burst int) {
+	rateLimitersMutex.Lock()
+	defer rateLimitersMutex.Unlock()
+	rateLimiters[service] = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+}
+
+// RemoveRateLimit removes the cap set with SetRateLimit on the number of requests sent to the given service.
+func RemoveRateLimit(service string) {
+	rateLimitersMutex.Lock()
+	defer rateLimitersMutex.Unlock()
+	delete(rateLimiters, service)
+}
+
+func getRateLimiter(service string) *rate.Limiter {
+	rateLimitersMutex.Lock()
+	defer rateLimitersMutex.Unlock()
+	return rateLimiters[service]
+}
+
+// rateLimitHandler waits until the rate limit of the service, if any, allows the request to be sent. It runs before
+// every send, so retries by the AWS SDK count towards the rate limit too.
+var rateLimitHandler = request.NamedHandler{
+	Name: "terratest.RateLimitHandler",
+	Fn: func(r *request.Request) {
+		limiter := getRateLimiter(r.ClientInfo.ServiceName)
+		if limiter == nil {
+			return
+		}
+		if err := limiter.Wait(r.Context()); err != nil {
+			r.Error = err
+		}
+	},
+}
+
+// addRateLimitHandler makes all the clients created from the given session respect the limits set with SetRateLimit.
+func addRateLimitHandler(sess *session.Session) *session.Session {
+	sess.Handlers.Send.PushFrontNamed(rateLimitHandler)
+	return sess
+}
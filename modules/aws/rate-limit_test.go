@@ -0,0 +1,50 @@
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// Rate limits are process-wide, so this test must not run while other tests are making EC2 requests.
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<DescribeRegionsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><regionInfo/></DescribeRegionsResponse>`))
+	}))
+	defer server.Close()
+
+	sess, err := session.NewSession(aws.NewConfig().
+		WithRegion("us-east-1").
+		WithEndpoint(server.URL).
+		WithCredentials(CreateAwsCredentials("AKIAEXAMPLE", "secret")))
+	require.NoError(t, err)
+	client := ec2.New(addRateLimitHandler(sess))
+
+	SetRateLimit("ec2", 10, 1)
+	defer RemoveRateLimit("ec2")
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.DescribeRegions(&ec2.DescribeRegionsInput{})
+		require.NoError(t, err)
+	}
+	// The first request uses the burst, and each of the other three waits 100ms for its turn
+	assert.True(t, time.Since(start) >= 250*time.Millisecond, "Requests were not rate limited: took %s", time.Since(start))
+
+	RemoveRateLimit("ec2")
+	start = time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.DescribeRegions(&ec2.DescribeRegionsInput{})
+		require.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) < 250*time.Millisecond, "Requests were rate limited after removing the limit: took %s", time.Since(start))
+}
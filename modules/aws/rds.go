@@ -3,10 +3,16 @@ package aws
 import (
 	"database/sql"
 	"fmt"
+	"net"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -190,15 +196,106 @@ func GetAllParametersOfRdsInstanceE(t testing.TestingT, dbInstanceID string, aws
 
 // GetRdsInstanceDetailsE gets the details of a single DB instance whose identifier is passed.
 func GetRdsInstanceDetailsE(t testing.TestingT, dbInstanceID string, awsRegion string) (*rds.DBInstance, error) {
-	rdsClient := NewRdsClient(t, awsRegion)
+	rdsClient, err := NewRdsClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	return GetRdsInstanceDetailsWithClientE(t, rdsClient, dbInstanceID)
+}
+
+// GetRdsInstanceDetailsWithClientE gets the details of a single DB instance whose identifier is passed, with the
+// ability to provide the RDS client.
+func GetRdsInstanceDetailsWithClientE(t testing.TestingT, rdsClient rdsiface.RDSAPI, dbInstanceID string) (*rds.DBInstance, error) {
 	input := rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(dbInstanceID)}
 	output, err := rdsClient.DescribeDBInstances(&input)
 	if err != nil {
 		return nil, err
 	}
+	if len(output.DBInstances) == 0 {
+		return nil, NewNotFoundError("RDS Instance", dbInstanceID, getRdsClientRegion(rdsClient))
+	}
 	return output.DBInstances[0], nil
 }
 
+// GetRdsEndpoint gets the endpoint of the given RDS Instance in the given region, as host:port. Use it with the
+// db-helper package to connect to the database and run validation queries.
+func GetRdsEndpoint(t testing.TestingT, dbInstanceID string, awsRegion string) string {
+	endpoint, err := GetRdsEndpointE(t, dbInstanceID, awsRegion)
+	require.NoError(t, err)
+	return endpoint
+}
+
+// GetRdsEndpointE gets the endpoint of the given RDS Instance in the given region, as host:port.
+func GetRdsEndpointE(t testing.TestingT, dbInstanceID string, awsRegion string) (string, error) {
+	dbInstance, err := GetRdsInstanceDetailsE(t, dbInstanceID, awsRegion)
+	if err != nil {
+		return "", err
+	}
+	return getRdsEndpointE(dbInstance)
+}
+
+// getRdsEndpointE returns the endpoint of the given DB instance as host:port, or an error if it doesn't have one yet
+// (e.g., because it's still being created).
+func getRdsEndpointE(dbInstance *rds.DBInstance) (string, error) {
+	if dbInstance.Endpoint == nil {
+		return "", RdsInstanceNotAvailableError{ID: aws.StringValue(dbInstance.DBInstanceIdentifier), Status: aws.StringValue(dbInstance.DBInstanceStatus)}
+	}
+	return net.JoinHostPort(aws.StringValue(dbInstance.Endpoint.Address), strconv.FormatInt(aws.Int64Value(dbInstance.Endpoint.Port), 10)), nil
+}
+
+// WaitForRdsInstanceAvailable waits until the given RDS Instance is available, retrying up to maxRetries times with
+// sleepBetweenRetries in between, and returns its endpoint as host:port.
+func WaitForRdsInstanceAvailable(t testing.TestingT, dbInstanceID string, awsRegion string, maxRetries int, sleepBetweenRetries time.Duration) string {
+	endpoint, err := WaitForRdsInstanceAvailableE(t, dbInstanceID, awsRegion, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return endpoint
+}
+
+// WaitForRdsInstanceAvailableE waits until the given RDS Instance is available, retrying up to maxRetries times with
+// sleepBetweenRetries in between, and returns its endpoint as host:port.
+func WaitForRdsInstanceAvailableE(t testing.TestingT, dbInstanceID string, awsRegion string, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	rdsClient, err := NewRdsClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+	return WaitForRdsInstanceAvailableWithClientE(t, rdsClient, dbInstanceID, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForRdsInstanceAvailableWithClientE waits until the given RDS Instance is available, with the ability to provide
+// the RDS client, and returns its endpoint as host:port.
+func WaitForRdsInstanceAvailableWithClientE(t testing.TestingT, rdsClient rdsiface.RDSAPI, dbInstanceID string, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	endpoint, err := retry.DoWithRetryE(
+		t,
+		fmt.Sprintf("Waiting for RDS Instance %s to be available.", dbInstanceID),
+		maxRetries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			dbInstance, err := GetRdsInstanceDetailsWithClientE(t, rdsClient, dbInstanceID)
+			if err != nil {
+				return "", err
+			}
+			if aws.StringValue(dbInstance.DBInstanceStatus) != "available" {
+				return "", RdsInstanceNotAvailableError{ID: dbInstanceID, Status: aws.StringValue(dbInstance.DBInstanceStatus)}
+			}
+			return getRdsEndpointE(dbInstance)
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Logf(t, "RDS Instance %s is available at %s", dbInstanceID, endpoint)
+	return endpoint, nil
+}
+
+// getRdsClientRegion returns the region of the given RDS client, or an empty string if it isn't an SDK client.
+func getRdsClientRegion(rdsClient rdsiface.RDSAPI) string {
+	if sdkClient, isSdkClient := rdsClient.(*rds.RDS); isSdkClient {
+		return aws.StringValue(sdkClient.Config.Region)
+	}
+	return ""
+}
+
 // NewRdsClient creates an RDS client.
 func NewRdsClient(t testing.TestingT, region string) *rds.RDS {
 	client, err := NewRdsClientE(t, region)
@@ -242,7 +339,7 @@ func GetRecommendedRdsInstanceTypeE(t testing.TestingT, region string, engine st
 // first instance type in the list that is available in the given region and for the given database engine type.
 // If none of the instances provided are avaiable for your combination of region and database engine, this function will return an error.
 // This function expects an authenticated RDS client from the AWS SDK Go library.
-func GetRecommendedRdsInstanceTypeWithClientE(t testing.TestingT, rdsClient *rds.RDS, engine string, engineVersion string, instanceTypeOptions []string) (string, error) {
+func GetRecommendedRdsInstanceTypeWithClientE(t testing.TestingT, rdsClient rdsiface.RDSAPI, engine string, engineVersion string, instanceTypeOptions []string) (string, error) {
 	for _, instanceTypeOption := range instanceTypeOptions {
 		instanceTypeExists, err := instanceTypeExistsForEngineAndRegionE(rdsClient, engine, engineVersion, instanceTypeOption)
 		if err != nil {
@@ -258,7 +355,7 @@ func GetRecommendedRdsInstanceTypeWithClientE(t testing.TestingT, rdsClient *rds
 
 // instanceTypeExistsForEngineAndRegionE returns a boolean that represents whether the provided instance type (e.g. db.t2.micro) exists for the given region and db engine type
 // This function will return an error if the RDS AWS SDK call fails.
-func instanceTypeExistsForEngineAndRegionE(client *rds.RDS, engine string, engineVersion string, instanceType string) (bool, error) {
+func instanceTypeExistsForEngineAndRegionE(client rdsiface.RDSAPI, engine string, engineVersion string, instanceType string) (bool, error) {
 	input := rds.DescribeOrderableDBInstanceOptionsInput{
 		Engine:          aws.String(engine),
 		EngineVersion:   aws.String(engineVersion),
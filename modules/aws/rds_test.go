@@ -3,8 +3,13 @@ package aws
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetRecommendedRdsInstanceTypeHappyPath(t *testing.T) {
@@ -145,3 +150,43 @@ func TestGetRecommendedRdsInstanceTypeErrors(t *testing.T) {
 		})
 	}
 }
+
+// fakeRdsInstance returns the next of the given DB instance states on each call.
+type fakeRdsInstance struct {
+	rdsiface.RDSAPI
+	instances []*rds.DBInstance
+}
+
+func (client *fakeRdsInstance) DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	if len(client.instances) == 0 {
+		return &rds.DescribeDBInstancesOutput{}, nil
+	}
+	instance := client.instances[0]
+	if len(client.instances) > 1 {
+		client.instances = client.instances[1:]
+	}
+	return &rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{instance}}, nil
+}
+
+func TestWaitForRdsInstanceAvailableWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeRdsInstance{instances: []*rds.DBInstance{
+		{DBInstanceIdentifier: aws.String("db"), DBInstanceStatus: aws.String("creating")},
+		{
+			DBInstanceIdentifier: aws.String("db"),
+			DBInstanceStatus:     aws.String("available"),
+			Endpoint:             &rds.Endpoint{Address: aws.String("db.example.com"), Port: aws.Int64(3306)},
+		},
+	}}
+	endpoint, err := WaitForRdsInstanceAvailableWithClientE(t, client, "db", 2, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "db.example.com:3306", endpoint)
+}
+
+func TestGetRdsInstanceDetailsWithClientNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := GetRdsInstanceDetailsWithClientE(t, &fakeRdsInstance{}, "db")
+	assert.IsType(t, NotFoundError{}, err)
+}
@@ -1,19 +1,19 @@
 package aws
 
 import (
-	"os"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/environment"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
 // You can set this environment variable to force Terratest to use a specific region rather than a random one. This is
-// convenient when iterating locally.
-const regionOverrideEnvVarName = "TERRATEST_REGION"
+// convenient when iterating locally. See environment.Overrides.
+const regionOverrideEnvVarName = environment.RegionEnvVar
 
 // AWS API calls typically require an AWS region. We typically require the user to set one explicitly, but in some
 // cases, this doesn't make sense (e.g., for fetching the lsit of regions in an account), so for those cases, we use
@@ -45,6 +45,17 @@ var stableRegions = []string{
 // those that have been around for at least 1 year.
 // Note that regions in the approvedRegions list that are not considered stable are ignored.
 func GetRandomStableRegion(t testing.TestingT, approvedRegions []string, forbiddenRegions []string) string {
+	region, err := GetRandomStableRegionE(t, approvedRegions, forbiddenRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return region
+}
+
+// GetRandomStableRegionE gets a randomly chosen AWS region that is considered stable. Like GetRandomRegionE, you can
+// further restrict the stable region list using approvedRegions and forbiddenRegions.
+// Note that regions in the approvedRegions list that are not considered stable are ignored.
+func GetRandomStableRegionE(t testing.TestingT, approvedRegions []string, forbiddenRegions []string) (string, error) {
 	regionsToPickFrom := stableRegions
 	if len(approvedRegions) > 0 {
 		regionsToPickFrom = collections.ListIntersection(regionsToPickFrom, approvedRegions)
@@ -52,7 +63,7 @@ func GetRandomStableRegion(t testing.TestingT, approvedRegions []string, forbidd
 	if len(forbiddenRegions) > 0 {
 		regionsToPickFrom = collections.ListSubtract(regionsToPickFrom, forbiddenRegions)
 	}
-	return GetRandomRegion(t, regionsToPickFrom, nil)
+	return GetRandomRegionE(t, regionsToPickFrom, nil)
 }
 
 // GetRandomRegion gets a randomly chosen AWS region. If approvedRegions is not empty, this will be a region from the approvedRegions
@@ -68,29 +79,100 @@ func GetRandomRegion(t testing.TestingT, approvedRegions []string, forbiddenRegi
 
 // GetRandomRegionE gets a randomly chosen AWS region. If approvedRegions is not empty, this will be a region from the approvedRegions
 // list; otherwise, this method will fetch the latest list of regions from the AWS APIs and pick one of those. If
-// forbiddenRegions is not empty, this method will make sure the returned region is not in the forbiddenRegions list.
+// forbiddenRegions is not empty, this method will make sure the returned region is not in the forbiddenRegions list. If
+// that leaves no region to pick from, this returns a NoRegionToPickFromError.
 func GetRandomRegionE(t testing.TestingT, approvedRegions []string, forbiddenRegions []string) (string, error) {
-	regionFromEnvVar := os.Getenv(regionOverrideEnvVarName)
+	regionFromEnvVar := environment.GetRegionOverride()
 	if regionFromEnvVar != "" {
 		logger.Logf(t, "Using AWS region %s from environment variable %s", regionFromEnvVar, regionOverrideEnvVarName)
 		return regionFromEnvVar, nil
 	}
 
+	regionsToPickFrom, err := getRegionsToPickFromE(t, approvedRegions, forbiddenRegions)
+	if err != nil {
+		return "", err
+	}
+	region := random.RandomString(regionsToPickFrom)
+
+	logger.Logf(t, "Using region %s", region)
+	return region, nil
+}
+
+// GetRandomRegionForInstanceType gets a randomly chosen AWS region, like GetRandomRegion, but only picks regions in
+// which the given instance type is offered in at least one AZ. Regions that don't offer the instance type are skipped
+// and another region is picked, until there are no regions left to pick from.
+func GetRandomRegionForInstanceType(t testing.TestingT, approvedRegions []string, forbiddenRegions []string, instanceType string) string {
+	region, err := GetRandomRegionForInstanceTypeE(t, approvedRegions, forbiddenRegions, instanceType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return region
+}
+
+// GetRandomRegionForInstanceTypeE gets a randomly chosen AWS region, like GetRandomRegionE, but only picks regions in
+// which the given instance type is offered in at least one AZ. Regions that don't offer the instance type are skipped
+// and another region is picked, until there are no regions left to pick from.
+// Offerings don't say whether there is capacity for the instance type right now, so launches can still fail with
+// InsufficientInstanceCapacity. To retry those with other instance types, use
+// terraform.InitAndApplyWithInstanceTypeFallback.
+func GetRandomRegionForInstanceTypeE(t testing.TestingT, approvedRegions []string, forbiddenRegions []string, instanceType string) (string, error) {
+	regionFromEnvVar := environment.GetRegionOverride()
+	if regionFromEnvVar != "" {
+		logger.Logf(t, "Using AWS region %s from environment variable %s", regionFromEnvVar, regionOverrideEnvVarName)
+		azs, err := GetAvailabilityZonesForInstanceTypeE(t, regionFromEnvVar, instanceType)
+		if err != nil {
+			return "", err
+		}
+		if len(azs) == 0 {
+			return "", NoRegionForInstanceTypeError{InstanceType: instanceType, Regions: []string{regionFromEnvVar}}
+		}
+		return regionFromEnvVar, nil
+	}
+
+	regionsToPickFrom, err := getRegionsToPickFromE(t, approvedRegions, forbiddenRegions)
+	if err != nil {
+		return "", err
+	}
+	triedRegions := []string{}
+
+	for len(regionsToPickFrom) > 0 {
+		region := random.RandomString(regionsToPickFrom)
+		regionsToPickFrom = collections.ListSubtract(regionsToPickFrom, []string{region})
+		triedRegions = append(triedRegions, region)
+
+		azs, err := GetAvailabilityZonesForInstanceTypeE(t, region, instanceType)
+		if err != nil {
+			return "", err
+		}
+		if len(azs) > 0 {
+			logger.Logf(t, "Using region %s, which offers instance type %s in AZs %v", region, instanceType, azs)
+			return region, nil
+		}
+
+		logger.Logf(t, "Instance type %s is not offered in region %s. Picking another region.", instanceType, region)
+	}
+
+	return "", NoRegionForInstanceTypeError{InstanceType: instanceType, Regions: triedRegions}
+}
+
+// getRegionsToPickFromE returns approvedRegions, or all the regions in this account if approvedRegions is empty, minus
+// forbiddenRegions. This returns a NoRegionToPickFromError if that leaves no region at all.
+func getRegionsToPickFromE(t testing.TestingT, approvedRegions []string, forbiddenRegions []string) ([]string, error) {
 	regionsToPickFrom := approvedRegions
 
 	if len(regionsToPickFrom) == 0 {
 		allRegions, err := GetAllAwsRegionsE(t)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		regionsToPickFrom = allRegions
 	}
 
 	regionsToPickFrom = collections.ListSubtract(regionsToPickFrom, forbiddenRegions)
-	region := random.RandomString(regionsToPickFrom)
-
-	logger.Logf(t, "Using region %s", region)
-	return region, nil
+	if len(regionsToPickFrom) == 0 {
+		return nil, NoRegionToPickFromError{ApprovedRegions: approvedRegions, ForbiddenRegions: forbiddenRegions}
+	}
+	return regionsToPickFrom, nil
 }
 
 // GetAllAwsRegions gets the list of AWS regions available in this account.
@@ -156,3 +238,116 @@ func GetAvailabilityZonesE(t testing.TestingT, region string) ([]string, error)
 
 	return out, nil
 }
+
+// GetRandomAvailabilityZone gets a randomly chosen Availability Zone in the given AWS region, out of those that are
+// currently available. Picking a random AZ spreads tests across AZs, which makes them less likely to all fail on a lack
+// of capacity in one of them.
+func GetRandomAvailabilityZone(t testing.TestingT, region string) string {
+	out, err := GetRandomAvailabilityZoneE(t, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetRandomAvailabilityZoneE gets a randomly chosen Availability Zone in the given AWS region, out of those that are
+// currently available.
+func GetRandomAvailabilityZoneE(t testing.TestingT, region string) (string, error) {
+	ec2Client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+	return GetRandomAvailabilityZoneWithClientE(t, ec2Client, region)
+}
+
+// GetRandomAvailabilityZoneWithClientE gets a randomly chosen Availability Zone in the given AWS region, out of those
+// that are currently available, with the ability to provide the EC2 client.
+func GetRandomAvailabilityZoneWithClientE(t testing.TestingT, ec2Client ec2iface.EC2API, region string) (string, error) {
+	logger.Logf(t, "Looking up available availability zones in region %s", region)
+
+	azs, err := getAllAvailabilityZonesE(ec2Client)
+	if err != nil {
+		return "", err
+	}
+	if len(azs) == 0 {
+		return "", NoAvailabilityZoneError{Region: region}
+	}
+
+	az := random.RandomString(azs)
+	logger.Logf(t, "Using availability zone %s", az)
+	return az, nil
+}
+
+// GetAvailabilityZonesForInstanceType gets the Availability Zones in the given AWS region in which the given instance
+// type is offered. This is empty if the instance type is not offered in the region at all.
+func GetAvailabilityZonesForInstanceType(t testing.TestingT, region string, instanceType string) []string {
+	out, err := GetAvailabilityZonesForInstanceTypeE(t, region, instanceType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetAvailabilityZonesForInstanceTypeE gets the Availability Zones in the given AWS region in which the given instance
+// type is offered. This is empty if the instance type is not offered in the region at all.
+// Offerings don't say whether there is capacity for the instance type right now, so launches can still fail with
+// InsufficientInstanceCapacity. To retry those with other instance types, use
+// terraform.InitAndApplyWithInstanceTypeFallback.
+func GetAvailabilityZonesForInstanceTypeE(t testing.TestingT, region string, instanceType string) ([]string, error) {
+	logger.Logf(t, "Looking up availability zones that offer instance type %s in region %s", instanceType, region)
+
+	ec2Client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	availabilityZones, err := getAllAvailabilityZonesE(ec2Client)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceTypeOfferings, err := getInstanceTypeOfferingsE(ec2Client, []string{instanceType})
+	if err != nil {
+		return nil, err
+	}
+
+	return availabilityZonesWithOffering(availabilityZones, instanceTypeOfferings, instanceType), nil
+}
+
+// GetRandomAvailabilityZoneForInstanceType gets a randomly chosen Availability Zone in the given AWS region in which
+// the given instance type is offered.
+func GetRandomAvailabilityZoneForInstanceType(t testing.TestingT, region string, instanceType string) string {
+	out, err := GetRandomAvailabilityZoneForInstanceTypeE(t, region, instanceType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetRandomAvailabilityZoneForInstanceTypeE gets a randomly chosen Availability Zone in the given AWS region in which
+// the given instance type is offered.
+func GetRandomAvailabilityZoneForInstanceTypeE(t testing.TestingT, region string, instanceType string) (string, error) {
+	azs, err := GetAvailabilityZonesForInstanceTypeE(t, region, instanceType)
+	if err != nil {
+		return "", err
+	}
+	if len(azs) == 0 {
+		return "", NoRegionForInstanceTypeError{InstanceType: instanceType, Regions: []string{region}}
+	}
+
+	az := random.RandomString(azs)
+	logger.Logf(t, "Using availability zone %s", az)
+	return az, nil
+}
+
+// availabilityZonesWithOffering returns the AZs from availabilityZones in which the given instance type is offered,
+// based on the availability data in instanceTypeOfferings.
+func availabilityZonesWithOffering(availabilityZones []string, instanceTypeOfferings []*ec2.InstanceTypeOffering, instanceType string) []string {
+	out := []string{}
+	for _, az := range availabilityZones {
+		if hasOffering(instanceTypeOfferings, az, instanceType) {
+			out = append(out, az)
+		}
+	}
+	return out
+}
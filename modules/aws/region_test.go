@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetRandomRegion(t *testing.T) {
@@ -26,6 +30,13 @@ func TestGetRandomRegionExcludesForbiddenRegions(t *testing.T) {
 	}
 }
 
+func TestGetRandomRegionWithAllApprovedRegionsForbidden(t *testing.T) {
+	t.Parallel()
+
+	_, err := GetRandomRegionE(t, []string{"us-east-1", "eu-west-1"}, []string{"eu-west-1", "us-east-1"})
+	assert.Equal(t, NoRegionToPickFromError{ApprovedRegions: []string{"us-east-1", "eu-west-1"}, ForbiddenRegions: []string{"eu-west-1", "us-east-1"}}, err)
+}
+
 func TestGetAllAwsRegions(t *testing.T) {
 	t.Parallel()
 
@@ -54,3 +65,58 @@ func TestGetAvailabilityZones(t *testing.T) {
 		assert.Regexp(t, fmt.Sprintf("^%s[a-z]$", randomRegion), az)
 	}
 }
+
+// fakeAvailabilityZones returns the given AZs, ignoring the filters it was asked for.
+type fakeAvailabilityZones struct {
+	ec2iface.EC2API
+	azs []string
+}
+
+func (client *fakeAvailabilityZones) DescribeAvailabilityZones(input *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	out := &ec2.DescribeAvailabilityZonesOutput{}
+	for _, az := range client.azs {
+		out.AvailabilityZones = append(out.AvailabilityZones, &ec2.AvailabilityZone{ZoneName: aws.String(az)})
+	}
+	return out, nil
+}
+
+func TestGetRandomAvailabilityZoneWithClient(t *testing.T) {
+	t.Parallel()
+
+	azs := []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}
+	az, err := GetRandomAvailabilityZoneWithClientE(t, &fakeAvailabilityZones{azs: azs}, "eu-west-1")
+	require.NoError(t, err)
+	assert.Contains(t, azs, az)
+
+	_, err = GetRandomAvailabilityZoneWithClientE(t, &fakeAvailabilityZones{}, "eu-west-1")
+	assert.Equal(t, NoAvailabilityZoneError{Region: "eu-west-1"}, err)
+}
+
+func TestGetRandomRegionForInstanceType(t *testing.T) {
+	t.Parallel()
+
+	approvedRegions := []string{"us-east-1", "us-east-2", "us-west-2", "eu-west-1"}
+	region := GetRandomRegionForInstanceType(t, approvedRegions, nil, "t3.micro")
+	assert.Contains(t, approvedRegions, region)
+	assert.NotEmpty(t, GetAvailabilityZonesForInstanceType(t, region, "t3.micro"))
+}
+
+func TestGetRandomRegionForInstanceTypeNotOffered(t *testing.T) {
+	t.Parallel()
+
+	_, err := GetRandomRegionForInstanceTypeE(t, []string{"us-east-1", "us-west-2"}, nil, "not-a-real.instance-type")
+	noRegionErr, isNoRegionErr := err.(NoRegionForInstanceTypeError)
+	require.True(t, isNoRegionErr)
+	assert.ElementsMatch(t, []string{"us-east-1", "us-west-2"}, noRegionErr.Regions)
+}
+
+func TestAvailabilityZonesWithOffering(t *testing.T) {
+	t.Parallel()
+
+	availabilityZones := []string{"us-east-1a", "us-east-1b", "us-east-1c"}
+	instanceTypeOfferings := offerings(map[string][]string{"us-east-1a": {"t2.micro", "t3.micro"}, "us-east-1c": {"t3.micro"}, "us-east-1d": {"t2.micro"}})
+
+	assert.Equal(t, []string{"us-east-1a"}, availabilityZonesWithOffering(availabilityZones, instanceTypeOfferings, "t2.micro"))
+	assert.Equal(t, []string{"us-east-1a", "us-east-1c"}, availabilityZonesWithOffering(availabilityZones, instanceTypeOfferings, "t3.micro"))
+	assert.Empty(t, availabilityZonesWithOffering(availabilityZones, instanceTypeOfferings, "m5.large"))
+}
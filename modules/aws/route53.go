@@ -0,0 +1,189 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// GetRoute53HostedZone gets the public or private Route 53 hosted zone with the given domain name (e.g. example.com).
+func GetRoute53HostedZone(t testing.TestingT, zoneName string, privateZone bool) *route53.HostedZone {
+	zone, err := GetRoute53HostedZoneE(t, zoneName, privateZone)
+	require.NoError(t, err)
+	return zone
+}
+
+// GetRoute53HostedZoneE gets the public or private Route 53 hosted zone with the given domain name (e.g. example.com).
+func GetRoute53HostedZoneE(t testing.TestingT, zoneName string, privateZone bool) (*route53.HostedZone, error) {
+	client, err := NewRoute53ClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRoute53HostedZoneWithClientE(t, client, zoneName, privateZone)
+}
+
+// GetRoute53HostedZoneWithClientE gets the public or private Route 53 hosted zone with the given domain name (e.g.
+// example.com), with the ability to provide the Route 53 client.
+func GetRoute53HostedZoneWithClientE(t testing.TestingT, client route53iface.Route53API, zoneName string, privateZone bool) (*route53.HostedZone, error) {
+	input := &route53.ListHostedZonesByNameInput{DNSName: aws.String(zoneName)}
+	for {
+		output, err := client.ListHostedZonesByName(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, zone := range output.HostedZones {
+			if !isSameDnsName(aws.StringValue(zone.Name), zoneName) {
+				// Zones are sorted by name, so there are no more matches
+				return nil, NewNotFoundError("Route 53 Hosted Zone", zoneName, "")
+			}
+			if zone.Config != nil && aws.BoolValue(zone.Config.PrivateZone) == privateZone {
+				return zone, nil
+			}
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return nil, NewNotFoundError("Route 53 Hosted Zone", zoneName, "")
+		}
+		input.DNSName = output.NextDNSName
+		input.HostedZoneId = output.NextHostedZoneId
+	}
+}
+
+// GetRoute53HostedZoneNameServers gets the name servers of the given Route 53 hosted zone. Pass them as the resolvers
+// to the functions in the dns-helper package (e.g. DNSLookupAuthoritativeAllWithValidationRetry) to wait for a record
+// in the zone to resolve to an expected value.
+func GetRoute53HostedZoneNameServers(t testing.TestingT, hostedZoneId string) []string {
+	nameServers, err := GetRoute53HostedZoneNameServersE(t, hostedZoneId)
+	require.NoError(t, err)
+	return nameServers
+}
+
+// GetRoute53HostedZoneNameServersE gets the name servers of the given Route 53 hosted zone.
+func GetRoute53HostedZoneNameServersE(t testing.TestingT, hostedZoneId string) ([]string, error) {
+	client, err := NewRoute53ClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRoute53HostedZoneNameServersWithClientE(t, client, hostedZoneId)
+}
+
+// GetRoute53HostedZoneNameServersWithClientE gets the name servers of the given Route 53 hosted zone, with the ability
+// to provide the Route 53 client. Private hosted zones have no name servers.
+func GetRoute53HostedZoneNameServersWithClientE(t testing.TestingT, client route53iface.Route53API, hostedZoneId string) ([]string, error) {
+	output, err := client.GetHostedZone(&route53.GetHostedZoneInput{Id: aws.String(hostedZoneId)})
+	if err != nil {
+		return nil, err
+	}
+	if output.DelegationSet == nil {
+		return []string{}, nil
+	}
+	return aws.StringValueSlice(output.DelegationSet.NameServers), nil
+}
+
+// GetRoute53RecordSets gets all the record sets in the given Route 53 hosted zone.
+func GetRoute53RecordSets(t testing.TestingT, hostedZoneId string) []*route53.ResourceRecordSet {
+	recordSets, err := GetRoute53RecordSetsE(t, hostedZoneId)
+	require.NoError(t, err)
+	return recordSets
+}
+
+// GetRoute53RecordSetsE gets all the record sets in the given Route 53 hosted zone.
+func GetRoute53RecordSetsE(t testing.TestingT, hostedZoneId string) ([]*route53.ResourceRecordSet, error) {
+	client, err := NewRoute53ClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRoute53RecordSetsWithClientE(t, client, hostedZoneId)
+}
+
+// GetRoute53RecordSetsWithClientE gets all the record sets in the given Route 53 hosted zone, with the ability to
+// provide the Route 53 client.
+func GetRoute53RecordSetsWithClientE(t testing.TestingT, client route53iface.Route53API, hostedZoneId string) ([]*route53.ResourceRecordSet, error) {
+	recordSets := []*route53.ResourceRecordSet{}
+	input := &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(hostedZoneId)}
+	err := client.ListResourceRecordSetsPages(input, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+		recordSets = append(recordSets, page.ResourceRecordSets...)
+		return true
+	})
+	return recordSets, err
+}
+
+// GetRoute53RecordValues gets the values of the record with the given name and type (e.g. A or CNAME) in the given
+// Route 53 hosted zone. For alias records, this is the DNS name of the alias target.
+func GetRoute53RecordValues(t testing.TestingT, hostedZoneId string, recordName string, recordType string) []string {
+	values, err := GetRoute53RecordValuesE(t, hostedZoneId, recordName, recordType)
+	require.NoError(t, err)
+	return values
+}
+
+// GetRoute53RecordValuesE gets the values of the record with the given name and type (e.g. A or CNAME) in the given
+// Route 53 hosted zone. For alias records, this is the DNS name of the alias target.
+func GetRoute53RecordValuesE(t testing.TestingT, hostedZoneId string, recordName string, recordType string) ([]string, error) {
+	client, err := NewRoute53ClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRoute53RecordValuesWithClientE(t, client, hostedZoneId, recordName, recordType)
+}
+
+// GetRoute53RecordValuesWithClientE gets the values of the record with the given name and type (e.g. A or CNAME) in
+// the given Route 53 hosted zone, with the ability to provide the Route 53 client. For alias records, this is the DNS
+// name of the alias target.
+func GetRoute53RecordValuesWithClientE(t testing.TestingT, client route53iface.Route53API, hostedZoneId string, recordName string, recordType string) ([]string, error) {
+	recordSets, err := GetRoute53RecordSetsWithClientE(t, client, hostedZoneId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recordSet := range recordSets {
+		if !isSameDnsName(aws.StringValue(recordSet.Name), recordName) || aws.StringValue(recordSet.Type) != recordType {
+			continue
+		}
+		if recordSet.AliasTarget != nil {
+			return []string{strings.TrimSuffix(aws.StringValue(recordSet.AliasTarget.DNSName), ".")}, nil
+		}
+
+		values := []string{}
+		for _, record := range recordSet.ResourceRecords {
+			values = append(values, aws.StringValue(record.Value))
+		}
+		return values, nil
+	}
+
+	return nil, NewNotFoundError("Route 53 "+recordType+" record", recordName, "")
+}
+
+// isSameDnsName returns true if the given DNS names are the same, ignoring case and the trailing dot that Route 53
+// adds to names.
+func isSameDnsName(name1 string, name2 string) bool {
+	return strings.EqualFold(strings.TrimSuffix(name1, "."), strings.TrimSuffix(name2, "."))
+}
+
+// NewRoute53Client creates a new Route 53 client. Route 53 is a global service, so the region is only used to sign
+// the requests.
+func NewRoute53Client(t testing.TestingT, region string) *route53.Route53 {
+	client, err := NewRoute53ClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewRoute53ClientE creates a new Route 53 client. Route 53 is a global service, so the region is only used to sign
+// the requests.
+func NewRoute53ClientE(t testing.TestingT, region string) (*route53.Route53, error) {
+	sess, err := NewAuthenticatedSession(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return route53.New(sess), nil
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRoute53 returns the given hosted zones and record sets.
+type fakeRoute53 struct {
+	route53iface.Route53API
+	hostedZones []*route53.HostedZone
+	recordSets  []*route53.ResourceRecordSet
+}
+
+func (client *fakeRoute53) ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+	return &route53.ListHostedZonesByNameOutput{HostedZones: client.hostedZones, IsTruncated: aws.Bool(false)}, nil
+}
+
+func (client *fakeRoute53) ListResourceRecordSetsPages(input *route53.ListResourceRecordSetsInput, fn func(*route53.ListResourceRecordSetsOutput, bool) bool) error {
+	fn(&route53.ListResourceRecordSetsOutput{ResourceRecordSets: client.recordSets}, true)
+	return nil
+}
+
+func TestGetRoute53HostedZoneWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeRoute53{hostedZones: []*route53.HostedZone{
+		{Id: aws.String("public"), Name: aws.String("example.com."), Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(false)}},
+		{Id: aws.String("private"), Name: aws.String("example.com."), Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(true)}},
+		{Id: aws.String("other"), Name: aws.String("example.net."), Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(false)}},
+	}}
+
+	zone, err := GetRoute53HostedZoneWithClientE(t, client, "example.com", true)
+	require.NoError(t, err)
+	assert.Equal(t, "private", aws.StringValue(zone.Id))
+
+	client.hostedZones = client.hostedZones[1:]
+	_, err = GetRoute53HostedZoneWithClientE(t, client, "example.com", false)
+	assert.IsType(t, NotFoundError{}, err)
+}
+
+func TestGetRoute53RecordValuesWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeRoute53{recordSets: []*route53.ResourceRecordSet{
+		{
+			Name:            aws.String("www.example.com."),
+			Type:            aws.String("A"),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}, {Value: aws.String("5.6.7.8")}},
+		},
+		{
+			Name:        aws.String("app.example.com."),
+			Type:        aws.String("A"),
+			AliasTarget: &route53.AliasTarget{DNSName: aws.String("my-alb-123.us-east-1.elb.amazonaws.com.")},
+		},
+	}}
+
+	values, err := GetRoute53RecordValuesWithClientE(t, client, "zone", "www.example.com", "A")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4", "5.6.7.8"}, values)
+
+	values, err = GetRoute53RecordValuesWithClientE(t, client, "zone", "APP.example.com.", "A")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"my-alb-123.us-east-1.elb.amazonaws.com"}, values)
+
+	_, err = GetRoute53RecordValuesWithClientE(t, client, "zone", "www.example.com", "CNAME")
+	assert.IsType(t, NotFoundError{}, err)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
@@ -28,6 +29,11 @@ func FindS3BucketWithTagE(t testing.TestingT, awsRegion string, key string, valu
 		return "", err
 	}
 
+	return FindS3BucketWithTagWithClientE(t, s3Client, key, value)
+}
+
+// FindS3BucketWithTagWithClientE finds the name of the S3 bucket with the given tag key=value, with the ability to provide the S3 client.
+func FindS3BucketWithTagWithClientE(t testing.TestingT, s3Client s3iface.S3API, key string, value string) (string, error) {
 	resp, err := s3Client.ListBuckets(&s3.ListBucketsInput{})
 	if err != nil {
 		return "", err
@@ -77,6 +83,11 @@ func GetS3BucketTagsE(t testing.TestingT, awsRegion string, bucket string) (map[
 		return nil, err
 	}
 
+	return GetS3BucketTagsWithClientE(t, s3Client, bucket)
+}
+
+// GetS3BucketTagsWithClientE fetches the given bucket's tags and returns them as a string map of strings, with the ability to provide the S3 client.
+func GetS3BucketTagsWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucket string) (map[string]string, error) {
 	out, err := s3Client.GetBucketTagging(&s3.GetBucketTaggingInput{
 		Bucket: &bucket,
 	})
@@ -107,6 +118,12 @@ func GetS3ObjectContentsE(t testing.TestingT, awsRegion string, bucket string, k
 		return "", err
 	}
 
+	return GetS3ObjectContentsWithClientE(t, s3Client, bucket, key)
+}
+
+// GetS3ObjectContentsWithClientE fetches the contents of the object in the given bucket with the given key and return it as a string, with the
+// ability to provide the S3 client.
+func GetS3ObjectContentsWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucket string, key string) (string, error) {
 	res, err := s3Client.GetObject(&s3.GetObjectInput{
 		Bucket: &bucket,
 		Key:    &key,
@@ -128,6 +145,38 @@ func GetS3ObjectContentsE(t testing.TestingT, awsRegion string, bucket string, k
 	return contents, nil
 }
 
+// PutS3ObjectContents uploads the given contents to the object in the given bucket with the given key.
+func PutS3ObjectContents(t testing.TestingT, awsRegion string, bucket string, key string, contents string) {
+	err := PutS3ObjectContentsE(t, awsRegion, bucket, key, contents)
+	require.NoError(t, err)
+}
+
+// PutS3ObjectContentsE uploads the given contents to the object in the given bucket with the given key.
+func PutS3ObjectContentsE(t testing.TestingT, awsRegion string, bucket string, key string, contents string) error {
+	s3Client, err := NewS3ClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	return PutS3ObjectContentsWithClientE(t, s3Client, bucket, key, contents)
+}
+
+// PutS3ObjectContentsWithClientE uploads the given contents to the object in the given bucket with the given key, with the
+// ability to provide the S3 client.
+func PutS3ObjectContentsWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucket string, key string, contents string) error {
+	_, err := s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   strings.NewReader(contents),
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Logf(t, "Wrote contents to s3://%s/%s", bucket, key)
+	return nil
+}
+
 // CreateS3Bucket creates an S3 bucket in the given region with the given name. Note that S3 bucket names must be globally unique.
 func CreateS3Bucket(t testing.TestingT, region string, name string) {
 	err := CreateS3BucketE(t, region, name)
@@ -143,10 +192,24 @@ func CreateS3BucketE(t testing.TestingT, region string, name string) error {
 		return err
 	}
 
+	return CreateS3BucketWithClientE(t, s3Client, name)
+}
+
+// CreateS3BucketWithClientE creates an S3 bucket with the given name, with the ability to provide the S3 client. Note that S3 bucket names
+// must be globally unique. The bucket is tagged with the tags of GetTestTags.
+func CreateS3BucketWithClientE(t testing.TestingT, s3Client s3iface.S3API, name string) error {
 	params := &s3.CreateBucketInput{
 		Bucket: aws.String(name),
 	}
-	_, err = s3Client.CreateBucket(params)
+	if _, err := s3Client.CreateBucket(params); err != nil {
+		return err
+	}
+
+	tagging := &s3.Tagging{}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		tagging.TagSet = append(tagging.TagSet, &s3.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err := s3Client.PutBucketTagging(&s3.PutBucketTaggingInput{Bucket: aws.String(name), Tagging: tagging})
 	return err
 }
 
@@ -165,12 +228,18 @@ func PutS3BucketPolicyE(t testing.TestingT, region string, bucketName string, po
 		return err
 	}
 
+	return PutS3BucketPolicyWithClientE(t, s3Client, bucketName, policyJSONString)
+}
+
+// PutS3BucketPolicyWithClientE applies an IAM resource policy to a given S3 bucket to create it's bucket policy, with the ability to provide the
+// S3 client.
+func PutS3BucketPolicyWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucketName string, policyJSONString string) error {
 	input := &s3.PutBucketPolicyInput{
 		Bucket: aws.String(bucketName),
 		Policy: aws.String(policyJSONString),
 	}
 
-	_, err = s3Client.PutBucketPolicy(input)
+	_, err := s3Client.PutBucketPolicy(input)
 	return err
 }
 
@@ -189,6 +258,12 @@ func PutS3BucketVersioningE(t testing.TestingT, region string, bucketName string
 		return err
 	}
 
+	return PutS3BucketVersioningWithClientE(t, s3Client, bucketName)
+}
+
+// PutS3BucketVersioningWithClientE creates an S3 bucket versioning configuration against the given bucket name, WITHOUT requiring MFA to
+// remove versioning, with the ability to provide the S3 client.
+func PutS3BucketVersioningWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucketName string) error {
 	input := &s3.PutBucketVersioningInput{
 		Bucket: aws.String(bucketName),
 		VersioningConfiguration: &s3.VersioningConfiguration{
@@ -197,7 +272,7 @@ func PutS3BucketVersioningE(t testing.TestingT, region string, bucketName string
 		},
 	}
 
-	_, err = s3Client.PutBucketVersioning(input)
+	_, err := s3Client.PutBucketVersioning(input)
 	return err
 }
 
@@ -216,10 +291,15 @@ func DeleteS3BucketE(t testing.TestingT, region string, name string) error {
 		return err
 	}
 
+	return DeleteS3BucketWithClientE(t, s3Client, name)
+}
+
+// DeleteS3BucketWithClientE destroys the S3 bucket with the given name, with the ability to provide the S3 client.
+func DeleteS3BucketWithClientE(t testing.TestingT, s3Client s3iface.S3API, name string) error {
 	params := &s3.DeleteBucketInput{
 		Bucket: aws.String(name),
 	}
-	_, err = s3Client.DeleteBucket(params)
+	_, err := s3Client.DeleteBucket(params)
 	return err
 }
 
@@ -238,6 +318,11 @@ func EmptyS3BucketE(t testing.TestingT, region string, name string) error {
 		return err
 	}
 
+	return EmptyS3BucketWithClientE(t, s3Client, name)
+}
+
+// EmptyS3BucketWithClientE removes the contents of the S3 bucket with the given name, with the ability to provide the S3 client.
+func EmptyS3BucketWithClientE(t testing.TestingT, s3Client s3iface.S3API, name string) error {
 	params := &s3.ListObjectVersionsInput{
 		Bucket: aws.String(name),
 	}
@@ -295,7 +380,7 @@ func EmptyS3BucketE(t testing.TestingT, region string, name string) error {
 		}
 	}
 	logger.Logf(t, "Bucket %s is now empty", name)
-	return err
+	return nil
 }
 
 // GetS3BucketLoggingTarget fetches the given bucket's logging target bucket and returns it as a string
@@ -375,6 +460,12 @@ func GetS3BucketVersioningE(t testing.TestingT, awsRegion string, bucket string)
 		return "", err
 	}
 
+	return GetS3BucketVersioningWithClientE(t, s3Client, bucket)
+}
+
+// GetS3BucketVersioningWithClientE fetches the given bucket's versioning configuration status and returns it as a string, with the ability to
+// provide the S3 client.
+func GetS3BucketVersioningWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucket string) (string, error) {
 	res, err := s3Client.GetBucketVersioning(&s3.GetBucketVersioningInput{
 		Bucket: &bucket,
 	})
@@ -400,6 +491,11 @@ func GetS3BucketPolicyE(t testing.TestingT, awsRegion string, bucket string) (st
 		return "", err
 	}
 
+	return GetS3BucketPolicyWithClientE(t, s3Client, bucket)
+}
+
+// GetS3BucketPolicyWithClientE fetches the given bucket's resource policy and returns it as a string, with the ability to provide the S3 client.
+func GetS3BucketPolicyWithClientE(t testing.TestingT, s3Client s3iface.S3API, bucket string) (string, error) {
 	res, err := s3Client.GetBucketPolicy(&s3.GetBucketPolicyInput{
 		Bucket: &bucket,
 	})
@@ -423,10 +519,15 @@ func AssertS3BucketExistsE(t testing.TestingT, region string, name string) error
 		return err
 	}
 
+	return AssertS3BucketExistsWithClientE(t, s3Client, name)
+}
+
+// AssertS3BucketExistsWithClientE checks if the given S3 bucket exists and return an error if it does not, with the ability to provide the S3 client.
+func AssertS3BucketExistsWithClientE(t testing.TestingT, s3Client s3iface.S3API, name string) error {
 	params := &s3.HeadBucketInput{
 		Bucket: aws.String(name),
 	}
-	_, err = s3Client.HeadBucket(params)
+	_, err := s3Client.HeadBucket(params)
 	return err
 }
 
@@ -438,12 +539,23 @@ func AssertS3BucketVersioningExists(t testing.TestingT, region string, bucketNam
 
 // AssertS3BucketVersioningExistsE checks if the given S3 bucket has a versioning configuration enabled and returns an error if it does not.
 func AssertS3BucketVersioningExistsE(t testing.TestingT, region string, bucketName string) error {
-	status, err := GetS3BucketVersioningE(t, region, bucketName)
+	s3Client, err := NewS3ClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	return AssertS3BucketVersioningExistsWithClientE(t, s3Client, region, bucketName)
+}
+
+// AssertS3BucketVersioningExistsWithClientE checks if the given S3 bucket has a versioning configuration enabled and returns an error if it
+// does not, with the ability to provide the S3 client.
+func AssertS3BucketVersioningExistsWithClientE(t testing.TestingT, s3Client s3iface.S3API, region string, bucketName string) error {
+	status, err := GetS3BucketVersioningWithClientE(t, s3Client, bucketName)
 	if err != nil {
 		return err
 	}
 
-	if status == "Enabled" {
+	if status == s3.BucketVersioningStatusEnabled {
 		return nil
 	}
 	return NewBucketVersioningNotEnabledError(bucketName, region, status)
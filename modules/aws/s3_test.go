@@ -3,6 +3,7 @@ package aws
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/random"
@@ -17,6 +19,116 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// fakeS3 returns canned tags for a single bucket.
+type fakeS3 struct {
+	s3iface.S3API
+	bucket string
+	tags   map[string]string
+}
+
+func (client fakeS3) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+	return &s3.ListBucketsOutput{Buckets: []*s3.Bucket{{Name: aws.String(client.bucket)}}}, nil
+}
+
+func (client fakeS3) GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+	if aws.StringValue(input.Bucket) != client.bucket {
+		return nil, fmt.Errorf("NoSuchBucket: %s", aws.StringValue(input.Bucket))
+	}
+	out := &s3.GetBucketTaggingOutput{}
+	for key, value := range client.tags {
+		out.TagSet = append(out.TagSet, &s3.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return out, nil
+}
+
+func TestS3HelpersWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := fakeS3{bucket: "terratest-bucket", tags: map[string]string{"Name": "terratest"}}
+
+	tags, err := GetS3BucketTagsWithClientE(t, client, "terratest-bucket")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Name": "terratest"}, tags)
+
+	bucket, err := FindS3BucketWithTagWithClientE(t, client, "Name", "terratest")
+	require.NoError(t, err)
+	assert.Equal(t, "terratest-bucket", bucket)
+}
+
+// fakeS3Objects stores the objects put into it in memory, and reports the given versioning status for every bucket.
+type fakeS3Objects struct {
+	s3iface.S3API
+	objects          map[string]string
+	versioningStatus string
+}
+
+func (client *fakeS3Objects) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	body, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	client.objects[aws.StringValue(input.Bucket)+"/"+aws.StringValue(input.Key)] = string(body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (client *fakeS3Objects) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	contents, exists := client.objects[aws.StringValue(input.Bucket)+"/"+aws.StringValue(input.Key)]
+	if !exists {
+		return nil, fmt.Errorf("NoSuchKey: %s", aws.StringValue(input.Key))
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(strings.NewReader(contents))}, nil
+}
+
+func (client *fakeS3Objects) GetBucketVersioning(input *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+	return &s3.GetBucketVersioningOutput{Status: aws.String(client.versioningStatus)}, nil
+}
+
+func TestS3ObjectHelpersWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeS3Objects{objects: map[string]string{}, versioningStatus: s3.BucketVersioningStatusSuspended}
+
+	require.NoError(t, PutS3ObjectContentsWithClientE(t, client, "terratest-bucket", "artifacts/output.txt", "hello"))
+	contents, err := GetS3ObjectContentsWithClientE(t, client, "terratest-bucket", "artifacts/output.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", contents)
+
+	err = AssertS3BucketVersioningExistsWithClientE(t, client, "us-east-1", "terratest-bucket")
+	assert.IsType(t, BucketVersioningNotEnabledError{}, err)
+	client.versioningStatus = s3.BucketVersioningStatusEnabled
+	assert.NoError(t, AssertS3BucketVersioningExistsWithClientE(t, client, "us-east-1", "terratest-bucket"))
+}
+
+// fakeS3Tagging records the tags that buckets are created with.
+type fakeS3Tagging struct {
+	s3iface.S3API
+	tags map[string]map[string]string
+}
+
+func (client *fakeS3Tagging) CreateBucket(input *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	client.tags[aws.StringValue(input.Bucket)] = map[string]string{}
+	return &s3.CreateBucketOutput{}, nil
+}
+
+func (client *fakeS3Tagging) PutBucketTagging(input *s3.PutBucketTaggingInput) (*s3.PutBucketTaggingOutput, error) {
+	for _, tag := range input.Tagging.TagSet {
+		client.tags[aws.StringValue(input.Bucket)][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+func TestCreateS3BucketWithClientTagsBucket(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeS3Tagging{tags: map[string]map[string]string{}}
+	require.NoError(t, CreateS3BucketWithClientE(t, client, "terratest-bucket"))
+
+	tags := client.tags["terratest-bucket"]
+	assert.Equal(t, CreatedByTagValue, tags[CreatedByTagKey])
+	assert.Equal(t, TestRunUniqueId(), tags[UniqueIdTagKey])
+	assert.Equal(t, t.Name(), tags[TestNameTagKey])
+}
+
 func TestCreateAndDestroyS3Bucket(t *testing.T) {
 	t.Parallel()
 
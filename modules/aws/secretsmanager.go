@@ -3,29 +3,46 @@ package aws
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
 
-// CreateSecretStringWithDefaultKey creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the secret ARN
+// CreateSecretStringWithDefaultKey creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the secret ARN.
+// The secret is tagged with the tags of GetTestTags.
 func CreateSecretStringWithDefaultKey(t testing.TestingT, awsRegion, description, name, secretString string) string {
 	arn, err := CreateSecretStringWithDefaultKeyE(t, awsRegion, description, name, secretString)
 	require.NoError(t, err)
 	return arn
 }
 
-// CreateSecretStringWithDefaultKeyE creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the secret ARN
+// CreateSecretStringWithDefaultKeyE creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the secret ARN.
+// The secret is tagged with the tags of GetTestTags.
 func CreateSecretStringWithDefaultKeyE(t testing.TestingT, awsRegion, description, name, secretString string) (string, error) {
-	logger.Logf(t, "Creating new secret in secrets manager named %s", name)
+	client, err := NewSecretsManagerClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	return CreateSecretStringWithDefaultKeyWithClientE(t, client, description, name, secretString)
+}
 
-	client := NewSecretsManagerClient(t, awsRegion)
+// CreateSecretStringWithDefaultKeyWithClientE creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the
+// secret ARN, with the ability to provide the Secrets Manager client.
+func CreateSecretStringWithDefaultKeyWithClientE(t testing.TestingT, client secretsmanageriface.SecretsManagerAPI, description, name, secretString string) (string, error) {
+	logger.Logf(t, "Creating new secret in secrets manager named %s", name)
 
-	secret, err := client.CreateSecret(&secretsmanager.CreateSecretInput{
+	input := &secretsmanager.CreateSecretInput{
 		Description:  aws.String(description),
 		Name:         aws.String(name),
 		SecretString: aws.String(secretString),
-	})
+	}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		input.Tags = append(input.Tags, &secretsmanager.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	secret, err := client.CreateSecret(input)
 
 	if err != nil {
 		return "", err
@@ -43,9 +60,18 @@ func GetSecretValue(t testing.TestingT, awsRegion, id string) string {
 
 // GetSecretValueE takes the friendly name or ARN of a secret and returns the plaintext value
 func GetSecretValueE(t testing.TestingT, awsRegion, id string) (string, error) {
-	logger.Logf(t, "Getting value of secret with ID %s", id)
+	client, err := NewSecretsManagerClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
 
-	client := NewSecretsManagerClient(t, awsRegion)
+	return GetSecretValueWithClientE(t, client, id)
+}
+
+// GetSecretValueWithClientE takes the friendly name or ARN of a secret and returns the plaintext value, with the ability to provide the Secrets
+// Manager client.
+func GetSecretValueWithClientE(t testing.TestingT, client secretsmanageriface.SecretsManagerAPI, id string) (string, error) {
+	logger.Logf(t, "Getting value of secret with ID %s", id)
 
 	secret, err := client.GetSecretValue(&secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(id),
@@ -57,6 +83,35 @@ func GetSecretValueE(t testing.TestingT, awsRegion, id string) (string, error) {
 	return aws.StringValue(secret.SecretString), nil
 }
 
+// PutSecretValue stores a new plaintext value in the secret with the given friendly name or ARN, e.g. to seed configuration that a template reads.
+func PutSecretValue(t testing.TestingT, awsRegion, id, secretString string) {
+	err := PutSecretValueE(t, awsRegion, id, secretString)
+	require.NoError(t, err)
+}
+
+// PutSecretValueE stores a new plaintext value in the secret with the given friendly name or ARN, e.g. to seed configuration that a template reads.
+func PutSecretValueE(t testing.TestingT, awsRegion, id, secretString string) error {
+	client, err := NewSecretsManagerClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	return PutSecretValueWithClientE(t, client, id, secretString)
+}
+
+// PutSecretValueWithClientE stores a new plaintext value in the secret with the given friendly name or ARN, with the ability to provide the
+// Secrets Manager client.
+func PutSecretValueWithClientE(t testing.TestingT, client secretsmanageriface.SecretsManagerAPI, id, secretString string) error {
+	logger.Logf(t, "Putting new value of secret with ID %s", id)
+
+	_, err := client.PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(id),
+		SecretString: aws.String(secretString),
+	})
+
+	return err
+}
+
 // DeleteSecret deletes a secret. If forceDelete is true, the secret will be deleted after a short delay. If forceDelete is false, the secret will be deleted after a 30 day recovery window.
 func DeleteSecret(t testing.TestingT, awsRegion, id string, forceDelete bool) {
 	err := DeleteSecretE(t, awsRegion, id, forceDelete)
@@ -65,9 +120,17 @@ func DeleteSecret(t testing.TestingT, awsRegion, id string, forceDelete bool) {
 
 // DeleteSecretE deletes a secret. If forceDelete is true, the secret will be deleted after a short delay. If forceDelete is false, the secret will be deleted after a 30 day recovery window.
 func DeleteSecretE(t testing.TestingT, awsRegion, id string, forceDelete bool) error {
-	logger.Logf(t, "Deleting secret with ID %s", id)
+	client, err := NewSecretsManagerClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	return DeleteSecretWithClientE(t, client, id, forceDelete)
+}
 
-	client := NewSecretsManagerClient(t, awsRegion)
+// DeleteSecretWithClientE deletes a secret, with the ability to provide the Secrets Manager client. See DeleteSecret for the meaning of forceDelete.
+func DeleteSecretWithClientE(t testing.TestingT, client secretsmanageriface.SecretsManagerAPI, id string, forceDelete bool) error {
+	logger.Logf(t, "Deleting secret with ID %s", id)
 
 	_, err := client.DeleteSecret(&secretsmanager.DeleteSecretInput{
 		ForceDeleteWithoutRecovery: aws.Bool(forceDelete),
@@ -3,6 +3,9 @@ package aws
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,6 +24,48 @@ func TestSecretsManagerMethods(t *testing.T) {
 
 	storedValue := GetSecretValue(t, region, secretARN)
 	assert.Equal(t, secretValue, storedValue)
+
+	PutSecretValue(t, region, secretARN, "This is the new secret value.")
+	assert.Equal(t, "This is the new secret value.", GetSecretValue(t, region, secretARN))
+}
+
+// fakeSecretsManager stores the values of the secrets it is given, and records their tags.
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+	values map[string]string
+	tags   map[string]string
+}
+
+func (client *fakeSecretsManager) CreateSecret(input *secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error) {
+	client.values[aws.StringValue(input.Name)] = aws.StringValue(input.SecretString)
+	for _, tag := range input.Tags {
+		client.tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &secretsmanager.CreateSecretOutput{ARN: aws.String("arn:" + aws.StringValue(input.Name))}, nil
+}
+
+func (client *fakeSecretsManager) PutSecretValue(input *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error) {
+	client.values[aws.StringValue(input.SecretId)] = aws.StringValue(input.SecretString)
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (client *fakeSecretsManager) GetSecretValue(input *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(client.values[aws.StringValue(input.SecretId)])}, nil
+}
+
+func TestSecretsManagerMethodsWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSecretsManager{values: map[string]string{}, tags: map[string]string{}}
+	arn, err := CreateSecretStringWithDefaultKeyWithClientE(t, client, "description", "my-secret", "value")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:my-secret", arn)
+	assert.Equal(t, CreatedByTagValue, client.tags[CreatedByTagKey])
+
+	require.NoError(t, PutSecretValueWithClientE(t, client, "my-secret", "new value"))
+	value, err := GetSecretValueWithClientE(t, client, "my-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "new value", value)
 }
 
 func deleteSecret(t *testing.T, region, id string) {
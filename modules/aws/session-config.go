@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+var (
+	configCustomizerMutex sync.Mutex
+	configCustomizer      func(config *aws.Config)
+)
+
+// SetConfigCustomizer sets a function that's called with the configuration of every session created by this package,
+// before the session is created, so it can be customized for all the AWS helpers at once. Use it to e.g. set a custom
+// Retryer, point the clients at a local Endpoint such as LocalStack, or swap in an HTTPClient that records requests.
+// Pass nil, the default, to use the configuration as is. This is typically called once from TestMain:
+//
+//	aws.SetConfigCustomizer(func(config *aws.Config) {
+//		config.Endpoint = aws.String("http://localhost:4566")
+//	})
+func SetConfigCustomizer(customizer func(config *aws.Config)) {
+	configCustomizerMutex.Lock()
+	defer configCustomizerMutex.Unlock()
+	configCustomizer = customizer
+}
+
+// newConfig returns the configuration for a new session in the given region, customized with the function set with
+// SetConfigCustomizer, if any.
+func newConfig(region string) *aws.Config {
+	config := aws.NewConfig().WithRegion(region)
+
+	configCustomizerMutex.Lock()
+	customizer := configCustomizer
+	configCustomizerMutex.Unlock()
+
+	if customizer != nil {
+		customizer(config)
+	}
+	return config
+}
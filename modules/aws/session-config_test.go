@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConfigCustomizer(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The config customizer is process-wide, so this test must not run while other tests are creating sessions.
+
+	SetConfigCustomizer(func(config *aws.Config) {
+		config.Endpoint = aws.String("http://localhost:4566")
+		config.MaxRetries = aws.Int(7)
+	})
+	defer SetConfigCustomizer(nil)
+
+	sess, err := CreateAwsSessionWithCreds("us-east-1", "AKIAEXAMPLE", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:4566", aws.StringValue(sess.Config.Endpoint))
+	assert.Equal(t, 7, aws.IntValue(sess.Config.MaxRetries))
+	assert.Equal(t, "us-east-1", aws.StringValue(sess.Config.Region))
+
+	SetConfigCustomizer(nil)
+	sess, err = CreateAwsSessionWithCreds("us-east-1", "AKIAEXAMPLE", "secret")
+	require.NoError(t, err)
+	assert.Nil(t, sess.Config.Endpoint)
+}
@@ -1,13 +1,19 @@
 package aws
 
 import (
+	"encoding/json"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/notify"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
-// CreateSnsTopic creates an SNS Topic and return the ARN.
+// CreateSnsTopic creates an SNS Topic tagged with the tags of GetTestTags and return the ARN.
 func CreateSnsTopic(t testing.TestingT, region string, snsTopicName string) string {
 	out, err := CreateSnsTopicE(t, region, snsTopicName)
 	if err != nil {
@@ -16,7 +22,7 @@ func CreateSnsTopic(t testing.TestingT, region string, snsTopicName string) stri
 	return out
 }
 
-// CreateSnsTopicE creates an SNS Topic and return the ARN.
+// CreateSnsTopicE creates an SNS Topic tagged with the tags of GetTestTags and return the ARN.
 func CreateSnsTopicE(t testing.TestingT, region string, snsTopicName string) (string, error) {
 	logger.Logf(t, "Creating SNS topic %s in %s", snsTopicName, region)
 
@@ -28,6 +34,9 @@ func CreateSnsTopicE(t testing.TestingT, region string, snsTopicName string) (st
 	createTopicInput := &sns.CreateTopicInput{
 		Name: &snsTopicName,
 	}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		createTopicInput.Tags = append(createTopicInput.Tags, &sns.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
 
 	output, err := snsClient.CreateTopic(createTopicInput)
 	if err != nil {
@@ -62,6 +71,151 @@ func DeleteSNSTopicE(t testing.TestingT, region string, snsTopicArn string) erro
 	return err
 }
 
+// PublishSnsMessage publishes the given message to the given SNS Topic and returns the ID of the message.
+func PublishSnsMessage(t testing.TestingT, region string, snsTopicArn string, message string) string {
+	messageId, err := PublishSnsMessageE(t, region, snsTopicArn, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messageId
+}
+
+// PublishSnsMessageE publishes the given message to the given SNS Topic and returns the ID of the message.
+func PublishSnsMessageE(t testing.TestingT, region string, snsTopicArn string, message string) (string, error) {
+	snsClient, err := NewSnsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	return PublishSnsMessageWithClientE(t, snsClient, snsTopicArn, message)
+}
+
+// PublishSnsMessageWithClientE publishes the given message to the given SNS Topic and returns the ID of the message,
+// with the ability to provide the SNS client.
+func PublishSnsMessageWithClientE(t testing.TestingT, snsClient snsiface.SNSAPI, snsTopicArn string, message string) (string, error) {
+	logger.Logf(t, "Publishing message %s to SNS topic %s", message, snsTopicArn)
+
+	output, err := snsClient.Publish(&sns.PublishInput{
+		TopicArn: aws.String(snsTopicArn),
+		Message:  aws.String(message),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(output.MessageId), nil
+}
+
+// SubscribeQueueToSnsTopic subscribes the SQS queue with the given URL to the given SNS Topic, and returns the ARN of
+// the subscription. This makes it possible to check what a template publishes to a topic, e.g.:
+//
+//	queueURL := aws.CreateRandomQueue(t, region, "sns-test")
+//	defer aws.DeleteQueue(t, region, queueURL)
+//	subscriptionArn := aws.SubscribeQueueToSnsTopic(t, region, topicArn, queueURL)
+//	defer aws.UnsubscribeFromSnsTopic(t, region, subscriptionArn)
+//
+//	aws.PublishSnsMessage(t, region, topicArn, "hello")
+//	message := aws.WaitForQueueMessage(t, region, queueURL, 60)
+//	assert.Equal(t, "hello", message.MessageBody)
+//
+// The queue policy is replaced with one that allows the topic to send messages to the queue, and raw message delivery
+// is enabled, so that the body of the queue messages is exactly the message that was published.
+func SubscribeQueueToSnsTopic(t testing.TestingT, region string, snsTopicArn string, queueURL string) string {
+	subscriptionArn, err := SubscribeQueueToSnsTopicE(t, region, snsTopicArn, queueURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subscriptionArn
+}
+
+// SubscribeQueueToSnsTopicE subscribes the SQS queue with the given URL to the given SNS Topic, and returns the ARN of
+// the subscription. See SubscribeQueueToSnsTopic for details.
+func SubscribeQueueToSnsTopicE(t testing.TestingT, region string, snsTopicArn string, queueURL string) (string, error) {
+	snsClient, err := NewSnsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	sqsClient, err := NewSqsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	return SubscribeQueueToSnsTopicWithClientsE(t, snsClient, sqsClient, snsTopicArn, queueURL)
+}
+
+// SubscribeQueueToSnsTopicWithClientsE subscribes the SQS queue with the given URL to the given SNS Topic, and returns
+// the ARN of the subscription, with the ability to provide the SNS and SQS clients. See SubscribeQueueToSnsTopic for
+// details.
+func SubscribeQueueToSnsTopicWithClientsE(t testing.TestingT, snsClient snsiface.SNSAPI, sqsClient sqsiface.SQSAPI, snsTopicArn string, queueURL string) (string, error) {
+	logger.Logf(t, "Subscribing SQS queue %s to SNS topic %s", queueURL, snsTopicArn)
+
+	attributes, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn}),
+	})
+	if err != nil {
+		return "", err
+	}
+	queueArn := aws.StringValue(attributes.Attributes[sqs.QueueAttributeNameQueueArn])
+
+	policy, err := json.Marshal(IamPolicyDocument{
+		Version: "2012-10-17",
+		Statement: []IamPolicyStatement{{
+			Effect:    "Allow",
+			Principal: IamPolicyPrincipal{"Service": {"sns.amazonaws.com"}},
+			Action:    IamPolicyValues{"sqs:SendMessage"},
+			Resource:  IamPolicyValues{queueArn},
+			Condition: map[string]interface{}{"ArnEquals": map[string]string{"aws:SourceArn": snsTopicArn}},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = sqsClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl:   aws.String(queueURL),
+		Attributes: map[string]*string{sqs.QueueAttributeNamePolicy: aws.String(string(policy))},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	output, err := snsClient.Subscribe(&sns.SubscribeInput{
+		TopicArn:              aws.String(snsTopicArn),
+		Protocol:              aws.String("sqs"),
+		Endpoint:              aws.String(queueArn),
+		Attributes:            map[string]*string{"RawMessageDelivery": aws.String("true")},
+		ReturnSubscriptionArn: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(output.SubscriptionArn), nil
+}
+
+// UnsubscribeFromSnsTopic deletes the SNS subscription with the given ARN.
+func UnsubscribeFromSnsTopic(t testing.TestingT, region string, subscriptionArn string) {
+	err := UnsubscribeFromSnsTopicE(t, region, subscriptionArn)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// UnsubscribeFromSnsTopicE deletes the SNS subscription with the given ARN.
+func UnsubscribeFromSnsTopicE(t testing.TestingT, region string, subscriptionArn string) error {
+	logger.Logf(t, "Deleting SNS subscription %s in %s", subscriptionArn, region)
+
+	snsClient, err := NewSnsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	_, err = snsClient.Unsubscribe(&sns.UnsubscribeInput{SubscriptionArn: aws.String(subscriptionArn)})
+	return err
+}
+
 // NewSnsClient creates a new SNS client.
 func NewSnsClient(t testing.TestingT, region string) *sns.SNS {
 	client, err := NewSnsClientE(t, region)
@@ -80,3 +234,38 @@ func NewSnsClientE(t testing.TestingT, region string) (*sns.SNS, error) {
 
 	return sns.New(sess), nil
 }
+
+// SnsNotifier is a notify.Notifier that publishes leaks to an SNS topic.
+type SnsNotifier struct {
+	Client   *sns.SNS
+	TopicArn string
+}
+
+// NewSnsNotifier returns an SnsNotifier that publishes leaks to the SNS topic with the given ARN in the given region.
+func NewSnsNotifier(t testing.TestingT, region string, topicArn string) *SnsNotifier {
+	notifier, err := NewSnsNotifierE(t, region, topicArn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notifier
+}
+
+// NewSnsNotifierE returns an SnsNotifier that publishes leaks to the SNS topic with the given ARN in the given region.
+func NewSnsNotifierE(t testing.TestingT, region string, topicArn string) (*SnsNotifier, error) {
+	client, err := NewSnsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return &SnsNotifier{Client: client, TopicArn: topicArn}, nil
+}
+
+// NotifyLeak publishes the given leak to the SNS topic.
+func (notifier *SnsNotifier) NotifyLeak(leak notify.Leak) error {
+	_, err := notifier.Client.Publish(&sns.PublishInput{
+		TopicArn: aws.String(notifier.TopicArn),
+		// Subjects are limited to 100 characters, so the test name is left to the message
+		Subject: aws.String("Terratest: resources might have leaked"),
+		Message: aws.String(leak.Message()),
+	})
+	return err
+}
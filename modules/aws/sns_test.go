@@ -7,8 +7,12 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateAndDeleteSnsTopic(t *testing.T) {
@@ -24,6 +28,51 @@ func TestCreateAndDeleteSnsTopic(t *testing.T) {
 	assert.True(t, snsTopicExists(t, region, arn))
 }
 
+// fakeSnsSubscribe records the subscriptions it was asked to create.
+type fakeSnsSubscribe struct {
+	snsiface.SNSAPI
+	subscribed *sns.SubscribeInput
+}
+
+func (client *fakeSnsSubscribe) Subscribe(input *sns.SubscribeInput) (*sns.SubscribeOutput, error) {
+	client.subscribed = input
+	return &sns.SubscribeOutput{SubscriptionArn: aws.String("arn:aws:sns:us-east-1:123456789012:topic:abc")}, nil
+}
+
+// fakeSqsQueuePolicy returns the given queue ARN and records the queue policy it was asked to set.
+type fakeSqsQueuePolicy struct {
+	sqsiface.SQSAPI
+	queueArn string
+	policy   string
+}
+
+func (client *fakeSqsQueuePolicy) GetQueueAttributes(input *sqs.GetQueueAttributesInput) (*sqs.GetQueueAttributesOutput, error) {
+	return &sqs.GetQueueAttributesOutput{Attributes: map[string]*string{sqs.QueueAttributeNameQueueArn: aws.String(client.queueArn)}}, nil
+}
+
+func (client *fakeSqsQueuePolicy) SetQueueAttributes(input *sqs.SetQueueAttributesInput) (*sqs.SetQueueAttributesOutput, error) {
+	client.policy = aws.StringValue(input.Attributes[sqs.QueueAttributeNamePolicy])
+	return &sqs.SetQueueAttributesOutput{}, nil
+}
+
+func TestSubscribeQueueToSnsTopicWithClients(t *testing.T) {
+	t.Parallel()
+
+	snsClient := &fakeSnsSubscribe{}
+	sqsClient := &fakeSqsQueuePolicy{queueArn: "arn:aws:sqs:us-east-1:123456789012:queue"}
+	subscriptionArn, err := SubscribeQueueToSnsTopicWithClientsE(t, snsClient, sqsClient, "arn:aws:sns:us-east-1:123456789012:topic", "https://queue")
+	require.NoError(t, err)
+
+	assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:topic:abc", subscriptionArn)
+	assert.Equal(t, "sqs", aws.StringValue(snsClient.subscribed.Protocol))
+	assert.Equal(t, sqsClient.queueArn, aws.StringValue(snsClient.subscribed.Endpoint))
+	assert.Equal(t, "true", aws.StringValue(snsClient.subscribed.Attributes["RawMessageDelivery"]))
+
+	policy := ParseIamPolicyDocument(t, sqsClient.policy)
+	assert.True(t, policy.IsActionAllowed("sqs:SendMessage", sqsClient.queueArn))
+	assert.Equal(t, IamPolicyPrincipal{"Service": {"sns.amazonaws.com"}}, policy.Statement[0].Principal)
+}
+
 func snsTopicExists(t *testing.T, region string, arn string) bool {
 	snsClient := NewSnsClient(t, region)
 
@@ -7,12 +7,14 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/google/uuid"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
 // CreateRandomQueue creates a new SQS queue with a random name that starts with the given prefix and return the queue URL.
+// The queue is tagged with the tags of GetTestTags.
 func CreateRandomQueue(t testing.TestingT, awsRegion string, prefix string) string {
 	url, err := CreateRandomQueueE(t, awsRegion, prefix)
 	if err != nil {
@@ -22,6 +24,7 @@ func CreateRandomQueue(t testing.TestingT, awsRegion string, prefix string) stri
 }
 
 // CreateRandomQueueE creates a new SQS queue with a random name that starts with the given prefix and return the queue URL.
+// The queue is tagged with the tags of GetTestTags.
 func CreateRandomQueueE(t testing.TestingT, awsRegion string, prefix string) (string, error) {
 	logger.Logf(t, "Creating randomly named SQS queue with prefix %s", prefix)
 
@@ -39,6 +42,7 @@ func CreateRandomQueueE(t testing.TestingT, awsRegion string, prefix string) (st
 
 	queue, err := sqsClient.CreateQueue(&sqs.CreateQueueInput{
 		QueueName: aws.String(channelName),
+		Tags:      aws.StringMap(GetTestTags(t, testRunUniqueId)),
 	})
 
 	if err != nil {
@@ -49,6 +53,7 @@ func CreateRandomQueueE(t testing.TestingT, awsRegion string, prefix string) (st
 }
 
 // CreateRandomFifoQueue creates a new FIFO SQS queue with a random name that starts with the given prefix and return the queue URL.
+// The queue is tagged with the tags of GetTestTags.
 func CreateRandomFifoQueue(t testing.TestingT, awsRegion string, prefix string) string {
 	url, err := CreateRandomFifoQueueE(t, awsRegion, prefix)
 	if err != nil {
@@ -58,6 +63,7 @@ func CreateRandomFifoQueue(t testing.TestingT, awsRegion string, prefix string)
 }
 
 // CreateRandomFifoQueueE creates a new FIFO SQS queue with a random name that starts with the given prefix and return the queue URL.
+// The queue is tagged with the tags of GetTestTags.
 func CreateRandomFifoQueueE(t testing.TestingT, awsRegion string, prefix string) (string, error) {
 	logger.Logf(t, "Creating randomly named FIFO SQS queue with prefix %s", prefix)
 
@@ -79,6 +85,7 @@ func CreateRandomFifoQueueE(t testing.TestingT, awsRegion string, prefix string)
 			"ContentBasedDeduplication": aws.String("true"),
 			"FifoQueue":                 aws.String("true"),
 		},
+		Tags: aws.StringMap(GetTestTags(t, testRunUniqueId)),
 	})
 
 	if err != nil {
@@ -112,6 +119,38 @@ func DeleteQueueE(t testing.TestingT, awsRegion string, queueURL string) error {
 	return err
 }
 
+// PurgeQueue deletes all the messages in the SQS queue with the given URL, e.g. to reuse a queue across test cases.
+// SQS only allows one purge per queue every 60 seconds.
+func PurgeQueue(t testing.TestingT, awsRegion string, queueURL string) {
+	err := PurgeQueueE(t, awsRegion, queueURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// PurgeQueueE deletes all the messages in the SQS queue with the given URL, e.g. to reuse a queue across test cases.
+// SQS only allows one purge per queue every 60 seconds.
+func PurgeQueueE(t testing.TestingT, awsRegion string, queueURL string) error {
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	return PurgeQueueWithClientE(t, sqsClient, queueURL)
+}
+
+// PurgeQueueWithClientE deletes all the messages in the SQS queue with the given URL, with the ability to provide the
+// SQS client.
+func PurgeQueueWithClientE(t testing.TestingT, sqsClient sqsiface.SQSAPI, queueURL string) error {
+	logger.Logf(t, "Purging SQS Queue %s", queueURL)
+
+	_, err := sqsClient.PurgeQueue(&sqs.PurgeQueueInput{
+		QueueUrl: aws.String(queueURL),
+	})
+
+	return err
+}
+
 // DeleteMessageFromQueue deletes the message with the given receipt from the SQS queue with the given URL.
 func DeleteMessageFromQueue(t testing.TestingT, awsRegion string, queueURL string, receipt string) {
 	err := DeleteMessageFromQueueE(t, awsRegion, queueURL, receipt)
@@ -216,12 +255,23 @@ type QueueMessageResponse struct {
 }
 
 // WaitForQueueMessage waits to receive a message from on the queueURL. Since the API only allows us to wait a max 20 seconds for a new
-// message to arrive, we must loop TIMEOUT/20 number of times to be able to wait for a total of TIMEOUT seconds
+// message to arrive, we must loop TIMEOUT/20 number of times to be able to wait for a total of TIMEOUT seconds. Any error
+// is returned in the Error field of the response.
 func WaitForQueueMessage(t testing.TestingT, awsRegion string, queueURL string, timeout int) QueueMessageResponse {
-	sqsClient, err := NewSqsClientE(t, awsRegion)
+	response, err := WaitForQueueMessageE(t, awsRegion, queueURL, timeout)
 	if err != nil {
 		return QueueMessageResponse{Error: err}
 	}
+	return response
+}
+
+// WaitForQueueMessageE waits to receive a message from on the queueURL. Since the API only allows us to wait a max 20 seconds for a new
+// message to arrive, we must loop TIMEOUT/20 number of times to be able to wait for a total of TIMEOUT seconds
+func WaitForQueueMessageE(t testing.TestingT, awsRegion string, queueURL string, timeout int) (QueueMessageResponse, error) {
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return QueueMessageResponse{}, err
+	}
 
 	cycles := timeout
 	cycleLength := 1
@@ -241,16 +291,16 @@ func WaitForQueueMessage(t testing.TestingT, awsRegion string, queueURL string,
 		})
 
 		if err != nil {
-			return QueueMessageResponse{Error: err}
+			return QueueMessageResponse{}, err
 		}
 
 		if len(result.Messages) > 0 {
 			logger.Logf(t, "Message %s received on %s", *result.Messages[0].MessageId, queueURL)
-			return QueueMessageResponse{ReceiptHandle: *result.Messages[0].ReceiptHandle, MessageBody: *result.Messages[0].Body}
+			return QueueMessageResponse{ReceiptHandle: *result.Messages[0].ReceiptHandle, MessageBody: *result.Messages[0].Body}, nil
 		}
 	}
 
-	return QueueMessageResponse{Error: ReceiveMessageTimeout{QueueUrl: queueURL, TimeoutSec: timeout}}
+	return QueueMessageResponse{}, ReceiveMessageTimeout{QueueUrl: queueURL, TimeoutSec: timeout}
 }
 
 // NewSqsClient creates a new SQS client.
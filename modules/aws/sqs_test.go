@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
 )
@@ -66,6 +67,26 @@ func TestFifoSqsQueueMethods(t *testing.T) {
 	assert.Error(t, secondResponse.Error, ReceiveMessageTimeout{QueueUrl: url, TimeoutSec: timeoutSec})
 }
 
+// fakeSqsPurge records the queues it was asked to purge.
+type fakeSqsPurge struct {
+	sqsiface.SQSAPI
+	purged []string
+}
+
+func (client *fakeSqsPurge) PurgeQueue(input *sqs.PurgeQueueInput) (*sqs.PurgeQueueOutput, error) {
+	client.purged = append(client.purged, aws.StringValue(input.QueueUrl))
+	return &sqs.PurgeQueueOutput{}, nil
+}
+
+func TestPurgeQueueWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSqsPurge{}
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/my-queue"
+	assert.NoError(t, PurgeQueueWithClientE(t, client, queueURL))
+	assert.Equal(t, []string{queueURL}, client.purged)
+}
+
 func queueExists(t *testing.T, region string, url string) bool {
 	sqsClient := NewSqsClient(t, region)
 
@@ -1,11 +1,13 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
@@ -30,7 +32,7 @@ func GetParameterE(t testing.TestingT, awsRegion string, keyName string) (string
 }
 
 // GetParameterE retrieves the latest version of SSM Parameter at keyName with decryption with the ability to provide the SSM client.
-func GetParameterWithClientE(t testing.TestingT, client *ssm.SSM, keyName string) (string, error) {
+func GetParameterWithClientE(t testing.TestingT, client ssmiface.SSMAPI, keyName string) (string, error) {
 	resp, err := client.GetParameter(&ssm.GetParameterInput{Name: aws.String(keyName), WithDecryption: aws.Bool(true)})
 	if err != nil {
 		return "", err
@@ -57,7 +59,7 @@ func PutParameterE(t testing.TestingT, awsRegion string, keyName string, keyDesc
 }
 
 // PutParameterE creates new version of SSM Parameter at keyName with keyValue as SecureString with the ability to provide the SSM client.
-func PutParameterWithClientE(t testing.TestingT, client *ssm.SSM, keyName string, keyDescription string, keyValue string) (int64, error) {
+func PutParameterWithClientE(t testing.TestingT, client ssmiface.SSMAPI, keyName string, keyDescription string, keyValue string) (int64, error) {
 	resp, err := client.PutParameter(&ssm.PutParameterInput{Name: aws.String(keyName), Description: aws.String(keyDescription), Value: aws.String(keyValue), Type: aws.String("SecureString")})
 	if err != nil {
 		return 0, err
@@ -66,6 +68,38 @@ func PutParameterWithClientE(t testing.TestingT, client *ssm.SSM, keyName string
 	return *resp.Version, nil
 }
 
+// GetParametersByPath retrieves the latest versions of all the SSM Parameters under the given path (e.g. /my-app/), recursively and with
+// decryption, keyed by parameter name. Use it to check the parameters a template writes.
+func GetParametersByPath(t testing.TestingT, awsRegion string, path string) map[string]string {
+	parameters, err := GetParametersByPathE(t, awsRegion, path)
+	require.NoError(t, err)
+	return parameters
+}
+
+// GetParametersByPathE retrieves the latest versions of all the SSM Parameters under the given path (e.g. /my-app/), recursively and with
+// decryption, keyed by parameter name.
+func GetParametersByPathE(t testing.TestingT, awsRegion string, path string) (map[string]string, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	return GetParametersByPathWithClientE(t, ssmClient, path)
+}
+
+// GetParametersByPathWithClientE retrieves the latest versions of all the SSM Parameters under the given path, recursively and with decryption,
+// keyed by parameter name, with the ability to provide the SSM client.
+func GetParametersByPathWithClientE(t testing.TestingT, client ssmiface.SSMAPI, path string) (map[string]string, error) {
+	parameters := map[string]string{}
+	input := &ssm.GetParametersByPathInput{Path: aws.String(path), Recursive: aws.Bool(true), WithDecryption: aws.Bool(true)}
+	err := client.GetParametersByPathPages(input, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, parameter := range page.Parameters {
+			parameters[aws.StringValue(parameter.Name)] = aws.StringValue(parameter.Value)
+		}
+		return true
+	})
+	return parameters, err
+}
+
 // DeleteParameter deletes all versions of SSM Parameter at keyName.
 func DeleteParameter(t testing.TestingT, awsRegion string, keyName string) {
 	err := DeleteParameterE(t, awsRegion, keyName)
@@ -82,7 +116,7 @@ func DeleteParameterE(t testing.TestingT, awsRegion string, keyName string) erro
 }
 
 // DeleteParameterE deletes all versions of SSM Parameter at keyName with the ability to provide the SSM client.
-func DeleteParameterWithClientE(t testing.TestingT, client *ssm.SSM, keyName string) error {
+func DeleteParameterWithClientE(t testing.TestingT, client ssmiface.SSMAPI, keyName string) error {
 	_, err := client.DeleteParameter(&ssm.DeleteParameterInput{Name: aws.String(keyName)})
 	if err != nil {
 		return err
@@ -118,7 +152,13 @@ func WaitForSsmInstanceE(t testing.TestingT, awsRegion, instanceID string, timeo
 }
 
 // WaitForSsmInstanceE waits until the instance get registered to the SSM inventory with the ability to provide the SSM client.
-func WaitForSsmInstanceWithClientE(t testing.TestingT, client *ssm.SSM, instanceID string, timeout time.Duration) error {
+func WaitForSsmInstanceWithClientE(t testing.TestingT, client ssmiface.SSMAPI, instanceID string, timeout time.Duration) error {
+	return WaitForSsmInstanceWithClientContextE(context.Background(), t, client, instanceID, timeout)
+}
+
+// WaitForSsmInstanceWithClientContextE works like WaitForSsmInstanceWithClientE, but stops waiting, and aborts the
+// pending API call, as soon as the given context is done.
+func WaitForSsmInstanceWithClientContextE(ctx context.Context, t testing.TestingT, client ssmiface.SSMAPI, instanceID string, timeout time.Duration) error {
 	timeBetweenRetries := 2 * time.Second
 	maxRetries := int(timeout.Seconds() / timeBetweenRetries.Seconds())
 	description := fmt.Sprintf("Waiting for %s to appear in the SSM inventory", instanceID)
@@ -132,8 +172,8 @@ func WaitForSsmInstanceWithClientE(t testing.TestingT, client *ssm.SSM, instance
 			},
 		},
 	}
-	_, err := retry.DoWithRetryE(t, description, maxRetries, timeBetweenRetries, func() (string, error) {
-		resp, err := client.GetInventory(input)
+	_, err := retry.DoWithRetryContextE(ctx, t, description, maxRetries, timeBetweenRetries, func() (string, error) {
+		resp, err := client.GetInventoryWithContext(ctx, input)
 
 		if err != nil {
 			return "", err
@@ -173,7 +213,7 @@ func CheckSsmCommandE(t testing.TestingT, awsRegion, instanceID, command string,
 }
 
 // CheckSSMCommandWithClientE checks that you can run the given command on the given instance through AWS SSM with the ability to provide the SSM client. Returns the result and an error if one occurs.
-func CheckSSMCommandWithClientE(t testing.TestingT, client *ssm.SSM, instanceID, command string, timeout time.Duration) (*CommandOutput, error) {
+func CheckSSMCommandWithClientE(t testing.TestingT, client ssmiface.SSMAPI, instanceID, command string, timeout time.Duration) (*CommandOutput, error) {
 	return CheckSSMCommandWithClientWithDocumentE(t, client, instanceID, command, "AWS-RunShellScript", timeout)
 }
 
@@ -197,12 +237,17 @@ func CheckSsmCommandWithDocumentE(t testing.TestingT, awsRegion, instanceID, com
 }
 
 // CheckSSMCommandWithClientWithDocumentE checks that you can run the given command on the given instance through AWS SSM with the ability to provide the SSM client with specified Command Doc type. Returns the result and an error if one occurs.
-func CheckSSMCommandWithClientWithDocumentE(t testing.TestingT, client *ssm.SSM, instanceID, command string, commandDocName string, timeout time.Duration) (*CommandOutput, error) {
+func CheckSSMCommandWithClientWithDocumentE(t testing.TestingT, client ssmiface.SSMAPI, instanceID, command string, commandDocName string, timeout time.Duration) (*CommandOutput, error) {
+	return CheckSSMCommandWithClientWithDocumentContextE(context.Background(), t, client, instanceID, command, commandDocName, timeout)
+}
 
+// CheckSSMCommandWithClientWithDocumentContextE works like CheckSSMCommandWithClientWithDocumentE, but stops waiting
+// for the result, and aborts the pending API call, as soon as the given context is done.
+func CheckSSMCommandWithClientWithDocumentContextE(ctx context.Context, t testing.TestingT, client ssmiface.SSMAPI, instanceID, command string, commandDocName string, timeout time.Duration) (*CommandOutput, error) {
 	timeBetweenRetries := 2 * time.Second
 	maxRetries := int(timeout.Seconds() / timeBetweenRetries.Seconds())
 
-	resp, err := client.SendCommand(&ssm.SendCommandInput{
+	resp, err := client.SendCommandWithContext(ctx, &ssm.SendCommandInput{
 		Comment:      aws.String("Terratest SSM"),
 		DocumentName: aws.String(commandDocName),
 		InstanceIds:  aws.StringSlice([]string{instanceID}),
@@ -224,8 +269,8 @@ func CheckSSMCommandWithClientWithDocumentE(t testing.TestingT, client *ssm.SSM,
 	}
 
 	result := &CommandOutput{}
-	_, err = retry.DoWithRetryableErrorsE(t, description, retryableErrors, maxRetries, timeBetweenRetries, func() (string, error) {
-		resp, err := client.GetCommandInvocation(&ssm.GetCommandInvocationInput{
+	_, err = retry.DoWithRetryableErrorsContextE(ctx, t, description, retryableErrors, maxRetries, timeBetweenRetries, func() (string, error) {
+		resp, err := client.GetCommandInvocationWithContext(ctx, &ssm.GetCommandInvocationInput{
 			CommandId:  resp.Command.CommandId,
 			InstanceId: &instanceID,
 		})
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
@@ -38,3 +41,30 @@ func TestParameterIsDeleted(t *testing.T) {
 	assert.Equal(t, actualValue, "")
 	assert.Error(t, err)
 }
+
+// fakeSsmParameters returns the given pages of parameters.
+type fakeSsmParameters struct {
+	ssmiface.SSMAPI
+	pages [][]*ssm.Parameter
+}
+
+func (client *fakeSsmParameters) GetParametersByPathPages(input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool) error {
+	for i, page := range client.pages {
+		if !fn(&ssm.GetParametersByPathOutput{Parameters: page}, i == len(client.pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestGetParametersByPathWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSsmParameters{pages: [][]*ssm.Parameter{
+		{{Name: aws.String("/my-app/db/host"), Value: aws.String("db.example.com")}},
+		{{Name: aws.String("/my-app/db/port"), Value: aws.String("5432")}},
+	}}
+	parameters, err := GetParametersByPathWithClientE(t, client, "/my-app/")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"/my-app/db/host": "db.example.com", "/my-app/db/port": "5432"}, parameters)
+}
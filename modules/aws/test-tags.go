@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// These are the tags that mark a resource as created by a test, so that resources leaked by killed test runs can be
+// found and deleted later (see the reaper package).
+const (
+	CreatedByTagKey   = "created-by"
+	CreatedByTagValue = "terratest"
+	UniqueIdTagKey    = "UniqueId"  // The random.UniqueId of the test run that created the resource
+	CreatedAtTagKey   = "CreatedAt" // When the resource was created, formatted as RFC 3339
+	TestNameTagKey    = "TestName"  // The name of the test that created the resource
+)
+
+// testRunUniqueId is the UniqueId that the helpers in this package tag the resources they create with.
+var testRunUniqueId = random.UniqueId()
+
+// TestRunUniqueId returns the UniqueId that the helpers in this package (e.g. CreateS3Bucket or ImportEC2KeyPair) tag
+// the resources they create with. It is generated once per run of the test binary.
+func TestRunUniqueId() string {
+	return testRunUniqueId
+}
+
+// GetTestTags returns the tags that mark a resource as created by the given test with the given UniqueId. The helpers
+// in this package tag the resources they create with these tags. To tag the resources of a Terraform module too, pass
+// them to the module as a variable, e.g. one that is used as the default_tags of the AWS provider:
+//
+//	terraformOptions := terraform.NewOptions("../examples/vpc", terraform.WithVars(map[string]interface{}{
+//	    "tags": aws.GetTestTags(t, uniqueId),
+//	}))
+func GetTestTags(t testing.TestingT, uniqueId string) map[string]string {
+	return map[string]string{
+		CreatedByTagKey: CreatedByTagValue,
+		UniqueIdTagKey:  uniqueId,
+		CreatedAtTagKey: time.Now().UTC().Format(time.RFC3339),
+		TestNameTagKey:  t.Name(),
+	}
+}
+
+// getEc2TestTagSpecifications returns the TagSpecifications to tag an EC2 resource of the given type with the tags of
+// GetTestTags when creating it.
+func getEc2TestTagSpecifications(t testing.TestingT, resourceType string) []*ec2.TagSpecification {
+	tagSpecification := &ec2.TagSpecification{ResourceType: aws.String(resourceType)}
+	for key, value := range GetTestTags(t, testRunUniqueId) {
+		tagSpecification.Tags = append(tagSpecification.Tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return []*ec2.TagSpecification{tagSpecification}
+}
@@ -0,0 +1,39 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTestTags(t *testing.T) {
+	t.Parallel()
+
+	tags := GetTestTags(t, "abc123")
+	assert.Equal(t, CreatedByTagValue, tags[CreatedByTagKey])
+	assert.Equal(t, "abc123", tags[UniqueIdTagKey])
+	assert.Equal(t, "TestGetTestTags", tags[TestNameTagKey])
+
+	createdAt, err := time.Parse(time.RFC3339, tags[CreatedAtTagKey])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), createdAt, time.Minute)
+}
+
+func TestGetEc2TestTagSpecifications(t *testing.T) {
+	t.Parallel()
+
+	tagSpecifications := getEc2TestTagSpecifications(t, ec2.ResourceTypeKeyPair)
+	require.Len(t, tagSpecifications, 1)
+	assert.Equal(t, ec2.ResourceTypeKeyPair, aws.StringValue(tagSpecifications[0].ResourceType))
+
+	tags := map[string]string{}
+	for _, tag := range tagSpecifications[0].Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	assert.Equal(t, TestRunUniqueId(), tags[UniqueIdTagKey])
+	assert.Len(t, TestRunUniqueId(), 6)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
@@ -217,23 +218,41 @@ func IsPublicSubnet(t testing.TestingT, subnetId string, region string) bool {
 
 // IsPublicSubnetE returns True if the subnet identified by the given id in the provided region is public.
 func IsPublicSubnetE(t testing.TestingT, subnetId string, region string) (bool, error) {
-	subnetIdFilterName := "association.subnet-id"
-
-	subnetIdFilter := ec2.Filter{
-		Name:   &subnetIdFilterName,
-		Values: []*string{&subnetId},
-	}
-
 	client, err := NewEc2ClientE(t, region)
 	if err != nil {
 		return false, err
 	}
 
+	return IsPublicSubnetWithClientE(t, client, subnetId)
+}
+
+// IsPublicSubnetWithClientE returns True if the subnet identified by the given id is public, i.e. if its route table
+// has a route to an Internet Gateway, with the ability to provide the EC2 client. Subnets that aren't explicitly
+// associated with a route table use the main route table of their VPC.
+func IsPublicSubnetWithClientE(t testing.TestingT, client ec2iface.EC2API, subnetId string) (bool, error) {
+	subnetIdFilter := ec2.Filter{Name: aws.String("association.subnet-id"), Values: []*string{&subnetId}}
 	rts, err := client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{Filters: []*ec2.Filter{&subnetIdFilter}})
 	if err != nil {
 		return false, err
 	}
 
+	if len(rts.RouteTables) == 0 {
+		subnets, err := client.DescribeSubnets(&ec2.DescribeSubnetsInput{SubnetIds: []*string{&subnetId}})
+		if err != nil {
+			return false, err
+		}
+		if len(subnets.Subnets) == 0 {
+			return false, NewNotFoundError("Subnet", subnetId, getEc2ClientRegion(client))
+		}
+
+		vpcIdFilter := ec2.Filter{Name: aws.String(vpcIDFilterName), Values: []*string{subnets.Subnets[0].VpcId}}
+		mainFilter := ec2.Filter{Name: aws.String("association.main"), Values: []*string{aws.String("true")}}
+		rts, err = client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{Filters: []*ec2.Filter{&vpcIdFilter, &mainFilter}})
+		if err != nil {
+			return false, err
+		}
+	}
+
 	for _, rt := range rts.RouteTables {
 		for _, r := range rt.Routes {
 			if strings.HasPrefix(aws.StringValue(r.GatewayId), "igw-") {
@@ -315,9 +334,21 @@ func GetRandomPrivateCidrBlock(routingPrefix int) string {
 	return fmt.Sprintf("%d.%d.%d.%d/%d", o1, o2, o3, o4, routingPrefix)
 }
 
-// GetFirstTwoOctets gets the first two octets from a CIDR block.
+// GetFirstTwoOctets gets the first two octets from a CIDR block. This will panic if the CIDR block is malformed.
 func GetFirstTwoOctets(cidrBlock string) string {
+	octets, err := GetFirstTwoOctetsE(cidrBlock)
+	if err != nil {
+		panic(err)
+	}
+	return octets
+}
+
+// GetFirstTwoOctetsE gets the first two octets from a CIDR block.
+func GetFirstTwoOctetsE(cidrBlock string) (string, error) {
 	ipAddr := strings.Split(cidrBlock, "/")[0]
 	octets := strings.Split(ipAddr, ".")
-	return octets[0] + "." + octets[1]
+	if len(octets) < 2 {
+		return "", InvalidCidrBlock{CidrBlock: cidrBlock}
+	}
+	return octets[0] + "." + octets[1], nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 )
 
 func TestGetDefaultVpc(t *testing.T) {
@@ -61,6 +62,13 @@ func TestGetFirstTwoOctets(t *testing.T) {
 	}
 }
 
+func TestGetFirstTwoOctetsEInvalidCidrBlock(t *testing.T) {
+	t.Parallel()
+
+	_, err := GetFirstTwoOctetsE("not-a-cidr-block")
+	assert.Equal(t, InvalidCidrBlock{CidrBlock: "not-a-cidr-block"}, err)
+}
+
 func TestIsPublicSubnet(t *testing.T) {
 	t.Parallel()
 
@@ -76,6 +84,39 @@ func TestIsPublicSubnet(t *testing.T) {
 	assert.True(t, IsPublicSubnet(t, *subnet.SubnetId, region))
 }
 
+// fakeMainRouteTable returns no explicitly associated route tables, so that subnets use the given main route table.
+type fakeMainRouteTable struct {
+	ec2iface.EC2API
+	mainRouteTable *ec2.RouteTable
+}
+
+func (client *fakeMainRouteTable) DescribeRouteTables(input *ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error) {
+	for _, filter := range input.Filters {
+		if aws.StringValue(filter.Name) == "association.main" {
+			return &ec2.DescribeRouteTablesOutput{RouteTables: []*ec2.RouteTable{client.mainRouteTable}}, nil
+		}
+	}
+	return &ec2.DescribeRouteTablesOutput{}, nil
+}
+
+func (client *fakeMainRouteTable) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{{SubnetId: input.SubnetIds[0], VpcId: aws.String("vpc-1")}}}, nil
+}
+
+func TestIsPublicSubnetWithClientUsesMainRouteTable(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeMainRouteTable{mainRouteTable: &ec2.RouteTable{Routes: []*ec2.Route{{GatewayId: aws.String("igw-1")}}}}
+	isPublic, err := IsPublicSubnetWithClientE(t, client, "subnet-1")
+	require.NoError(t, err)
+	assert.True(t, isPublic)
+
+	client.mainRouteTable.Routes = []*ec2.Route{{GatewayId: aws.String("local")}}
+	isPublic, err = IsPublicSubnetWithClientE(t, client, "subnet-1")
+	require.NoError(t, err)
+	assert.False(t, isPublic)
+}
+
 func TestGetTagsForVpc(t *testing.T) {
 	t.Parallel()
 
@@ -288,6 +288,42 @@ func ListVirtualMachinesForResourceGroupE(resourceGroupName string, subscription
 	return vmDetails, nil
 }
 
+// ListVirtualMachinesByTag gets a list of the names of the Virtual Machines in the specified Resource Group that have
+// the given tag with the given value.
+// This function would fail the test if there is an error.
+func ListVirtualMachinesByTag(t testing.TestingT, tagName string, tagValue string, resGroupName string, subscriptionID string) []string {
+	vms, err := ListVirtualMachinesByTagE(tagName, tagValue, resGroupName, subscriptionID)
+	require.NoError(t, err)
+	return vms
+}
+
+// ListVirtualMachinesByTagE gets a list of the names of the Virtual Machines in the specified Resource Group that have
+// the given tag with the given value.
+func ListVirtualMachinesByTagE(tagName string, tagValue string, resourceGroupName string, subscriptionID string) ([]string, error) {
+	vmNames := []string{}
+
+	vmClient, err := GetVirtualMachineClientE(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Go through all the pages of VMs, as a Resource Group may contain more VMs than fit in a single page
+	vms, err := vmClient.ListComplete(context.Background(), resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+	for vms.NotDone() {
+		vm := vms.Value()
+		if value, hasTag := vm.Tags[tagName]; hasTag && value != nil && *value == tagValue {
+			vmNames = append(vmNames, *vm.Name)
+		}
+		if err := vms.NextWithContext(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return vmNames, nil
+}
+
 // GetVirtualMachinesForResourceGroup gets all Virtual Machine objects in the specified Resource Group. Each
 // VM Object represents the entire set of VM compute properties accessible by using the VM name as the map key.
 // This function would fail the test if there is an error.
@@ -40,6 +40,17 @@ func TestListVirtualMachinesForResourceGroupE(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestListVirtualMachinesByTagE(t *testing.T) {
+	t.Parallel()
+
+	rgName := ""
+	subID := ""
+
+	_, err := ListVirtualMachinesByTagE("tagName", "tagValue", rgName, subID)
+
+	require.Error(t, err)
+}
+
 func TestGetVirtualMachinesForResourceGroupE(t *testing.T) {
 	t.Parallel()
 
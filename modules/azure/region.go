@@ -65,6 +65,17 @@ var stableRegions = []string{
 // those that have been around for at least 1 year.
 // Note that regions in the approvedRegions list that are not considered stable are ignored.
 func GetRandomStableRegion(t testing.TestingT, approvedRegions []string, forbiddenRegions []string, subscriptionID string) string {
+	region, err := GetRandomStableRegionE(t, approvedRegions, forbiddenRegions, subscriptionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return region
+}
+
+// GetRandomStableRegionE gets a randomly chosen Azure region that is considered stable. Like GetRandomRegionE, you can
+// further restrict the stable region list using approvedRegions and forbiddenRegions.
+// Note that regions in the approvedRegions list that are not considered stable are ignored.
+func GetRandomStableRegionE(t testing.TestingT, approvedRegions []string, forbiddenRegions []string, subscriptionID string) (string, error) {
 	regionsToPickFrom := stableRegions
 	if len(approvedRegions) > 0 {
 		regionsToPickFrom = collections.ListIntersection(regionsToPickFrom, approvedRegions)
@@ -72,7 +83,7 @@ func GetRandomStableRegion(t testing.TestingT, approvedRegions []string, forbidd
 	if len(forbiddenRegions) > 0 {
 		regionsToPickFrom = collections.ListSubtract(regionsToPickFrom, forbiddenRegions)
 	}
-	return GetRandomRegion(t, regionsToPickFrom, nil, subscriptionID)
+	return GetRandomRegionE(t, regionsToPickFrom, nil, subscriptionID)
 }
 
 // GetRandomRegion gets a randomly chosen Azure region. If approvedRegions is not empty, this will be a region from the approvedRegions
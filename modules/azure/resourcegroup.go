@@ -40,6 +40,51 @@ func GetResourceGroupE(resourceGroupName, subscriptionID string) (bool, error) {
 	return (resourceGroupName == *rg.Name), nil
 }
 
+// CreateResourceGroup creates a resource group with the given name in the given location, so that a test can deploy
+// its resources into a resource group of its own and delete them all at once with DeleteResourceGroup.
+// This function would fail the test if there is an error.
+func CreateResourceGroup(t *testing.T, resourceGroupName string, location string, subscriptionID string) *resources.Group {
+	rg, err := CreateResourceGroupE(resourceGroupName, location, subscriptionID)
+	require.NoError(t, err)
+	return rg
+}
+
+// CreateResourceGroupE creates a resource group with the given name in the given location, so that a test can deploy
+// its resources into a resource group of its own and delete them all at once with DeleteResourceGroupE.
+func CreateResourceGroupE(resourceGroupName string, location string, subscriptionID string) (*resources.Group, error) {
+	client, err := CreateResourceGroupClientE(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rg, err := client.CreateOrUpdate(context.Background(), resourceGroupName, resources.Group{Location: &location})
+	if err != nil {
+		return nil, err
+	}
+	return &rg, nil
+}
+
+// DeleteResourceGroup deletes a resource group, and all the resources in it, and waits for the deletion to complete.
+// This function would fail the test if there is an error.
+func DeleteResourceGroup(t *testing.T, resourceGroupName string, subscriptionID string) {
+	err := DeleteResourceGroupE(resourceGroupName, subscriptionID)
+	require.NoError(t, err)
+}
+
+// DeleteResourceGroupE deletes a resource group, and all the resources in it, and waits for the deletion to complete.
+func DeleteResourceGroupE(resourceGroupName, subscriptionID string) error {
+	client, err := CreateResourceGroupClientE(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	future, err := client.Delete(context.Background(), resourceGroupName)
+	if err != nil {
+		return err
+	}
+	return future.WaitForCompletionRef(context.Background(), client.Client)
+}
+
 // GetResourceGroupClientE gets a resource group client in a subscription
 // TODO: remove in next version
 func GetResourceGroupClientE(subscriptionID string) (*resources.GroupsClient, error) {
@@ -13,7 +13,6 @@ import (
 
 /*
 The below tests are currently stubbed out, with the expectation that they will throw errors.
-If/when methods to create and delete resource groups are added, these tests can be extended.
 */
 
 func TestResourceGroupExists(t *testing.T) {
@@ -32,3 +31,22 @@ func TestGetAResourceGroup(t *testing.T) {
 	_, err := GetAResourceGroupE(resourceGroupName, "")
 	require.Error(t, err)
 }
+
+func TestCreateResourceGroupE(t *testing.T) {
+	t.Parallel()
+
+	resourceGroupName := ""
+	location := ""
+
+	_, err := CreateResourceGroupE(resourceGroupName, location, "")
+	require.Error(t, err)
+}
+
+func TestDeleteResourceGroupE(t *testing.T) {
+	t.Parallel()
+
+	resourceGroupName := "fakeResourceGroupName"
+
+	err := DeleteResourceGroupE(resourceGroupName, "")
+	require.Error(t, err)
+}
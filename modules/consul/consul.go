@@ -0,0 +1,130 @@
+// Package consul allows to check the health of Consul clusters through the Consul HTTP API.
+package consul
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// MemberStatusAlive is the serf status of a Consul member that is alive.
+const MemberStatusAlive = 1
+
+// Member represents one agent in a Consul cluster, as returned by /v1/agent/members.
+type Member struct {
+	Name   string
+	Addr   string
+	Port   int
+	Status int
+	Tags   map[string]string
+}
+
+// IsServer returns true if the member is a Consul server (as opposed to a client agent).
+func (member Member) IsServer() bool {
+	return member.Tags["role"] == "consul"
+}
+
+// GetMembers returns the members of the Consul cluster that the agent at the given address (e.g.
+// http://1.2.3.4:8500) belongs to.
+func GetMembers(t testing.TestingT, consulAddr string, tlsConfig *tls.Config) []Member {
+	members, err := GetMembersE(t, consulAddr, tlsConfig)
+	require.NoError(t, err)
+	return members
+}
+
+// GetMembersE returns the members of the Consul cluster that the agent at the given address (e.g.
+// http://1.2.3.4:8500) belongs to.
+func GetMembersE(t testing.TestingT, consulAddr string, tlsConfig *tls.Config) ([]Member, error) {
+	members := []Member{}
+	if err := getJsonE(t, fmt.Sprintf("%s/v1/agent/members", consulAddr), tlsConfig, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetLeader returns the address of the Raft leader of the Consul cluster, or an empty string if there is no leader.
+func GetLeader(t testing.TestingT, consulAddr string, tlsConfig *tls.Config) string {
+	leader, err := GetLeaderE(t, consulAddr, tlsConfig)
+	require.NoError(t, err)
+	return leader
+}
+
+// GetLeaderE returns the address of the Raft leader of the Consul cluster, or an empty string if there is no leader.
+func GetLeaderE(t testing.TestingT, consulAddr string, tlsConfig *tls.Config) (string, error) {
+	var leader string
+	if err := getJsonE(t, fmt.Sprintf("%s/v1/status/leader", consulAddr), tlsConfig, &leader); err != nil {
+		return "", err
+	}
+	return leader, nil
+}
+
+// WaitForClusterSize waits until the Consul cluster has elected a leader and has at least the expected number of alive
+// servers and clients, failing the test if that does not happen after the given number of retries.
+func WaitForClusterSize(t testing.TestingT, consulAddr string, tlsConfig *tls.Config, expectedServers int, expectedClients int, retries int, sleepBetweenRetries time.Duration) {
+	err := WaitForClusterSizeE(t, consulAddr, tlsConfig, expectedServers, expectedClients, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForClusterSizeE waits until the Consul cluster has elected a leader and has at least the expected number of
+// alive servers and clients, returning an error if that does not happen after the given number of retries.
+func WaitForClusterSizeE(t testing.TestingT, consulAddr string, tlsConfig *tls.Config, expectedServers int, expectedClients int, retries int, sleepBetweenRetries time.Duration) error {
+	description := fmt.Sprintf("Waiting for Consul cluster at %s to have %d servers and %d clients", consulAddr, expectedServers, expectedClients)
+	_, err := retry.DoWithRetryE(t, description, retries, sleepBetweenRetries, func() (string, error) {
+		leader, err := GetLeaderE(t, consulAddr, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+		if leader == "" {
+			return "", NoLeader(consulAddr)
+		}
+
+		members, err := GetMembersE(t, consulAddr, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+
+		servers, clients := countAliveMembers(members)
+		if servers < expectedServers || clients < expectedClients {
+			return "", ClusterSizeNotMet{ExpectedServers: expectedServers, ExpectedClients: expectedClients, ActualServers: servers, ActualClients: clients}
+		}
+
+		logger.Logf(t, "Consul cluster at %s has leader %s, %d servers, and %d clients", consulAddr, leader, servers, clients)
+		return "", nil
+	})
+	return err
+}
+
+// countAliveMembers returns the number of alive servers and alive clients in the given list of members.
+func countAliveMembers(members []Member) (int, int) {
+	servers := 0
+	clients := 0
+	for _, member := range members {
+		if member.Status != MemberStatusAlive {
+			continue
+		}
+		if member.IsServer() {
+			servers++
+		} else {
+			clients++
+		}
+	}
+	return servers, clients
+}
+
+func getJsonE(t testing.TestingT, url string, tlsConfig *tls.Config, v interface{}) error {
+	statusCode, body, err := http_helper.HttpGetE(t, url, tlsConfig)
+	if err != nil {
+		return err
+	}
+	if statusCode != 200 {
+		return UnexpectedResponse{Url: url, Status: statusCode, Body: body}
+	}
+	return json.Unmarshal([]byte(body), v)
+}
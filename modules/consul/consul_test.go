@@ -0,0 +1,65 @@
+package consul
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const membersJson = `[
+	{"Name": "server-1", "Addr": "10.0.0.1", "Port": 8301, "Status": 1, "Tags": {"role": "consul"}},
+	{"Name": "server-2", "Addr": "10.0.0.2", "Port": 8301, "Status": 1, "Tags": {"role": "consul"}},
+	{"Name": "server-3", "Addr": "10.0.0.3", "Port": 8301, "Status": 4, "Tags": {"role": "consul"}},
+	{"Name": "client-1", "Addr": "10.0.0.4", "Port": 8301, "Status": 1, "Tags": {"role": "node"}}
+]`
+
+func newFakeConsulServer(leader string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/agent/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, membersJson)
+	})
+	mux.HandleFunc("/v1/status/leader", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%q", leader)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGetMembers(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeConsulServer("10.0.0.1:8300")
+	defer server.Close()
+
+	members := GetMembers(t, server.URL, nil)
+	require.Len(t, members, 4)
+	assert.Equal(t, "server-1", members[0].Name)
+	assert.True(t, members[0].IsServer())
+	assert.False(t, members[3].IsServer())
+}
+
+func TestWaitForClusterSize(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeConsulServer("10.0.0.1:8300")
+	defer server.Close()
+
+	assert.NoError(t, WaitForClusterSizeE(t, server.URL, nil, 2, 1, 1, time.Millisecond))
+
+	err := WaitForClusterSizeE(t, server.URL, nil, 3, 1, 1, time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestWaitForClusterSizeNoLeader(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeConsulServer("")
+	defer server.Close()
+
+	err := WaitForClusterSizeE(t, server.URL, nil, 1, 0, 1, time.Millisecond)
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package consul
+
+import "fmt"
+
+// NoLeader is an error that occurs when the Consul cluster has not elected a leader.
+type NoLeader string
+
+func (err NoLeader) Error() string {
+	return fmt.Sprintf("Consul cluster at %s has no leader", string(err))
+}
+
+// ClusterSizeNotMet is an error that occurs when the Consul cluster does not have the expected number of members.
+type ClusterSizeNotMet struct {
+	ExpectedServers int
+	ExpectedClients int
+	ActualServers   int
+	ActualClients   int
+}
+
+func (err ClusterSizeNotMet) Error() string {
+	return fmt.Sprintf("Expected %d alive servers and %d alive clients, but found %d servers and %d clients", err.ExpectedServers, err.ExpectedClients, err.ActualServers, err.ActualClients)
+}
+
+// UnexpectedResponse is an error that occurs when the Consul HTTP API returns a non-200 response.
+type UnexpectedResponse struct {
+	Url    string
+	Status int
+	Body   string
+}
+
+func (err UnexpectedResponse) Error() string {
+	return fmt.Sprintf("Unexpected response from %s. Response status: %d. Response body:\n%s", err.Url, err.Status, err.Body)
+}
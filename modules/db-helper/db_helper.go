@@ -0,0 +1,244 @@
+// Package db_helper contains helpers to connect to deployed databases, run queries, and check the results.
+package db_helper
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// DbType is the type of database to connect to.
+type DbType string
+
+const (
+	// DbTypeMySql is used for MySQL, MariaDB, and Aurora MySQL databases.
+	DbTypeMySql DbType = "mysql"
+
+	// DbTypePostgres is used for Postgres and Aurora Postgres databases. No Postgres driver is bundled with this
+	// package, so your test must import one that registers itself as "postgres" (e.g. _ "github.com/lib/pq").
+	DbTypePostgres DbType = "postgres"
+)
+
+// DbConfig holds the settings to connect to a database. To connect through a tunnel (e.g., a k8s.Tunnel or an SSH
+// port forward), set Host and Port to the local end of the tunnel.
+type DbConfig struct {
+	Type     DbType
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	Params   map[string]string // Extra connection parameters (e.g. "sslmode": "disable" for Postgres or "tls": "true" for MySQL)
+}
+
+// ConnectionString returns the connection string the database driver expects for this config.
+func (config DbConfig) ConnectionString() (string, error) {
+	address := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+
+	switch config.Type {
+	case DbTypeMySql:
+		connectionString := fmt.Sprintf("%s:%s@tcp(%s)/%s", config.User, config.Password, address, config.Database)
+		if query := encodeParams(config.Params); query != "" {
+			connectionString += "?" + query
+		}
+		return connectionString, nil
+	case DbTypePostgres:
+		connectionUrl := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.User, config.Password),
+			Host:     address,
+			Path:     "/" + config.Database,
+			RawQuery: encodeParams(config.Params),
+		}
+		return connectionUrl.String(), nil
+	default:
+		return "", UnsupportedDbType(config.Type)
+	}
+}
+
+// encodeParams encodes the given params as a URL query string, sorted by key so the result is stable.
+func encodeParams(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(params[key]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// Connect opens a connection to the database with the given config and verifies it is reachable. The caller is
+// responsible for closing the returned connection. If there's any error, fail the test.
+func Connect(t testing.TestingT, config DbConfig) *sql.DB {
+	db, err := ConnectE(t, config)
+	require.NoError(t, err)
+	return db
+}
+
+// ConnectE opens a connection to the database with the given config and verifies it is reachable. The caller is
+// responsible for closing the returned connection.
+func ConnectE(t testing.TestingT, config DbConfig) (*sql.DB, error) {
+	connectionString, err := config.ConnectionString()
+	if err != nil {
+		return nil, err
+	}
+
+	if !collections.ListContains(sql.Drivers(), string(config.Type)) {
+		return nil, DriverNotRegistered(config.Type)
+	}
+
+	logger.Logf(t, "Connecting to %s database %s on %s:%d as %s", config.Type, config.Database, config.Host, config.Port, config.User)
+
+	db, err := sql.Open(string(config.Type), connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Query runs the given query against the database with the given config and returns the rows it returned, with each
+// column formatted as a string (NULL is returned as an empty string). If there's any error, fail the test.
+func Query(t testing.TestingT, config DbConfig, query string, args ...interface{}) [][]string {
+	rows, err := QueryE(t, config, query, args...)
+	require.NoError(t, err)
+	return rows
+}
+
+// QueryE runs the given query against the database with the given config and returns the rows it returned, with each
+// column formatted as a string (NULL is returned as an empty string).
+func QueryE(t testing.TestingT, config DbConfig, query string, args ...interface{}) ([][]string, error) {
+	db, err := ConnectE(t, config)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	logger.Logf(t, "Running query: %s", query)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsAsStrings(rows)
+}
+
+// scanRowsAsStrings reads all the given rows, formatting each column as a string.
+func scanRowsAsStrings(rows *sql.Rows) ([][]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := [][]string{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = value.String
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// QueryWithCustomValidation runs the given query against the database with the given config and validates the
+// returned rows using the given function. If there's any error, fail the test.
+func QueryWithCustomValidation(t testing.TestingT, config DbConfig, query string, validateRows func([][]string) bool) {
+	err := QueryWithCustomValidationE(t, config, query, validateRows)
+	require.NoError(t, err)
+}
+
+// QueryWithCustomValidationE runs the given query against the database with the given config and validates the
+// returned rows using the given function.
+func QueryWithCustomValidationE(t testing.TestingT, config DbConfig, query string, validateRows func([][]string) bool) error {
+	rows, err := QueryE(t, config, query)
+	if err != nil {
+		return err
+	}
+
+	if !validateRows(rows) {
+		return ValidationFunctionFailed{Query: query, Rows: rows}
+	}
+
+	return nil
+}
+
+// QueryWithRetry repeatedly runs the given query against the database with the given config until it returns the
+// expected rows or until max retries has been exceeded. If it never does, fail the test.
+func QueryWithRetry(t testing.TestingT, config DbConfig, query string, expectedRows [][]string, retries int, sleepBetweenRetries time.Duration) {
+	err := QueryWithRetryE(t, config, query, expectedRows, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// QueryWithRetryE repeatedly runs the given query against the database with the given config until it returns the
+// expected rows or until max retries has been exceeded.
+func QueryWithRetryE(t testing.TestingT, config DbConfig, query string, expectedRows [][]string, retries int, sleepBetweenRetries time.Duration) error {
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Query %s", query), retries, sleepBetweenRetries, func() (string, error) {
+		rows, err := QueryE(t, config, query)
+		if err != nil {
+			return "", err
+		}
+
+		if !reflect.DeepEqual(rows, expectedRows) {
+			return "", UnexpectedQueryResult{Query: query, Expected: expectedRows, Actual: rows}
+		}
+
+		return "", nil
+	})
+
+	return err
+}
+
+// QueryWithRetryWithCustomValidation repeatedly runs the given query against the database with the given config
+// until the given validation function returns true or until max retries has been exceeded. If it never does, fail
+// the test.
+func QueryWithRetryWithCustomValidation(t testing.TestingT, config DbConfig, query string, retries int, sleepBetweenRetries time.Duration, validateRows func([][]string) bool) {
+	err := QueryWithRetryWithCustomValidationE(t, config, query, retries, sleepBetweenRetries, validateRows)
+	require.NoError(t, err)
+}
+
+// QueryWithRetryWithCustomValidationE repeatedly runs the given query against the database with the given config
+// until the given validation function returns true or until max retries has been exceeded.
+func QueryWithRetryWithCustomValidationE(t testing.TestingT, config DbConfig, query string, retries int, sleepBetweenRetries time.Duration, validateRows func([][]string) bool) error {
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Query %s", query), retries, sleepBetweenRetries, func() (string, error) {
+		return "", QueryWithCustomValidationE(t, config, query, validateRows)
+	})
+
+	return err
+}
@@ -0,0 +1,38 @@
+package db_helper
+
+import "fmt"
+
+// UnsupportedDbType is an error that occurs when a DbConfig has a database type this package does not support.
+type UnsupportedDbType DbType
+
+func (err UnsupportedDbType) Error() string {
+	return fmt.Sprintf("Unsupported database type %q. Use DbTypeMySql or DbTypePostgres.", string(err))
+}
+
+// DriverNotRegistered is an error that occurs when no database/sql driver is registered for the database type.
+type DriverNotRegistered DbType
+
+func (err DriverNotRegistered) Error() string {
+	return fmt.Sprintf("No database driver is registered for %q. Import one in your test (e.g. _ \"github.com/lib/pq\" for postgres).", string(err))
+}
+
+// UnexpectedQueryResult is an error that occurs when a query does not return the expected rows.
+type UnexpectedQueryResult struct {
+	Query    string
+	Expected [][]string
+	Actual   [][]string
+}
+
+func (err UnexpectedQueryResult) Error() string {
+	return fmt.Sprintf("Query %q returned %v, but expected %v", err.Query, err.Actual, err.Expected)
+}
+
+// ValidationFunctionFailed is an error that occurs if a validation function fails.
+type ValidationFunctionFailed struct {
+	Query string
+	Rows  [][]string
+}
+
+func (err ValidationFunctionFailed) Error() string {
+	return fmt.Sprintf("Validation failed for query %q. Returned rows: %v", err.Query, err.Rows)
+}
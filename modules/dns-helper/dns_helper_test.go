@@ -384,3 +384,43 @@ func shutDownServers(t *testing.T, s1, s2 *dnsTestServer) {
 	err = s2.Server.Shutdown()
 	assert.NoError(t, err)
 }
+
+// Propagation wait should succeed when all resolvers answer with the expected value
+func TestOkWaitForDnsPropagation(t *testing.T) {
+	t.Parallel()
+	s1, s2 := setupTestDNSServers(t)
+	defer shutDownServers(t, s1, s2)
+	dnsQuery := DNSQuery{"CNAME", "app." + testDomain}
+	s1.AddEntryToDNSDatabase(dnsQuery, DNSAnswers{{"CNAME", "lb.example.com."}})
+	s2.AddEntryToDNSDatabase(dnsQuery, DNSAnswers{{"CNAME", "lb.example.com."}})
+	err := WaitForDnsPropagationE(t, dnsQuery, "lb.example.com", []string{s1.Address(), s2.Address()}, time.Second)
+	require.NoError(t, err)
+}
+
+// Propagation wait should fail listing the resolver that never answered with the expected value
+func TestErrorWaitForDnsPropagation(t *testing.T) {
+	t.Parallel()
+	s1, s2 := setupTestDNSServers(t)
+	defer shutDownServers(t, s1, s2)
+	dnsQuery := DNSQuery{"A", "app." + testDomain}
+	s1.AddEntryToDNSDatabase(dnsQuery, DNSAnswers{{"A", "1.1.1.1"}})
+	s2.AddEntryToDNSDatabase(dnsQuery, DNSAnswers{{"A", "2.2.2.2"}})
+	err := WaitForDnsPropagationE(t, dnsQuery, "1.1.1.1", []string{s1.Address(), s2.Address()}, time.Second)
+	require.Error(t, err)
+	notPropagatedErr, ok := err.(*NotPropagatedError)
+	require.True(t, ok, "unexpected error, got %q", err)
+	assert.Equal(t, []string{s2.Address()}, notPropagatedErr.Resolvers)
+}
+
+// Propagation wait should stop right away on a query that can never succeed
+func TestErrorWaitForDnsPropagationQueryType(t *testing.T) {
+	t.Parallel()
+	s1, s2 := setupTestDNSServers(t)
+	defer shutDownServers(t, s1, s2)
+	dnsQuery := DNSQuery{"SOA", testDomain}
+	err := WaitForDnsPropagationE(t, dnsQuery, "ns1."+testDomain, []string{s1.Address(), s2.Address()}, time.Minute)
+	require.Error(t, err)
+	fatalErr, ok := err.(retry.FatalError)
+	require.True(t, ok, "unexpected error, got %q", err)
+	assert.IsType(t, &QueryTypeError{}, fatalErr.Underlying)
+}
@@ -1,6 +1,9 @@
 package dns_helper
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // NoResolversError is an error that occurs if no resolvers have been set for DNSLookupE
 type NoResolversError struct{}
@@ -70,3 +73,15 @@ type ValidationError struct {
 func (err ValidationError) Error() string {
 	return fmt.Sprintf("Unexpected answer to DNS query %s. Got: %s Expected: %s", err.Query, err.Answers, err.ExpectedAnswers)
 }
+
+// NotPropagatedError is an error that occurs when a DNS record has not propagated to all resolvers before the timeout
+type NotPropagatedError struct {
+	Query         DNSQuery
+	ExpectedValue string
+	Resolvers     []string
+	Timeout       time.Duration
+}
+
+func (err NotPropagatedError) Error() string {
+	return fmt.Sprintf("DNS query %s did not return %s from resolvers %v within %s", err.Query, err.ExpectedValue, err.Resolvers, err.Timeout)
+}
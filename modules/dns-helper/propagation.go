@@ -0,0 +1,96 @@
+package dns_helper
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// PublicResolvers are the public DNS resolvers WaitForDnsPropagation polls when no resolvers are given: Google,
+// Cloudflare, Quad9, and OpenDNS.
+var PublicResolvers = []string{"8.8.8.8", "1.1.1.1", "9.9.9.9", "208.67.222.222"}
+
+// dnsPropagationPollInterval is how long WaitForDnsPropagation waits between polls of the resolvers.
+const dnsPropagationPollInterval = 5 * time.Second
+
+// WaitForDnsPropagation polls each of the given resolvers (or PublicResolvers if none are given) until ALL of them
+// answer the query with the expected value, or until the timeout is exceeded. Fails on any error from
+// WaitForDnsPropagationE.
+func WaitForDnsPropagation(t testing.TestingT, query DNSQuery, expectedValue string, resolvers []string, timeout time.Duration) {
+	err := WaitForDnsPropagationE(t, query, expectedValue, resolvers, timeout)
+	require.NoError(t, err)
+}
+
+// WaitForDnsPropagationE polls each of the given resolvers (or PublicResolvers if none are given) until ALL of them
+// answer the query with the expected value, or until the timeout is exceeded. Trailing dots and TXT quotes are ignored
+// when comparing values. Returns NotPropagatedError, listing the resolvers that never answered as expected, when the
+// timeout is exceeded. Lookup errors that won't go away by waiting, such as QueryTypeError, are returned right away,
+// wrapped in a retry.FatalError.
+func WaitForDnsPropagationE(t testing.TestingT, query DNSQuery, expectedValue string, resolvers []string, timeout time.Duration) error {
+	if len(resolvers) == 0 {
+		resolvers = PublicResolvers
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pendingResolvers, err := resolversWithoutValue(t, query, expectedValue, resolvers)
+		if err != nil {
+			return err
+		}
+		if len(pendingResolvers) == 0 {
+			logger.Logf(t, "%s record for %s with value %s has propagated to all resolvers", query.Type, query.Name, expectedValue)
+			return nil
+		}
+
+		if time.Now().Add(dnsPropagationPollInterval).After(deadline) {
+			return &NotPropagatedError{Query: query, ExpectedValue: expectedValue, Resolvers: pendingResolvers, Timeout: timeout}
+		}
+
+		logger.Logf(t, "%s record for %s has not propagated to resolvers %v yet. Sleeping for %s and will try again.", query.Type, query.Name, pendingResolvers, dnsPropagationPollInterval)
+		time.Sleep(dnsPropagationPollInterval)
+	}
+}
+
+// resolversWithoutValue returns the resolvers whose answers to the query do not include the expected value. Lookup
+// errors count as not propagated yet, except for the ones that won't go away by waiting, which are returned wrapped in a
+// retry.FatalError.
+func resolversWithoutValue(t testing.TestingT, query DNSQuery, expectedValue string, resolvers []string) ([]string, error) {
+	var pendingResolvers []string
+
+	for _, resolver := range resolvers {
+		answers, err := dnsLookup(t, query, resolver)
+		if isFatalDNSLookupError(err) {
+			return nil, retry.FatalError{Underlying: err}
+		}
+		if err != nil || !answersContainValue(answers, expectedValue) {
+			pendingResolvers = append(pendingResolvers, resolver)
+		}
+	}
+
+	return pendingResolvers, nil
+}
+
+// isFatalDNSLookupError returns true if the given lookup error is caused by the query itself rather than by the state
+// of the resolver, so sending the same query again will never succeed.
+func isFatalDNSLookupError(err error) bool {
+	_, isQueryTypeErr := err.(*QueryTypeError)
+	return isQueryTypeErr
+}
+
+// answersContainValue returns true if any of the answers has the given value, ignoring trailing dots and TXT quotes.
+func answersContainValue(answers DNSAnswers, value string) bool {
+	for _, answer := range answers {
+		if normalizeDNSValue(answer.Value) == normalizeDNSValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDNSValue(value string) string {
+	return strings.TrimSuffix(strings.Trim(value, `"`), ".")
+}
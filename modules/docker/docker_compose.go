@@ -1,7 +1,13 @@
 package docker
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/shell"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
@@ -11,6 +17,9 @@ import (
 type Options struct {
 	WorkingDir string
 	EnvVars    map[string]string
+	// The docker-compose project name. Defaults to the name of the test, so that containers from multiple different
+	// tests using Docker Compose don't end up in the same project and end up conflicting with each other.
+	ProjectName string
 	// Set a logger that should be used. See the logger package for more info.
 	Logger *logger.Logger
 }
@@ -31,6 +40,11 @@ func RunDockerComposeAndGetStdOut(t testing.TestingT, options *Options, args ...
 	return out
 }
 
+// RunDockerComposeAndGetStdOutE runs docker-compose with the given arguments and options and returns only stdout.
+func RunDockerComposeAndGetStdOutE(t testing.TestingT, options *Options, args ...string) (string, error) {
+	return runDockerComposeE(t, true, options, args...)
+}
+
 // RunDockerComposeE runs docker-compose with the given arguments and options and return stdout/stderr.
 func RunDockerComposeE(t testing.TestingT, options *Options, args ...string) (string, error) {
 	return runDockerComposeE(t, false, options, args...)
@@ -38,17 +52,130 @@ func RunDockerComposeE(t testing.TestingT, options *Options, args ...string) (st
 
 func runDockerComposeE(t testing.TestingT, stdout bool, options *Options, args ...string) (string, error) {
 	cmd := shell.Command{
-		Command: "docker-compose",
-		// We append --project-name to ensure containers from multiple different tests using Docker Compose don't end
-		// up in the same project and end up conflicting with each other.
-		Args:       append([]string{"--project-name", t.Name()}, args...),
+		Command:    "docker-compose",
+		Args:       append([]string{"--project-name", getProjectName(t, options)}, args...),
 		WorkingDir: options.WorkingDir,
 		Env:        options.EnvVars,
 		Logger:     options.Logger,
 	}
 
 	if stdout {
-		return shell.RunCommandAndGetStdOut(t, cmd), nil
+		return shell.RunCommandAndGetStdOutE(t, cmd)
 	}
 	return shell.RunCommandAndGetOutputE(t, cmd)
 }
+
+// invalidProjectNameChars matches the characters that are not allowed in a docker-compose project name.
+var invalidProjectNameChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// getProjectName returns the docker-compose project name to use for the given options. docker-compose only accepts
+// lowercase letters, digits, dashes, and underscores, so the test name (e.g. TestFoo/subtest) is converted accordingly.
+func getProjectName(t testing.TestingT, options *Options) string {
+	name := options.ProjectName
+	if name == "" {
+		name = t.Name()
+	}
+	return invalidProjectNameChars.ReplaceAllString(strings.ToLower(name), "_")
+}
+
+// DockerComposeUp runs 'docker-compose up -d' with the given options and any additional arguments (e.g. the names of
+// the services to start), and returns stdout/stderr. This method fails the test if there are any errors.
+func DockerComposeUp(t testing.TestingT, options *Options, args ...string) string {
+	out, err := DockerComposeUpE(t, options, args...)
+	require.NoError(t, err)
+	return out
+}
+
+// DockerComposeUpE runs 'docker-compose up -d' with the given options and any additional arguments (e.g. the names of
+// the services to start), and returns stdout/stderr.
+func DockerComposeUpE(t testing.TestingT, options *Options, args ...string) (string, error) {
+	return RunDockerComposeE(t, options, append([]string{"up", "-d"}, args...)...)
+}
+
+// DockerComposeDown runs 'docker-compose down' with the given options, removing the containers, networks, and volumes
+// of the project, and returns stdout/stderr. This method fails the test if there are any errors.
+func DockerComposeDown(t testing.TestingT, options *Options) string {
+	out, err := DockerComposeDownE(t, options)
+	require.NoError(t, err)
+	return out
+}
+
+// DockerComposeDownE runs 'docker-compose down' with the given options, removing the containers, networks, and volumes
+// of the project, and returns stdout/stderr.
+func DockerComposeDownE(t testing.TestingT, options *Options) (string, error) {
+	return RunDockerComposeE(t, options, "down", "--volumes", "--remove-orphans")
+}
+
+// GetDockerComposeLogs returns the logs of the given services of the project, or of all of its services if none are
+// given. This method fails the test if there are any errors.
+func GetDockerComposeLogs(t testing.TestingT, options *Options, services ...string) string {
+	out, err := GetDockerComposeLogsE(t, options, services...)
+	require.NoError(t, err)
+	return out
+}
+
+// GetDockerComposeLogsE returns the logs of the given services of the project, or of all of its services if none are
+// given.
+func GetDockerComposeLogsE(t testing.TestingT, options *Options, services ...string) (string, error) {
+	return RunDockerComposeAndGetStdOutE(t, options, append([]string{"logs", "--no-color"}, services...)...)
+}
+
+// WaitForDockerComposeHealthy waits until all the containers of the project are healthy, retrying the check up to
+// maxRetries times, with sleepBetweenRetries in between. Containers without a health check only need to be running.
+// This method fails the test if the containers don't become healthy in time.
+func WaitForDockerComposeHealthy(t testing.TestingT, options *Options, maxRetries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitForDockerComposeHealthyE(t, options, maxRetries, sleepBetweenRetries))
+}
+
+// WaitForDockerComposeHealthyE waits until all the containers of the project are healthy, retrying the check up to
+// maxRetries times, with sleepBetweenRetries in between. Containers without a health check only need to be running.
+func WaitForDockerComposeHealthyE(t testing.TestingT, options *Options, maxRetries int, sleepBetweenRetries time.Duration) error {
+	description := fmt.Sprintf("Waiting for the containers of docker-compose project %s to be healthy", getProjectName(t, options))
+	_, err := retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		out, err := RunDockerComposeAndGetStdOutE(t, options, "ps", "--quiet")
+		if err != nil {
+			return "", err
+		}
+		ids := strings.Fields(out)
+		if len(ids) == 0 {
+			return "", fmt.Errorf("docker-compose project %s has no containers", getProjectName(t, options))
+		}
+
+		cmd := shell.Command{
+			Command: "docker",
+			Args:    append([]string{"container", "inspect", "--format", containerHealthFormat}, ids...),
+			// inspect is a short-running command, don't print the output.
+			Logger: logger.Discard,
+		}
+		out, err = shell.RunCommandAndGetStdOutE(t, cmd)
+		if err != nil {
+			return "", err
+		}
+		return "", checkContainersHealthy(out)
+	})
+	return err
+}
+
+// containerHealthFormat is the 'docker inspect' format for printing the name of a container along with its health
+// status, or its state if it has no health check.
+const containerHealthFormat = `{{.Name}} {{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}`
+
+// checkContainersHealthy returns an error if any of the containers in the given 'docker inspect' output, formatted
+// with containerHealthFormat, is neither healthy nor running without a health check.
+func checkContainersHealthy(inspectOutput string) error {
+	var unhealthy []string
+	for _, line := range strings.Split(strings.TrimSpace(inspectOutput), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		name, status := strings.TrimLeft(fields[0], "/"), fields[1]
+		if status != "healthy" && status != "running" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", name, status))
+		}
+	}
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("containers are not healthy yet: %s", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProjectName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "testgetprojectname", getProjectName(t, &Options{}))
+	assert.Equal(t, "my-project_1", getProjectName(t, &Options{ProjectName: "My-Project.1"}))
+
+	t.Run("Subtest", func(t *testing.T) {
+		assert.Equal(t, "testgetprojectname_subtest", getProjectName(t, &Options{}))
+	})
+}
+
+func TestCheckContainersHealthy(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkContainersHealthy("/project_web_1 healthy\n/project_db_1 running\n"))
+
+	err := checkContainersHealthy("/project_web_1 starting\n/project_db_1 running\n/project_cache_1 exited")
+	assert.EqualError(t, err, "containers are not healthy yet: project_web_1 (starting), project_cache_1 (exited)")
+}
@@ -103,10 +103,20 @@ func ListImagesE(t testing.TestingT, logger *logger.Logger) ([]Image, error) {
 // DoesImageExist lists the images in the docker daemon and returns true if the given image label (repo:tag) exists.
 // This will fail the test if there is an error.
 func DoesImageExist(t testing.TestingT, imgLabel string, logger *logger.Logger) bool {
-	images := ListImages(t, logger)
+	exists, err := DoesImageExistE(t, imgLabel, logger)
+	require.NoError(t, err)
+	return exists
+}
+
+// DoesImageExistE lists the images in the docker daemon and returns true if the given image label (repo:tag) exists.
+func DoesImageExistE(t testing.TestingT, imgLabel string, logger *logger.Logger) (bool, error) {
+	images, err := ListImagesE(t, logger)
+	if err != nil {
+		return false, err
+	}
 	imageTags := []string{}
 	for _, image := range images {
 		imageTags = append(imageTags, image.String())
 	}
-	return collections.ListContains(imageTags, imgLabel)
+	return collections.ListContains(imageTags, imgLabel), nil
 }
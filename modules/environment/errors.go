@@ -0,0 +1,14 @@
+package environment
+
+import "fmt"
+
+// InvalidEnvVarValue is returned when an environment variable is set to a value that can't be used.
+type InvalidEnvVarValue struct {
+	Name     string
+	Value    string
+	Expected string
+}
+
+func (err InvalidEnvVarValue) Error() string {
+	return fmt.Sprintf("Environment variable %s is set to %q, but it must be %s", err.Name, err.Value, err.Expected)
+}
@@ -0,0 +1,131 @@
+package environment
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// The environment variables that override the behavior of Terratest at runtime, so that CI can tweak how a test suite
+// runs without code changes. See Overrides for what each of them does.
+const (
+	SkipDestroyEnvVar    = "TERRATEST_SKIP_DESTROY"
+	RegionEnvVar         = "TERRATEST_REGION"
+	MaxRetriesEnvVar     = "TERRATEST_MAX_RETRIES"
+	LogLevelEnvVar       = "TERRATEST_LOG_LEVEL"
+	SkipHeavyTestsEnvVar = "TERRATEST_SKIP_HEAVY_TESTS"
+)
+
+// The log levels that can be set with TERRATEST_LOG_LEVEL.
+const (
+	LogLevelInfo  = "info"  // Log everything, the default
+	LogLevelQuiet = "quiet" // Discard everything logged with the default logger, such as the output of commands
+)
+
+// Overrides are the overrides of the behavior of Terratest set in the environment.
+type Overrides struct {
+	SkipDestroy    bool   // TERRATEST_SKIP_DESTROY: terraform.Destroy and terraform.TgDestroyAll do nothing, so resources can be inspected after a test
+	Region         string // TERRATEST_REGION: aws.GetRandomRegion and friends always return this region
+	MaxRetries     *int   // TERRATEST_MAX_RETRIES: Terraform commands are retried this many times, instead of Options.MaxRetries
+	LogLevel       string // TERRATEST_LOG_LEVEL: one of LogLevelInfo and LogLevelQuiet, read once when the test binary starts
+	SkipHeavyTests bool   // TERRATEST_SKIP_HEAVY_TESTS: tests that call SkipIfHeavyTestsDisabled are skipped
+}
+
+// LoadOverrides reads the overrides of the behavior of Terratest from the environment, failing the test if any of
+// them is invalid.
+func LoadOverrides(t testing.TestingT) Overrides {
+	overrides, err := LoadOverridesE(t)
+	require.NoError(t, err)
+	return overrides
+}
+
+// LoadOverridesE reads the overrides of the behavior of Terratest from the environment, returning an
+// InvalidEnvVarValue error if any of them is invalid. Unset and empty environment variables don't override anything.
+// Code paths that only need one of the overrides, such as destroy, should read it with its own getter (e.g.,
+// GetSkipDestroyOverrideE), so that an invalid value of an unrelated variable doesn't break them.
+func LoadOverridesE(t testing.TestingT) (Overrides, error) {
+	overrides := Overrides{Region: GetRegionOverride()}
+
+	var err error
+	if overrides.SkipDestroy, err = GetSkipDestroyOverrideE(); err != nil {
+		return Overrides{}, err
+	}
+	if overrides.SkipHeavyTests, err = getBoolEnvVarE(SkipHeavyTestsEnvVar); err != nil {
+		return Overrides{}, err
+	}
+	if overrides.MaxRetries, err = GetMaxRetriesOverrideE(); err != nil {
+		return Overrides{}, err
+	}
+	if overrides.LogLevel, err = GetLogLevelOverrideE(); err != nil {
+		return Overrides{}, err
+	}
+
+	return overrides, nil
+}
+
+// GetSkipDestroyOverrideE returns the value of TERRATEST_SKIP_DESTROY, or an InvalidEnvVarValue error if it's not a bool.
+func GetSkipDestroyOverrideE() (bool, error) {
+	return getBoolEnvVarE(SkipDestroyEnvVar)
+}
+
+// GetRegionOverride returns the value of TERRATEST_REGION, or an empty string if it's not set.
+func GetRegionOverride() string {
+	return os.Getenv(RegionEnvVar)
+}
+
+// GetMaxRetriesOverrideE returns the value of TERRATEST_MAX_RETRIES, or nil if it's not set. Returns an
+// InvalidEnvVarValue error if it's not a non-negative integer.
+func GetMaxRetriesOverrideE() (*int, error) {
+	value := os.Getenv(MaxRetriesEnvVar)
+	if value == "" {
+		return nil, nil
+	}
+	maxRetries, err := strconv.Atoi(value)
+	if err != nil || maxRetries < 0 {
+		return nil, InvalidEnvVarValue{Name: MaxRetriesEnvVar, Value: value, Expected: "a non-negative integer"}
+	}
+	return &maxRetries, nil
+}
+
+// GetLogLevelOverrideE returns the value of TERRATEST_LOG_LEVEL, or LogLevelInfo if it's not set. Returns an
+// InvalidEnvVarValue error if it's not one of LogLevelInfo and LogLevelQuiet.
+func GetLogLevelOverrideE() (string, error) {
+	value := os.Getenv(LogLevelEnvVar)
+	if value == "" {
+		return LogLevelInfo, nil
+	}
+	logLevel := strings.ToLower(value)
+	if logLevel != LogLevelInfo && logLevel != LogLevelQuiet {
+		return "", InvalidEnvVarValue{Name: LogLevelEnvVar, Value: value, Expected: LogLevelInfo + " or " + LogLevelQuiet}
+	}
+	return logLevel, nil
+}
+
+// SkipIfHeavyTestsDisabled skips the test if TERRATEST_SKIP_HEAVY_TESTS is set. Call it at the start of tests that
+// deploy a lot of infrastructure or run a lot in parallel, so CI can leave them out of e.g. runs on pull requests. It
+// fails the test if the overrides in the environment are invalid, and does nothing if t can't be skipped.
+func SkipIfHeavyTestsDisabled(t testing.TestingT) {
+	overrides := LoadOverrides(t)
+	if !overrides.SkipHeavyTests {
+		return
+	}
+	if skipper, canSkip := t.(interface{ Skip(args ...interface{}) }); canSkip {
+		skipper.Skip("Skipping heavy test because " + SkipHeavyTestsEnvVar + " is set")
+	}
+}
+
+// getBoolEnvVarE returns the value of the given environment variable as a bool, or false if it's not set.
+func getBoolEnvVarE(name string) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, InvalidEnvVarValue{Name: name, Value: value, Expected: "true or false"}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,88 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOverridesDefaults(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	for _, name := range []string{SkipDestroyEnvVar, RegionEnvVar, MaxRetriesEnvVar, LogLevelEnvVar, SkipHeavyTestsEnvVar} {
+		t.Setenv(name, "")
+	}
+
+	assert.Equal(t, Overrides{LogLevel: LogLevelInfo}, LoadOverrides(t))
+}
+
+func TestLoadOverrides(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(SkipDestroyEnvVar, "true")
+	t.Setenv(RegionEnvVar, "eu-west-1")
+	t.Setenv(MaxRetriesEnvVar, "0")
+	t.Setenv(LogLevelEnvVar, "QUIET")
+	t.Setenv(SkipHeavyTestsEnvVar, "1")
+
+	maxRetries := 0
+	assert.Equal(t, Overrides{
+		SkipDestroy:    true,
+		Region:         "eu-west-1",
+		MaxRetries:     &maxRetries,
+		LogLevel:       LogLevelQuiet,
+		SkipHeavyTests: true,
+	}, LoadOverrides(t))
+}
+
+func TestLoadOverridesInvalid(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(MaxRetriesEnvVar, "many")
+
+	_, err := LoadOverridesE(t)
+	require.Error(t, err)
+	assert.Equal(t, InvalidEnvVarValue{Name: MaxRetriesEnvVar, Value: "many", Expected: "a non-negative integer"}, err)
+
+	mockT := new(MockT)
+	LoadOverrides(mockT)
+	assert.True(t, mockT.Failed)
+}
+
+func TestSkipIfHeavyTestsDisabled(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(SkipHeavyTestsEnvVar, "true")
+
+	ran := false
+	t.Run("heavy", func(t *testing.T) {
+		SkipIfHeavyTestsDisabled(t)
+		ran = true
+	})
+	assert.False(t, ran)
+}
+
+func TestOverrideGettersIgnoreOtherInvalidOverrides(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(SkipDestroyEnvVar, "true")
+	t.Setenv(RegionEnvVar, "eu-west-1")
+	t.Setenv(MaxRetriesEnvVar, "x")
+	t.Setenv(LogLevelEnvVar, "debug")
+
+	skipDestroy, err := GetSkipDestroyOverrideE()
+	require.NoError(t, err)
+	assert.True(t, skipDestroy)
+	assert.Equal(t, "eu-west-1", GetRegionOverride())
+
+	_, err = GetMaxRetriesOverrideE()
+	assert.Equal(t, InvalidEnvVarValue{Name: MaxRetriesEnvVar, Value: "x", Expected: "a non-negative integer"}, err)
+	_, err = GetLogLevelOverrideE()
+	assert.Equal(t, InvalidEnvVarValue{Name: LogLevelEnvVar, Value: "debug", Expected: "info or quiet"}, err)
+}
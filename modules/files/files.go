@@ -47,17 +47,7 @@ func IsExistingDir(path string) bool {
 // as you typically don't want them interfering with your tests.
 // This method is useful when running through a build tool so the files are copied to a destination that is cleaned on each run of the pipeline.
 func CopyTerraformFolderToDest(folderPath string, destRootFolder string, tempFolderPrefix string) (string, error) {
-	filter := func(path string) bool {
-		if PathIsTerraformVersionFile(path) {
-			return true
-		}
-		if PathContainsHiddenFileOrFolder(path) || PathContainsTerraformStateOrVars(path) {
-			return false
-		}
-		return true
-	}
-
-	destFolder, err := CopyFolderToDest(folderPath, destRootFolder, tempFolderPrefix, filter)
+	destFolder, err := CopyFolderToDest(folderPath, destRootFolder, tempFolderPrefix, terraformFilter)
 	if err != nil {
 		return "", err
 	}
@@ -74,11 +64,7 @@ func CopyTerraformFolderToTemp(folderPath string, tempFolderPrefix string) (stri
 // Since terragrunt uses tfvars files to specify modules, they are copied to the directory as well.
 // Terraform state files are excluded as well as .terragrunt-cache to avoid overwriting contents.
 func CopyTerragruntFolderToDest(folderPath string, destRootFolder string, tempFolderPrefix string) (string, error) {
-	filter := func(path string) bool {
-		return !PathContainsHiddenFileOrFolder(path) && !PathContainsTerraformState(path)
-	}
-
-	destFolder, err := CopyFolderToDest(folderPath, destRootFolder, tempFolderPrefix, filter)
+	destFolder, err := CopyFolderToDest(folderPath, destRootFolder, tempFolderPrefix, terragruntFilter)
 	if err != nil {
 		return "", err
 	}
@@ -183,6 +169,23 @@ func CopyFolderContentsWithFilter(source string, destination string, filter func
 	return nil
 }
 
+// terraformFilter excludes hidden files and folders (except .terraform-version and .terraform.lock.hcl), Terraform
+// state files, and terraform.tfvars files.
+func terraformFilter(path string) bool {
+	if PathIsTerraformVersionFile(path) || PathIsTerraformLockFile(path) {
+		return true
+	}
+	if PathContainsHiddenFileOrFolder(path) || PathContainsTerraformStateOrVars(path) {
+		return false
+	}
+	return true
+}
+
+// terragruntFilter excludes hidden files and folders (such as .terragrunt-cache) and Terraform state files.
+func terragruntFilter(path string) bool {
+	return !PathContainsHiddenFileOrFolder(path) && !PathContainsTerraformState(path)
+}
+
 // PathContainsTerraformStateOrVars returns true if the path corresponds to a Terraform state file or .tfvars/.tfvars.json file.
 func PathContainsTerraformStateOrVars(path string) bool {
 	filename := filepath.Base(path)
@@ -195,9 +198,10 @@ func PathContainsTerraformState(path string) bool {
 	return filename == "terraform.tfstate" || filename == "terraform.tfstate.backup"
 }
 
-// PathContainsHiddenFileOrFolder returns true if the given path contains a hidden file or folder.
+// PathContainsHiddenFileOrFolder returns true if the given path contains a hidden file or folder. Both forward slashes
+// and, on Windows, backslashes are treated as separators.
 func PathContainsHiddenFileOrFolder(path string) bool {
-	pathParts := strings.Split(path, string(filepath.Separator))
+	pathParts := strings.Split(filepath.ToSlash(path), "/")
 	for _, pathPart := range pathParts {
 		if strings.HasPrefix(pathPart, ".") && pathPart != "." && pathPart != ".." {
 			return true
@@ -211,6 +215,12 @@ func PathIsTerraformVersionFile(path string) bool {
 	return filepath.Base(path) == ".terraform-version"
 }
 
+// PathIsTerraformLockFile returns true if the given path is a '.terraform.lock.hcl' dependency lock file, which pins the
+// versions of the providers the module was tested with. Lock files inside a hidden folder (e.g. .terraform) are not.
+func PathIsTerraformLockFile(path string) bool {
+	return filepath.Base(path) == ".terraform.lock.hcl" && !PathContainsHiddenFileOrFolder(filepath.Dir(path))
+}
+
 // CopyFile copies a file from source to destination.
 func CopyFile(source string, destination string) error {
 	contents, err := ioutil.ReadFile(source)
@@ -2,8 +2,8 @@ package files
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -222,14 +222,63 @@ func TestPathContainsTerraformStateOrVars(t *testing.T) {
 	}
 }
 
-// Diffing two directories to ensure they have the exact same files, contents, etc and showing exactly what's different
-// takes a lot of code. Why waste time on that when this functionality is already nicely implemented in the Unix/Linux
-// "diff" command? We shell out to that command at test time.
+func TestPathContainsHiddenFileOrFolder(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, PathContainsHiddenFileOrFolder("folder/.terraform/plugins"))
+	assert.True(t, PathContainsHiddenFileOrFolder(".git"))
+	assert.False(t, PathContainsHiddenFileOrFolder("../folder/main.tf"))
+	assert.False(t, PathContainsHiddenFileOrFolder("./folder/main.tf"))
+}
+
+func TestPathIsTerraformLockFile(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, PathIsTerraformLockFile(".terraform.lock.hcl"))
+	assert.True(t, PathIsTerraformLockFile("../folder/.terraform.lock.hcl"))
+	assert.False(t, PathIsTerraformLockFile("../folder/.terraform/.terraform.lock.hcl"))
+	assert.False(t, PathIsTerraformLockFile("../folder/terraform.lock.hcl"))
+}
+
+// requireDirectoriesEqual requires the two directories to have the exact same files, folders, and contents. Symlinks
+// are compared by their target rather than dereferenced, so this works for broken symlinks too. Unlike shelling out to
+// diff, this works on Windows.
 func requireDirectoriesEqual(t *testing.T, folderWithExpectedContents string, folderWithActualContents string) {
-	cmd := exec.Command("diff", "-r", "-u", folderWithExpectedContents, folderWithActualContents)
+	require.Equal(t, readDirectoryContents(t, folderWithExpectedContents), readDirectoryContents(t, folderWithActualContents), "The contents of %s and %s are different", folderWithExpectedContents, folderWithActualContents)
+}
 
-	bytes, err := cmd.Output()
-	output := string(bytes)
+// readDirectoryContents returns a description of every file, folder, and symlink in the given directory, keyed by its
+// slash-separated path relative to the directory.
+func readDirectoryContents(t *testing.T, dir string) map[string]string {
+	contents := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
 
-	require.NoError(t, err, "diff command exited with an error. This likely means the contents of %s and %s are different. Here is the output of the diff command:\n%s", folderWithExpectedContents, folderWithActualContents, output)
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			contents[relPath] = "symlink to " + filepath.ToSlash(target)
+		case info.IsDir():
+			contents[relPath] = "folder"
+		default:
+			bytes, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			contents[relPath] = string(bytes)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return contents
 }
@@ -0,0 +1,33 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathContainsHiddenFileOrFolderWithBackslashesOnWindows(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, PathContainsHiddenFileOrFolder(`C:\folder\.terraform\plugins`))
+	assert.True(t, PathContainsHiddenFileOrFolder(`folder\.git`))
+	assert.False(t, PathContainsHiddenFileOrFolder(`..\folder\main.tf`))
+	assert.False(t, PathContainsHiddenFileOrFolder(`C:\folder\main.tf`))
+}
+
+func TestCopyFolderContentsWithHiddenFilesFilterOnWindows(t *testing.T) {
+	t.Parallel()
+
+	// The filter gets paths with backslashes, which must be split into parts just like paths with slashes
+	originalDir := `..\..\test\fixtures\copy-folder-contents\original`
+	expectedDir := `..\..\test\fixtures\copy-folder-contents\no-hidden-files`
+	tmpDir := t.TempDir()
+
+	err := CopyFolderContentsWithFilter(originalDir, tmpDir, func(path string) bool {
+		return !PathContainsHiddenFileOrFolder(path)
+	})
+	require.NoError(t, err)
+
+	requireDirectoriesEqual(t, expectedDir, tmpDir)
+}
@@ -0,0 +1,137 @@
+package files
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// CopyTerraformFSToDest extracts the given folder of the fs.FS and all its contents to a folder in destRootFolder
+// with a unique name and the given prefix, and returns the path to the extracted folder. It filters the contents the
+// same way as CopyTerraformFolderToDest. This is useful with an embed.FS, as it makes the test binary self-contained,
+// so it can be shipped to and run on a remote runner without the source tree:
+//
+//	//go:embed fixtures
+//	var fixtures embed.FS
+//
+//	terraformDir, err := files.CopyTerraformFSToTemp(fixtures, "fixtures/my-module", t.Name())
+//
+// Note that go:embed leaves out files whose names start with "." or "_" unless you ask for them explicitly.
+func CopyTerraformFSToDest(fsys fs.FS, root string, destRootFolder string, tempFolderPrefix string) (string, error) {
+	return CopyFSToDest(fsys, root, destRootFolder, tempFolderPrefix, terraformFilter)
+}
+
+// CopyTerraformFSToTemp calls CopyTerraformFSToDest, passing os.TempDir() as the root destination folder.
+func CopyTerraformFSToTemp(fsys fs.FS, root string, tempFolderPrefix string) (string, error) {
+	return CopyTerraformFSToDest(fsys, root, os.TempDir(), tempFolderPrefix)
+}
+
+// CopyTerragruntFSToDest extracts the given folder of the fs.FS and all its contents to a folder in destRootFolder
+// with a unique name and the given prefix, and returns the path to the extracted folder. It filters the contents the
+// same way as CopyTerragruntFolderToDest.
+func CopyTerragruntFSToDest(fsys fs.FS, root string, destRootFolder string, tempFolderPrefix string) (string, error) {
+	return CopyFSToDest(fsys, root, destRootFolder, tempFolderPrefix, terragruntFilter)
+}
+
+// CopyTerragruntFSToTemp calls CopyTerragruntFSToDest, passing os.TempDir() as the root destination folder.
+func CopyTerragruntFSToTemp(fsys fs.FS, root string, tempFolderPrefix string) (string, error) {
+	return CopyTerragruntFSToDest(fsys, root, os.TempDir(), tempFolderPrefix)
+}
+
+// CopyFSToDest extracts the given folder of the fs.FS and all its filtered contents to a folder in destRootFolder with
+// a unique name and the given prefix. Like CopyFolderToDest, the extracted folder keeps the name of the folder it was
+// extracted from, unless root is ".". The filter is called with the slash-separated path of each file in the fs.FS.
+func CopyFSToDest(fsys fs.FS, root string, destRootFolder string, tempFolderPrefix string, filter func(path string) bool) (string, error) {
+	destRootExists, err := FileExistsE(destRootFolder)
+	if err != nil {
+		return "", err
+	}
+	if !destRootExists {
+		return "", DirNotFoundError{Directory: destRootFolder}
+	}
+
+	info, err := fs.Stat(fsys, root)
+	if err != nil || !info.IsDir() {
+		return "", DirNotFoundError{Directory: root}
+	}
+
+	tmpDir, err := ioutil.TempDir(destRootFolder, tempFolderPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	destFolder := tmpDir
+	if root != "." {
+		destFolder = filepath.Join(tmpDir, path.Base(root))
+	}
+
+	if err := os.MkdirAll(destFolder, 0777); err != nil {
+		return "", err
+	}
+
+	if err := CopyFSContentsWithFilter(fsys, root, destFolder, filter); err != nil {
+		return "", err
+	}
+
+	return destFolder, nil
+}
+
+// CopyFSToTemp calls CopyFSToDest, passing os.TempDir() as the root destination folder.
+func CopyFSToTemp(fsys fs.FS, root string, tempFolderPrefix string, filter func(path string) bool) (string, error) {
+	return CopyFSToDest(fsys, root, os.TempDir(), tempFolderPrefix, filter)
+}
+
+// CopyFSContentsWithFilter copies the files and folders within the given folder of the fs.FS that pass the given filter
+// (return true) to the destination folder. Since files in an fs.FS (and an embed.FS in particular) are typically read
+// only, the copies are made writable, so Terraform can create its working files next to them.
+func CopyFSContentsWithFilter(fsys fs.FS, source string, destination string, filter func(path string) bool) error {
+	entries, err := fs.ReadDir(fsys, source)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		src := path.Join(source, entry.Name())
+		dest := filepath.Join(destination, entry.Name())
+
+		if !filter(src) {
+			continue
+		} else if entry.IsDir() {
+			if err := os.MkdirAll(dest, 0777); err != nil {
+				return err
+			}
+
+			if err := CopyFSContentsWithFilter(fsys, src, dest, filter); err != nil {
+				return err
+			}
+		} else {
+			if err := copyFSFile(fsys, src, dest); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyFSFile copies a file out of the fs.FS, keeping it executable if it was executable in the fs.FS.
+func copyFSFile(fsys fs.FS, source string, destination string) error {
+	contents, err := fs.ReadFile(fsys, source)
+	if err != nil {
+		return err
+	}
+
+	info, err := fs.Stat(fsys, source)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info.Mode()&0111 != 0 {
+		mode = 0755
+	}
+
+	return ioutil.WriteFile(destination, contents, mode)
+}
@@ -0,0 +1,67 @@
+package files
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyTerraformFSToTemp(t *testing.T) {
+	t.Parallel()
+
+	fixtures := os.DirFS(copyFolderContentsFixtureRoot)
+	expectedDir := filepath.Join(copyFolderContentsFixtureRoot, "no-hidden-files-no-terraform-files")
+
+	tmpDir, err := CopyTerraformFSToTemp(fixtures, "original", t.Name())
+	require.NoError(t, err)
+
+	assert.Equal(t, "original", filepath.Base(tmpDir))
+	requireDirectoriesEqual(t, expectedDir, tmpDir)
+}
+
+func TestCopyTerragruntFSToDest(t *testing.T) {
+	t.Parallel()
+
+	fixtures := os.DirFS(copyFolderContentsFixtureRoot)
+	expectedDir := filepath.Join(copyFolderContentsFixtureRoot, "no-state-files")
+
+	tmpDir, err := CopyTerragruntFSToDest(fixtures, "terragrunt-files", os.TempDir(), t.Name())
+	require.NoError(t, err)
+
+	requireDirectoriesEqual(t, expectedDir, tmpDir)
+}
+
+func TestCopyFSToTempFromRootIsWritable(t *testing.T) {
+	t.Parallel()
+
+	fixtures := fstest.MapFS{
+		"main.tf":          {Data: []byte("# main"), Mode: 0444},
+		"modules/vpc.tf":   {Data: []byte("# vpc"), Mode: 0444},
+		"scripts/setup.sh": {Data: []byte("#!/bin/sh"), Mode: 0555},
+	}
+
+	tmpDir, err := CopyFSToTemp(fixtures, ".", t.Name(), func(path string) bool { return true })
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadFile(filepath.Join(tmpDir, "modules", "vpc.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "# vpc", string(contents))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte("# changed"), 0644))
+
+	info, err := os.Stat(filepath.Join(tmpDir, "scripts", "setup.sh"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100)
+}
+
+func TestCopyFSToTempMissingRoot(t *testing.T) {
+	t.Parallel()
+
+	_, err := CopyFSToTemp(fstest.MapFS{}, "does-not-exist", t.Name(), func(path string) bool { return true })
+	assert.Equal(t, DirNotFoundError{Directory: "does-not-exist"}, err)
+}
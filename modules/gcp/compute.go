@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -84,6 +85,61 @@ func FetchInstanceE(t testing.TestingT, projectID string, name string) (*Instanc
 	return nil, fmt.Errorf("Compute Instance %s could not be found in project %s", name, projectID)
 }
 
+// FetchInstancesByLabels queries GCP to return all the Compute Instances in the project, in any zone, that have all
+// the given labels, e.g. the labels a Terraform module applied to the Instances it launched.
+func FetchInstancesByLabels(t testing.TestingT, projectID string, labels map[string]string) []*Instance {
+	instances, err := FetchInstancesByLabelsE(t, projectID, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return instances
+}
+
+// FetchInstancesByLabelsE queries GCP to return all the Compute Instances in the project, in any zone, that have all
+// the given labels, e.g. the labels a Terraform module applied to the Instances it launched.
+func FetchInstancesByLabelsE(t testing.TestingT, projectID string, labels map[string]string) ([]*Instance, error) {
+	filter := instanceLabelsFilter(labels)
+	logger.Logf(t, "Getting Compute Instances matching filter %s", filter)
+
+	ctx := context.Background()
+	service, err := NewComputeServiceE(t)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := []*Instance{}
+	err = service.Instances.AggregatedList(projectID).Filter(filter).Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+		for _, instanceList := range page.Items {
+			for _, instance := range instanceList.Instances {
+				instances = append(instances, &Instance{projectID, instance})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Instances.AggregatedList(%s) got error: %v", projectID, err)
+	}
+
+	return instances, nil
+}
+
+// instanceLabelsFilter returns a Compute API filter expression that matches the resources that have all the given
+// labels, e.g. (labels.env = "test") (labels.team = "infra").
+func instanceLabelsFilter(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	expressions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		expressions = append(expressions, fmt.Sprintf("(labels.%s = %q)", key, labels[key]))
+	}
+	return strings.Join(expressions, " ")
+}
+
 // FetchImage queries GCP to return a new instance of the (GCP Compute) Image type
 func FetchImage(t testing.TestingT, projectID string, name string) *Image {
 	image, err := FetchImageE(t, projectID, name)
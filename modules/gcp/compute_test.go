@@ -68,6 +68,23 @@ func TestZoneUrlToZone(t *testing.T) {
 	}
 }
 
+func TestInstanceLabelsFilter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		labels         map[string]string
+		expectedFilter string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"context": "terratest"}, `(labels.context = "terratest")`},
+		{map[string]string{"team": "infra", "env": "test"}, `(labels.env = "test") (labels.team = "infra")`},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedFilter, instanceLabelsFilter(tc.labels))
+	}
+}
+
 func TestGetAndSetLabels(t *testing.T) {
 	t.Parallel()
 
@@ -100,7 +117,13 @@ func TestGetAndSetLabels(t *testing.T) {
 			return "", fmt.Errorf("Labels that were written did not match labels that were read. Retrying.\n")
 		}
 
-		return "", nil
+		instances := FetchInstancesByLabels(t, projectID, labelsToWrite)
+		for _, instance := range instances {
+			if instance.Name == instanceName {
+				return "", nil
+			}
+		}
+		return "", fmt.Errorf("Instance %s was not found by its labels. Retrying.\n", instanceName)
 	})
 }
 
@@ -0,0 +1,38 @@
+package helm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+// maxReleaseNameLength is the longest release name helm accepts, so that the names of the resources it derives from
+// the release name fit within the 63 character limit of Kubernetes labels.
+const maxReleaseNameLength = 53
+
+// invalidReleaseNameChars matches the characters that are not allowed in a helm release name.
+var invalidReleaseNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// GetUniqueReleaseName returns a release name made of the given prefix and a random.UniqueId, so that tests running
+// in parallel on the same cluster don't install over each other's releases. The prefix is lowercased, characters that
+// are not allowed in release names are replaced with dashes, and it is truncated so the name fits helm's limit of 53
+// characters. This is useful to build the release name from t.Name(), e.g.:
+//
+//	releaseName := helm.GetUniqueReleaseName(t.Name())
+//	defer helm.Delete(t, options, releaseName, true)
+//	helm.Install(t, options, chartPath, releaseName)
+func GetUniqueReleaseName(prefix string) string {
+	uniqueID := strings.ToLower(random.UniqueId())
+	prefix = strings.Trim(invalidReleaseNameChars.ReplaceAllString(strings.ToLower(prefix), "-"), "-")
+
+	maxPrefixLength := maxReleaseNameLength - len(uniqueID) - 1
+	if len(prefix) > maxPrefixLength {
+		prefix = strings.TrimRight(prefix[:maxPrefixLength], "-")
+	}
+	if prefix == "" {
+		return uniqueID
+	}
+	return fmt.Sprintf("%s-%s", prefix, uniqueID)
+}
@@ -0,0 +1,36 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUniqueReleaseName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		prefix         string
+		expectedPrefix string
+	}{
+		{"Simple", "nginx", "nginx-"},
+		{"TestName", "TestRemoteChartInstall/Subtest_1", "testremotechartinstall-subtest-1-"},
+		{"Empty", "", ""},
+		{"Long", strings.Repeat("a", 60), strings.Repeat("a", 46) + "-"},
+	}
+
+	for _, testCase := range testCases {
+		// Capture range variable so that it doesn't update when the subtest goroutine swaps.
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			releaseName := GetUniqueReleaseName(testCase.prefix)
+			assert.True(t, strings.HasPrefix(releaseName, testCase.expectedPrefix), releaseName)
+			assert.Regexp(t, "^[a-z0-9]([a-z0-9-]*[a-z0-9])?$", releaseName)
+			assert.LessOrEqual(t, len(releaseName), maxReleaseNameLength)
+			assert.NotEqual(t, releaseName, GetUniqueReleaseName(testCase.prefix))
+		})
+	}
+}
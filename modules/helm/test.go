@@ -0,0 +1,26 @@
+package helm
+
+import (
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// Test will run the tests of the provided release, i.e. the chart's hooks annotated with `helm.sh/hook: test`, and wait
+// for them to pass. This will fail the test if there is an error or if any of the chart tests fail.
+func Test(t testing.TestingT, options *Options, releaseName string) {
+	require.NoError(t, TestE(t, options, releaseName))
+}
+
+// TestE will run the tests of the provided release, i.e. the chart's hooks annotated with `helm.sh/hook: test`, and
+// wait for them to pass. This returns an error if any of the chart tests fail.
+func TestE(t testing.TestingT, options *Options, releaseName string) error {
+	args := []string{}
+	if options.ExtraArgs != nil {
+		if testArgs, ok := options.ExtraArgs["test"]; ok {
+			args = append(args, testArgs...)
+		}
+	}
+	args = append(args, releaseName)
+	_, err := RunHelmCommandAndGetOutputE(t, options, "test", args...)
+	return err
+}
@@ -0,0 +1,114 @@
+package http_helper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ExpectedCertificate describes the certificate an endpoint is expected to serve. Checks for fields that are left empty
+// are skipped.
+type ExpectedCertificate struct {
+	CommonName              string        // The expected CN of the leaf certificate
+	SubjectAlternativeNames []string      // DNS names and IPs that must all be in the SANs of the leaf certificate
+	IssuerCommonName        string        // The expected CN of the issuer of the leaf certificate
+	MinRemainingValidity    time.Duration // The leaf certificate must be valid for at least this long from now
+}
+
+// GetCertificateChain connects to the given address (host:port), performs a TLS handshake with an optional pointer to
+// a custom TLS configuration, and returns the certificate chain served by the endpoint, starting with the leaf
+// certificate. If there's any error, fail the test.
+func GetCertificateChain(t testing.TestingT, address string, tlsConfig *tls.Config) []*x509.Certificate {
+	chain, err := GetCertificateChainE(t, address, tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+// GetCertificateChainE connects to the given address (host:port), performs a TLS handshake with an optional pointer to
+// a custom TLS configuration, and returns the certificate chain served by the endpoint, starting with the leaf
+// certificate. To retrieve a certificate that is not trusted by the system roots, set RootCAs in tlsConfig.
+func GetCertificateChainE(t testing.TestingT, address string, tlsConfig *tls.Config) ([]*x509.Certificate, error) {
+	logger.Logf(t, "Retrieving the certificate chain served on %s", address)
+
+	dialer := &net.Dialer{
+		// By default, Go does not impose a timeout, so a connection attempt can hang for a LONG time.
+		Timeout: 10 * time.Second,
+	}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	chain := conn.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, NoCertificatesServed(address)
+	}
+	return chain, nil
+}
+
+// AssertCertificate connects to the given address (host:port) and verifies that the certificate it serves matches the
+// given expectations. If it doesn't, fail the test.
+func AssertCertificate(t testing.TestingT, address string, tlsConfig *tls.Config, expected ExpectedCertificate) {
+	err := AssertCertificateE(t, address, tlsConfig, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertCertificateE connects to the given address (host:port) and verifies that the certificate it serves matches
+// the given expectations, returning an error that lists every mismatch if it doesn't.
+func AssertCertificateE(t testing.TestingT, address string, tlsConfig *tls.Config, expected ExpectedCertificate) error {
+	chain, err := GetCertificateChainE(t, address, tlsConfig)
+	if err != nil {
+		return err
+	}
+	return checkCertificate(address, chain[0], expected, time.Now())
+}
+
+// checkCertificate verifies that the given certificate matches the given expectations at the given time.
+func checkCertificate(address string, cert *x509.Certificate, expected ExpectedCertificate, now time.Time) error {
+	errorsOccurred := new(multierror.Error)
+
+	if expected.CommonName != "" && cert.Subject.CommonName != expected.CommonName {
+		errorsOccurred = multierror.Append(errorsOccurred, CertificateMismatch{Address: address, Field: "common name", Expected: expected.CommonName, Actual: cert.Subject.CommonName})
+	}
+
+	if len(expected.SubjectAlternativeNames) > 0 {
+		actualNames := certificateSubjectAlternativeNames(cert)
+		for _, name := range expected.SubjectAlternativeNames {
+			if !collections.ListContains(actualNames, name) {
+				errorsOccurred = multierror.Append(errorsOccurred, CertificateMismatch{Address: address, Field: "subject alternative names", Expected: name, Actual: fmt.Sprintf("%v", actualNames)})
+			}
+		}
+	}
+
+	if expected.IssuerCommonName != "" && cert.Issuer.CommonName != expected.IssuerCommonName {
+		errorsOccurred = multierror.Append(errorsOccurred, CertificateMismatch{Address: address, Field: "issuer common name", Expected: expected.IssuerCommonName, Actual: cert.Issuer.CommonName})
+	}
+
+	if now.Before(cert.NotBefore) || now.Add(expected.MinRemainingValidity).After(cert.NotAfter) {
+		errorsOccurred = multierror.Append(errorsOccurred, CertificateNotValidLongEnough{Address: address, NotBefore: cert.NotBefore, NotAfter: cert.NotAfter, MinRemainingValidity: expected.MinRemainingValidity})
+	}
+
+	return errorsOccurred.ErrorOrNil()
+}
+
+// certificateSubjectAlternativeNames returns the DNS names and IP addresses in the SANs of the given certificate.
+func certificateSubjectAlternativeNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
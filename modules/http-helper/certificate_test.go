@@ -0,0 +1,80 @@
+package http_helper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertCertificate(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(bodyCopyHandler))
+	defer ts.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+	tlsConfig := &tls.Config{RootCAs: rootCAs}
+	address := strings.TrimPrefix(ts.URL, "https://")
+
+	chain := GetCertificateChain(t, address, tlsConfig)
+	require.NotEmpty(t, chain)
+
+	AssertCertificate(t, address, tlsConfig, ExpectedCertificate{
+		SubjectAlternativeNames: []string{"example.com", "127.0.0.1"},
+		MinRemainingValidity:    24 * time.Hour,
+	})
+
+	err := AssertCertificateE(t, address, tlsConfig, ExpectedCertificate{SubjectAlternativeNames: []string{"www.example.org"}})
+	assert.Error(t, err)
+}
+
+func TestGetCertificateChainUntrusted(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(bodyCopyHandler))
+	defer ts.Close()
+
+	_, err := GetCertificateChainE(t, strings.TrimPrefix(ts.URL, "https://"), nil)
+	assert.Error(t, err)
+}
+
+func TestCheckCertificate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "app.example.com"},
+		Issuer:    pkix.Name{CommonName: "Amazon RSA 2048 M01"},
+		DNSNames:  []string{"app.example.com", "www.app.example.com"},
+		NotBefore: now.Add(-24 * time.Hour),
+		NotAfter:  now.Add(30 * 24 * time.Hour),
+	}
+
+	assert.NoError(t, checkCertificate("app.example.com:443", cert, ExpectedCertificate{
+		CommonName:              "app.example.com",
+		SubjectAlternativeNames: []string{"www.app.example.com"},
+		IssuerCommonName:        "Amazon RSA 2048 M01",
+		MinRemainingValidity:    7 * 24 * time.Hour,
+	}, now))
+
+	err := checkCertificate("app.example.com:443", cert, ExpectedCertificate{
+		CommonName:           "other.example.com",
+		IssuerCommonName:     "Let's Encrypt R3",
+		MinRemainingValidity: 60 * 24 * time.Hour,
+	}, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "common name app.example.com, but expected other.example.com")
+	assert.Contains(t, err.Error(), "issuer common name Amazon RSA 2048 M01, but expected Let's Encrypt R3")
+	assert.Contains(t, err.Error(), "must be valid now and for at least 1440h0m0s more")
+
+	assert.Error(t, checkCertificate("app.example.com:443", cert, ExpectedCertificate{}, now.Add(-48*time.Hour)))
+}
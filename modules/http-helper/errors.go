@@ -1,6 +1,9 @@
 package http_helper
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // ValidationFunctionFailed is an error that occurs if a validation function fails.
 type ValidationFunctionFailed struct {
@@ -12,3 +15,35 @@ type ValidationFunctionFailed struct {
 func (err ValidationFunctionFailed) Error() string {
 	return fmt.Sprintf("Validation failed for URL %s. Response status: %d. Response body:\n%s", err.Url, err.Status, err.Body)
 }
+
+// NoCertificatesServed is an error that occurs if an endpoint completes a TLS handshake without serving a certificate.
+type NoCertificatesServed string
+
+func (err NoCertificatesServed) Error() string {
+	return fmt.Sprintf("The endpoint %s did not serve any certificates", string(err))
+}
+
+// CertificateMismatch is an error that occurs if the certificate served by an endpoint doesn't match the expected value.
+type CertificateMismatch struct {
+	Address  string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (err CertificateMismatch) Error() string {
+	return fmt.Sprintf("The certificate served on %s has %s %s, but expected %s", err.Address, err.Field, err.Actual, err.Expected)
+}
+
+// CertificateNotValidLongEnough is an error that occurs if the certificate served by an endpoint is not yet valid or
+// expires sooner than required.
+type CertificateNotValidLongEnough struct {
+	Address              string
+	NotBefore            time.Time
+	NotAfter             time.Time
+	MinRemainingValidity time.Duration
+}
+
+func (err CertificateNotValidLongEnough) Error() string {
+	return fmt.Sprintf("The certificate served on %s is valid from %s to %s, but it must be valid now and for at least %s more", err.Address, err.NotBefore, err.NotAfter, err.MinRemainingValidity)
+}
@@ -3,6 +3,7 @@ package http_helper
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -29,6 +30,11 @@ func HttpGet(t testing.TestingT, url string, tlsConfig *tls.Config) (int, string
 // HttpGetE performs an HTTP GET, with an optional pointer to a custom TLS configuration, on the given URL and
 // return the HTTP status code, body, and any error.
 func HttpGetE(t testing.TestingT, url string, tlsConfig *tls.Config) (int, string, error) {
+	return HttpGetContextE(context.Background(), t, url, tlsConfig)
+}
+
+// HttpGetContextE works like HttpGetE, but aborts the request if the given context is done before it completes.
+func HttpGetContextE(ctx context.Context, t testing.TestingT, url string, tlsConfig *tls.Config) (int, string, error) {
 	logger.Logf(t, "Making an HTTP GET call to URL %s", url)
 
 	// Set HTTP client transport config
@@ -42,7 +48,12 @@ func HttpGetE(t testing.TestingT, url string, tlsConfig *tls.Config) (int, strin
 		Transport: tr,
 	}
 
-	resp, err := client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return -1, "", err
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return -1, "", err
 	}
@@ -84,7 +95,13 @@ func HttpGetWithCustomValidation(t testing.TestingT, url string, tlsConfig *tls.
 
 // HttpGetWithCustomValidationE performs an HTTP GET on the given URL and validate the returned status code and body using the given function.
 func HttpGetWithCustomValidationE(t testing.TestingT, url string, tlsConfig *tls.Config, validateResponse func(int, string) bool) error {
-	statusCode, body, err := HttpGetE(t, url, tlsConfig)
+	return httpGetWithCustomValidationContextE(context.Background(), t, url, tlsConfig, validateResponse)
+}
+
+// httpGetWithCustomValidationContextE performs an HTTP GET on the given URL with the given context and validate the
+// returned status code and body using the given function.
+func httpGetWithCustomValidationContextE(ctx context.Context, t testing.TestingT, url string, tlsConfig *tls.Config, validateResponse func(int, string) bool) error {
+	statusCode, body, err := HttpGetContextE(ctx, t, url, tlsConfig)
 
 	if err != nil {
 		return err
@@ -109,11 +126,33 @@ func HttpGetWithRetry(t testing.TestingT, url string, tlsConfig *tls.Config, exp
 // HttpGetWithRetryE repeatedly performs an HTTP GET on the given URL until the given status code and body are returned or until max
 // retries has been exceeded.
 func HttpGetWithRetryE(t testing.TestingT, url string, tlsConfig *tls.Config, expectedStatus int, expectedBody string, retries int, sleepBetweenRetries time.Duration) error {
-	_, err := retry.DoWithRetryE(t, fmt.Sprintf("HTTP GET to URL %s", url), retries, sleepBetweenRetries, func() (string, error) {
-		return "", HttpGetWithValidationE(t, url, tlsConfig, expectedStatus, expectedBody)
+	return HttpGetWithRetryContextE(context.Background(), t, url, tlsConfig, expectedStatus, expectedBody, retries, sleepBetweenRetries)
+}
+
+// HttpGetWithRetryContextE works like HttpGetWithRetryE, but stops retrying, and aborts the pending request, as soon as
+// the given context is done.
+func HttpGetWithRetryContextE(ctx context.Context, t testing.TestingT, url string, tlsConfig *tls.Config, expectedStatus int, expectedBody string, retries int, sleepBetweenRetries time.Duration) error {
+	return HttpGetWithRetryWithCustomValidationContextE(ctx, t, url, tlsConfig, retries, sleepBetweenRetries, func(statusCode int, body string) bool {
+		return statusCode == expectedStatus && body == expectedBody
 	})
+}
 
-	return err
+// HttpGetWithRetryContains repeatedly performs an HTTP GET on the given URL until the given status code is returned
+// with a body that contains expectedBodySubstring, or until max retries has been exceeded. This is handy for polling
+// pages, such as the HTML served by a load balancer, whose full body is not known in advance.
+func HttpGetWithRetryContains(t testing.TestingT, url string, tlsConfig *tls.Config, expectedStatus int, expectedBodySubstring string, retries int, sleepBetweenRetries time.Duration) {
+	err := HttpGetWithRetryContainsE(t, url, tlsConfig, expectedStatus, expectedBodySubstring, retries, sleepBetweenRetries)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// HttpGetWithRetryContainsE repeatedly performs an HTTP GET on the given URL until the given status code is returned
+// with a body that contains expectedBodySubstring, or until max retries has been exceeded.
+func HttpGetWithRetryContainsE(t testing.TestingT, url string, tlsConfig *tls.Config, expectedStatus int, expectedBodySubstring string, retries int, sleepBetweenRetries time.Duration) error {
+	return HttpGetWithRetryWithCustomValidationE(t, url, tlsConfig, retries, sleepBetweenRetries, func(statusCode int, body string) bool {
+		return statusCode == expectedStatus && strings.Contains(body, expectedBodySubstring)
+	})
 }
 
 // HttpGetWithRetryWithCustomValidation repeatedly performs an HTTP GET on the given URL until the given validation function returns true or max retries
@@ -128,8 +167,14 @@ func HttpGetWithRetryWithCustomValidation(t testing.TestingT, url string, tlsCon
 // HttpGetWithRetryWithCustomValidationE repeatedly performs an HTTP GET on the given URL until the given validation function returns true or max retries
 // has been exceeded.
 func HttpGetWithRetryWithCustomValidationE(t testing.TestingT, url string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration, validateResponse func(int, string) bool) error {
-	_, err := retry.DoWithRetryE(t, fmt.Sprintf("HTTP GET to URL %s", url), retries, sleepBetweenRetries, func() (string, error) {
-		return "", HttpGetWithCustomValidationE(t, url, tlsConfig, validateResponse)
+	return HttpGetWithRetryWithCustomValidationContextE(context.Background(), t, url, tlsConfig, retries, sleepBetweenRetries, validateResponse)
+}
+
+// HttpGetWithRetryWithCustomValidationContextE works like HttpGetWithRetryWithCustomValidationE, but stops retrying,
+// and aborts the pending request, as soon as the given context is done.
+func HttpGetWithRetryWithCustomValidationContextE(ctx context.Context, t testing.TestingT, url string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration, validateResponse func(int, string) bool) error {
+	_, err := retry.DoWithRetryContextE(ctx, t, fmt.Sprintf("HTTP GET to URL %s", url), retries, sleepBetweenRetries, func() (string, error) {
+		return "", httpGetWithCustomValidationContextE(ctx, t, url, tlsConfig, validateResponse)
 	})
 
 	return err
@@ -152,6 +197,14 @@ func HTTPDo(
 func HTTPDoE(
 	t testing.TestingT, method string, url string, body io.Reader,
 	headers map[string]string, tlsConfig *tls.Config,
+) (int, string, error) {
+	return HTTPDoContextE(context.Background(), t, method, url, body, headers, tlsConfig)
+}
+
+// HTTPDoContextE works like HTTPDoE, but aborts the request if the given context is done before it completes.
+func HTTPDoContextE(
+	ctx context.Context, t testing.TestingT, method string, url string, body io.Reader,
+	headers map[string]string, tlsConfig *tls.Config,
 ) (int, string, error) {
 	logger.Logf(t, "Making an HTTP %s call to URL %s", method, url)
 
@@ -165,7 +218,7 @@ func HTTPDoE(
 		Transport: tr,
 	}
 
-	req := newRequest(method, url, body, headers)
+	req := newRequest(ctx, method, url, body, headers)
 	resp, err := client.Do(req)
 	if err != nil {
 		return -1, "", err
@@ -205,11 +258,21 @@ func HTTPDoWithRetryE(
 	body []byte, headers map[string]string, expectedStatus int,
 	retries int, sleepBetweenRetries time.Duration, tlsConfig *tls.Config,
 ) (string, error) {
-	out, err := retry.DoWithRetryE(
-		t, fmt.Sprintf("HTTP %s to URL %s", method, url), retries,
+	return HTTPDoWithRetryContextE(context.Background(), t, method, url, body, headers, expectedStatus, retries, sleepBetweenRetries, tlsConfig)
+}
+
+// HTTPDoWithRetryContextE works like HTTPDoWithRetryE, but stops retrying, and aborts the pending request, as soon as
+// the given context is done.
+func HTTPDoWithRetryContextE(
+	ctx context.Context, t testing.TestingT, method string, url string,
+	body []byte, headers map[string]string, expectedStatus int,
+	retries int, sleepBetweenRetries time.Duration, tlsConfig *tls.Config,
+) (string, error) {
+	out, err := retry.DoWithRetryContextE(
+		ctx, t, fmt.Sprintf("HTTP %s to URL %s", method, url), retries,
 		sleepBetweenRetries, func() (string, error) {
 			bodyReader := bytes.NewReader(body)
-			statusCode, out, err := HTTPDoE(t, method, url, bodyReader, headers, tlsConfig)
+			statusCode, out, err := HTTPDoContextE(ctx, t, method, url, bodyReader, headers, tlsConfig)
 			if err != nil {
 				return "", err
 			}
@@ -294,8 +357,8 @@ func HTTPDoWithCustomValidationE(t testing.TestingT, method string, url string,
 	return nil
 }
 
-func newRequest(method string, url string, body io.Reader, headers map[string]string) *http.Request {
-	req, err := http.NewRequest(method, url, body)
+func newRequest(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) *http.Request {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil
 	}
@@ -2,6 +2,7 @@ package http_helper
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -120,6 +121,20 @@ func TestOkWithRetry(t *testing.T) {
 	require.Equal(t, body, response)
 }
 
+func TestHttpGetWithRetryContains(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>Hello, Terratest!</body></html>"))
+	})
+	defer ts.Close()
+
+	HttpGetWithRetryContains(t, ts.URL, nil, 200, "Hello, Terratest!", 2, time.Millisecond)
+
+	err := HttpGetWithRetryContainsE(t, ts.URL, nil, 200, "Goodbye", 1, time.Millisecond)
+	require.Error(t, err)
+}
+
 func TestErrorWithRetry(t *testing.T) {
 	t.Parallel()
 	ts := getTestServerForFunction(failRetryHandler)
@@ -139,6 +154,20 @@ func TestErrorWithRetry(t *testing.T) {
 	}
 }
 
+func TestHttpGetWithRetryContextStopsAtDeadline(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(wrongStatusHandler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := HttpGetWithRetryContextE(ctx, t, ts.URL, nil, 200, "", 100, time.Second)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.WithinDuration(t, start, time.Now(), 10*time.Second)
+}
+
 func bodyCopyHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	body, _ := ioutil.ReadAll(r.Body)
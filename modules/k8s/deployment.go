@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListDeployments will look for deployments in the given namespace that match the given filters and return them. This
+// will fail the test if there is an error.
+func ListDeployments(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []appsv1.Deployment {
+	deployments, err := ListDeploymentsE(t, options, filters)
+	require.NoError(t, err)
+	return deployments
+}
+
+// ListDeploymentsE will look for deployments in the given namespace that match the given filters and return them.
+func ListDeploymentsE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]appsv1.Deployment, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := clientset.AppsV1().Deployments(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return deployments.Items, nil
+}
+
+// GetDeployment returns a Kubernetes deployment resource in the provided namespace with the given name. This will
+// fail the test if there is an error.
+func GetDeployment(t testing.TestingT, options *KubectlOptions, deploymentName string) *appsv1.Deployment {
+	deployment, err := GetDeploymentE(t, options, deploymentName)
+	require.NoError(t, err)
+	return deployment
+}
+
+// GetDeploymentE returns a Kubernetes deployment resource in the provided namespace with the given name.
+func GetDeploymentE(t testing.TestingT, options *KubectlOptions, deploymentName string) (*appsv1.Deployment, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.AppsV1().Deployments(options.Namespace).Get(context.Background(), deploymentName, metav1.GetOptions{})
+}
+
+// WaitUntilDeploymentAvailable waits until the given deployment has rolled out and all its replicas are available,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. This will
+// fail the test if there is an error or if the check times out.
+func WaitUntilDeploymentAvailable(t testing.TestingT, options *KubectlOptions, deploymentName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilDeploymentAvailableE(t, options, deploymentName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilDeploymentAvailableE waits until the given deployment has rolled out and all its replicas are available,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilDeploymentAvailableE(t testing.TestingT, options *KubectlOptions, deploymentName string, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for deployment %s to be provisioned.", deploymentName)
+	message, err := retry.DoWithRetryE(
+		t,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			deployment, err := GetDeploymentE(t, options, deploymentName)
+			if err != nil {
+				return "", err
+			}
+			if !IsDeploymentAvailable(deployment) {
+				return "", NewDeploymentNotAvailableError(deployment)
+			}
+			return "Deployment is now available", nil
+		},
+	)
+	if err != nil {
+		logger.Logf(t, "Timed out waiting for Deployment to be provisioned: %s", err)
+		return err
+	}
+	logger.Logf(t, message)
+	return nil
+}
+
+// IsDeploymentAvailable returns true when the latest spec of the deployment has been rolled out to all its replicas,
+// no replicas of older specs are left, and the "Available" status condition is true. This mirrors the checks of
+// `kubectl rollout status`.
+func IsDeploymentAvailable(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+
+	desiredReplicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	if status.UpdatedReplicas < desiredReplicas || status.Replicas > status.UpdatedReplicas || status.AvailableReplicas < status.UpdatedReplicas {
+		return false
+	}
+
+	for _, condition := range status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
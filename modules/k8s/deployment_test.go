@@ -0,0 +1,136 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestGetDeploymentEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetDeploymentE(t, options, "sample-deployment")
+	require.Error(t, err)
+}
+
+func TestListDeploymentsAndWaitUntilDeploymentAvailable(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(EXAMPLE_DEPLOYMENT_YAML_TEMPLATE, uniqueID, uniqueID)
+	defer KubectlDeleteFromString(t, options, configData)
+	KubectlApplyFromString(t, options, configData)
+
+	deployments := ListDeployments(t, options, metav1.ListOptions{})
+	require.Equal(t, len(deployments), 1)
+	require.Equal(t, "sample-deployment", deployments[0].Name)
+
+	WaitUntilDeploymentAvailable(t, options, "sample-deployment", 60, 1*time.Second)
+
+	deployment := GetDeployment(t, options, "sample-deployment")
+	require.Equal(t, int32(2), deployment.Status.AvailableReplicas)
+}
+
+func TestIsDeploymentAvailable(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	available := []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+
+	cases := []struct {
+		title          string
+		deployment     *appsv1.Deployment
+		expectedResult bool
+	}{
+		{
+			title: "TestIsDeploymentAvailable",
+			deployment: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2, Conditions: available},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "TestIsDeploymentRollingOut",
+			deployment: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2, Conditions: available},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "TestIsDeploymentNotObserved",
+			deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2, Conditions: available},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "TestIsDeploymentStarting",
+			deployment: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{},
+			},
+			expectedResult: false,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			actualResult := IsDeploymentAvailable(tc.deployment)
+			require.Equal(t, tc.expectedResult, actualResult)
+		})
+	}
+}
+
+const EXAMPLE_DEPLOYMENT_YAML_TEMPLATE = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: sample-deployment
+  namespace: %s
+  labels:
+    app: sample-deployment
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      name: sample-deployment
+  template:
+    metadata:
+      labels:
+        name: sample-deployment
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+        ports:
+        - containerPort: 80
+`
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// CreateEksKubeConfig generates a kubectl config for the EKS cluster with the given name in a new temp file, and returns
+// its path. The config has a single context named after the cluster, which authenticates with `aws eks get-token`, so
+// the AWS CLI must be installed. Use the path as the ConfigPath of the KubectlOptions, and delete the file at the end of
+// the test. This will fail the test if there is an error.
+func CreateEksKubeConfig(t testing.TestingT, region string, clusterName string) string {
+	configPath, err := CreateEksKubeConfigE(t, region, clusterName)
+	require.NoError(t, err)
+	return configPath
+}
+
+// CreateEksKubeConfigE generates a kubectl config for the EKS cluster with the given name in a new temp file, and
+// returns its path. The config has a single context named after the cluster, which authenticates with
+// `aws eks get-token`, so the AWS CLI must be installed.
+func CreateEksKubeConfigE(t testing.TestingT, region string, clusterName string) (string, error) {
+	cluster, err := aws.GetEksClusterE(t, region, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := newEksKubeConfig(cluster, region)
+	if err != nil {
+		return "", err
+	}
+
+	tmpConfig, err := ioutil.TempFile("", "kubeconfig")
+	if err != nil {
+		return "", err
+	}
+	defer tmpConfig.Close()
+
+	logger.Logf(t, "Writing kubectl config for EKS cluster %s to %s", clusterName, tmpConfig.Name())
+	return tmpConfig.Name(), clientcmd.WriteToFile(*config, tmpConfig.Name())
+}
+
+// newEksKubeConfig returns a kubectl config with a single context for the given EKS cluster, named after the cluster.
+func newEksKubeConfig(cluster *eks.Cluster, region string) (*api.Config, error) {
+	clusterName := awssdk.StringValue(cluster.Name)
+
+	var caData []byte
+	if cluster.CertificateAuthority != nil {
+		var err error
+		caData, err = base64.StdEncoding.DecodeString(awssdk.StringValue(cluster.CertificateAuthority.Data))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := api.NewConfig()
+	config.Clusters[clusterName] = &api.Cluster{
+		Server:                   awssdk.StringValue(cluster.Endpoint),
+		CertificateAuthorityData: caData,
+	}
+	config.AuthInfos[clusterName] = &api.AuthInfo{
+		Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "aws",
+			Args:       []string{"eks", "get-token", "--cluster-name", clusterName, "--region", region},
+		},
+	}
+	UpsertConfigContext(config, clusterName, clusterName, clusterName)
+	config.CurrentContext = clusterName
+	return config, nil
+}
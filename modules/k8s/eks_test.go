@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEksKubeConfig(t *testing.T) {
+	t.Parallel()
+
+	cluster := &eks.Cluster{
+		Name:                 aws.String("terratest"),
+		Endpoint:             aws.String("https://ABC123.gr7.us-east-1.eks.amazonaws.com"),
+		CertificateAuthority: &eks.Certificate{Data: aws.String("Y2VydA==")},
+	}
+
+	config, err := newEksKubeConfig(cluster, "us-east-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "terratest", config.CurrentContext)
+	assert.Equal(t, "https://ABC123.gr7.us-east-1.eks.amazonaws.com", config.Clusters["terratest"].Server)
+	assert.Equal(t, []byte("cert"), config.Clusters["terratest"].CertificateAuthorityData)
+	assert.Equal(t, "terratest", config.Contexts["terratest"].AuthInfo)
+	assert.Equal(t, []string{"eks", "get-token", "--cluster-name", "terratest", "--region", "us-east-1"}, config.AuthInfos["terratest"].Exec.Args)
+}
@@ -3,6 +3,7 @@ package k8s
 import (
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -91,6 +92,23 @@ func NewJobNotSucceeded(job *batchv1.Job) JobNotSucceeded {
 	return JobNotSucceeded{job}
 }
 
+// DeploymentNotAvailable is returned when a Kubernetes deployment has not yet rolled out to all its replicas.
+type DeploymentNotAvailable struct {
+	deployment *appsv1.Deployment
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err DeploymentNotAvailable) Error() string {
+	return fmt.Sprintf(
+		"Deployment %s is not available: %d of %d replicas are updated and %d are available",
+		err.deployment.Name, err.deployment.Status.UpdatedReplicas, err.deployment.Status.Replicas, err.deployment.Status.AvailableReplicas)
+}
+
+// NewDeploymentNotAvailableError returns a DeploymentNotAvailable when the deployment has not yet rolled out
+func NewDeploymentNotAvailableError(deployment *appsv1.Deployment) DeploymentNotAvailable {
+	return DeploymentNotAvailable{deployment}
+}
+
 // ServiceNotAvailable is returned when a Kubernetes service is not yet available to accept traffic.
 type ServiceNotAvailable struct {
 	service *corev1.Service
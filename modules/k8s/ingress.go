@@ -63,8 +63,13 @@ func IsIngressAvailable(ingress *networkingv1.Ingress) bool {
 
 // WaitUntilIngressAvailable waits until the Ingress resource has an endpoint provisioned for it.
 func WaitUntilIngressAvailable(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilIngressAvailableE(t, options, ingressName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilIngressAvailableE waits until the Ingress resource has an endpoint provisioned for it.
+func WaitUntilIngressAvailableE(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for ingress %s to be provisioned.", ingressName)
-	message := retry.DoWithRetry(
+	message, err := retry.DoWithRetryE(
 		t,
 		statusMsg,
 		retries,
@@ -80,7 +85,12 @@ func WaitUntilIngressAvailable(t testing.TestingT, options *KubectlOptions, ingr
 			return "Ingress is now available", nil
 		},
 	)
+	if err != nil {
+		logger.Logf(t, "Timedout waiting for Ingress to be provisioned: %s", err)
+		return err
+	}
 	logger.Logf(t, message)
+	return nil
 }
 
 // ListIngressesV1Beta1 will look for Ingress resources in the given namespace that match the given filters and return
@@ -135,8 +145,14 @@ func IsIngressAvailableV1Beta1(ingress *networkingv1beta1.Ingress) bool {
 // WaitUntilIngressAvailableV1Beta1 waits until the Ingress resource has an endpoint provisioned for it, using
 // networking.k8s.io/v1beta1 API.
 func WaitUntilIngressAvailableV1Beta1(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilIngressAvailableV1Beta1E(t, options, ingressName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilIngressAvailableV1Beta1E waits until the Ingress resource has an endpoint provisioned for it, using
+// networking.k8s.io/v1beta1 API.
+func WaitUntilIngressAvailableV1Beta1E(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for ingress %s to be provisioned.", ingressName)
-	message := retry.DoWithRetry(
+	message, err := retry.DoWithRetryE(
 		t,
 		statusMsg,
 		retries,
@@ -152,5 +168,10 @@ func WaitUntilIngressAvailableV1Beta1(t testing.TestingT, options *KubectlOption
 			return "Ingress is now available", nil
 		},
 	)
+	if err != nil {
+		logger.Logf(t, "Timedout waiting for Ingress to be provisioned: %s", err)
+		return err
+	}
 	logger.Logf(t, message)
+	return nil
 }
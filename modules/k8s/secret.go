@@ -34,8 +34,14 @@ func GetSecretE(t testing.TestingT, options *KubectlOptions, secretName string)
 // WaitUntilSecretAvailable waits until the secret is present on the cluster in cases where it is not immediately
 // available (for example, when using ClusterIssuer to request a certificate).
 func WaitUntilSecretAvailable(t testing.TestingT, options *KubectlOptions, secretName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilSecretAvailableE(t, options, secretName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilSecretAvailableE waits until the secret is present on the cluster in cases where it is not immediately
+// available (for example, when using ClusterIssuer to request a certificate).
+func WaitUntilSecretAvailableE(t testing.TestingT, options *KubectlOptions, secretName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for secret %s to be provisioned.", secretName)
-	message := retry.DoWithRetry(
+	message, err := retry.DoWithRetryE(
 		t,
 		statusMsg,
 		retries,
@@ -49,5 +55,10 @@ func WaitUntilSecretAvailable(t testing.TestingT, options *KubectlOptions, secre
 			return "Secret is now available", nil
 		},
 	)
+	if err != nil {
+		logger.Logf(t, "Timedout waiting for Secret to be provisioned: %s", err)
+		return err
+	}
 	logger.Logf(t, message)
+	return nil
 }
@@ -58,8 +58,13 @@ func GetServiceE(t testing.TestingT, options *KubectlOptions, serviceName string
 
 // WaitUntilServiceAvailable waits until the service endpoint is ready to accept traffic.
 func WaitUntilServiceAvailable(t testing.TestingT, options *KubectlOptions, serviceName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilServiceAvailableE(t, options, serviceName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilServiceAvailableE waits until the service endpoint is ready to accept traffic.
+func WaitUntilServiceAvailableE(t testing.TestingT, options *KubectlOptions, serviceName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for service %s to be provisioned.", serviceName)
-	message := retry.DoWithRetry(
+	message, err := retry.DoWithRetryE(
 		t,
 		statusMsg,
 		retries,
@@ -83,7 +88,12 @@ func WaitUntilServiceAvailable(t testing.TestingT, options *KubectlOptions, serv
 			return "Service is now available", nil
 		},
 	)
+	if err != nil {
+		logger.Logf(t, "Timedout waiting for Service to be provisioned: %s", err)
+		return err
+	}
 	logger.Logf(t, message)
+	return nil
 }
 
 // IsServiceAvailable returns true if the service endpoint is ready to accept traffic. Note that for Minikube, this
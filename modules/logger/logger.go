@@ -7,16 +7,20 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	gotesting "testing"
 	"time"
 
+	"github.com/gruntwork-io/terratest/modules/environment"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
 var (
 	// Default is the default logger that is used for the Logf function, if no one is provided. It uses the
-	// TerratestLogger to log messages. This can be overwritten to change the logging globally.
-	Default = New(terratestLogger{})
+	// TerratestLogger to log messages, or discards them if the TERRATEST_LOG_LEVEL environment variable is set to
+	// quiet when the test binary starts (see environment.Overrides). This can be overwritten to change the logging
+	// globally.
+	Default = New(defaultTestLogger())
 	// Discard discards all logging.
 	Discard = New(discardLogger{})
 	// Terratest logs the given format and arguments, formatted using fmt.Sprintf, to stdout, along with a timestamp and
@@ -67,15 +71,72 @@ func (l *Logger) Logf(t testing.TestingT, format string, args ...interface{}) {
 		return
 	}
 
+	if hasMaskedValues() {
+		l.l.Logf(t, "%s", mask(fmt.Sprintf(format, args...)))
+		return
+	}
+
 	l.l.Logf(t, format, args...)
 }
 
+// MaskedValuePlaceholder replaces the values registered with MaskValue in log messages.
+const MaskedValuePlaceholder = "***"
+
+var (
+	maskedValuesMutex sync.RWMutex
+	maskedValues      []string
+)
+
+// MaskValue replaces the given value with MaskedValuePlaceholder in every message logged from now on by any Logger
+// (and by the Logf, Log, and DoLog functions), e.g. in the arguments of a command that is echoed before it's run. Use
+// it for secrets, such as generated passwords, that your test needs to pass around. Empty values are ignored.
+func MaskValue(value string) {
+	if value == "" {
+		return
+	}
+
+	maskedValuesMutex.Lock()
+	defer maskedValuesMutex.Unlock()
+	for _, maskedValue := range maskedValues {
+		if maskedValue == value {
+			return
+		}
+	}
+	maskedValues = append(maskedValues, value)
+}
+
+func hasMaskedValues() bool {
+	maskedValuesMutex.RLock()
+	defer maskedValuesMutex.RUnlock()
+	return len(maskedValues) > 0
+}
+
+// mask replaces all the values registered with MaskValue in the given message.
+func mask(message string) string {
+	maskedValuesMutex.RLock()
+	defer maskedValuesMutex.RUnlock()
+	for _, maskedValue := range maskedValues {
+		message = strings.ReplaceAll(message, maskedValue, MaskedValuePlaceholder)
+	}
+	return message
+}
+
 // helper is used to mark this library as a "helper", and thus not appearing in the line numbers. testing.T implements
 // this interface, for example.
 type helper interface {
 	Helper()
 }
 
+// defaultTestLogger returns the TestLogger of the Default logger for the log level set in the environment. An invalid
+// log level is ignored here, as there is no test to fail yet; it fails the tests that load the overrides.
+func defaultTestLogger() TestLogger {
+	logLevel, err := environment.GetLogLevelOverrideE()
+	if err == nil && logLevel == environment.LogLevelQuiet {
+		return discardLogger{}
+	}
+	return terratestLogger{}
+}
+
 type discardLogger struct{}
 
 func (_ discardLogger) Logf(_ testing.TestingT, format string, args ...interface{}) {}
@@ -139,12 +200,21 @@ func Log(t testing.TestingT, args ...interface{}) {
 }
 
 // DoLog logs the given arguments to the given writer, along with a timestamp and information about what test and file is
-// doing the logging.
+// doing the logging. Every line of a multi-line message gets this prefix, and the message is written in one go, so that
+// the output of tests running in parallel can still be told apart.
 func DoLog(t testing.TestingT, callDepth int, writer io.Writer, args ...interface{}) {
 	date := time.Now()
 	prefix := fmt.Sprintf("%s %s %s:", t.Name(), date.Format(time.RFC3339), CallerPrefix(callDepth+1))
-	allArgs := append([]interface{}{prefix}, args...)
-	fmt.Fprintln(writer, allArgs...)
+	message := strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	if hasMaskedValues() {
+		message = mask(message)
+	}
+
+	var out strings.Builder
+	for _, line := range strings.Split(message, "\n") {
+		out.WriteString(fmt.Sprintln(prefix, line))
+	}
+	fmt.Fprint(writer, out.String())
 }
 
 // CallerPrefix returns the file and line number information about the methods that called this method, based on the current
@@ -21,6 +21,19 @@ func TestDoLog(t *testing.T) {
 	assert.Regexp(t, fmt.Sprintf("^%s .+? [[:word:]]+.go:[0-9]+: %s$", t.Name(), text), strings.TrimSpace(buffer.String()))
 }
 
+func TestDoLogPrefixesEveryLine(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	DoLog(t, 1, &buffer, "first line\nsecond line")
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Regexp(t, fmt.Sprintf("^%s .+? [[:word:]]+.go:[0-9]+: first line$", t.Name()), lines[0])
+	assert.Regexp(t, fmt.Sprintf("^%s .+? [[:word:]]+.go:[0-9]+: second line$", t.Name()), lines[1])
+}
+
 type customLogger struct {
 	logs []string
 }
@@ -52,3 +65,21 @@ func TestCustomLogger(t *testing.T) {
 	assert.Equal(t, "log output 2", c.logs[1])
 	assert.Equal(t, "subtest log", c.logs[2])
 }
+
+func TestMaskValue(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// MaskValue changes the messages of every logger, so don't log concurrently with other tests.
+
+	secret := "TestMaskValue-s3cr3t"
+	MaskValue(secret)
+	MaskValue("")
+
+	c := &customLogger{}
+	New(c).Logf(t, "password=%s", secret)
+	assert.Equal(t, []string{"password=" + MaskedValuePlaceholder}, c.logs)
+
+	var buffer bytes.Buffer
+	DoLog(t, 1, &buffer, "password="+secret)
+	assert.NotContains(t, buffer.String(), secret)
+	assert.Contains(t, buffer.String(), "password="+MaskedValuePlaceholder)
+}
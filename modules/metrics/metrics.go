@@ -0,0 +1,122 @@
+// Package metrics allows to emit metrics about tests, such as their durations, retries, failures, and the number of
+// resources they create, to a monitoring system, so the health of infrastructure tests can be trended over time without
+// scraping CI logs. Metrics are only recorded if a Sink is set, typically in TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		metrics.SetSink(metrics.NewStatsdSink("localhost:8125", "terratest"))
+//		code := m.Run()
+//		metrics.Flush()
+//		os.Exit(code)
+//	}
+//
+// Besides the metrics recorded with Record and TrackTest, the terraform package records the duration, retries, and
+// failures of every Terraform command, and the number of resources added, changed, and destroyed by every apply.
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// The units of metrics.
+const (
+	UnitCount   = "Count"
+	UnitSeconds = "Seconds"
+)
+
+// Metric is a single measurement.
+type Metric struct {
+	Name      string            // The name of the metric, e.g., terraform.command.duration
+	Value     float64           // The measured value
+	Unit      string            // The unit of the value, e.g., UnitSeconds
+	Tags      map[string]string // Dimensions of the metric, e.g., the name of the test
+	Timestamp time.Time         // When the value was measured
+}
+
+// Sink sends metrics to a monitoring system.
+type Sink interface {
+	Send(metrics []Metric) error
+}
+
+var (
+	mutex    sync.Mutex
+	sink     Sink
+	buffered []Metric
+)
+
+// SetSink sets the process-wide Sink that metrics are sent to. Pass nil, the default, to not record any metrics.
+func SetSink(newSink Sink) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	sink = newSink
+}
+
+// Enabled returns true if a Sink is set, so that callers can skip measuring values that would be thrown away.
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return sink != nil
+}
+
+// Record records a metric for the given test, adding the name of the test to its tags. Metrics are buffered until
+// Flush is called. If no Sink is set, this does nothing.
+func Record(t testing.TestingT, name string, value float64, unit string, tags map[string]string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if sink == nil {
+		return
+	}
+
+	allTags := map[string]string{"test": t.Name()}
+	for key, tagValue := range tags {
+		allTags[key] = tagValue
+	}
+	buffered = append(buffered, Metric{Name: name, Value: value, Unit: unit, Tags: allTags, Timestamp: time.Now()})
+}
+
+// TrackTest records how long the given test took, and whether it failed, when the returned function is called. It's
+// meant to be deferred at the start of a test:
+//
+//	defer metrics.TrackTest(t)()
+func TrackTest(t testing.TestingT) func() {
+	start := time.Now()
+	return func() {
+		Record(t, "test.duration", time.Since(start).Seconds(), UnitSeconds, nil)
+
+		// testing.TestingT doesn't include Failed, but *testing.T implements it
+		if failer, ok := t.(interface{ Failed() bool }); ok {
+			failed := 0.0
+			if failer.Failed() {
+				failed = 1
+			}
+			Record(t, "test.failed", failed, UnitCount, nil)
+		}
+	}
+}
+
+// Flush sends all the buffered metrics to the Sink. Errors are written to stderr rather than returned, as metrics should
+// never make a test fail. It's meant to be called from TestMain, where there is no test to log to.
+func Flush() {
+	if err := FlushE(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send metrics: %v\n", err)
+	}
+}
+
+// FlushE sends all the buffered metrics to the Sink.
+func FlushE() error {
+	mutex.Lock()
+	currentSink := sink
+	metrics := buffered
+	buffered = nil
+	mutex.Unlock()
+
+	if currentSink == nil || len(metrics) == 0 {
+		return nil
+	}
+	return currentSink.Send(metrics)
+}
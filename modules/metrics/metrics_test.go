@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSink struct {
+	sent []Metric
+}
+
+func (sink *fakeSink) Send(metrics []Metric) error {
+	sink.sent = append(sink.sent, metrics...)
+	return nil
+}
+
+func TestRecordAndFlush(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The sink is process-wide.
+
+	Record(t, "ignored", 1, UnitCount, nil)
+
+	sink := &fakeSink{}
+	SetSink(sink)
+	defer SetSink(nil)
+
+	done := TrackTest(t)
+	Record(t, "terraform.resources.added", 3, UnitCount, map[string]string{"module": "vpc"})
+	done()
+	require.NoError(t, FlushE())
+
+	require.Len(t, sink.sent, 3)
+	assert.Equal(t, "terraform.resources.added", sink.sent[0].Name)
+	assert.Equal(t, 3.0, sink.sent[0].Value)
+	assert.Equal(t, map[string]string{"test": t.Name(), "module": "vpc"}, sink.sent[0].Tags)
+	assert.Equal(t, "test.duration", sink.sent[1].Name)
+	assert.Equal(t, UnitSeconds, sink.sent[1].Unit)
+	assert.Equal(t, "test.failed", sink.sent[2].Name)
+	assert.Equal(t, 0.0, sink.sent[2].Value)
+
+	// Flushing again sends nothing new
+	require.NoError(t, FlushE())
+	assert.Len(t, sink.sent, 3)
+}
+
+func TestFormatStatsd(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "terratest.test.duration:1500|ms|#module:vpc,test:TestVpc", formatStatsd("terratest", Metric{
+		Name:  "test.duration",
+		Value: 1.5,
+		Unit:  UnitSeconds,
+		Tags:  map[string]string{"test": "TestVpc", "module": "vpc"},
+	}))
+	assert.Equal(t, "terraform.command.retries:2|g", formatStatsd("", Metric{Name: "terraform.command.retries", Value: 2, Unit: UnitCount}))
+}
+
+func TestStatsdSink(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink := NewStatsdSink(conn.LocalAddr().String(), "terratest")
+	require.NoError(t, sink.Send([]Metric{{Name: "test.failed", Value: 1, Unit: UnitCount}}))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "terratest.test.failed:1|g", string(buf[:n]))
+}
+
+func TestPushgatewaySink(t *testing.T) {
+	t.Parallel()
+
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		contents, _ := ioutil.ReadAll(r.Body)
+		body = string(contents)
+	}))
+	defer server.Close()
+
+	sink := NewPushgatewaySink(server.URL+"/", "terratest")
+	require.NoError(t, sink.Send([]Metric{
+		{Name: "test.duration", Value: 12.5, Unit: UnitSeconds, Tags: map[string]string{"test": "TestVpc"}},
+		{Name: "terraform.command.retries", Value: 1, Unit: UnitCount, Tags: map[string]string{"test": "TestVpc", "command": "apply"}},
+		{Name: "terraform.command.retries", Value: 0, Unit: UnitCount, Tags: map[string]string{"test": "TestVpc", "command": "apply"}},
+	}))
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/terratest", path)
+	assert.Equal(t, `terraform_command_retries{command="apply",test="TestVpc"} 0
+test_duration_seconds{test="TestVpc"} 12.5
+`, body)
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StatsdSink sends metrics to a statsd server over UDP. Metrics in seconds are sent as timers (in milliseconds), and
+// all other metrics as gauges. Tags are sent in the DogStatsD format, which is understood by most statsd servers.
+type StatsdSink struct {
+	Address string // The host:port of the statsd server, e.g., localhost:8125
+	Prefix  string // If set, prefixed to the names of all metrics, followed by a dot
+}
+
+// NewStatsdSink returns a StatsdSink that sends metrics to the statsd server at the given host:port.
+func NewStatsdSink(address string, prefix string) *StatsdSink {
+	return &StatsdSink{Address: address, Prefix: prefix}
+}
+
+// Send sends the given metrics, one per packet.
+func (sink *StatsdSink) Send(metrics []Metric) error {
+	conn, err := net.Dial("udp", sink.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, metric := range metrics {
+		if _, err := conn.Write([]byte(formatStatsd(sink.Prefix, metric))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatStatsd(prefix string, metric Metric) string {
+	name := metric.Name
+	if prefix != "" {
+		name = prefix + "." + name
+	}
+
+	line := fmt.Sprintf("%s:%g|g", name, metric.Value)
+	if metric.Unit == UnitSeconds {
+		line = fmt.Sprintf("%s:%g|ms", name, metric.Value*1000)
+	}
+
+	if len(metric.Tags) > 0 {
+		tags := []string{}
+		for _, key := range sortedKeys(metric.Tags) {
+			tags = append(tags, key+":"+metric.Tags[key])
+		}
+		line += "|#" + strings.Join(tags, ",")
+	}
+	return line
+}
+
+// PushgatewaySink sends metrics to a Prometheus Pushgateway. Every Send replaces the metrics previously pushed for the
+// same Job, so Flush should only be called once per test run.
+type PushgatewaySink struct {
+	URL    string       // The URL of the Pushgateway, e.g., http://localhost:9091
+	Job    string       // The job label to push the metrics under
+	Client *http.Client // The HTTP client to use. Defaults to http.DefaultClient.
+}
+
+// NewPushgatewaySink returns a PushgatewaySink that pushes metrics to the Pushgateway at the given URL under the given
+// job.
+func NewPushgatewaySink(url string, job string) *PushgatewaySink {
+	return &PushgatewaySink{URL: url, Job: job}
+}
+
+// Send pushes the given metrics in the Prometheus text format.
+func (sink *PushgatewaySink) Send(metrics []Metric) error {
+	client := sink.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	url := fmt.Sprintf("%s/metrics/job/%s", strings.TrimSuffix(sink.URL, "/"), sink.Job)
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(formatPrometheus(metrics)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("pushing metrics to %s failed with status %s", url, resp.Status)
+	}
+	return nil
+}
+
+var invalidPrometheusNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// formatPrometheus formats the given metrics in the Prometheus text format. The Pushgateway doesn't accept timestamps
+// or several samples of the same series, so the last value of every series wins.
+func formatPrometheus(metrics []Metric) string {
+	series := map[string]float64{}
+	for _, metric := range metrics {
+		name := invalidPrometheusNameChars.ReplaceAllString(metric.Name, "_")
+		if metric.Unit == UnitSeconds {
+			name += "_seconds"
+		}
+
+		labels := []string{}
+		for _, key := range sortedKeys(metric.Tags) {
+			labelName := invalidPrometheusNameChars.ReplaceAllString(key, "_")
+			labels = append(labels, fmt.Sprintf("%s=%q", labelName, metric.Tags[key]))
+		}
+
+		series[fmt.Sprintf("%s{%s}", name, strings.Join(labels, ","))] = metric.Value
+	}
+
+	keys := []string{}
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&out, "%s %g\n", key, series[key])
+	}
+	return out.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
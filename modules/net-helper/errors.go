@@ -0,0 +1,13 @@
+package net_helper
+
+import "fmt"
+
+// PortOpen is an error that occurs when a TCP port that is expected to be closed accepts connections.
+type PortOpen struct {
+	Host string
+	Port int
+}
+
+func (err PortOpen) Error() string {
+	return fmt.Sprintf("Expected TCP port %d on %s to be closed, but a connection was opened", err.Port, err.Host)
+}
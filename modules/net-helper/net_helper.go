@@ -0,0 +1,69 @@
+// Package net_helper contains helpers to check network connectivity to deployed resources, e.g. to verify that
+// security groups and network ACLs allow or block the expected ports. ICMP (ping) checks are not included, as they
+// require raw socket privileges that tests usually don't have.
+package net_helper
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// CanConnectTcp returns true if a TCP connection to the given host and port can be opened within the given timeout.
+func CanConnectTcp(t testing.TestingT, host string, port int, timeout time.Duration) bool {
+	return CanConnectTcpE(t, host, port, timeout) == nil
+}
+
+// CanConnectTcpE tries to open a TCP connection to the given host and port within the given timeout, returning the
+// error if it can't.
+func CanConnectTcpE(t testing.TestingT, host string, port int, timeout time.Duration) error {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	logger.Logf(t, "Opening a TCP connection to %s", address)
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// AssertPortOpen repeatedly tries to open a TCP connection to the given host and port until it succeeds or until max
+// retries has been exceeded. If it never succeeds, fail the test.
+func AssertPortOpen(t testing.TestingT, host string, port int, timeout time.Duration, retries int, sleepBetweenRetries time.Duration) {
+	err := AssertPortOpenE(t, host, port, timeout, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// AssertPortOpenE repeatedly tries to open a TCP connection to the given host and port until it succeeds or until max
+// retries has been exceeded.
+func AssertPortOpenE(t testing.TestingT, host string, port int, timeout time.Duration, retries int, sleepBetweenRetries time.Duration) error {
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Open TCP connection to %s:%d", host, port), retries, sleepBetweenRetries, func() (string, error) {
+		return "", CanConnectTcpE(t, host, port, timeout)
+	})
+	return err
+}
+
+// AssertPortClosed repeatedly tries to open a TCP connection to the given host and port until it fails (the connection
+// is refused or times out) or until max retries has been exceeded. If the port stays open, fail the test.
+func AssertPortClosed(t testing.TestingT, host string, port int, timeout time.Duration, retries int, sleepBetweenRetries time.Duration) {
+	err := AssertPortClosedE(t, host, port, timeout, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// AssertPortClosedE repeatedly tries to open a TCP connection to the given host and port until it fails (the
+// connection is refused or times out) or until max retries has been exceeded.
+func AssertPortClosedE(t testing.TestingT, host string, port int, timeout time.Duration, retries int, sleepBetweenRetries time.Duration) error {
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Verify TCP port %s:%d is closed", host, port), retries, sleepBetweenRetries, func() (string, error) {
+		if CanConnectTcp(t, host, port, timeout) {
+			return "", PortOpen{Host: host, Port: port}
+		}
+		return "", nil
+	})
+	return err
+}
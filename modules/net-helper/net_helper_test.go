@@ -0,0 +1,44 @@
+package net_helper
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listenOnRandomPort(t *testing.T) (net.Listener, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return listener, listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestAssertPortOpen(t *testing.T) {
+	t.Parallel()
+
+	listener, port := listenOnRandomPort(t)
+	defer listener.Close()
+
+	assert.True(t, CanConnectTcp(t, "127.0.0.1", port, time.Second))
+	AssertPortOpen(t, "127.0.0.1", port, time.Second, 3, time.Millisecond)
+
+	err := AssertPortClosedE(t, "127.0.0.1", port, time.Second, 2, time.Millisecond)
+	_, isMaxRetriesErr := err.(retry.MaxRetriesExceeded)
+	assert.True(t, isMaxRetriesErr, "unexpected error, got %v", err)
+}
+
+func TestAssertPortClosed(t *testing.T) {
+	t.Parallel()
+
+	listener, port := listenOnRandomPort(t)
+	listener.Close()
+
+	assert.False(t, CanConnectTcp(t, "127.0.0.1", port, time.Second))
+	AssertPortClosed(t, "127.0.0.1", port, time.Second, 3, time.Millisecond)
+
+	err := AssertPortOpenE(t, "127.0.0.1", port, time.Second, 2, time.Millisecond)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+// Package nomad allows to check the health of Nomad clusters through the Nomad HTTP API.
+package nomad
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// NodeStatusReady is the status of a Nomad client node that is ready to run allocations.
+const NodeStatusReady = "ready"
+
+// Node represents a Nomad client node, as returned by /v1/nodes.
+type Node struct {
+	ID                    string
+	Name                  string
+	Address               string
+	Datacenter            string
+	Status                string
+	SchedulingEligibility string
+}
+
+// GetNodes returns the client nodes registered with the Nomad cluster at the given address (e.g.
+// http://1.2.3.4:4646).
+func GetNodes(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config) []Node {
+	nodes, err := GetNodesE(t, nomadAddr, tlsConfig)
+	require.NoError(t, err)
+	return nodes
+}
+
+// GetNodesE returns the client nodes registered with the Nomad cluster at the given address (e.g.
+// http://1.2.3.4:4646).
+func GetNodesE(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config) ([]Node, error) {
+	nodes := []Node{}
+	if err := getJsonE(t, fmt.Sprintf("%s/v1/nodes", nomadAddr), tlsConfig, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// GetServerPeers returns the addresses of the Raft peers (i.e. the servers) of the Nomad cluster.
+func GetServerPeers(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config) []string {
+	peers, err := GetServerPeersE(t, nomadAddr, tlsConfig)
+	require.NoError(t, err)
+	return peers
+}
+
+// GetServerPeersE returns the addresses of the Raft peers (i.e. the servers) of the Nomad cluster.
+func GetServerPeersE(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config) ([]string, error) {
+	peers := []string{}
+	if err := getJsonE(t, fmt.Sprintf("%s/v1/status/peers", nomadAddr), tlsConfig, &peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// GetLeader returns the address of the Raft leader of the Nomad cluster, or an empty string if there is no leader.
+func GetLeader(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config) string {
+	leader, err := GetLeaderE(t, nomadAddr, tlsConfig)
+	require.NoError(t, err)
+	return leader
+}
+
+// GetLeaderE returns the address of the Raft leader of the Nomad cluster, or an empty string if there is no leader.
+func GetLeaderE(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config) (string, error) {
+	var leader string
+	if err := getJsonE(t, fmt.Sprintf("%s/v1/status/leader", nomadAddr), tlsConfig, &leader); err != nil {
+		return "", err
+	}
+	return leader, nil
+}
+
+// WaitForClusterSize waits until the Nomad cluster has elected a leader, has at least the expected number of servers,
+// and has at least the expected number of ready clients, failing the test if that does not happen after the given
+// number of retries.
+func WaitForClusterSize(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config, expectedServers int, expectedClients int, retries int, sleepBetweenRetries time.Duration) {
+	err := WaitForClusterSizeE(t, nomadAddr, tlsConfig, expectedServers, expectedClients, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForClusterSizeE waits until the Nomad cluster has elected a leader, has at least the expected number of servers,
+// and has at least the expected number of ready clients, returning an error if that does not happen after the given
+// number of retries.
+func WaitForClusterSizeE(t testing.TestingT, nomadAddr string, tlsConfig *tls.Config, expectedServers int, expectedClients int, retries int, sleepBetweenRetries time.Duration) error {
+	description := fmt.Sprintf("Waiting for Nomad cluster at %s to have %d servers and %d clients", nomadAddr, expectedServers, expectedClients)
+	_, err := retry.DoWithRetryE(t, description, retries, sleepBetweenRetries, func() (string, error) {
+		leader, err := GetLeaderE(t, nomadAddr, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+		if leader == "" {
+			return "", NoLeader(nomadAddr)
+		}
+
+		peers, err := GetServerPeersE(t, nomadAddr, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+
+		nodes, err := GetNodesE(t, nomadAddr, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+
+		readyClients := countReadyNodes(nodes)
+		if len(peers) < expectedServers || readyClients < expectedClients {
+			return "", ClusterSizeNotMet{ExpectedServers: expectedServers, ExpectedClients: expectedClients, ActualServers: len(peers), ActualClients: readyClients}
+		}
+
+		logger.Logf(t, "Nomad cluster at %s has leader %s, %d servers, and %d ready clients", nomadAddr, leader, len(peers), readyClients)
+		return "", nil
+	})
+	return err
+}
+
+// countReadyNodes returns the number of nodes that are ready and eligible for scheduling.
+func countReadyNodes(nodes []Node) int {
+	ready := 0
+	for _, node := range nodes {
+		if node.Status == NodeStatusReady && node.SchedulingEligibility != "ineligible" {
+			ready++
+		}
+	}
+	return ready
+}
+
+func getJsonE(t testing.TestingT, url string, tlsConfig *tls.Config, v interface{}) error {
+	statusCode, body, err := http_helper.HttpGetE(t, url, tlsConfig)
+	if err != nil {
+		return err
+	}
+	if statusCode != 200 {
+		return UnexpectedResponse{Url: url, Status: statusCode, Body: body}
+	}
+	return json.Unmarshal([]byte(body), v)
+}
@@ -0,0 +1,63 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nodesJson = `[
+	{"ID": "node-1", "Name": "client-1", "Address": "10.0.0.4", "Datacenter": "dc1", "Status": "ready", "SchedulingEligibility": "eligible"},
+	{"ID": "node-2", "Name": "client-2", "Address": "10.0.0.5", "Datacenter": "dc1", "Status": "ready", "SchedulingEligibility": "ineligible"},
+	{"ID": "node-3", "Name": "client-3", "Address": "10.0.0.6", "Datacenter": "dc1", "Status": "down", "SchedulingEligibility": "eligible"}
+]`
+
+func newFakeNomadServer(leader string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, nodesJson)
+	})
+	mux.HandleFunc("/v1/status/peers", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["10.0.0.1:4647", "10.0.0.2:4647", "10.0.0.3:4647"]`)
+	})
+	mux.HandleFunc("/v1/status/leader", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%q", leader)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGetNodes(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeNomadServer("10.0.0.1:4647")
+	defer server.Close()
+
+	nodes := GetNodes(t, server.URL, nil)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "client-1", nodes[0].Name)
+	assert.Equal(t, 1, countReadyNodes(nodes))
+}
+
+func TestWaitForClusterSize(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeNomadServer("10.0.0.1:4647")
+	defer server.Close()
+
+	assert.NoError(t, WaitForClusterSizeE(t, server.URL, nil, 3, 1, 1, time.Millisecond))
+	assert.Error(t, WaitForClusterSizeE(t, server.URL, nil, 3, 2, 1, time.Millisecond))
+}
+
+func TestWaitForClusterSizeNoLeader(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeNomadServer("")
+	defer server.Close()
+
+	assert.Error(t, WaitForClusterSizeE(t, server.URL, nil, 1, 0, 1, time.Millisecond))
+}
@@ -0,0 +1,89 @@
+// Package notify allows to send notifications about resources that a test might have leaked, e.g., because destroy
+// failed or was skipped, to a webhook, Slack, or (with aws.SnsNotifier) an SNS topic, so the leaks can be triaged right
+// away instead of when the bill arrives.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Leak describes resources that a test might have leaked.
+type Leak struct {
+	TestName     string `json:"test_name"`
+	UniqueID     string `json:"unique_id,omitempty"`     // The unique ID the resources were namespaced with, if any
+	Region       string `json:"region,omitempty"`        // The region the resources were created in, if any
+	TemplatePath string `json:"template_path,omitempty"` // The path to the Terraform code that created the resources
+	Reason       string `json:"reason"`                  // Why the resources might have leaked, e.g., "destroy failed"
+	Error        string `json:"error,omitempty"`         // The error that caused the leak, if any
+}
+
+// Message returns a human readable description of the leak.
+func (leak Leak) Message() string {
+	lines := []string{fmt.Sprintf("Resources created by test %s might have leaked: %s.", leak.TestName, leak.Reason)}
+	if leak.UniqueID != "" {
+		lines = append(lines, fmt.Sprintf("Unique ID: %s", leak.UniqueID))
+	}
+	if leak.Region != "" {
+		lines = append(lines, fmt.Sprintf("Region: %s", leak.Region))
+	}
+	if leak.TemplatePath != "" {
+		lines = append(lines, fmt.Sprintf("Template: %s", leak.TemplatePath))
+	}
+	if leak.Error != "" {
+		lines = append(lines, fmt.Sprintf("Error: %s", leak.Error))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Notifier sends notifications about leaks.
+type Notifier interface {
+	NotifyLeak(leak Leak) error
+}
+
+// WebhookNotifier sends leaks as JSON in the body of a POST request to a URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client // The HTTP client to use. Defaults to http.DefaultClient.
+}
+
+// NotifyLeak sends the given leak to the webhook.
+func (notifier WebhookNotifier) NotifyLeak(leak Leak) error {
+	return postJSON(notifier.Client, notifier.URL, leak)
+}
+
+// SlackNotifier sends leaks as messages to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client // The HTTP client to use. Defaults to http.DefaultClient.
+}
+
+// NotifyLeak sends the given leak to Slack.
+func (notifier SlackNotifier) NotifyLeak(leak Leak) error {
+	return postJSON(notifier.Client, notifier.WebhookURL, map[string]string{"text": leak.Message()})
+}
+
+func postJSON(client *http.Client, url string, body interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	contents, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(contents))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sending notification to %s failed with status %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLeak = Leak{
+	TestName:     "TestVpc",
+	UniqueID:     "a1b2c3",
+	Region:       "us-east-1",
+	TemplatePath: "/tmp/terraform-vpc",
+	Reason:       "destroy failed",
+	Error:        "Error: DependencyViolation",
+}
+
+func TestLeakMessage(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Resources created by test TestVpc might have leaked: destroy failed.\nUnique ID: a1b2c3\nRegion: us-east-1\nTemplate: /tmp/terraform-vpc\nError: Error: DependencyViolation", testLeak.Message())
+	assert.Equal(t, "Resources created by test TestVpc might have leaked: destroy skipped.", Leak{TestName: "TestVpc", Reason: "destroy skipped"}.Message())
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Parallel()
+
+	var received Leak
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	require.NoError(t, WebhookNotifier{URL: server.URL}.NotifyLeak(testLeak))
+	assert.Equal(t, testLeak, received)
+}
+
+func TestSlackNotifier(t *testing.T) {
+	t.Parallel()
+
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	require.NoError(t, SlackNotifier{WebhookURL: server.URL}.NotifyLeak(testLeak))
+	assert.Equal(t, map[string]string{"text": testLeak.Message()}, received)
+}
+
+func TestNotifierFailsOnErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	assert.Error(t, WebhookNotifier{URL: server.URL}.NotifyLeak(testLeak))
+}
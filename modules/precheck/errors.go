@@ -0,0 +1,60 @@
+package precheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingEnvVar is an error that occurs when a required environment variable is not set.
+type MissingEnvVar string
+
+func (err MissingEnvVar) Error() string {
+	return fmt.Sprintf("Environment variable %s must be set to run these tests.", string(err))
+}
+
+// BinaryNotFound is an error that occurs when the terraform binary is not on the PATH.
+type BinaryNotFound struct {
+	Binary     string
+	Underlying error
+}
+
+func (err BinaryNotFound) Error() string {
+	return fmt.Sprintf("Could not find %s on the PATH: %v. Install it or add it to the PATH.", err.Binary, err.Underlying)
+}
+
+// UnsupportedVersion is an error that occurs when the terraform binary does not satisfy the version constraint.
+type UnsupportedVersion struct {
+	Binary     string
+	Version    string
+	Constraint string
+}
+
+func (err UnsupportedVersion) Error() string {
+	return fmt.Sprintf("Found %s version %s, but these tests require a version matching %q.", err.Binary, err.Version, err.Constraint)
+}
+
+// VersionNotParsable is an error that occurs when the version can't be found in the output of `terraform version`.
+type VersionNotParsable string
+
+func (err VersionNotParsable) Error() string {
+	return fmt.Sprintf("Could not parse the version from output: %q", string(err))
+}
+
+// InvalidAwsCredentials is an error that occurs when the configured AWS credentials can't be used to make API calls.
+type InvalidAwsCredentials struct {
+	Underlying error
+}
+
+func (err InvalidAwsCredentials) Error() string {
+	return fmt.Sprintf("AWS credentials are missing or invalid (%v). Configure them, e.g. via AWS_PROFILE or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.", err.Underlying)
+}
+
+// AwsAccountNotAllowed is an error that occurs when the AWS credentials belong to an account that is not allowed.
+type AwsAccountNotAllowed struct {
+	AccountId         string
+	AllowedAccountIds []string
+}
+
+func (err AwsAccountNotAllowed) Error() string {
+	return fmt.Sprintf("AWS credentials are for account %s, but these tests may only run in accounts [%s]. Switch to one of those accounts.", err.AccountId, strings.Join(err.AllowedAccountIds, ", "))
+}
@@ -0,0 +1,128 @@
+// Package precheck allows to verify that the environment is set up correctly before any resources are created.
+package precheck
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/require"
+)
+
+// Requirements describes what the environment must provide for the tests to run. Checks for fields that are left
+// empty are skipped.
+type Requirements struct {
+	TerraformBinary            string   // Name of the terraform binary that must be on the PATH (e.g. terraform or terragrunt)
+	TerraformVersionConstraint string   // A version constraint the terraform binary must satisfy (e.g. ">= 0.13, < 2.0")
+	AwsCredentials             bool     // Whether valid AWS credentials must be configured
+	AllowedAwsAccountIds       []string // If set, the AWS credentials must belong to one of these accounts
+	RequiredEnvVars            []string // Environment variables that must be set to a non-empty value
+}
+
+// terraformVersionRegexp matches the version in the output of `terraform version` (e.g. "Terraform v1.0.9").
+var terraformVersionRegexp = regexp.MustCompile(`(?m)^\S+ v(\d+\.\d+\.\d+\S*)`)
+
+// Precheck verifies that the environment meets the given requirements, failing the test immediately with a message
+// that lists every unmet requirement if it does not. Call it at the start of a test, before any resources are created.
+func Precheck(t testing.TestingT, requirements Requirements) {
+	err := PrecheckE(t, requirements)
+	require.NoError(t, err)
+}
+
+// PrecheckE verifies that the environment meets the given requirements, returning an error that lists every unmet
+// requirement if it does not.
+func PrecheckE(t testing.TestingT, requirements Requirements) error {
+	errorsOccurred := new(multierror.Error)
+
+	for _, envVarName := range requirements.RequiredEnvVars {
+		if os.Getenv(envVarName) == "" {
+			errorsOccurred = multierror.Append(errorsOccurred, MissingEnvVar(envVarName))
+		}
+	}
+
+	if requirements.TerraformBinary != "" || requirements.TerraformVersionConstraint != "" {
+		if err := checkTerraformBinary(t, requirements); err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+		}
+	}
+
+	if requirements.AwsCredentials || len(requirements.AllowedAwsAccountIds) > 0 {
+		if err := checkAwsAccount(t, requirements.AllowedAwsAccountIds); err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+		}
+	}
+
+	return errorsOccurred.ErrorOrNil()
+}
+
+func checkTerraformBinary(t testing.TestingT, requirements Requirements) error {
+	binary := requirements.TerraformBinary
+	if binary == "" {
+		binary = "terraform"
+	}
+
+	if _, err := exec.LookPath(binary); err != nil {
+		return BinaryNotFound{Binary: binary, Underlying: err}
+	}
+
+	if requirements.TerraformVersionConstraint == "" {
+		return nil
+	}
+
+	constraints, err := version.NewConstraint(requirements.TerraformVersionConstraint)
+	if err != nil {
+		return err
+	}
+
+	cmd := shell.Command{
+		Command: binary,
+		Args:    []string{"version"},
+		Logger:  logger.Discard,
+	}
+	out, err := shell.RunCommandAndGetStdOutE(t, cmd)
+	if err != nil {
+		return err
+	}
+
+	localVersion, err := parseTerraformVersion(out)
+	if err != nil {
+		return err
+	}
+
+	if !constraints.Check(localVersion) {
+		return UnsupportedVersion{Binary: binary, Version: localVersion.String(), Constraint: requirements.TerraformVersionConstraint}
+	}
+
+	logger.Logf(t, "Found %s version %s, which satisfies %s", binary, localVersion, requirements.TerraformVersionConstraint)
+	return nil
+}
+
+// parseTerraformVersion extracts the version from the output of `terraform version`.
+func parseTerraformVersion(out string) (*version.Version, error) {
+	matches := terraformVersionRegexp.FindStringSubmatch(out)
+	if len(matches) != 2 {
+		return nil, VersionNotParsable(out)
+	}
+	return version.NewVersion(matches[1])
+}
+
+func checkAwsAccount(t testing.TestingT, allowedAccountIds []string) error {
+	accountId, err := aws.GetAccountIdE(t)
+	if err != nil {
+		return InvalidAwsCredentials{Underlying: err}
+	}
+
+	if len(allowedAccountIds) > 0 && !collections.ListContains(allowedAccountIds, accountId) {
+		return AwsAccountNotAllowed{AccountId: accountId, AllowedAccountIds: allowedAccountIds}
+	}
+
+	logger.Logf(t, "Using AWS account %s", accountId)
+	return nil
+}
@@ -0,0 +1,65 @@
+package precheck
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerraformVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		out      string
+		expected string
+	}{
+		{"Terraform v1.0.9\non linux_amd64\n", "1.0.9"},
+		{"Terraform v0.12.31\n\nYour version of Terraform is out of date!", "0.12.31"},
+		{"terragrunt version v0.35.4\n", ""},
+		{"Terraform v1.1.0-beta2\non darwin_arm64\n", "1.1.0-beta2"},
+	}
+
+	for _, testCase := range testCases {
+		parsed, err := parseTerraformVersion(testCase.out)
+		if testCase.expected == "" {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, parsed.String())
+	}
+}
+
+func TestPrecheckReportsAllMissingEnvVars(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	os.Setenv("TERRATEST_PRECHECK_SET", "value")
+	defer os.Unsetenv("TERRATEST_PRECHECK_SET")
+
+	err := PrecheckE(t, Requirements{
+		RequiredEnvVars: []string{"TERRATEST_PRECHECK_SET", "TERRATEST_PRECHECK_MISSING_1", "TERRATEST_PRECHECK_MISSING_2"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TERRATEST_PRECHECK_MISSING_1")
+	assert.Contains(t, err.Error(), "TERRATEST_PRECHECK_MISSING_2")
+	assert.NotContains(t, err.Error(), "TERRATEST_PRECHECK_SET")
+}
+
+func TestPrecheckBinaryNotFound(t *testing.T) {
+	t.Parallel()
+
+	err := PrecheckE(t, Requirements{TerraformBinary: "terratest-binary-that-does-not-exist"})
+	var notFoundErr BinaryNotFound
+	require.True(t, errors.As(err, &notFoundErr))
+	assert.Equal(t, "terratest-binary-that-does-not-exist", notFoundErr.Binary)
+}
+
+func TestPrecheckNoRequirements(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, PrecheckE(t, Requirements{}))
+}
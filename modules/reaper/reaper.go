@@ -0,0 +1,218 @@
+// Package reaper deletes AWS resources that test runs left behind, e.g. because the CI job running them was killed
+// before the tests could clean up. It only considers resources tagged with both aws.CreatedByTagKey =
+// aws.CreatedByTagValue and an aws.UniqueIdTagKey, and only deletes them once they are older than a given age, so that
+// it can run on a schedule alongside tests that are still in progress.
+package reaper
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// The types of resources the reaper deletes, in the order it deletes them in, so that resources are deleted before the
+// resources they depend on.
+const (
+	ResourceTypeInstance      = "instance"
+	ResourceTypeKeyPair       = "key-pair"
+	ResourceTypeSecurityGroup = "security-group"
+	ResourceTypeSubnet        = "subnet"
+	ResourceTypeVpc           = "vpc"
+)
+
+// Options configures which resources the reaper deletes.
+type Options struct {
+	Regions   []string      // The AWS regions to look for leaked resources in
+	OlderThan time.Duration // Only delete resources created at least this long ago
+	DryRun    bool          // If true, only find and log the leaked resources, without deleting them
+}
+
+// Resource is a leaked resource found by the reaper.
+type Resource struct {
+	Region    string
+	Type      string // One of the ResourceType constants
+	Id        string
+	UniqueId  string    // The UniqueId of the test run that created the resource
+	CreatedAt time.Time // When the resource was created
+}
+
+// Reap finds the resources tagged as created by a test run that are older than options.OlderThan in options.Regions
+// and deletes them, unless options.DryRun is set. It returns the resources it found. This will fail the test if there
+// is an error.
+func Reap(t testing.TestingT, options Options) []Resource {
+	resources, err := ReapE(t, options)
+	require.NoError(t, err)
+	return resources
+}
+
+// ReapE finds the resources tagged as created by a test run that are older than options.OlderThan in options.Regions
+// and deletes them, unless options.DryRun is set. It returns the resources it found. It keeps going when a resource
+// can't be deleted (e.g., a VPC that still contains untagged resources), and returns all such errors at the end.
+func ReapE(t testing.TestingT, options Options) ([]Resource, error) {
+	var allResources []Resource
+	var errorsOccurred = new(multierror.Error)
+
+	for _, region := range options.Regions {
+		client, err := terratest_aws.NewEc2ClientE(t, region)
+		if err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+			continue
+		}
+
+		resources, err := ReapWithClientE(t, client, region, options)
+		allResources = append(allResources, resources...)
+		errorsOccurred = multierror.Append(errorsOccurred, err)
+	}
+
+	return allResources, errorsOccurred.ErrorOrNil()
+}
+
+// ReapWithClientE finds the leaked resources in the given region that are older than options.OlderThan and deletes them,
+// unless options.DryRun is set, with the ability to provide the EC2 client. options.Regions is ignored.
+func ReapWithClientE(t testing.TestingT, client ec2iface.EC2API, region string, options Options) ([]Resource, error) {
+	resources, err := findLeakedResources(client, region, time.Now().Add(-options.OlderThan))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		logger.Logf(t, "Found %s %s in %s, created at %s by test run %s", resource.Type, resource.Id, region, resource.CreatedAt.Format(time.RFC3339), resource.UniqueId)
+	}
+	if options.DryRun {
+		return resources, nil
+	}
+
+	return resources, deleteResources(t, client, resources)
+}
+
+// findLeakedResources returns the resources tagged as created by a test run in the given region that were created
+// before the given cutoff, in the order they should be deleted in.
+func findLeakedResources(client ec2iface.EC2API, region string, cutoff time.Time) ([]Resource, error) {
+	filters := []*ec2.Filter{
+		{Name: aws.String("tag:" + terratest_aws.CreatedByTagKey), Values: aws.StringSlice([]string{terratest_aws.CreatedByTagValue})},
+		{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{terratest_aws.UniqueIdTagKey})},
+	}
+
+	var resources []Resource
+	add := func(resourceType string, id *string, tags []*ec2.Tag, launchTime *time.Time) {
+		resource := Resource{Region: region, Type: resourceType, Id: aws.StringValue(id)}
+		for _, tag := range tags {
+			switch aws.StringValue(tag.Key) {
+			case terratest_aws.UniqueIdTagKey:
+				resource.UniqueId = aws.StringValue(tag.Value)
+			case terratest_aws.CreatedAtTagKey:
+				resource.CreatedAt, _ = time.Parse(time.RFC3339, aws.StringValue(tag.Value))
+			}
+		}
+		if resource.CreatedAt.IsZero() && launchTime != nil {
+			resource.CreatedAt = *launchTime
+		}
+
+		// Resources of unknown age may belong to a test that is still running
+		if !resource.CreatedAt.IsZero() && resource.CreatedAt.Before(cutoff) {
+			resources = append(resources, resource)
+		}
+	}
+
+	err := client.DescribeInstancesPages(&ec2.DescribeInstancesInput{Filters: filters}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.State != nil && aws.StringValue(instance.State.Name) == ec2.InstanceStateNameTerminated {
+					continue
+				}
+				add(ResourceTypeInstance, instance.InstanceId, instance.Tags, instance.LaunchTime)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyPairs, err := client.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	for _, keyPair := range keyPairs.KeyPairs {
+		add(ResourceTypeKeyPair, keyPair.KeyName, keyPair.Tags, nil)
+	}
+
+	securityGroups, err := client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	for _, securityGroup := range securityGroups.SecurityGroups {
+		add(ResourceTypeSecurityGroup, securityGroup.GroupId, securityGroup.Tags, nil)
+	}
+
+	subnets, err := client.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	for _, subnet := range subnets.Subnets {
+		add(ResourceTypeSubnet, subnet.SubnetId, subnet.Tags, nil)
+	}
+
+	vpcs, err := client.DescribeVpcs(&ec2.DescribeVpcsInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	for _, vpc := range vpcs.Vpcs {
+		add(ResourceTypeVpc, vpc.VpcId, vpc.Tags, nil)
+	}
+
+	return resources, nil
+}
+
+// deleteResources deletes the given resources in order, waiting for the instances to terminate before deleting the
+// resources they may use. It keeps going when a resource can't be deleted, and returns all the errors at the end.
+func deleteResources(t testing.TestingT, client ec2iface.EC2API, resources []Resource) error {
+	var errorsOccurred = new(multierror.Error)
+
+	var instanceIds []string
+	for _, resource := range resources {
+		if resource.Type == ResourceTypeInstance {
+			instanceIds = append(instanceIds, resource.Id)
+		}
+	}
+	if len(instanceIds) > 0 {
+		logger.Logf(t, "Terminating instances %v", instanceIds)
+		input := &ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice(instanceIds)}
+		if _, err := client.TerminateInstances(input); err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+		} else if err := client.WaitUntilInstanceTerminated(&ec2.DescribeInstancesInput{InstanceIds: input.InstanceIds}); err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+		}
+	}
+
+	for _, resource := range resources {
+		var err error
+		switch resource.Type {
+		case ResourceTypeKeyPair:
+			_, err = client.DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: aws.String(resource.Id)})
+		case ResourceTypeSecurityGroup:
+			_, err = client.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String(resource.Id)})
+		case ResourceTypeSubnet:
+			_, err = client.DeleteSubnet(&ec2.DeleteSubnetInput{SubnetId: aws.String(resource.Id)})
+		case ResourceTypeVpc:
+			_, err = client.DeleteVpc(&ec2.DeleteVpcInput{VpcId: aws.String(resource.Id)})
+		default:
+			continue
+		}
+		if err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+			continue
+		}
+		logger.Logf(t, "Deleted %s %s", resource.Type, resource.Id)
+	}
+
+	return errorsOccurred.ErrorOrNil()
+}
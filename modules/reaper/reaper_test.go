@@ -0,0 +1,129 @@
+package reaper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+)
+
+// fakeEc2 returns the given tagged resources for any filters and records the resources it was asked to delete.
+type fakeEc2 struct {
+	ec2iface.EC2API
+	instances      []*ec2.Instance
+	keyPairs       []*ec2.KeyPairInfo
+	securityGroups []*ec2.SecurityGroup
+	vpcs           []*ec2.Vpc
+	deleted        []string
+}
+
+func (client *fakeEc2) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: client.instances}}}, true)
+	return nil
+}
+
+func (client *fakeEc2) DescribeKeyPairs(input *ec2.DescribeKeyPairsInput) (*ec2.DescribeKeyPairsOutput, error) {
+	return &ec2.DescribeKeyPairsOutput{KeyPairs: client.keyPairs}, nil
+}
+
+func (client *fakeEc2) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: client.securityGroups}, nil
+}
+
+func (client *fakeEc2) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{}, nil
+}
+
+func (client *fakeEc2) DescribeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
+	return &ec2.DescribeVpcsOutput{Vpcs: client.vpcs}, nil
+}
+
+func (client *fakeEc2) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	client.deleted = append(client.deleted, aws.StringValueSlice(input.InstanceIds)...)
+	return &ec2.TerminateInstancesOutput{}, nil
+}
+
+func (client *fakeEc2) WaitUntilInstanceTerminated(input *ec2.DescribeInstancesInput) error {
+	return nil
+}
+
+func (client *fakeEc2) DeleteKeyPair(input *ec2.DeleteKeyPairInput) (*ec2.DeleteKeyPairOutput, error) {
+	client.deleted = append(client.deleted, aws.StringValue(input.KeyName))
+	return &ec2.DeleteKeyPairOutput{}, nil
+}
+
+func (client *fakeEc2) DeleteSecurityGroup(input *ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error) {
+	client.deleted = append(client.deleted, aws.StringValue(input.GroupId))
+	return &ec2.DeleteSecurityGroupOutput{}, nil
+}
+
+func (client *fakeEc2) DeleteVpc(input *ec2.DeleteVpcInput) (*ec2.DeleteVpcOutput, error) {
+	client.deleted = append(client.deleted, aws.StringValue(input.VpcId))
+	return &ec2.DeleteVpcOutput{}, nil
+}
+
+func testTags(uniqueId string, createdAt time.Time) []*ec2.Tag {
+	tags := []*ec2.Tag{
+		{Key: aws.String(terratest_aws.CreatedByTagKey), Value: aws.String(terratest_aws.CreatedByTagValue)},
+		{Key: aws.String(terratest_aws.UniqueIdTagKey), Value: aws.String(uniqueId)},
+	}
+	if !createdAt.IsZero() {
+		tags = append(tags, &ec2.Tag{Key: aws.String(terratest_aws.CreatedAtTagKey), Value: aws.String(createdAt.Format(time.RFC3339))})
+	}
+	return tags
+}
+
+func newFakeEc2() *fakeEc2 {
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	running := &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)}
+	terminated := &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameTerminated)}
+
+	return &fakeEc2{
+		instances: []*ec2.Instance{
+			{InstanceId: aws.String("i-old"), State: running, LaunchTime: &old, Tags: testTags("abc123", time.Time{})},
+			{InstanceId: aws.String("i-recent"), State: running, LaunchTime: &recent, Tags: testTags("def456", time.Time{})},
+			{InstanceId: aws.String("i-terminated"), State: terminated, LaunchTime: &old, Tags: testTags("abc123", time.Time{})},
+		},
+		keyPairs: []*ec2.KeyPairInfo{
+			{KeyName: aws.String("terratest-abc123"), Tags: testTags("abc123", old)},
+			{KeyName: aws.String("terratest-unknown-age"), Tags: testTags("ghi789", time.Time{})},
+		},
+		securityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-old"), Tags: testTags("abc123", old)}},
+		vpcs:           []*ec2.Vpc{{VpcId: aws.String("vpc-recent"), Tags: testTags("def456", recent)}},
+	}
+}
+
+func TestReapWithClient(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeEc2()
+	resources, err := ReapWithClientE(t, client, "us-east-1", Options{OlderThan: 24 * time.Hour})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, resource := range resources {
+		ids = append(ids, resource.Id)
+		assert.Equal(t, "abc123", resource.UniqueId)
+		assert.Equal(t, "us-east-1", resource.Region)
+	}
+	assert.Equal(t, []string{"i-old", "terratest-abc123", "sg-old"}, ids)
+	assert.Equal(t, []string{"i-old", "terratest-abc123", "sg-old"}, client.deleted)
+}
+
+func TestReapWithClientDryRun(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeEc2()
+	resources, err := ReapWithClientE(t, client, "us-east-1", Options{OlderThan: 30 * time.Minute, DryRun: true})
+	require.NoError(t, err)
+
+	assert.Len(t, resources, 5)
+	assert.Empty(t, client.deleted)
+}
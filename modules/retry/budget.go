@@ -0,0 +1,131 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a retry budget that can be shared by many tests running in parallel. Every retry takes a token from
+// the bucket, and the bucket refills at a fixed rate, up to its capacity. When the bucket is empty, retries wait for
+// the next token, which spreads them out and keeps dozens of tests that all hit the same throttling error at once from
+// amplifying it into a failure of the whole suite.
+type TokenBucket struct {
+	mutex          sync.Mutex
+	capacity       int
+	tokens         int
+	refillInterval time.Duration
+	lastRefill     time.Time
+}
+
+// NewTokenBucket returns a full TokenBucket that holds up to capacity tokens and adds one token every refillInterval.
+// It panics if the capacity or refillInterval is not positive, since retries would wait forever for a token.
+func NewTokenBucket(capacity int, refillInterval time.Duration) *TokenBucket {
+	bucket, err := NewTokenBucketE(capacity, refillInterval)
+	if err != nil {
+		panic(err)
+	}
+	return bucket
+}
+
+// NewTokenBucketE returns a full TokenBucket that holds up to capacity tokens and adds one token every refillInterval,
+// or an InvalidTokenBucket error if the capacity or refillInterval is not positive.
+func NewTokenBucketE(capacity int, refillInterval time.Duration) (*TokenBucket, error) {
+	if capacity <= 0 || refillInterval <= 0 {
+		return nil, InvalidTokenBucket{Capacity: capacity, RefillInterval: refillInterval}
+	}
+	return &TokenBucket{
+		capacity:       capacity,
+		tokens:         capacity,
+		refillInterval: refillInterval,
+		lastRefill:     time.Now(),
+	}, nil
+}
+
+// InvalidTokenBucket is returned when creating a TokenBucket that would never have a token to take.
+type InvalidTokenBucket struct {
+	Capacity       int
+	RefillInterval time.Duration
+}
+
+func (err InvalidTokenBucket) Error() string {
+	return fmt.Sprintf("a token bucket needs a positive capacity and refill interval, but got capacity %d and refill interval %s", err.Capacity, err.RefillInterval)
+}
+
+// Take takes a token from the bucket, waiting for one to become available if the bucket is empty.
+func (bucket *TokenBucket) Take() {
+	bucket.TakeContext(context.Background())
+}
+
+// TakeContext takes a token from the bucket, waiting for one to become available if the bucket is empty, unless the
+// given context is done first, in which case the context's error is returned.
+func (bucket *TokenBucket) TakeContext(ctx context.Context) error {
+	for {
+		wait, ok := bucket.tryTake()
+		if ok {
+			return nil
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryTake takes a token from the bucket if one is available and returns true, or returns false without waiting if the
+// bucket is empty.
+func (bucket *TokenBucket) TryTake() bool {
+	_, ok := bucket.tryTake()
+	return ok
+}
+
+// tryTake takes a token if one is available. If not, it returns how long it will take until the next token is added.
+func (bucket *TokenBucket) tryTake() (time.Duration, bool) {
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+
+	bucket.refill(time.Now())
+	if bucket.tokens > 0 {
+		bucket.tokens--
+		return 0, true
+	}
+	return bucket.lastRefill.Add(bucket.refillInterval).Sub(time.Now()), false
+}
+
+func (bucket *TokenBucket) refill(now time.Time) {
+	added := int(now.Sub(bucket.lastRefill) / bucket.refillInterval)
+	if added == 0 {
+		return
+	}
+	bucket.lastRefill = bucket.lastRefill.Add(time.Duration(added) * bucket.refillInterval)
+	bucket.tokens += added
+	if bucket.tokens >= bucket.capacity {
+		bucket.tokens = bucket.capacity
+		bucket.lastRefill = now
+	}
+}
+
+var (
+	sharedBudgetMutex sync.Mutex
+	sharedBudget      *TokenBucket
+)
+
+// SetSharedBudget sets the process-wide retry budget that every retry in DoWithRetry, DoWithRetryableErrors, and the
+// functions built on them (such as the retries of terraform commands and the aws helpers) takes a token from before
+// retrying. Pass nil, the default, to retry without limits. This is typically called once from TestMain:
+//
+//	retry.SetSharedBudget(retry.NewTokenBucket(20, 1*time.Second))
+func SetSharedBudget(bucket *TokenBucket) {
+	sharedBudgetMutex.Lock()
+	defer sharedBudgetMutex.Unlock()
+	sharedBudget = bucket
+}
+
+// getSharedBudget returns the process-wide retry budget, or nil if there is none.
+func getSharedBudget() *TokenBucket {
+	sharedBudgetMutex.Lock()
+	defer sharedBudgetMutex.Unlock()
+	return sharedBudget
+}
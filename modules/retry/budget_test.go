@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	bucket := NewTokenBucket(2, 50*time.Millisecond)
+	assert.True(t, bucket.TryTake())
+	assert.True(t, bucket.TryTake())
+	assert.False(t, bucket.TryTake())
+
+	start := time.Now()
+	bucket.Take()
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+	assert.False(t, bucket.TryTake())
+}
+
+func TestTokenBucketDoesNotExceedCapacity(t *testing.T) {
+	t.Parallel()
+
+	bucket := NewTokenBucket(1, 1*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, bucket.TryTake())
+	assert.False(t, bucket.TryTake())
+}
+
+func TestNewTokenBucketRejectsInvalidParameters(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewTokenBucketE(0, time.Second)
+	assert.Equal(t, InvalidTokenBucket{Capacity: 0, RefillInterval: time.Second}, err)
+
+	_, err = NewTokenBucketE(1, 0)
+	assert.Equal(t, InvalidTokenBucket{Capacity: 1, RefillInterval: 0}, err)
+
+	assert.Panics(t, func() { NewTokenBucket(-1, time.Second) })
+}
+
+func TestTokenBucketTakeContextStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	bucket := NewTokenBucket(1, time.Hour)
+	require.NoError(t, bucket.TakeContext(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, bucket.TakeContext(ctx))
+}
+
+func TestDoWithRetryStopsWaitingForSharedBudgetWhenContextIsDone(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The shared budget is process-wide, so this test must not run while other tests are retrying.
+
+	SetSharedBudget(NewTokenBucket(1, time.Hour))
+	defer SetSharedBudget(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	count := 0
+	_, err := DoWithRetryContextE(ctx, t, "always fails", 5, 1*time.Millisecond, func() (string, error) {
+		count++
+		return "", fmt.Errorf("error %d", count)
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestDoWithRetryTakesFromSharedBudget(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The shared budget is process-wide, so this test must not run while other tests are retrying.
+
+	bucket := NewTokenBucket(3, time.Hour)
+	SetSharedBudget(bucket)
+	defer SetSharedBudget(nil)
+
+	count := 0
+	_, err := DoWithRetryE(t, "always fails", 2, 1*time.Millisecond, func() (string, error) {
+		count++
+		return "", fmt.Errorf("error %d", count)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, count)
+
+	// The first attempt is free, so only the two retries took a token
+	assert.True(t, bucket.TryTake())
+	assert.False(t, bucket.TryTake())
+}
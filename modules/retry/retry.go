@@ -65,7 +65,16 @@ func DoWithRetry(t testing.TestingT, actionDescription string, maxRetries int, s
 // immediately. If it returns any other type of error, sleep for sleepBetweenRetries and try again, up to a maximum of
 // maxRetries retries. If maxRetries is exceeded, return a MaxRetriesExceeded error.
 func DoWithRetryE(t testing.TestingT, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
-	out, err := DoWithRetryInterfaceE(t, actionDescription, maxRetries, sleepBetweenRetries, func() (interface{}, error) { return action() })
+	return DoWithRetryContextE(context.Background(), t, actionDescription, maxRetries, sleepBetweenRetries, action)
+}
+
+// DoWithRetryContextE works like DoWithRetryE, but stops retrying as soon as the given context is done, returning the
+// context's error. This allows enforcing a global deadline, or cancelling all pending polls when one of them fails.
+func DoWithRetryContextE(ctx context.Context, t testing.TestingT, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
+	out, err := DoWithRetryInterfaceContextE(ctx, t, actionDescription, maxRetries, sleepBetweenRetries, func() (interface{}, error) { return action() })
+	if out == nil {
+		return "", err
+	}
 	return out.(string), err
 }
 
@@ -84,10 +93,71 @@ func DoWithRetryInterface(t testing.TestingT, actionDescription string, maxRetri
 // immediately. If it returns any other type of error, sleep for sleepBetweenRetries and try again, up to a maximum of
 // maxRetries retries. If maxRetries is exceeded, return a MaxRetriesExceeded error.
 func DoWithRetryInterfaceE(t testing.TestingT, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (interface{}, error)) (interface{}, error) {
+	return DoWithRetryInterfaceContextE(context.Background(), t, actionDescription, maxRetries, sleepBetweenRetries, action)
+}
+
+// DoWithRetryInterfaceContextE works like DoWithRetryInterfaceE, but stops retrying as soon as the given context is
+// done, returning the context's error.
+func DoWithRetryInterfaceContextE(ctx context.Context, t testing.TestingT, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (interface{}, error)) (interface{}, error) {
+	return doWithRetryInterface(ctx, t, actionDescription, maxRetries, func(int) time.Duration { return sleepBetweenRetries }, action)
+}
+
+// DoWithRetryExponentialBackoff runs the specified action like DoWithRetry, but sleeps for initialSleep after the first
+// failure and doubles the sleep after each further failure, up to maxSleep, so that retries don't keep hammering a
+// service that needs time to recover (e.g. one that is rate limiting requests). If maxRetries is exceeded, fail the
+// test.
+func DoWithRetryExponentialBackoff(t testing.TestingT, actionDescription string, maxRetries int, initialSleep time.Duration, maxSleep time.Duration, action func() (string, error)) string {
+	out, err := DoWithRetryExponentialBackoffE(t, actionDescription, maxRetries, initialSleep, maxSleep, action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// DoWithRetryExponentialBackoffE runs the specified action like DoWithRetryE, but sleeps for initialSleep after the
+// first failure and doubles the sleep after each further failure, up to maxSleep. If maxRetries is exceeded, return a
+// MaxRetriesExceeded error.
+func DoWithRetryExponentialBackoffE(t testing.TestingT, actionDescription string, maxRetries int, initialSleep time.Duration, maxSleep time.Duration, action func() (string, error)) (string, error) {
+	out, err := doWithRetryInterface(context.Background(), t, actionDescription, maxRetries, func(retry int) time.Duration { return exponentialBackoff(initialSleep, maxSleep, retry) }, func() (interface{}, error) { return action() })
+	if out == nil {
+		return "", err
+	}
+	return out.(string), err
+}
+
+// exponentialBackoff returns how long to sleep before the given retry (starting at 0): initialSleep, doubled for each
+// earlier retry, but no more than maxSleep.
+func exponentialBackoff(initialSleep time.Duration, maxSleep time.Duration, retry int) time.Duration {
+	sleep := initialSleep
+	for i := 0; i < retry && sleep < maxSleep; i++ {
+		sleep *= 2
+	}
+	if sleep > maxSleep {
+		return maxSleep
+	}
+	return sleep
+}
+
+// doWithRetryInterface runs the specified action until it succeeds, returns a FatalError, or fails maxRetries+1 times,
+// sleeping for the duration sleepBeforeRetry returns for each retry (starting at 0) in between.
+func doWithRetryInterface(ctx context.Context, t testing.TestingT, actionDescription string, maxRetries int, sleepBeforeRetry func(retry int) time.Duration, action func() (interface{}, error)) (interface{}, error) {
 	var output interface{}
 	var err error
 
 	for i := 0; i <= maxRetries; i++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			logger.Logf(t, "Stopped retrying '%s': %v", actionDescription, ctxErr)
+			return output, ctxErr
+		}
+
+		if budget := getSharedBudget(); i > 0 && budget != nil && !budget.TryTake() {
+			logger.Logf(t, "The shared retry budget is exhausted. Waiting for it to refill before retrying '%s'.", actionDescription)
+			if err := budget.TakeContext(ctx); err != nil {
+				logger.Logf(t, "Stopped retrying '%s': %v", actionDescription, err)
+				return output, err
+			}
+		}
+
 		logger.Log(t, actionDescription)
 
 		output, err = action()
@@ -100,13 +170,78 @@ func DoWithRetryInterfaceE(t testing.TestingT, actionDescription string, maxRetr
 			return output, err
 		}
 
+		sleepBetweenRetries := sleepBeforeRetry(i)
 		logger.Logf(t, "%s returned an error: %s. Sleeping for %s and will try again.", actionDescription, err.Error(), sleepBetweenRetries)
-		time.Sleep(sleepBetweenRetries)
+		select {
+		case <-time.After(sleepBetweenRetries):
+		case <-ctx.Done():
+			logger.Logf(t, "Stopped retrying '%s': %v", actionDescription, ctx.Err())
+			return output, ctx.Err()
+		}
 	}
 
 	return output, MaxRetriesExceeded{Description: actionDescription, MaxRetries: maxRetries}
 }
 
+// WaitUntil calls the specified check every interval, up to maxAttempts times, until it returns true, so that you don't
+// have to write a poll loop for every condition you wait for. If the check returns a FatalError, stop right away. Fail
+// the test if the condition isn't met after maxAttempts, with the last error returned by the check.
+func WaitUntil(t testing.TestingT, description string, maxAttempts int, interval time.Duration, check func() (bool, error)) {
+	err := WaitUntilE(t, description, maxAttempts, interval, check)
+	require.NoError(t, err)
+}
+
+// WaitUntilE calls the specified check every interval, up to maxAttempts times, until it returns true. If the check
+// returns a FatalError, return that error right away. If the condition isn't met after maxAttempts, return a
+// ConditionNotMet error, which wraps the last error returned by the check.
+func WaitUntilE(t testing.TestingT, description string, maxAttempts int, interval time.Duration, check func() (bool, error)) error {
+	return WaitUntilContextE(context.Background(), t, description, maxAttempts, interval, check)
+}
+
+// WaitUntilContextE works like WaitUntilE, but stops waiting as soon as the given context is done, returning the
+// context's error.
+func WaitUntilContextE(ctx context.Context, t testing.TestingT, description string, maxAttempts int, interval time.Duration, check func() (bool, error)) error {
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			logger.Logf(t, "Stopped waiting until %s: %v", description, ctxErr)
+			return ctxErr
+		}
+
+		done, err := check()
+		if err == nil && done {
+			logger.Logf(t, "Done waiting until %s after %d attempt(s)", description, attempt)
+			return nil
+		}
+
+		if _, isFatalErr := err.(FatalError); isFatalErr {
+			logger.Logf(t, "Stopped waiting until %s due to fatal error: %v", description, err)
+			return err
+		}
+
+		lastErr = err
+		if attempt == maxAttempts {
+			break
+		}
+
+		if err != nil {
+			logger.Logf(t, "Waiting until %s (attempt %d/%d): %v. Checking again in %s.", description, attempt, maxAttempts, err, interval)
+		} else {
+			logger.Logf(t, "Waiting until %s (attempt %d/%d). Checking again in %s.", description, attempt, maxAttempts, interval)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			logger.Logf(t, "Stopped waiting until %s: %v", description, ctx.Err())
+			return ctx.Err()
+		}
+	}
+
+	return ConditionNotMet{Description: description, Attempts: maxAttempts, LastError: lastErr}
+}
+
 // DoWithRetryableErrors runs the specified action. If it returns a value, return that value. If it returns an error,
 // check if error message or the string output from the action (which is often stdout/stderr from running some command)
 // matches any of the regular expressions in the specified retryableErrors map. If there is a match, sleep for
@@ -120,34 +255,120 @@ func DoWithRetryableErrors(t testing.TestingT, actionDescription string, retryab
 
 // DoWithRetryableErrorsE runs the specified action. If it returns a value, return that value. If it returns an error,
 // check if error message or the string output from the action (which is often stdout/stderr from running some command)
-// matches any of the regular expressions in the specified retryableErrors map. If there is a match, sleep for
-// sleepBetweenRetries, and retry the specified action, up to a maximum of maxRetries retries. If there is no match,
-// return that error immediately, wrapped in a FatalError. If maxRetries is exceeded, return a MaxRetriesExceeded error.
+// matches any of the regular expressions in the specified retryableErrors map. As commands such as terraform wrap long
+// error messages over several lines, the regular expressions are also matched with each line break (and the
+// indentation around it) replaced by a single space. If there is a match, sleep for sleepBetweenRetries, and retry the
+// specified action, up to a maximum of maxRetries retries. If there is no match, return that error immediately, wrapped
+// in a FatalError. If maxRetries is exceeded, return a MaxRetriesExceeded error.
 func DoWithRetryableErrorsE(t testing.TestingT, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
+	out, _, err := DoWithRetryableErrorsAndReportContextE(context.Background(), t, actionDescription, retryableErrors, maxRetries, sleepBetweenRetries, action)
+	return out, err
+}
+
+// DoWithRetryableErrorsContextE works like DoWithRetryableErrorsE, but stops retrying as soon as the given context is
+// done, returning the context's error.
+func DoWithRetryableErrorsContextE(ctx context.Context, t testing.TestingT, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
+	out, _, err := DoWithRetryableErrorsAndReportContextE(ctx, t, actionDescription, retryableErrors, maxRetries, sleepBetweenRetries, action)
+	return out, err
+}
+
+// DoWithRetryableErrorsAndReportE works like DoWithRetryableErrorsE, but also returns a Report of every attempt that was
+// made, so callers can check programmatically how often the action was retried and why. The Report is returned even if
+// the action ultimately failed.
+func DoWithRetryableErrorsAndReportE(t testing.TestingT, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, *Report, error) {
+	return DoWithRetryableErrorsAndReportContextE(context.Background(), t, actionDescription, retryableErrors, maxRetries, sleepBetweenRetries, action)
+}
+
+// DoWithRetryableErrorsAndReportContextE works like DoWithRetryableErrorsAndReportE, but stops retrying as soon as the
+// given context is done, returning the context's error.
+func DoWithRetryableErrorsAndReportContextE(ctx context.Context, t testing.TestingT, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, *Report, error) {
+	report := &Report{Description: actionDescription}
+
 	retryableErrorsRegexp := map[*regexp.Regexp]string{}
 	for errorStr, errorMessage := range retryableErrors {
 		errorRegex, err := regexp.Compile(errorStr)
 		if err != nil {
-			return "", FatalError{Underlying: err}
+			return "", report, FatalError{Underlying: err}
 		}
 		retryableErrorsRegexp[errorRegex] = errorMessage
 	}
 
-	return DoWithRetryE(t, actionDescription, maxRetries, sleepBetweenRetries, func() (string, error) {
+	out, err := DoWithRetryContextE(ctx, t, actionDescription, maxRetries, sleepBetweenRetries, func() (string, error) {
+		start := time.Now()
 		output, err := action()
+		attempt := Attempt{Duration: time.Since(start), Error: err}
+		defer func() { report.Attempts = append(report.Attempts, attempt) }()
+
 		if err == nil {
 			return output, nil
 		}
 
+		texts := []string{output, err.Error(), joinLines(output), joinLines(err.Error())}
 		for errorRegexp, errorMessage := range retryableErrorsRegexp {
-			if errorRegexp.MatchString(output) || errorRegexp.MatchString(err.Error()) {
+			if matchesAny(errorRegexp, texts) {
 				logger.Logf(t, "'%s' failed with the error '%s' but this error was expected and warrants a retry. Further details: %s\n", actionDescription, err.Error(), errorMessage)
+				attempt.MatchedMessage = errorMessage
 				return output, err
 			}
 		}
 
 		return output, FatalError{Underlying: err}
 	})
+	return out, report, err
+}
+
+// lineBreakRegexp matches a line break along with the indentation around it.
+var lineBreakRegexp = regexp.MustCompile(`[ \t]*\r?\n[ \t]*`)
+
+// joinLines returns the given text with each line break, and the indentation around it, replaced by a single space, so
+// that an error message that was wrapped over several lines can be matched as a single line.
+func joinLines(text string) string {
+	return lineBreakRegexp.ReplaceAllString(text, " ")
+}
+
+// matchesAny returns true if the given regular expression matches any of the given texts.
+func matchesAny(re *regexp.Regexp, texts []string) bool {
+	for _, text := range texts {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Report describes the attempts made by DoWithRetryableErrorsAndReportE.
+type Report struct {
+	Description string    // The description of the action
+	Attempts    []Attempt // Every attempt that was made, in order
+}
+
+// Attempt describes a single attempt at running an action.
+type Attempt struct {
+	Duration       time.Duration // How long the action took
+	Error          error         // The error returned by the action, or nil if it succeeded
+	MatchedMessage string        // If the error matched one of the retryable errors, the message for that error
+}
+
+// Retries returns the number of times the action was retried, i.e., the number of attempts after the first one.
+func (report *Report) Retries() int {
+	if report == nil || len(report.Attempts) == 0 {
+		return 0
+	}
+	return len(report.Attempts) - 1
+}
+
+// MatchedMessages returns the messages of the retryable errors that caused a retry, in the order they occurred.
+func (report *Report) MatchedMessages() []string {
+	messages := []string{}
+	if report == nil {
+		return messages
+	}
+	for _, attempt := range report.Attempts {
+		if attempt.MatchedMessage != "" {
+			messages = append(messages, attempt.MatchedMessage)
+		}
+	}
+	return messages
 }
 
 // Done can be stopped.
@@ -208,6 +429,26 @@ func (err MaxRetriesExceeded) Error() string {
 	return fmt.Sprintf("'%s' unsuccessful after %d retries", err.Description, err.MaxRetries)
 }
 
+// ConditionNotMet is an error that occurs when the condition waited for by WaitUntilE is still not met after the
+// maximum number of attempts. LastError is the error returned by the last check, if any.
+type ConditionNotMet struct {
+	Description string
+	Attempts    int
+	LastError   error
+}
+
+func (err ConditionNotMet) Error() string {
+	if err.LastError == nil {
+		return fmt.Sprintf("'%s' not met after %d attempts", err.Description, err.Attempts)
+	}
+	return fmt.Sprintf("'%s' not met after %d attempts. Last error: %v", err.Description, err.Attempts, err.LastError)
+}
+
+// Unwrap returns the error returned by the last check, so that it can be checked with errors.Is and errors.As.
+func (err ConditionNotMet) Unwrap() error {
+	return err.LastError
+}
+
 // FatalError is a marker interface for errors that should not be retried.
 type FatalError struct {
 	Underlying error
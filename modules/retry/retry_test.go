@@ -1,6 +1,8 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -58,6 +60,36 @@ func TestDoWithRetry(t *testing.T) {
 	}
 }
 
+func TestDoWithRetryExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	out, err := DoWithRetryExponentialBackoffE(t, "flaky action", 3, time.Millisecond, 4*time.Millisecond, func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("not yet")
+		}
+		return "done", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 3, attempts)
+
+	_, err = DoWithRetryExponentialBackoffE(t, "failing action", 2, time.Millisecond, 4*time.Millisecond, func() (string, error) {
+		return "", errors.New("never")
+	})
+	assert.Equal(t, MaxRetriesExceeded{Description: "failing action", MaxRetries: 2}, err)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for retry, sleep := range expected {
+		assert.Equal(t, sleep, exponentialBackoff(time.Second, 5*time.Second, retry))
+	}
+}
+
 func TestDoWithTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -261,3 +293,141 @@ type ErrorCounter int
 func (count ErrorCounter) Error() string {
 	return fmt.Sprintf("%d", int(count))
 }
+
+func TestDoWithRetryableErrorsMatchesWrappedLines(t *testing.T) {
+	t.Parallel()
+
+	count := 0
+	action := func() (string, error) {
+		count++
+		if count > 1 {
+			return "done", nil
+		}
+		output := "Error: creating EC2 Instance: InvalidKeyPair.NotFound: The key pair\n  'terratest-abc123' does not exist\n"
+		return output, fmt.Errorf("exit status 1")
+	}
+	retryableErrors := map[string]string{"InvalidKeyPair.NotFound: The key pair 'terratest-[a-z0-9]+' does not exist": "Key pair not propagated yet."}
+
+	out, err := DoWithRetryableErrorsE(t, "wrapped error", retryableErrors, 3, 1*time.Millisecond, action)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 2, count)
+}
+
+func TestDoWithRetryableErrorsAndReport(t *testing.T) {
+	t.Parallel()
+
+	count := 0
+	action := func() (string, error) {
+		count++
+		if count > 2 {
+			return "done", nil
+		}
+		return "Error: connection reset by peer", fmt.Errorf("exit status 1")
+	}
+	retryableErrors := map[string]string{".*connection reset.*": "Transient network error."}
+
+	out, report, err := DoWithRetryableErrorsAndReportE(t, "flaky action", retryableErrors, 5, 1*time.Millisecond, action)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, "flaky action", report.Description)
+	assert.Len(t, report.Attempts, 3)
+	assert.Equal(t, 2, report.Retries())
+	assert.Equal(t, []string{"Transient network error.", "Transient network error."}, report.MatchedMessages())
+	assert.Error(t, report.Attempts[0].Error)
+	assert.NoError(t, report.Attempts[2].Error)
+
+	_, report, err = DoWithRetryableErrorsAndReportE(t, "failing action", retryableErrors, 5, 1*time.Millisecond, func() (string, error) {
+		return "", fmt.Errorf("unexpected error")
+	})
+	assert.Error(t, err)
+	assert.Len(t, report.Attempts, 1)
+	assert.Equal(t, 0, report.Retries())
+	assert.Empty(t, report.MatchedMessages())
+}
+
+func TestDoWithRetryContextStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	action := func() (string, error) {
+		attempts++
+		cancel()
+		return "", fmt.Errorf("expected error")
+	}
+
+	start := time.Now()
+	_, err := DoWithRetryContextE(ctx, t, "Cancelled action", 10, time.Minute, action)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, attempts)
+	assert.WithinDuration(t, start, time.Now(), 10*time.Second)
+}
+
+func TestDoWithRetryContextAlreadyDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out, err := DoWithRetryContextE(ctx, t, "Never run", 10, time.Second, func() (string, error) {
+		t.Fatal("The action should not run when the context is already done")
+		return "", nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, "", out)
+}
+
+func TestWaitUntil(t *testing.T) {
+	t.Parallel()
+
+	checks := 0
+	WaitUntil(t, "condition is met on third check", 5, time.Millisecond, func() (bool, error) {
+		checks++
+		return checks == 3, nil
+	})
+	assert.Equal(t, 3, checks)
+}
+
+func TestWaitUntilReturnsLastError(t *testing.T) {
+	t.Parallel()
+
+	checks := 0
+	err := WaitUntilE(t, "condition is never met", 3, time.Millisecond, func() (bool, error) {
+		checks++
+		return false, fmt.Errorf("check %d failed", checks)
+	})
+
+	assert.Equal(t, 3, checks)
+	assert.Equal(t, ConditionNotMet{Description: "condition is never met", Attempts: 3, LastError: fmt.Errorf("check 3 failed")}, err)
+	assert.EqualError(t, errors.Unwrap(err), "check 3 failed")
+}
+
+func TestWaitUntilStopsOnFatalError(t *testing.T) {
+	t.Parallel()
+
+	checks := 0
+	fatalErr := FatalError{Underlying: fmt.Errorf("resource was deleted")}
+	err := WaitUntilE(t, "fatal error", 10, time.Millisecond, func() (bool, error) {
+		checks++
+		return false, fatalErr
+	})
+
+	assert.Equal(t, fatalErr, err)
+	assert.Equal(t, 1, checks)
+}
+
+func TestWaitUntilContextStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	checks := 0
+	err := WaitUntilContextE(ctx, t, "cancelled wait", 10, time.Minute, func() (bool, error) {
+		checks++
+		cancel()
+		return false, nil
+	})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, checks)
+}
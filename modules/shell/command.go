@@ -2,14 +2,17 @@ package shell
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
@@ -24,6 +27,19 @@ type Command struct {
 	Env        map[string]string // Additional environment variables to set
 	// Use the specified logger for the command's output. Use logger.Discard to not print the output while executing the command.
 	Logger *logger.Logger
+
+	// If greater than zero, only the first and last OutputMaxLines/2 lines of stdout, stderr, and their combination are
+	// kept in memory and returned, so commands with huge output (e.g., plans with thousands of resources) don't use
+	// hundreds of MB. The lines in between are dropped, except for up to OutputMaxLines lines that match one of
+	// OutputKeepPatterns, which are kept along with a couple of lines of context around each.
+	OutputMaxLines int
+	// Regular expressions matching lines to keep even if they're in the middle of output truncated by OutputMaxLines,
+	// e.g. the retryable errors to scan the output for. A pattern also matches a message wrapped over a line and the
+	// couple of lines before it, joined with spaces.
+	OutputKeepPatterns []string
+	// If set, the full combined stdout and stderr of the command are also appended to this file, after a header line
+	// naming the command, so that the output of every command (and retry) sharing the file is kept.
+	OutputFile string
 }
 
 // RunCommand runs a shell command and redirects its stdout and stderr to the stdout of the atomic script itself. If
@@ -36,7 +52,12 @@ func RunCommand(t testing.TestingT, command Command) {
 // RunCommandE runs a shell command and redirects its stdout and stderr to the stdout of the atomic script itself. Any
 // returned error will be of type ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandE(t testing.TestingT, command Command) error {
-	output, err := runCommand(t, command)
+	return RunCommandContextE(context.Background(), t, command)
+}
+
+// RunCommandContextE works like RunCommandE, but kills the command if the given context is done before it completes.
+func RunCommandContextE(ctx context.Context, t testing.TestingT, command Command) error {
+	output, err := runCommand(ctx, t, command)
 	if err != nil {
 		return &ErrWithCmdOutput{err, output}
 	}
@@ -55,7 +76,13 @@ func RunCommandAndGetOutput(t testing.TestingT, command Command) string {
 // that command will also be logged with Command.Log to make debugging easier. Any returned error will be of type
 // ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandAndGetOutputE(t testing.TestingT, command Command) (string, error) {
-	output, err := runCommand(t, command)
+	return RunCommandAndGetOutputContextE(context.Background(), t, command)
+}
+
+// RunCommandAndGetOutputContextE works like RunCommandAndGetOutputE, but kills the command if the given context is done
+// before it completes.
+func RunCommandAndGetOutputContextE(ctx context.Context, t testing.TestingT, command Command) (string, error) {
+	output, err := runCommand(ctx, t, command)
 	if err != nil {
 		return output.Combined(), &ErrWithCmdOutput{err, output}
 	}
@@ -76,7 +103,13 @@ func RunCommandAndGetStdOut(t testing.TestingT, command Command) string {
 // and stderr of that command will also be printed to the stdout and stderr of this Go program to make debugging easier.
 // Any returned error will be of type ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandAndGetStdOutE(t testing.TestingT, command Command) (string, error) {
-	output, err := runCommand(t, command)
+	return RunCommandAndGetStdOutContextE(context.Background(), t, command)
+}
+
+// RunCommandAndGetStdOutContextE works like RunCommandAndGetStdOutE, but kills the command if the given context is done
+// before it completes.
+func RunCommandAndGetStdOutContextE(ctx context.Context, t testing.TestingT, command Command) (string, error) {
+	output, err := runCommand(ctx, t, command)
 	if err != nil {
 		return output.Stdout(), &ErrWithCmdOutput{err, output}
 	}
@@ -84,6 +117,33 @@ func RunCommandAndGetStdOutE(t testing.TestingT, command Command) (string, error
 	return output.Stdout(), nil
 }
 
+// RunCommandAndGetStdOutErr runs a shell command and returns its stdout and stderr as separate strings. The stdout and
+// stderr of that command will also be logged with Command.Log to make debugging easier. If there are any errors, fail
+// the test.
+func RunCommandAndGetStdOutErr(t testing.TestingT, command Command) (string, string) {
+	stdout, stderr, err := RunCommandAndGetStdOutErrE(t, command)
+	require.NoError(t, err)
+	return stdout, stderr
+}
+
+// RunCommandAndGetStdOutErrE runs a shell command and returns its stdout and stderr as separate strings. The stdout and
+// stderr of that command will also be logged with Command.Log to make debugging easier. Any returned error will be of
+// type ErrWithCmdOutput, containing the output streams and the underlying error.
+func RunCommandAndGetStdOutErrE(t testing.TestingT, command Command) (string, string, error) {
+	return RunCommandAndGetStdOutErrContextE(context.Background(), t, command)
+}
+
+// RunCommandAndGetStdOutErrContextE works like RunCommandAndGetStdOutErrE, but kills the command if the given context
+// is done before it completes.
+func RunCommandAndGetStdOutErrContextE(ctx context.Context, t testing.TestingT, command Command) (string, string, error) {
+	output, err := runCommand(ctx, t, command)
+	if err != nil {
+		return output.Stdout(), output.Stderr(), &ErrWithCmdOutput{err, output}
+	}
+
+	return output.Stdout(), output.Stderr(), nil
+}
+
 type ErrWithCmdOutput struct {
 	Underlying error
 	Output     *output
@@ -95,11 +155,11 @@ func (e *ErrWithCmdOutput) Error() string {
 
 // runCommand runs a shell command and stores each line from stdout and stderr in Output. Depending on the logger, the
 // stdout and stderr of that command will also be printed to the stdout and stderr of this Go program to make debugging
-// easier.
-func runCommand(t testing.TestingT, command Command) (*output, error) {
+// easier. If ctx is done before the command completes, the command is killed.
+func runCommand(ctx context.Context, t testing.TestingT, command Command) (*output, error) {
 	command.Logger.Logf(t, "Running command %s with args %s", command.Command, command.Args)
 
-	cmd := exec.Command(command.Command, command.Args...)
+	cmd := exec.CommandContext(ctx, command.Command, command.Args...)
 	cmd.Dir = command.WorkingDir
 	cmd.Stdin = os.Stdin
 	cmd.Env = formatEnvVars(command)
@@ -114,12 +174,18 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 		return nil, err
 	}
 
+	limits, closeOutputFile, err := getOutputLimits(command)
+	if err != nil {
+		return nil, err
+	}
+	defer closeOutputFile()
+
 	err = cmd.Start()
 	if err != nil {
 		return nil, err
 	}
 
-	output, err := readStdoutAndStderr(t, command.Logger, stdout, stderr)
+	output, err := readStdoutAndStderr(t, command.Logger, stdout, stderr, limits)
 	if err != nil {
 		return output, err
 	}
@@ -129,8 +195,8 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 
 // This function captures stdout and stderr into the given variables while still printing it to the stdout and stderr
 // of this Go program
-func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr io.ReadCloser) (*output, error) {
-	out := newOutput()
+func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr io.ReadCloser, limits outputLimits) (*output, error) {
+	out := newLimitedOutput(limits)
 	stdoutReader := bufio.NewReader(stdout)
 	stderrReader := bufio.NewReader(stderr)
 
@@ -158,6 +224,45 @@ func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr
 	return out, nil
 }
 
+// getOutputLimits returns the limits on the output kept in memory for the given command, and a function to close the
+// output file, if any.
+func getOutputLimits(command Command) (outputLimits, func(), error) {
+	limits := outputLimits{maxLines: command.OutputMaxLines}
+	for _, pattern := range command.OutputKeepPatterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return limits, func() {}, err
+		}
+		limits.keepPatterns = append(limits.keepPatterns, regex)
+	}
+
+	if command.OutputFile == "" {
+		return limits, func() {}, nil
+	}
+
+	file, err := os.OpenFile(command.OutputFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return limits, func() {}, err
+	}
+	if _, err := file.WriteString(formatOutputFileHeader(command)); err != nil {
+		file.Close()
+		return limits, func() {}, err
+	}
+	limits.file = file
+	limits.filePath = command.OutputFile
+	return limits, func() { file.Close() }, nil
+}
+
+// formatOutputFileHeader returns the line written to the output file before the output of the given command. Only the
+// first arg is included (e.g., the Terraform subcommand), since the rest may contain secrets.
+func formatOutputFileHeader(command Command) string {
+	name := command.Command
+	if len(command.Args) > 0 {
+		name += " " + command.Args[0]
+	}
+	return fmt.Sprintf("==> %s (started at %s)\n", name, time.Now().Format(time.RFC3339))
+}
+
 func readData(t testing.TestingT, log *logger.Logger, reader *bufio.Reader, writer io.StringWriter) error {
 	var line string
 	var readErr error
@@ -219,6 +324,11 @@ func GetExitCodeForRunCommandError(err error) (int, error) {
 		return 1, errors.New("could not determine exit code")
 	}
 
+	// Errors that aren't from a real process, e.g. from fakes, can report an exit code too
+	if exitCoder, ok := err.(interface{ ExitCode() int }); ok {
+		return exitCoder.ExitCode(), nil
+	}
+
 	return 0, nil
 }
 
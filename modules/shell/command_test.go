@@ -2,15 +2,22 @@ package shell
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/random"
+	ttesting "github.com/gruntwork-io/terratest/modules/testing"
 )
 
 func TestRunCommandAndGetOutput(t *testing.T) {
@@ -26,6 +33,23 @@ func TestRunCommandAndGetOutput(t *testing.T) {
 	assert.Equal(t, text, strings.TrimSpace(out))
 }
 
+func TestRunCommandContextKillsCommandWhenDeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	cmd := Command{
+		Command: "sleep",
+		Args:    []string{"30"},
+	}
+
+	start := time.Now()
+	err := RunCommandContextE(ctx, t, cmd)
+	require.Error(t, err)
+	assert.WithinDuration(t, start, time.Now(), 10*time.Second)
+}
+
 func TestRunCommandAndGetOutputOrder(t *testing.T) {
 	t.Parallel()
 
@@ -146,6 +170,41 @@ echo
 	assert.Equal(t, out, buffer.String())
 }
 
+// timedLogger records when each line was logged.
+type timedLogger struct {
+	mutex sync.Mutex
+	lines map[string]time.Time
+}
+
+func (l *timedLogger) Logf(t ttesting.TestingT, format string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.lines[fmt.Sprintf(format, args...)] = time.Now()
+}
+
+// TestRunCommandStreamsOutput ensures that each line is logged as soon as the command writes it, rather than when the
+// command completes, so that long running commands (e.g., terraform apply) don't look hung.
+func TestRunCommandStreamsOutput(t *testing.T) {
+	t.Parallel()
+
+	log := &timedLogger{lines: map[string]time.Time{}}
+	cmd := Command{
+		Command: "bash",
+		Args:    []string{"-c", "echo first; sleep 0.1; (>&2 echo second); sleep 2; echo last"},
+		Logger:  logger.New(log),
+	}
+
+	out, err := RunCommandAndGetOutputE(t, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\nlast", out)
+
+	finished := time.Now()
+	require.Contains(t, log.lines, "first")
+	require.Contains(t, log.lines, "second")
+	assert.True(t, finished.Sub(log.lines["first"]) >= time.Second)
+	assert.True(t, finished.Sub(log.lines["second"]) >= time.Second)
+}
+
 // TestRunCommandOutputError ensures that getting the output never panics, even if no command was ever run.
 func TestRunCommandOutputError(t *testing.T) {
 	t.Parallel()
@@ -161,6 +220,27 @@ func TestRunCommandOutputError(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRunCommandAndGetStdOutErr(t *testing.T) {
+	t.Parallel()
+
+	stdout, stderr := RunCommandAndGetStdOutErr(t, Command{
+		Command: "sh",
+		Args:    []string{"-c", `echo "hello world" && echo "a warning" >&2`},
+		Logger:  logger.Discard,
+	})
+	assert.Equal(t, "hello world", stdout)
+	assert.Equal(t, "a warning", stderr)
+
+	stdout, stderr, err := RunCommandAndGetStdOutErrE(t, Command{
+		Command: "sh",
+		Args:    []string{"-c", `echo "partial output" && echo "this command has failed" >&2 && exit 1`},
+		Logger:  logger.Discard,
+	})
+	require.Error(t, err)
+	assert.Equal(t, "partial output", stdout)
+	assert.Equal(t, "this command has failed", stderr)
+}
+
 func TestCommandOutputType(t *testing.T) {
 	t.Parallel()
 
@@ -183,3 +263,102 @@ func TestCommandOutputType(t *testing.T) {
 		assert.Len(t, o.Output.Combined(), len(stdout)+len(stderr)+1) // +1 for newline
 	}
 }
+
+func TestRunCommandWithOutputMaxLines(t *testing.T) {
+	t.Parallel()
+
+	outputFile := filepath.Join(t.TempDir(), "output.log")
+	cmd := Command{
+		Command:            "bash",
+		Args:               []string{"-c", `for i in $(seq 1 100); do echo "line $i"; done; echo "Error: transient failure"; for i in $(seq 101 200); do echo "line $i"; done`},
+		OutputMaxLines:     6,
+		OutputKeepPatterns: []string{"transient failure"},
+		OutputFile:         outputFile,
+		Logger:             logger.Discard,
+	}
+
+	out, err := RunCommandAndGetOutputE(t, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"line 1",
+		"line 2",
+		"line 3",
+		fmt.Sprintf("[... 195 lines omitted, see %s for the full output, 5 of which matched a pattern to keep (or surround such a line) and follow ...]", outputFile),
+		"line 99",
+		"line 100",
+		"Error: transient failure",
+		"line 101",
+		"line 102",
+		"line 198",
+		"line 199",
+		"line 200",
+	}, "\n"), out)
+
+	fullOutput, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(fullOutput), "==> bash -c (started at "))
+	assert.Equal(t, 202, strings.Count(string(fullOutput), "\n"))
+	assert.Contains(t, string(fullOutput), "line 150\n")
+}
+
+func TestRunCommandWithOutputMaxLinesKeepsWrappedMatches(t *testing.T) {
+	t.Parallel()
+
+	cmd := Command{
+		Command:            "bash",
+		Args:               []string{"-c", `for i in $(seq 1 100); do echo "line $i"; done; echo "Error: creating instance: RequestLimit"; echo "  Exceeded: slow down"; for i in $(seq 101 1100); do echo "line $i"; done`},
+		OutputMaxLines:     10,
+		OutputKeepPatterns: []string{"RequestLimit ?Exceeded"},
+		Logger:             logger.Discard,
+	}
+
+	out, err := RunCommandAndGetOutputE(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, out, "line 100\nError: creating instance: RequestLimit\n  Exceeded: slow down\nline 101\nline 102\nline 1096")
+
+	// The kept lines are capped at OutputMaxLines
+	cmd.Args = []string{"-c", `for i in $(seq 1 100); do echo "Error: RequestLimitExceeded $i"; done`}
+	out, err = RunCommandAndGetOutputE(t, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, 5+1+10+5, strings.Count(out, "\n")+1)
+}
+
+func TestRunCommandAppendsToOutputFile(t *testing.T) {
+	t.Parallel()
+
+	outputFile := filepath.Join(t.TempDir(), "output.log")
+	for _, word := range []string{"apply", "destroy"} {
+		_, err := RunCommandAndGetOutputE(t, Command{Command: "echo", Args: []string{word}, OutputFile: outputFile, Logger: logger.Discard})
+		require.NoError(t, err)
+	}
+
+	fullOutput, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(fullOutput), "\n"), "\n")
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "==> echo apply (started at "))
+	assert.Equal(t, "apply", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "==> echo destroy (started at "))
+	assert.Equal(t, "destroy", lines[3])
+}
+
+func TestLineBufferWithoutLimit(t *testing.T) {
+	t.Parallel()
+
+	buffer := newLineBuffer(outputLimits{})
+	for i := 0; i < 1000; i++ {
+		buffer.add(fmt.Sprintf("line %d", i))
+	}
+	assert.Equal(t, 1000, len(strings.Split(buffer.String(), "\n")))
+	assert.Equal(t, 0, buffer.omitted)
+}
+
+func TestLineBufferWithLimitNotReached(t *testing.T) {
+	t.Parallel()
+
+	buffer := newLineBuffer(outputLimits{maxLines: 5})
+	for i := 0; i < 5; i++ {
+		buffer.add(fmt.Sprintf("line %d", i))
+	}
+	assert.Equal(t, "line 0\nline 1\nline 2\nline 3\nline 4", buffer.String())
+}
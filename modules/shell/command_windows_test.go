@@ -0,0 +1,38 @@
+package shell
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommandAndGetOutputOnWindows(t *testing.T) {
+	t.Parallel()
+
+	cmd := Command{
+		Command: "cmd",
+		Args:    []string{"/c", "echo %GREETING%"},
+		Env:     map[string]string{"GREETING": "hello"},
+	}
+
+	out := RunCommandAndGetOutput(t, cmd)
+	assert.Equal(t, "hello", strings.TrimSpace(out))
+}
+
+func TestRunCommandGetExitCodeOnWindows(t *testing.T) {
+	t.Parallel()
+
+	cmd := Command{
+		Command: "cmd",
+		Args:    []string{"/c", "exit 3"},
+	}
+
+	err := RunCommandE(t, cmd)
+	require.Error(t, err)
+
+	exitCode, err := GetExitCodeForRunCommandError(err)
+	require.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+}
@@ -1,6 +1,9 @@
 package shell
 
 import (
+	"fmt"
+	"io"
+	"regexp"
 	"strings"
 	"sync"
 )
@@ -13,14 +16,24 @@ type output struct {
 	merged *merged
 }
 
-func newOutput() *output {
-	m := new(merged)
+// outputLimits configures how much of the output of a command is kept in memory. See Command for details.
+type outputLimits struct {
+	maxLines     int
+	keepPatterns []*regexp.Regexp
+	file         io.Writer
+	filePath     string
+}
+
+func newLimitedOutput(limits outputLimits) *output {
+	m := &merged{lines: newLineBuffer(limits), file: limits.file}
 	return &output{
 		merged: m,
 		stdout: &outputStream{
+			lines:  newLineBuffer(limits),
 			merged: m,
 		},
 		stderr: &outputStream{
+			lines:  newLineBuffer(limits),
 			merged: m,
 		},
 	}
@@ -51,12 +64,12 @@ func (o *output) Combined() string {
 }
 
 type outputStream struct {
-	Lines []string
+	lines *lineBuffer
 	*merged
 }
 
 func (st *outputStream) WriteString(s string) (n int, err error) {
-	st.Lines = append(st.Lines, string(s))
+	st.lines.add(s)
 	return st.merged.WriteString(s)
 }
 
@@ -65,13 +78,14 @@ func (st *outputStream) String() string {
 		return ""
 	}
 
-	return strings.Join(st.Lines, "\n")
+	return st.lines.String()
 }
 
 type merged struct {
 	// ensure that there are no parallel writes
 	sync.Mutex
-	Lines []string
+	lines *lineBuffer
+	file  io.Writer // If set, every line is also written to this file, so the full output is available
 }
 
 func (m *merged) String() string {
@@ -79,14 +93,137 @@ func (m *merged) String() string {
 		return ""
 	}
 
-	return strings.Join(m.Lines, "\n")
+	return m.lines.String()
 }
 
 func (m *merged) WriteString(s string) (n int, err error) {
 	m.Lock()
 	defer m.Unlock()
 
-	m.Lines = append(m.Lines, string(s))
+	m.lines.add(s)
+
+	if m.file != nil {
+		if _, err := io.WriteString(m.file, s+"\n"); err != nil {
+			return 0, err
+		}
+	}
 
 	return len(s), nil
 }
+
+// keepContextLines is the number of lines kept before and after each line in the middle of truncated output that
+// matches one of the keepPatterns, so that error messages wrapped over several lines are kept whole.
+const keepContextLines = 2
+
+// lineBuffer stores lines of output. If maxLines is greater than zero, it only stores the first and last maxLines/2
+// lines, and, from the lines in between, only up to maxLines of the ones that match one of keepPatterns, along with
+// keepContextLines of context around each, so that errors such as the retryable errors of Terraform can still be found
+// in the output.
+type lineBuffer struct {
+	limits  outputLimits
+	head    []string
+	tail    []string
+	kept    []string
+	omitted int
+	// The last dropped lines that weren't kept, which are kept as context if the next dropped line matches
+	before []string
+	// The number of dropped lines to keep as context after the last dropped line that matched
+	after int
+}
+
+func newLineBuffer(limits outputLimits) *lineBuffer {
+	return &lineBuffer{limits: limits}
+}
+
+func (b *lineBuffer) add(line string) {
+	if b.limits.maxLines <= 0 || len(b.head) < b.limits.maxLines/2 {
+		b.head = append(b.head, line)
+		return
+	}
+
+	b.tail = append(b.tail, line)
+	tailSize := b.limits.maxLines - b.limits.maxLines/2
+	if len(b.tail) <= tailSize {
+		return
+	}
+
+	dropped := b.tail[0]
+	b.tail = b.tail[1:]
+	b.omitted++
+	b.keepIfMatches(dropped)
+}
+
+// keepIfMatches keeps the given line dropped from the middle of the output if it, or it along with the lines before it
+// (in case an error message is wrapped over several lines), matches one of the keepPatterns, or if it's context after
+// such a line. Once maxLines lines are kept, no more are.
+func (b *lineBuffer) keepIfMatches(dropped string) {
+	if len(b.kept) >= b.limits.maxLines {
+		return
+	}
+
+	if b.matchesKeepPattern(dropped) {
+		b.keep(append(b.before, dropped)...)
+		b.before = nil
+		b.after = keepContextLines
+		return
+	}
+
+	if b.after > 0 {
+		b.keep(dropped)
+		b.after--
+		return
+	}
+
+	b.before = append(b.before, dropped)
+	if len(b.before) > keepContextLines {
+		b.before = b.before[1:]
+	}
+}
+
+// matchesKeepPattern returns true if one of the keepPatterns matches the given line, either on its own or joined with
+// the lines before it.
+func (b *lineBuffer) matchesKeepPattern(line string) bool {
+	joined := strings.TrimSpace(line)
+	for i := len(b.before) - 1; i >= 0; i-- {
+		joined = strings.TrimSpace(b.before[i]) + " " + joined
+	}
+
+	for _, pattern := range b.limits.keepPatterns {
+		if pattern.MatchString(line) || pattern.MatchString(joined) {
+			return true
+		}
+	}
+	return false
+}
+
+// keep keeps the given lines, up to maxLines in total.
+func (b *lineBuffer) keep(lines ...string) {
+	for _, line := range lines {
+		if len(b.kept) >= b.limits.maxLines {
+			return
+		}
+		b.kept = append(b.kept, line)
+	}
+}
+
+func (b *lineBuffer) String() string {
+	if b.omitted == 0 {
+		return strings.Join(append(append([]string{}, b.head...), b.tail...), "\n")
+	}
+
+	notice := fmt.Sprintf("[... %d lines omitted", b.omitted)
+	if b.limits.filePath != "" {
+		notice += fmt.Sprintf(", see %s for the full output", b.limits.filePath)
+	}
+	if len(b.kept) > 0 {
+		notice += fmt.Sprintf(", %d of which matched a pattern to keep (or surround such a line) and follow", len(b.kept))
+	}
+	notice += " ...]"
+
+	lines := make([]string, 0, len(b.head)+1+len(b.kept)+len(b.tail))
+	lines = append(lines, b.head...)
+	lines = append(lines, notice)
+	lines = append(lines, b.kept...)
+	lines = append(lines, b.tail...)
+	return strings.Join(lines, "\n")
+}
@@ -5,8 +5,13 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"runtime"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/shell"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"golang.org/x/crypto/ssh"
 )
@@ -55,3 +60,41 @@ func GenerateRSAKeyPairE(t testing.TestingT, keySize int) (*KeyPair, error) {
 	// Return
 	return &KeyPair{PublicKey: sshPubKeyStr, PrivateKey: keyPem}, nil
 }
+
+// WritePrivateKeyToFile writes the private key of the given key pair to the given path, readable only by the current
+// user, so that tools such as ssh accept it as an identity file. Fails the test on error.
+func WritePrivateKeyToFile(t testing.TestingT, keyPair *KeyPair, path string) {
+	err := WritePrivateKeyToFileE(t, keyPair, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// WritePrivateKeyToFileE writes the private key of the given key pair to the given path, readable only by the current
+// user, so that tools such as ssh accept it as an identity file. Windows ignores file modes, and ssh checks the ACL of
+// the file instead, so on Windows the ACL is replaced by one that only grants the current user access.
+func WritePrivateKeyToFileE(t testing.TestingT, keyPair *KeyPair, path string) error {
+	logger.Logf(t, "Writing private key to %s", path)
+
+	if err := ioutil.WriteFile(path, []byte(keyPair.PrivateKey), 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0600); err != nil {
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		return nil
+	}
+
+	currentUser, err := user.Current()
+	if err != nil {
+		return err
+	}
+	return shell.RunCommandE(t, shell.Command{
+		Command: "icacls",
+		Args:    []string{path, "/inheritance:r", "/grant:r", currentUser.Username + ":F"},
+		Logger:  logger.Discard,
+	})
+}
@@ -0,0 +1,29 @@
+package ssh
+
+import (
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePrivateKeyToFileRestrictsAclOnWindows(t *testing.T) {
+	t.Parallel()
+
+	keyPair := GenerateRSAKeyPair(t, 2048)
+	path := filepath.Join(t.TempDir(), "id_rsa")
+
+	WritePrivateKeyToFile(t, keyPair, path)
+
+	currentUser, err := user.Current()
+	require.NoError(t, err)
+
+	acl := shell.RunCommandAndGetOutput(t, shell.Command{Command: "icacls", Args: []string{path}})
+	assert.Contains(t, acl, currentUser.Username+":(F)")
+	assert.NotContains(t, acl, `BUILTIN\Users`)
+	assert.NotContains(t, strings.ToLower(acl), "everyone")
+}
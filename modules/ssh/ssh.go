@@ -2,6 +2,7 @@
 package ssh
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -32,6 +33,7 @@ type Host struct {
 	OverrideSshAgent *SshAgent // enable an in process `SshAgent` for connections to this host (disabled by default)
 	Password         string    // plain text password (blank by default)
 	CustomPort       int       // port number to use to connect to the host (port 22 will be used if unset)
+	JumpHost         *Host     // if set, connect to the host through this jump host, e.g. a bastion host in front of private instances (its own JumpHost is ignored)
 }
 
 type ScpDownloadOptions struct {
@@ -52,24 +54,17 @@ func ScpFileTo(t testing.TestingT, host Host, mode os.FileMode, remotePath, cont
 
 // ScpFileToE uploads the contents using SCP to the given host and return an error if the process fails.
 func ScpFileToE(t testing.TestingT, host Host, mode os.FileMode, remotePath, contents string) error {
-	authMethods, err := createAuthMethodsForHost(host)
-	if err != nil {
-		return err
-	}
 	dir, file := filepath.Split(remotePath)
 
-	hostOptions := SshConnectionOptions{
-		Username:    host.SshUserName,
-		Address:     host.Hostname,
-		Port:        host.getPort(),
-		Command:     "/usr/bin/scp -t " + dir,
-		AuthMethods: authMethods,
+	hostOptions, err := createSshConnectionOptions(host, "/usr/bin/scp -t "+dir)
+	if err != nil {
+		return err
 	}
 
 	scp := sendScpCommandsToCopyFile(mode, file, contents)
 
 	sshSession := &SshSession{
-		Options:  &hostOptions,
+		Options:  hostOptions,
 		JumpHost: &JumpHostSession{},
 		Input:    &scp,
 	}
@@ -91,24 +86,15 @@ func ScpFileFrom(t testing.TestingT, host Host, remotePath string, localDestinat
 
 // ScpFileFromE downloads the file from remotePath on the given host using SCP and returns an error if the process fails.
 func ScpFileFromE(t testing.TestingT, host Host, remotePath string, localDestination *os.File, useSudo bool) error {
-	authMethods, err := createAuthMethodsForHost(host)
+	dir := filepath.Dir(remotePath)
 
+	hostOptions, err := createSshConnectionOptions(host, "/usr/bin/scp -t "+dir)
 	if err != nil {
 		return err
 	}
 
-	dir := filepath.Dir(remotePath)
-
-	hostOptions := SshConnectionOptions{
-		Username:    host.SshUserName,
-		Address:     host.Hostname,
-		Port:        host.getPort(),
-		Command:     "/usr/bin/scp -t " + dir,
-		AuthMethods: authMethods,
-	}
-
 	sshSession := &SshSession{
-		Options:  &hostOptions,
+		Options:  hostOptions,
 		JumpHost: &JumpHostSession{},
 	}
 
@@ -131,21 +117,13 @@ func ScpDirFrom(t testing.TestingT, options ScpDownloadOptions, useSudo bool) {
 // be downloaded. This function will not recursively download subdirectories or follow
 // symlinks.
 func ScpDirFromE(t testing.TestingT, options ScpDownloadOptions, useSudo bool) error {
-	authMethods, err := createAuthMethodsForHost(options.RemoteHost)
+	hostOptions, err := createSshConnectionOptions(options.RemoteHost, "/usr/bin/scp -t "+options.RemoteDir)
 	if err != nil {
 		return err
 	}
 
-	hostOptions := SshConnectionOptions{
-		Username:    options.RemoteHost.SshUserName,
-		Address:     options.RemoteHost.Hostname,
-		Port:        options.RemoteHost.getPort(),
-		Command:     "/usr/bin/scp -t " + options.RemoteDir,
-		AuthMethods: authMethods,
-	}
-
 	sshSession := &SshSession{
-		Options:  &hostOptions,
+		Options:  hostOptions,
 		JumpHost: &JumpHostSession{},
 	}
 
@@ -216,11 +194,17 @@ func CheckSshConnectionWithRetry(t testing.TestingT, host Host, retries int, sle
 // CheckSshConnectionWithRetryE attempts to connect via SSH until max retries has been exceeded and returns an error if
 // the connection fails
 func CheckSshConnectionWithRetryE(t testing.TestingT, host Host, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host) error) error {
+	return CheckSshConnectionWithRetryContextE(context.Background(), t, host, retries, sleepBetweenRetries, f...)
+}
+
+// CheckSshConnectionWithRetryContextE works like CheckSshConnectionWithRetryE, but stops retrying as soon as the given
+// context is done. An attempt that is already in progress is not interrupted.
+func CheckSshConnectionWithRetryContextE(ctx context.Context, t testing.TestingT, host Host, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host) error) error {
 	handler := CheckSshConnectionE
 	if f != nil {
 		handler = f[0]
 	}
-	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Checking SSH connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
+	_, err := retry.DoWithRetryContextE(ctx, t, fmt.Sprintf("Checking SSH connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
 		return "", handler(t, host)
 	})
 
@@ -238,21 +222,13 @@ func CheckSshCommand(t testing.TestingT, host Host, command string) string {
 
 // CheckSshCommandE checks that you can connect via SSH to the given host and run the given command. Returns the stdout/stderr.
 func CheckSshCommandE(t testing.TestingT, host Host, command string) (string, error) {
-	authMethods, err := createAuthMethodsForHost(host)
+	hostOptions, err := createSshConnectionOptions(host, command)
 	if err != nil {
 		return "", err
 	}
 
-	hostOptions := SshConnectionOptions{
-		Username:    host.SshUserName,
-		Address:     host.Hostname,
-		Port:        host.getPort(),
-		Command:     command,
-		AuthMethods: authMethods,
-	}
-
 	sshSession := &SshSession{
-		Options:  &hostOptions,
+		Options:  hostOptions,
 		JumpHost: &JumpHostSession{},
 	}
 
@@ -278,11 +254,17 @@ func CheckSshCommandWithRetry(t testing.TestingT, host Host, command string, ret
 // It return an error if the command fails after max retries has been exceeded.
 
 func CheckSshCommandWithRetryE(t testing.TestingT, host Host, command string, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host, string) (string, error)) (string, error) {
+	return CheckSshCommandWithRetryContextE(context.Background(), t, host, command, retries, sleepBetweenRetries, f...)
+}
+
+// CheckSshCommandWithRetryContextE works like CheckSshCommandWithRetryE, but stops retrying as soon as the given
+// context is done. An attempt that is already in progress is not interrupted.
+func CheckSshCommandWithRetryContextE(ctx context.Context, t testing.TestingT, host Host, command string, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host, string) (string, error)) (string, error) {
 	handler := CheckSshCommandE
 	if f != nil {
 		handler = f[0]
 	}
-	return retry.DoWithRetryE(t, fmt.Sprintf("Checking SSH connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
+	return retry.DoWithRetryContextE(ctx, t, fmt.Sprintf("Checking SSH connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
 		return handler(t, host, command)
 	})
 }
@@ -300,42 +282,11 @@ func CheckPrivateSshConnection(t testing.TestingT, publicHost Host, privateHost
 
 // CheckPrivateSshConnectionE attempts to connect to privateHost (which is not addressable from the Internet) via a
 // separate publicHost (which is addressable from the Internet) and then executes "command" on privateHost and returns
-// its output. It is useful for checking that it's possible to SSH from a Bastion Host to a private instance.
+// its output. It is useful for checking that it's possible to SSH from a Bastion Host to a private instance. To run
+// other commands or copy files through a Bastion Host, set the JumpHost of the Host instead.
 func CheckPrivateSshConnectionE(t testing.TestingT, publicHost Host, privateHost Host, command string) (string, error) {
-	jumpHostAuthMethods, err := createAuthMethodsForHost(publicHost)
-	if err != nil {
-		return "", err
-	}
-
-	jumpHostOptions := SshConnectionOptions{
-		Username:    publicHost.SshUserName,
-		Address:     publicHost.Hostname,
-		Port:        publicHost.getPort(),
-		AuthMethods: jumpHostAuthMethods,
-	}
-
-	hostAuthMethods, err := createAuthMethodsForHost(privateHost)
-	if err != nil {
-		return "", err
-	}
-
-	hostOptions := SshConnectionOptions{
-		Username:    privateHost.SshUserName,
-		Address:     privateHost.Hostname,
-		Port:        privateHost.getPort(),
-		Command:     command,
-		AuthMethods: hostAuthMethods,
-		JumpHost:    &jumpHostOptions,
-	}
-
-	sshSession := &SshSession{
-		Options:  &hostOptions,
-		JumpHost: &JumpHostSession{},
-	}
-
-	defer sshSession.Cleanup(t)
-
-	return runSSHCommand(t, sshSession)
+	privateHost.JumpHost = &publicHost
+	return CheckSshCommandE(t, privateHost, command)
 }
 
 // FetchContentsOfFiles connects to the given host via SSH and fetches the contents of the files at the given filePaths.
@@ -546,6 +497,39 @@ func setUpSSHSession(sshSession *SshSession) error {
 	return nil
 }
 
+// createSshConnectionOptions returns the options to connect to the given host, through its JumpHost if it has one, and
+// run the given command on it.
+func createSshConnectionOptions(host Host, command string) (*SshConnectionOptions, error) {
+	authMethods, err := createAuthMethodsForHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	hostOptions := &SshConnectionOptions{
+		Username:    host.SshUserName,
+		Address:     host.Hostname,
+		Port:        host.getPort(),
+		Command:     command,
+		AuthMethods: authMethods,
+	}
+
+	if host.JumpHost != nil {
+		jumpHostAuthMethods, err := createAuthMethodsForHost(*host.JumpHost)
+		if err != nil {
+			return nil, err
+		}
+
+		hostOptions.JumpHost = &SshConnectionOptions{
+			Username:    host.JumpHost.SshUserName,
+			Address:     host.JumpHost.Hostname,
+			Port:        host.JumpHost.getPort(),
+			AuthMethods: jumpHostAuthMethods,
+		}
+	}
+
+	return hostOptions, nil
+}
+
 func createSSHClient(options *SshConnectionOptions) (*ssh.Client, error) {
 	sshClientConfig := createSSHClientConfig(options)
 	return ssh.Dial("tcp", options.ConnectionString(), sshClientConfig)
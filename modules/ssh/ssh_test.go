@@ -1,12 +1,14 @@
 package ssh
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 
 	grunttest "github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHostWithDefaultPort(t *testing.T) {
@@ -26,6 +28,25 @@ func TestHostWithCustomPort(t *testing.T) {
 	assert.Equal(t, customPort, host.getPort(), "host.getPort() did not return the custom port number")
 }
 
+func TestCreateSshConnectionOptionsWithJumpHost(t *testing.T) {
+	t.Parallel()
+
+	bastion := Host{Hostname: "203.0.113.10", SshUserName: "ubuntu", Password: "bastion"}
+	host := Host{Hostname: "10.0.1.20", SshUserName: "ec2-user", Password: "private", CustomPort: 2222, JumpHost: &bastion}
+
+	options, err := createSshConnectionOptions(host, "hostname")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.1.20:2222", options.ConnectionString())
+	assert.Equal(t, "hostname", options.Command)
+	require.NotNil(t, options.JumpHost)
+	assert.Equal(t, "203.0.113.10:22", options.JumpHost.ConnectionString())
+	assert.Equal(t, "ubuntu", options.JumpHost.Username)
+
+	bastion.Password = ""
+	_, err = createSshConnectionOptions(host, "hostname")
+	assert.Error(t, err)
+}
+
 // global var for use in mock callback
 var timesCalled int
 
@@ -98,6 +119,22 @@ func TestCheckSshCommandWithRetry(t *testing.T) {
 	CheckSshCommandWithRetry(t, host, command, retries, 3, mockSshCommandE)
 }
 
+func TestCheckSshConnectionWithRetryContextEStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	host := Host{Hostname: "Host"}
+	attempts := 0
+
+	err := CheckSshConnectionWithRetryContextE(ctx, t, host, 10, 3, func(t grunttest.TestingT, host Host) error {
+		attempts++
+		cancel()
+		return errors.New("connection refused")
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, attempts)
+}
+
 func mockSshConnectionE(t grunttest.TestingT, host Host) error {
 	timesCalled += 1
 	if timesCalled >= 5 {
@@ -0,0 +1,217 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// AnnotationFormat is a format for reporting Terraform errors to a CI system, so they show up inline on pull requests.
+type AnnotationFormat string
+
+const (
+	// AnnotationFormatNone doesn't report errors to a CI system.
+	AnnotationFormatNone AnnotationFormat = ""
+
+	// AnnotationFormatGitHub prints GitHub Actions workflow commands (::error ...) to stdout. Note that GitHub only
+	// picks these up from the raw output of go test, not the JSON output of go test -json.
+	AnnotationFormatGitHub AnnotationFormat = "github"
+
+	// AnnotationFormatGitLab adds the errors to the GitLab Code Quality report at the path in the
+	// TERRATEST_GITLAB_CODE_QUALITY_REPORT environment variable (gl-code-quality-report.json by default), which should be
+	// published with artifacts:reports:codequality. Packages that run in parallel must use different paths.
+	AnnotationFormatGitLab AnnotationFormat = "gitlab"
+)
+
+const (
+	// AnnotationFormatEnvVar is the environment variable that sets the AnnotationFormat for all Options that don't set
+	// one themselves, e.g., TERRATEST_CI_ANNOTATIONS=github.
+	AnnotationFormatEnvVar = "TERRATEST_CI_ANNOTATIONS"
+
+	// GitLabCodeQualityReportEnvVar is the environment variable with the path of the GitLab Code Quality report.
+	GitLabCodeQualityReportEnvVar = "TERRATEST_GITLAB_CODE_QUALITY_REPORT"
+
+	defaultGitLabCodeQualityReport = "gl-code-quality-report.json"
+)
+
+// annotation is an error in the output of a Terraform command.
+type annotation struct {
+	Summary string // The summary of the error, e.g., "Invalid reference"
+	File    string // The file the error is in, if known
+	Line    int    // The line the error is on, if known
+}
+
+var (
+	ansiEscapeRegexp      = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	errorSummaryRegexp    = regexp.MustCompile(`^Error: (.+)$`)
+	errorLocationRegexp   = regexp.MustCompile(`^on (.+) line (\d+)`)
+	diagnosticBoxPrefixes = []string{"│", "╷", "╵"}
+)
+
+// parseTerraformErrors parses the errors, and where they are, from the output of a Terraform command.
+func parseTerraformErrors(output string) []annotation {
+	annotations := []annotation{}
+	for _, line := range strings.Split(ansiEscapeRegexp.ReplaceAllString(output, ""), "\n") {
+		// Since Terraform 0.15, diagnostics are drawn in a box
+		line = strings.TrimSpace(line)
+		for _, prefix := range diagnosticBoxPrefixes {
+			line = strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+
+		if matches := errorSummaryRegexp.FindStringSubmatch(line); matches != nil {
+			annotations = append(annotations, annotation{Summary: matches[1]})
+			continue
+		}
+
+		// The location follows the summary of the error it belongs to
+		if matches := errorLocationRegexp.FindStringSubmatch(line); matches != nil && len(annotations) > 0 {
+			current := &annotations[len(annotations)-1]
+			if current.File == "" {
+				current.File = matches[1]
+				current.Line, _ = strconv.Atoi(matches[2])
+			}
+		}
+	}
+	return annotations
+}
+
+// annotateErrors reports the errors in the output of a failed Terraform command in the AnnotationFormat of the options,
+// if any. If no errors can be parsed from the output, the error itself is reported. Failing to report errors is logged,
+// rather than returned, so it doesn't hide the error of the command.
+func annotateErrors(t testing.TestingT, options *Options, args []string, output string, cmdErr error) {
+	format := options.AnnotationFormat
+	if format == AnnotationFormatNone {
+		format = AnnotationFormat(os.Getenv(AnnotationFormatEnvVar))
+	}
+	if format == AnnotationFormatNone || len(args) == 0 {
+		return
+	}
+
+	annotations := parseTerraformErrors(output)
+	if len(annotations) == 0 {
+		annotations = []annotation{{Summary: strings.SplitN(cmdErr.Error(), "\n", 2)[0]}}
+	}
+	for i := range annotations {
+		annotations[i].File = annotationPath(options.TerraformDir, annotations[i].File)
+	}
+
+	title := fmt.Sprintf("terraform %s failed in %s (%s)", args[0], options.TerraformDir, t.Name())
+
+	switch format {
+	case AnnotationFormatGitHub:
+		for _, annotation := range annotations {
+			fmt.Println(formatGitHubAnnotation(title, annotation))
+		}
+	case AnnotationFormatGitLab:
+		path := os.Getenv(GitLabCodeQualityReportEnvVar)
+		if path == "" {
+			path = defaultGitLabCodeQualityReport
+		}
+		if err := appendToGitLabCodeQualityReport(path, title, annotations); err != nil {
+			options.Logger.Logf(t, "Failed to add the errors to the GitLab Code Quality report %s: %v", path, err)
+		}
+	default:
+		options.Logger.Logf(t, "Unknown CI annotation format %q. Supported formats are %q and %q.", format, AnnotationFormatGitHub, AnnotationFormatGitLab)
+	}
+}
+
+// annotationPath returns the path of the given file, which is relative to the Terraform dir, relative to the root of the
+// repo (the working directory of the CI job), which is what CI systems expect. If the file is unknown, the path of the
+// Terraform dir is returned instead.
+func annotationPath(terraformDir string, file string) string {
+	path := filepath.Join(terraformDir, file)
+
+	root := os.Getenv("GITHUB_WORKSPACE")
+	if root == "" {
+		root = os.Getenv("CI_PROJECT_DIR")
+	}
+	if root == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			root = cwd
+		}
+	}
+
+	if absPath, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(root, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+var gitHubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var gitHubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+func formatGitHubAnnotation(title string, annotation annotation) string {
+	properties := []string{"file=" + gitHubPropertyEscaper.Replace(annotation.File)}
+	if annotation.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", annotation.Line))
+	}
+	properties = append(properties, "title="+gitHubPropertyEscaper.Replace(title))
+	return fmt.Sprintf("::error %s::%s", strings.Join(properties, ","), gitHubDataEscaper.Replace(annotation.Summary))
+}
+
+// gitLabCodeQualityIssue is an issue in the GitLab Code Quality report format, a subset of the Code Climate format.
+type gitLabCodeQualityIssue struct {
+	Description string                    `json:"description"`
+	CheckName   string                    `json:"check_name"`
+	Fingerprint string                    `json:"fingerprint"`
+	Severity    string                    `json:"severity"`
+	Location    gitLabCodeQualityLocation `json:"location"`
+}
+
+type gitLabCodeQualityLocation struct {
+	Path  string                 `json:"path"`
+	Lines gitLabCodeQualityLines `json:"lines"`
+}
+
+type gitLabCodeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// gitLabCodeQualityReportMutex keeps tests running in parallel from overwriting each other's issues.
+var gitLabCodeQualityReportMutex sync.Mutex
+
+func appendToGitLabCodeQualityReport(path string, title string, annotations []annotation) error {
+	gitLabCodeQualityReportMutex.Lock()
+	defer gitLabCodeQualityReportMutex.Unlock()
+
+	issues := []gitLabCodeQualityIssue{}
+	if contents, err := ioutil.ReadFile(path); err == nil {
+		if err := json.Unmarshal(contents, &issues); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, annotation := range annotations {
+		line := annotation.Line
+		if line == 0 {
+			line = 1
+		}
+		description := fmt.Sprintf("%s: %s", title, annotation.Summary)
+		issues = append(issues, gitLabCodeQualityIssue{
+			Description: description,
+			CheckName:   "terratest",
+			Fingerprint: fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%s", annotation.File, line, description)))),
+			Severity:    "major",
+			Location:    gitLabCodeQualityLocation{Path: annotation.File, Lines: gitLabCodeQualityLines{Begin: line}},
+		})
+	}
+
+	contents, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, contents, 0644)
+}
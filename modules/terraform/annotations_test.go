@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerraformErrors(t *testing.T) {
+	t.Parallel()
+
+	output := "\x1b[31m╷\x1b[0m\x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m\x1b[1m\x1b[31mError: \x1b[0m\x1b[0m\x1b[1mReference to undeclared input variable\x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m\x1b[0m  on main.tf line 12, in resource \"aws_instance\" \"web\":\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m  12:   ami = \x1b[4mvar.amii\x1b[0m\x1b[0m\n" +
+		"\x1b[31m╵\x1b[0m\x1b[0m\n" +
+		"\n" +
+		"Error: Error launching source instance: UnauthorizedOperation\n"
+
+	assert.Equal(t, []annotation{
+		{Summary: "Reference to undeclared input variable", File: "main.tf", Line: 12},
+		{Summary: "Error launching source instance: UnauthorizedOperation"},
+	}, parseTerraformErrors(output))
+
+	assert.Empty(t, parseTerraformErrors("Apply complete! Resources: 1 added, 0 changed, 0 destroyed."))
+}
+
+func TestFormatGitHubAnnotation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"::error file=examples/vpc/main.tf,line=12,title=terraform apply failed in examples/vpc (TestVpc%2C retry%3A 1)::Invalid reference%0Asee 100%25 of output",
+		formatGitHubAnnotation("terraform apply failed in examples/vpc (TestVpc, retry: 1)", annotation{Summary: "Invalid reference\nsee 100% of output", File: "examples/vpc/main.tf", Line: 12}),
+	)
+	assert.Equal(t,
+		"::error file=examples/vpc,title=terraform init failed::exit status 1",
+		formatGitHubAnnotation("terraform init failed", annotation{Summary: "exit status 1", File: "examples/vpc"}),
+	)
+}
+
+func TestAppendToGitLabCodeQualityReport(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "gl-code-quality-report.json")
+	require.NoError(t, appendToGitLabCodeQualityReport(path, "terraform apply failed", []annotation{{Summary: "Invalid reference", File: "examples/vpc/main.tf", Line: 12}}))
+	require.NoError(t, appendToGitLabCodeQualityReport(path, "terraform plan failed", []annotation{{Summary: "exit status 1", File: "examples/vpc"}}))
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	issues := []gitLabCodeQualityIssue{}
+	require.NoError(t, json.Unmarshal(contents, &issues))
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, "terraform apply failed: Invalid reference", issues[0].Description)
+	assert.Equal(t, gitLabCodeQualityLocation{Path: "examples/vpc/main.tf", Lines: gitLabCodeQualityLines{Begin: 12}}, issues[0].Location)
+	assert.Equal(t, 1, issues[1].Location.Lines.Begin)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+}
+
+func TestAnnotateErrorsGitLab(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// This test sets an environment variable.
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	t.Setenv(GitLabCodeQualityReportEnvVar, path)
+
+	options := &Options{TerraformDir: "examples/vpc", AnnotationFormat: AnnotationFormatGitLab}
+	annotateErrors(t, options, []string{"apply"}, "", fmt.Errorf("exit status 1\nmore details"))
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	issues := []gitLabCodeQualityIssue{}
+	require.NoError(t, json.Unmarshal(contents, &issues))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "terraform apply failed in examples/vpc (TestAnnotateErrorsGitLab): exit status 1", issues[0].Description)
+	assert.Equal(t, "examples/vpc", issues[0].Location.Path)
+}
@@ -1,8 +1,8 @@
 package terraform
 
 import (
-	"errors"
-
+	"github.com/gruntwork-io/terratest/modules/metrics"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -46,7 +46,110 @@ func TgApplyAll(t testing.TestingT, options *Options) string {
 // ApplyE runs terraform apply with the given options and return stdout/stderr. Note that this method does NOT call destroy and
 // assumes the caller is responsible for cleaning up any resources created by running apply.
 func ApplyE(t testing.TestingT, options *Options) (string, error) {
-	return RunTerraformCommandE(t, options, FormatArgs(options, "apply", "-input=false", "-auto-approve")...)
+	out, err := RunTerraformCommandE(t, options, FormatArgs(options, "apply", "-input=false", "-auto-approve")...)
+	if err == nil {
+		recordResourceCountMetrics(t, out)
+	}
+	return out, err
+}
+
+// recordResourceCountMetrics records the number of resources added, changed, and destroyed by an apply, if metrics are
+// enabled and the output contains the summary line.
+func recordResourceCountMetrics(t testing.TestingT, out string) {
+	if !metrics.Enabled() {
+		return
+	}
+	cnt, err := GetResourceCountE(t, out)
+	if err != nil {
+		return
+	}
+	metrics.Record(t, "terraform.resources.added", float64(cnt.Add), metrics.UnitCount, nil)
+	metrics.Record(t, "terraform.resources.changed", float64(cnt.Change), metrics.UnitCount, nil)
+	metrics.Record(t, "terraform.resources.destroyed", float64(cnt.Destroy), metrics.UnitCount, nil)
+}
+
+// ApplyWithRetryReport runs terraform apply with the given options and returns stdout/stderr along with a report of
+// every attempt, so tests can check how often the apply was retried and which RetryableTerraformErrors caused it. Note
+// that this method does NOT call destroy and assumes the caller is responsible for cleaning up any resources created by
+// running apply.
+func ApplyWithRetryReport(t testing.TestingT, options *Options) (string, *retry.Report) {
+	out, report, err := ApplyWithRetryReportE(t, options)
+	require.NoError(t, err)
+	return out, report
+}
+
+// ApplyWithRetryReportE runs terraform apply with the given options and returns stdout/stderr along with a report of
+// every attempt, so tests can check how often the apply was retried and which RetryableTerraformErrors caused it. The
+// report is returned even if the apply ultimately failed. Note that this method does NOT call destroy and assumes the
+// caller is responsible for cleaning up any resources created by running apply.
+func ApplyWithRetryReportE(t testing.TestingT, options *Options) (string, *retry.Report, error) {
+	out, report, err := RunTerraformCommandAndGetRetryReportE(t, options, FormatArgs(options, "apply", "-input=false", "-auto-approve")...)
+	if err == nil {
+		recordResourceCountMetrics(t, out)
+	}
+	return out, report, err
+}
+
+// InitAndApplyWithRetryReport runs terraform init and apply with the given options and returns stdout/stderr from the
+// apply command along with a report of every apply attempt. Note that this method does NOT call destroy and assumes the
+// caller is responsible for cleaning up any resources created by running apply.
+func InitAndApplyWithRetryReport(t testing.TestingT, options *Options) (string, *retry.Report) {
+	out, report, err := InitAndApplyWithRetryReportE(t, options)
+	require.NoError(t, err)
+	return out, report
+}
+
+// InitAndApplyWithRetryReportE runs terraform init and apply with the given options and returns stdout/stderr from the
+// apply command along with a report of every apply attempt. The report is nil if init failed. Note that this method
+// does NOT call destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
+func InitAndApplyWithRetryReportE(t testing.TestingT, options *Options) (string, *retry.Report, error) {
+	if _, err := InitE(t, options); err != nil {
+		return "", nil, err
+	}
+
+	return ApplyWithRetryReportE(t, options)
+}
+
+// ApplyWithResourceCount runs terraform apply with the given options and returns stdout/stderr along with the number of
+// resources added, changed, and destroyed, as reported in the summary line of the apply. Note that this method does NOT
+// call destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
+func ApplyWithResourceCount(t testing.TestingT, options *Options) (string, *ResourceCount) {
+	out, cnt, err := ApplyWithResourceCountE(t, options)
+	require.NoError(t, err)
+	return out, cnt
+}
+
+// ApplyWithResourceCountE runs terraform apply with the given options and returns stdout/stderr along with the number
+// of resources added, changed, and destroyed, as reported in the summary line of the apply. Note that this method does
+// NOT call destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
+func ApplyWithResourceCountE(t testing.TestingT, options *Options) (string, *ResourceCount, error) {
+	out, err := ApplyE(t, options)
+	if err != nil {
+		return out, nil, err
+	}
+
+	cnt, err := GetResourceCountE(t, out)
+	return out, cnt, err
+}
+
+// InitAndApplyWithResourceCount runs terraform init and apply with the given options and returns stdout/stderr from the
+// apply command along with the number of resources added, changed, and destroyed. Note that this method does NOT call
+// destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
+func InitAndApplyWithResourceCount(t testing.TestingT, options *Options) (string, *ResourceCount) {
+	out, cnt, err := InitAndApplyWithResourceCountE(t, options)
+	require.NoError(t, err)
+	return out, cnt
+}
+
+// InitAndApplyWithResourceCountE runs terraform init and apply with the given options and returns stdout/stderr from
+// the apply command along with the number of resources added, changed, and destroyed. Note that this method does NOT
+// call destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
+func InitAndApplyWithResourceCountE(t testing.TestingT, options *Options) (string, *ResourceCount, error) {
+	if _, err := InitE(t, options); err != nil {
+		return "", nil, err
+	}
+
+	return ApplyWithResourceCountE(t, options)
 }
 
 // TgApplyAllE runs terragrunt apply-all with the given options and return stdout/stderr. Note that this method does NOT call destroy and
@@ -60,8 +163,8 @@ func TgApplyAllE(t testing.TestingT, options *Options) (string, error) {
 }
 
 // ApplyAndIdempotent runs terraform apply with the given options and return stdout/stderr from the apply command. It then runs
-// plan again and will fail the test if plan requires additional changes. Note that this method does NOT call destroy and assumes
-// the caller is responsible for cleaning up any resources created by running apply.
+// plan again and will fail the test if plan requires additional changes, listing the resources that would change. Note that
+// this method does NOT call destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
 func ApplyAndIdempotent(t testing.TestingT, options *Options) string {
 	out, err := ApplyAndIdempotentE(t, options)
 	require.NoError(t, err)
@@ -70,8 +173,9 @@ func ApplyAndIdempotent(t testing.TestingT, options *Options) string {
 }
 
 // ApplyAndIdempotentE runs terraform apply with the given options and return stdout/stderr from the apply command. It then runs
-// plan again and will fail the test if plan requires additional changes. Note that this method does NOT call destroy and assumes
-// the caller is responsible for cleaning up any resources created by running apply.
+// plan again and returns a NotIdempotent error listing the resources that would change if plan requires additional changes.
+// Note that this method does NOT call destroy and assumes the caller is responsible for cleaning up any resources created by
+// running apply.
 func ApplyAndIdempotentE(t testing.TestingT, options *Options) (string, error) {
 	out, err := ApplyE(t, options)
 
@@ -79,19 +183,45 @@ func ApplyAndIdempotentE(t testing.TestingT, options *Options) (string, error) {
 		return out, err
 	}
 
-	exitCode, err := PlanExitCodeE(t, options)
+	// Save the plan, so the changes it detects can be listed without planning again
+	planOptions, cleanup, err := withPlanFileE(options)
+	if err != nil {
+		return out, err
+	}
+	defer cleanup()
+
+	exitCode, err := PlanExitCodeE(t, planOptions)
 
 	if err != nil {
 		return out, err
 	}
 
-	if exitCode != 0 {
-		return out, errors.New("terraform configuration not idempotent")
+	if exitCode != DefaultSuccessExitCode {
+		return out, notIdempotentE(t, planOptions, exitCode)
 	}
 
 	return out, nil
 }
 
+// notIdempotentE returns a NotIdempotent error listing the resources that would change according to the plan file of
+// the given options, which was saved by a plan that exited with the given code. If the plan didn't detect changes but
+// failed, or its plan file can't be read, the error is returned without them.
+func notIdempotentE(t testing.TestingT, options *Options, exitCode int) error {
+	if exitCode != TerraformPlanChangesPresentExitCode {
+		return NotIdempotent{}
+	}
+
+	jsonOut, err := ShowE(t, options)
+	if err == nil {
+		var plan *PlanStruct
+		if plan, err = parsePlanJson(jsonOut); err == nil {
+			return NotIdempotent{Changes: plan.Changes}
+		}
+	}
+	options.Logger.Logf(t, "Failed to determine the resources that would change: %v", err)
+	return NotIdempotent{}
+}
+
 // InitAndApplyAndIdempotent runs terraform init and apply with the given options and return stdout/stderr from the apply command. It then runs
 // plan again and will fail the test if plan requires additional changes. Note that this method does NOT call destroy and assumes
 // the caller is responsible for cleaning up any resources created by running apply.
@@ -112,3 +242,37 @@ func InitAndApplyAndIdempotentE(t testing.TestingT, options *Options) (string, e
 
 	return ApplyAndIdempotentE(t, options)
 }
+
+// InitAndApplyAndDestroy runs terraform init and apply with the given options, calls validate so the test can check the
+// deployed infrastructure (e.g., SSH to hosts or make HTTP requests to endpoints), and then runs terraform destroy.
+// Destroy runs even if apply or validate fails the test or panics, and if the test binary receives SIGINT or SIGTERM
+// (e.g., from Ctrl+C) in the meantime, in which case validate is skipped and the binary exits once destroy is done.
+// To keep the infrastructure around for several steps, call InitAndApply and defer Destroy instead.
+func InitAndApplyAndDestroy(t testing.TestingT, options *Options, validate func()) {
+	InitAndApplyAndDestroyWithRetryReport(t, options, validate)
+}
+
+// InitAndApplyAndDestroyWithRetryReport works like InitAndApplyAndDestroy, but also returns a report of every apply
+// attempt, so tests can check how often the apply was retried and which RetryableTerraformErrors caused it, instead of
+// searching the logs for the retry messages.
+func InitAndApplyAndDestroyWithRetryReport(t testing.TestingT, options *Options, validate func()) *retry.Report {
+	interrupts := handleInterrupts(t)
+	defer interrupts.Stop()
+	defer destroyOnExit(t, options)
+
+	_, report := InitAndApplyWithRetryReport(t, options)
+	if sig := interrupts.Received(); sig != nil {
+		t.Fatalf("Received %s during apply, so not running the validations", sig)
+	}
+	validate()
+	return report
+}
+
+// destroyOnExit runs terraform destroy with the given options. Defer it, so that it runs when the function returns,
+// fails the test with FailNow, or panics. Errors fail the test with Errorf rather than Fatal, so that they don't hide a
+// panic that is in progress.
+func destroyOnExit(t testing.TestingT, options *Options) {
+	if _, err := DestroyE(t, options); err != nil {
+		t.Errorf("Failed to destroy the resources of %s: %v", options.TerraformDir, err)
+	}
+}
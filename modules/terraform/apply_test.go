@@ -29,6 +29,29 @@ func TestApplyNoError(t *testing.T) {
 	require.NotRegexp(t, `\[\d*m`, out, "Output should not contain color escape codes")
 }
 
+func TestInitAndApplyAndDestroy(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-no-error", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		NoColor:      true,
+	}
+
+	validated := false
+	InitAndApplyAndDestroy(t, options, func() {
+		assert.Equal(t, "Hello, World", Output(t, options, "test"))
+		validated = true
+	})
+	assert.True(t, validated)
+
+	// The outputs are gone from the state once it's destroyed
+	_, err = OutputE(t, options, "test")
+	assert.Error(t, err)
+}
+
 func TestApplyWithErrorNoRetry(t *testing.T) {
 	t.Parallel()
 
@@ -63,6 +86,56 @@ func TestApplyWithErrorWithRetry(t *testing.T) {
 
 	require.Contains(t, out, "This is the first run, exiting with an error")
 }
+
+func TestApplyWithRetryReport(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-with-error", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		MaxRetries:   1,
+		RetryableTerraformErrors: map[string]string{
+			"This is the first run, exiting with an error": "Intentional failure in test fixture",
+		},
+	}
+
+	Init(t, options)
+	out, report := ApplyWithRetryReport(t, options)
+
+	require.Contains(t, out, "This is the first run, exiting with an error")
+	require.Len(t, report.Attempts, 2)
+	assert.Equal(t, 1, report.Retries())
+	assert.Equal(t, []string{"Intentional failure in test fixture"}, report.MatchedMessages())
+	assert.Error(t, report.Attempts[0].Error)
+	assert.NoError(t, report.Attempts[1].Error)
+}
+
+func TestInitAndApplyAndDestroyWithRetryReport(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-with-error", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		MaxRetries:   1,
+		RetryableTerraformErrors: map[string]string{
+			"This is the first run, exiting with an error": "Intentional failure in test fixture",
+		},
+	}
+
+	validated := false
+	report := InitAndApplyAndDestroyWithRetryReport(t, options, func() {
+		validated = true
+	})
+
+	assert.True(t, validated)
+	assert.Equal(t, 1, report.Retries())
+	assert.Equal(t, []string{"Intentional failure in test fixture"}, report.MatchedMessages())
+}
+
 func TestTgApplyAllTgError(t *testing.T) {
 	t.Parallel()
 
@@ -151,7 +224,7 @@ func TestIdempotentWithChanges(t *testing.T) {
 
 	require.NotEmpty(t, out)
 	require.Error(t, err)
-	require.EqualError(t, err, "terraform configuration not idempotent")
+	require.EqualError(t, err, "terraform configuration not idempotent, a second plan would change:\n  - null_resource.test (replace)")
 }
 
 func TestParallelism(t *testing.T) {
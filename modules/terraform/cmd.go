@@ -1,25 +1,108 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/metrics"
 	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/shell"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
+// CommandRunner runs the commands generated from Options. The default runs them on the shell, but it can be replaced
+// (see Options.CommandRunner), e.g. with a fake that returns canned output, so that code calling the functions in this
+// package can be unit tested offline.
+type CommandRunner interface {
+	// RunCommandAndGetOutputE runs the given command and returns its stdout and stderr, interleaved.
+	RunCommandAndGetOutputE(ctx context.Context, t testing.TestingT, command shell.Command) (string, error)
+	// RunCommandAndGetStdOutE runs the given command and returns solely its stdout.
+	RunCommandAndGetStdOutE(ctx context.Context, t testing.TestingT, command shell.Command) (string, error)
+	// RunCommandAndGetStdOutErrE runs the given command and returns its stdout and stderr as separate strings.
+	RunCommandAndGetStdOutErrE(ctx context.Context, t testing.TestingT, command shell.Command) (string, string, error)
+}
+
+// shellCommandRunner is the CommandRunner that runs commands on the shell.
+type shellCommandRunner struct{}
+
+func (shellCommandRunner) RunCommandAndGetOutputE(ctx context.Context, t testing.TestingT, command shell.Command) (string, error) {
+	return shell.RunCommandAndGetOutputContextE(ctx, t, command)
+}
+
+func (shellCommandRunner) RunCommandAndGetStdOutE(ctx context.Context, t testing.TestingT, command shell.Command) (string, error) {
+	return shell.RunCommandAndGetStdOutContextE(ctx, t, command)
+}
+
+func (shellCommandRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t testing.TestingT, command shell.Command) (string, string, error) {
+	return shell.RunCommandAndGetStdOutErrContextE(ctx, t, command)
+}
+
 func generateCommand(options *Options, args ...string) shell.Command {
 	cmd := shell.Command{
-		Command:    options.TerraformBinary,
-		Args:       args,
-		WorkingDir: options.TerraformDir,
-		Env:        options.EnvVars,
-		Logger:     options.Logger,
+		Command:        options.TerraformBinary,
+		Args:           args,
+		WorkingDir:     options.TerraformDir,
+		Env:            options.EnvVars,
+		Logger:         options.Logger,
+		OutputMaxLines: options.OutputMaxLines,
+		OutputFile:     options.OutputFile,
+	}
+	// Keep any lines with retryable errors, so they're still found when the output is truncated
+	for pattern := range options.RetryableTerraformErrors {
+		cmd.OutputKeepPatterns = append(cmd.OutputKeepPatterns, pattern)
+	}
+	sort.Strings(cmd.OutputKeepPatterns)
+	if options.Docker != nil {
+		return wrapCommandInDocker(options.Docker, cmd)
 	}
 	return cmd
 }
 
+// generateCommandE works like generateCommand, but first merges the environment variables returned by
+// options.EnvVarsFunc, if set, over options.EnvVars. It's called right before every attempt at running a command, so
+// short-lived values such as credentials are fresh for each one.
+func generateCommandE(options *Options, args ...string) (shell.Command, error) {
+	if options.EnvVarsFunc == nil {
+		return generateCommand(options, args...), nil
+	}
+
+	dynamicEnvVars, err := options.EnvVarsFunc()
+	if err != nil {
+		return shell.Command{}, err
+	}
+
+	envVars := make(map[string]string, len(options.EnvVars)+len(dynamicEnvVars))
+	for name, value := range options.EnvVars {
+		envVars[name] = value
+	}
+	for name, value := range dynamicEnvVars {
+		envVars[name] = value
+	}
+
+	optionsWithEnvVars := *options
+	optionsWithEnvVars.EnvVars = envVars
+	return generateCommand(&optionsWithEnvVars, args...), nil
+}
+
+// insertAfterCommand inserts the given flag right after the command (e.g. apply, or run-all apply) at the start of the
+// given args. Terraform stops parsing flags at the first positional argument, so a flag appended after the plan file of
+// an apply would be rejected.
+func insertAfterCommand(args []string, flag string) []string {
+	commandLength := 1
+	if args[0] == "run-all" && len(args) > 1 {
+		commandLength = 2
+	}
+
+	result := make([]string, 0, len(args)+1)
+	result = append(result, args[:commandLength]...)
+	result = append(result, flag)
+	return append(result, args[commandLength:]...)
+}
+
 var commandsWithParallelism = []string{
 	"plan",
 	"apply",
@@ -41,21 +124,26 @@ func GetCommonOptions(options *Options, args ...string) (*Options, []string) {
 	}
 
 	if options.Parallelism > 0 && len(args) > 0 && collections.ListContains(commandsWithParallelism, args[0]) {
-		args = append(args, fmt.Sprintf("--parallelism=%d", options.Parallelism))
+		args = insertAfterCommand(args, fmt.Sprintf("--parallelism=%d", options.Parallelism))
 	}
 
-	// if SshAgent is provided, override the local SSH agent with the socket of our in-process agent
+	// if SshAgent is provided, override the local SSH agent with the socket of our in-process agent. EnvVars is copied
+	// first, as the same map is often shared by the options of several tests running in parallel.
 	if options.SshAgent != nil {
-		// Initialize EnvVars, if it hasn't been set yet
-		if options.EnvVars == nil {
-			options.EnvVars = map[string]string{}
+		envVars := copyStringMap(options.EnvVars)
+		if envVars == nil {
+			envVars = map[string]string{}
 		}
-		options.EnvVars["SSH_AUTH_SOCK"] = options.SshAgent.SocketFile()
+		envVars["SSH_AUTH_SOCK"] = options.SshAgent.SocketFile()
+		options.EnvVars = envVars
 	}
 	return options, args
 }
 
-// RunTerraformCommand runs terraform with the given arguments and options and return stdout/stderr.
+// RunTerraformCommand runs terraform with the given arguments and options and return stdout/stderr. Use it to run the
+// commands this package has no function for, e.g. `terraform state mv`, with the same working dir, environment
+// variables, logging, and retries. The arguments are passed as is: use FormatArgs to add the vars, targets and lock
+// options to them.
 func RunTerraformCommand(t testing.TestingT, additionalOptions *Options, args ...string) string {
 	out, err := RunTerraformCommandE(t, additionalOptions, args...)
 	if err != nil {
@@ -66,25 +154,110 @@ func RunTerraformCommand(t testing.TestingT, additionalOptions *Options, args ..
 
 // RunTerraformCommandE runs terraform with the given arguments and options and return stdout/stderr.
 func RunTerraformCommandE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, error) {
+	out, _, err := RunTerraformCommandAndGetRetryReportE(t, additionalOptions, additionalArgs...)
+	return out, err
+}
+
+// RunTerraformCommandAndGetRetryReportE runs terraform with the given arguments and options and returns stdout/stderr
+// along with a report of every attempt, including the ones that were retried due to RetryableTerraformErrors. The
+// report is returned even if the command ultimately failed.
+func RunTerraformCommandAndGetRetryReportE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, *retry.Report, error) {
+	return runTerraformCommandE(t, additionalOptions, additionalArgs, func(options *Options, cmd shell.Command) (string, error) {
+		return options.getCommandRunner().RunCommandAndGetOutputE(options.getContext(), t, cmd)
+	})
+}
+
+// runTerraformCommandE runs terraform with the given arguments and options, retrying RetryableTerraformErrors, recording
+// metrics, and annotating errors, for all the functions that run Terraform commands. The given function runs a single
+// attempt of the command, and returns the output to check for retryable errors and to annotate errors with, which is
+// also returned, along with a report of every attempt.
+func runTerraformCommandE(t testing.TestingT, additionalOptions *Options, additionalArgs []string, run func(options *Options, cmd shell.Command) (string, error)) (string, *retry.Report, error) {
+	if err := additionalOptions.Validate(); err != nil {
+		return "", nil, err
+	}
+
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
+	maxRetries, err := options.getMaxRetriesE(t)
+	if err != nil {
+		return "", nil, err
+	}
 
-	cmd := generateCommand(options, args...)
 	description := fmt.Sprintf("%s %v", options.TerraformBinary, args)
-	return retry.DoWithRetryableErrorsE(t, description, options.RetryableTerraformErrors, options.MaxRetries, options.TimeBetweenRetries, func() (string, error) {
-		return shell.RunCommandAndGetOutputE(t, cmd)
+	out, report, err := retry.DoWithRetryableErrorsAndReportContextE(options.getContext(), t, description, options.RetryableTerraformErrors, maxRetries, options.TimeBetweenRetries, func() (string, error) {
+		cmd, err := generateCommandE(options, args...)
+		if err != nil {
+			return "", err
+		}
+		return run(options, cmd)
 	})
+	recordCommandMetrics(t, args, report, err)
+	if err != nil {
+		annotateErrors(t, options, args, out, err)
+	}
+	return out, report, err
+}
+
+// recordCommandMetrics records the total duration, number of retries, and failure of a Terraform command, if metrics
+// are enabled.
+func recordCommandMetrics(t testing.TestingT, args []string, report *retry.Report, err error) {
+	if !metrics.Enabled() || len(args) == 0 {
+		return
+	}
+
+	var duration time.Duration
+	for _, attempt := range report.Attempts {
+		duration += attempt.Duration
+	}
+	failed := 0.0
+	if err != nil {
+		failed = 1
+	}
+
+	tags := map[string]string{"command": args[0]}
+	metrics.Record(t, "terraform.command.duration", duration.Seconds(), metrics.UnitSeconds, tags)
+	metrics.Record(t, "terraform.command.retries", float64(report.Retries()), metrics.UnitCount, tags)
+	metrics.Record(t, "terraform.command.failed", failed, metrics.UnitCount, tags)
 }
 
 // RunTerraformCommandAndGetStdoutE runs terraform with the given arguments and options and returns solely its stdout
 // (but not stderr).
 func RunTerraformCommandAndGetStdoutE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, error) {
-	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
+	var stdout string
+	_, _, err := runTerraformCommandE(t, additionalOptions, additionalArgs, func(options *Options, cmd shell.Command) (string, error) {
+		// The stdout of these commands is parsed (e.g., the JSON of terraform output), so it must never be truncated
+		cmd.OutputMaxLines = 0
+		var err error
+		stdout, err = options.getCommandRunner().RunCommandAndGetStdOutE(options.getContext(), t, cmd)
+		if err != nil {
+			// The error of the shell package includes stderr, where Terraform writes its errors
+			return strings.Join([]string{stdout, err.Error()}, "\n"), err
+		}
+		return stdout, nil
+	})
+	return stdout, err
+}
 
-	cmd := generateCommand(options, args...)
-	description := fmt.Sprintf("%s %v", options.TerraformBinary, args)
-	return retry.DoWithRetryableErrorsE(t, description, options.RetryableTerraformErrors, options.MaxRetries, options.TimeBetweenRetries, func() (string, error) {
-		return shell.RunCommandAndGetStdOutE(t, cmd)
+// RunTerraformCommandAndGetStdOutErr runs terraform with the given arguments and options and returns its stdout and
+// stderr as separate strings. This will fail the test if there is an error.
+func RunTerraformCommandAndGetStdOutErr(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, string) {
+	stdout, stderr, err := RunTerraformCommandAndGetStdOutErrE(t, additionalOptions, additionalArgs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stdout, stderr
+}
+
+// RunTerraformCommandAndGetStdOutErrE runs terraform with the given arguments and options and returns its stdout and
+// stderr as separate strings. Terraform writes its errors to stderr, so both streams are checked against
+// RetryableTerraformErrors.
+func RunTerraformCommandAndGetStdOutErrE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, string, error) {
+	var stdout, stderr string
+	_, _, err := runTerraformCommandE(t, additionalOptions, additionalArgs, func(options *Options, cmd shell.Command) (string, error) {
+		var err error
+		stdout, stderr, err = options.getCommandRunner().RunCommandAndGetStdOutErrE(options.getContext(), t, cmd)
+		return strings.Join([]string{stdout, stderr}, "\n"), err
 	})
+	return stdout, stderr, err
 }
 
 // GetExitCodeForTerraformCommand runs terraform with the given arguments and options and returns exit code
@@ -98,11 +271,18 @@ func GetExitCodeForTerraformCommand(t testing.TestingT, additionalOptions *Optio
 
 // GetExitCodeForTerraformCommandE runs terraform with the given arguments and options and returns exit code
 func GetExitCodeForTerraformCommandE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (int, error) {
+	if err := additionalOptions.Validate(); err != nil {
+		return DefaultErrorExitCode, err
+	}
+
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
 
 	additionalOptions.Logger.Logf(t, "Running %s with args %v", options.TerraformBinary, args)
-	cmd := generateCommand(options, args...)
-	_, err := shell.RunCommandAndGetOutputE(t, cmd)
+	cmd, err := generateCommandE(options, args...)
+	if err != nil {
+		return DefaultErrorExitCode, err
+	}
+	_, err = options.getCommandRunner().RunCommandAndGetOutputE(options.getContext(), t, cmd)
 	if err == nil {
 		return DefaultSuccessExitCode, nil
 	}
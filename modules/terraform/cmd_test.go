@@ -0,0 +1,216 @@
+package terraform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/environment"
+	"github.com/gruntwork-io/terratest/modules/metrics"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/shell"
+	ttesting "github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMetricsSink struct {
+	sent []metrics.Metric
+}
+
+func (sink *fakeMetricsSink) Send(sent []metrics.Metric) error {
+	sink.sent = append(sink.sent, sent...)
+	return nil
+}
+
+func TestRecordCommandMetrics(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The metrics sink is process-wide.
+
+	sink := &fakeMetricsSink{}
+	metrics.SetSink(sink)
+	defer metrics.SetSink(nil)
+
+	report := &retry.Report{Attempts: []retry.Attempt{
+		{Duration: 2 * time.Second, Error: fmt.Errorf("exit status 1"), MatchedMessage: "Transient error"},
+		{Duration: 3 * time.Second},
+	}}
+	recordCommandMetrics(t, []string{"apply", "-input=false"}, report, nil)
+	recordResourceCountMetrics(t, "Apply complete! Resources: 2 added, 1 changed, 0 destroyed.")
+	require.NoError(t, metrics.FlushE())
+
+	values := map[string]float64{}
+	for _, metric := range sink.sent {
+		values[metric.Name] = metric.Value
+	}
+	assert.Equal(t, map[string]float64{
+		"terraform.command.duration":    5,
+		"terraform.command.retries":     1,
+		"terraform.command.failed":      0,
+		"terraform.resources.added":     2,
+		"terraform.resources.changed":   1,
+		"terraform.resources.destroyed": 0,
+	}, values)
+	assert.Equal(t, "apply", sink.sent[0].Tags["command"])
+}
+
+func TestRunTerraformCommandVariantsRecordMetrics(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The metrics sink is process-wide.
+
+	sink := &fakeMetricsSink{}
+	metrics.SetSink(sink)
+	defer metrics.SetSink(nil)
+
+	options := &Options{TerraformDir: ".", CommandRunner: newRecordingCommandRunner()}
+	_, err := StateListE(t, options)
+	require.NoError(t, err)
+	_, _, err = RunTerraformCommandAndGetStdOutErrE(t, options, "version")
+	require.NoError(t, err)
+	require.NoError(t, metrics.FlushE())
+
+	commands := []string{}
+	for _, metric := range sink.sent {
+		if metric.Name == "terraform.command.failed" {
+			commands = append(commands, metric.Tags["command"])
+		}
+	}
+	assert.Equal(t, []string{"state", "version"}, commands)
+}
+
+func TestGetCommonOptionsPassesParallelism(t *testing.T) {
+	t.Parallel()
+
+	_, args := GetCommonOptions(&Options{Parallelism: 5}, "apply", "-input=false", "-auto-approve", "plan.out")
+	assert.Equal(t, []string{"apply", "--parallelism=5", "-input=false", "-auto-approve", "plan.out"}, args)
+
+	_, args = GetCommonOptions(&Options{Parallelism: 5}, "destroy", "-auto-approve")
+	assert.Equal(t, []string{"destroy", "--parallelism=5", "-auto-approve"}, args)
+
+	_, args = GetCommonOptions(&Options{TerraformBinary: "terragrunt", Parallelism: 5}, "run-all", "apply")
+	assert.Equal(t, []string{"run-all", "apply", "--parallelism=5", "--terragrunt-non-interactive"}, args)
+
+	_, args = GetCommonOptions(&Options{Parallelism: 5}, "output", "-json")
+	assert.Equal(t, []string{"output", "-json"}, args)
+}
+
+func TestRunTerraformCommandWithCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	options := &Options{
+		TerraformDir: ".",
+		Context:      ctx,
+	}
+
+	_, err := RunTerraformCommandE(t, options, "version")
+	assert.Equal(t, context.Canceled, err)
+}
+
+// countingCommandRunner is a CommandRunner that fails every command with the given output and counts the runs.
+type countingCommandRunner struct {
+	output string
+	runs   int
+}
+
+func (runner *countingCommandRunner) RunCommandAndGetOutputE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	runner.runs++
+	return runner.output, errors.New(runner.output)
+}
+
+func (runner *countingCommandRunner) RunCommandAndGetStdOutE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	return runner.RunCommandAndGetOutputE(ctx, t, command)
+}
+
+func (runner *countingCommandRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, string, error) {
+	runner.runs++
+	return "", runner.output, errors.New("exit status 1")
+}
+
+func TestRunTerraformCommandMaxRetriesOverriddenByEnvironment(t *testing.T) {
+	// This test can not run in parallel, since it manipulates env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(environment.MaxRetriesEnvVar, "1")
+
+	runner := &countingCommandRunner{output: "Error: RequestLimitExceeded"}
+	options := &Options{
+		TerraformDir:             ".",
+		RetryableTerraformErrors: map[string]string{".*RequestLimitExceeded.*": "Rate limited"},
+		MaxRetries:               5,
+		CommandRunner:            runner,
+	}
+
+	_, err := RunTerraformCommandE(t, options, "apply")
+	require.Error(t, err)
+	assert.Equal(t, 2, runner.runs)
+}
+
+func TestRunTerraformCommandAndGetStdOutErrRetriesOnStderr(t *testing.T) {
+	t.Parallel()
+
+	runner := &countingCommandRunner{output: "Error: RequestLimitExceeded"}
+	options := &Options{
+		TerraformDir:             ".",
+		RetryableTerraformErrors: map[string]string{".*RequestLimitExceeded.*": "Rate limited"},
+		MaxRetries:               2,
+		CommandRunner:            runner,
+	}
+
+	stdout, stderr, err := RunTerraformCommandAndGetStdOutErrE(t, options, "apply")
+	require.Error(t, err)
+	assert.Equal(t, 3, runner.runs)
+	assert.Empty(t, stdout)
+	assert.Equal(t, "Error: RequestLimitExceeded", stderr)
+}
+
+// recordingCommandRunner is a CommandRunner that succeeds every command and records the last command run, by
+// subcommand.
+type recordingCommandRunner struct {
+	commands map[string]shell.Command
+}
+
+func newRecordingCommandRunner() *recordingCommandRunner {
+	return &recordingCommandRunner{commands: map[string]shell.Command{}}
+}
+
+func (runner *recordingCommandRunner) RunCommandAndGetOutputE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	runner.commands[command.Args[0]] = command
+	return "", nil
+}
+
+func (runner *recordingCommandRunner) RunCommandAndGetStdOutE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	return runner.RunCommandAndGetOutputE(ctx, t, command)
+}
+
+func (runner *recordingCommandRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, string, error) {
+	out, err := runner.RunCommandAndGetOutputE(ctx, t, command)
+	return out, "", err
+}
+
+func TestEnvVarsPassedToEveryCommand(t *testing.T) {
+	t.Parallel()
+
+	envVars := map[string]string{"TF_LOG": "DEBUG", "AWS_PROFILE": "terratest", "TF_VAR_name": "test"}
+	runner := newRecordingCommandRunner()
+	options := &Options{
+		TerraformDir:  ".",
+		EnvVars:       envVars,
+		CommandRunner: runner,
+	}
+
+	_, err := InitAndPlanE(t, options)
+	require.NoError(t, err)
+	_, err = ApplyE(t, options)
+	require.NoError(t, err)
+	_, err = DestroyE(t, options)
+	require.NoError(t, err)
+
+	for _, subcommand := range []string{"init", "plan", "apply", "destroy"} {
+		assert.Equal(t, envVars, runner.commands[subcommand].Env, subcommand)
+	}
+}
@@ -18,9 +18,9 @@ type ResourceCount struct {
 
 // Regular expressions for terraform commands stdout pattern matching.
 const (
-	applyRegexp             = `Apply complete! Resources: (\d+) added, (\d+) changed, (\d+) destroyed\.`
+	applyRegexp             = `Apply complete! Resources: (?:\d+ imported, )?(\d+) added, (\d+) changed, (\d+) destroyed\.`
 	destroyRegexp           = `Destroy complete! Resources: (\d+) destroyed\.`
-	planWithChangesRegexp   = `(\033\[1m)?Plan:(\033\[0m)? (\d+) to add, (\d+) to change, (\d+) to destroy\.`
+	planWithChangesRegexp   = `(\033\[1m)?Plan:(\033\[0m)? (?:\d+ to import, )?(\d+) to add, (\d+) to change, (\d+) to destroy\.`
 	planWithNoChangesRegexp = `No changes\. (Infrastructure is up-to-date)|(Your infrastructure matches the configuration)\.`
 )
 
@@ -85,3 +85,22 @@ func GetResourceCountE(t testing.TestingT, cmdout string) (*ResourceCount, error
 
 	return nil, errors.New(getResourceCountErrMessage)
 }
+
+// AssertResourceCount parses stdout/stderr of apply/plan/destroy commands and fails the test if the number of affected
+// resources is not exactly the expected number, e.g., to check a change touched only the resources it was supposed to.
+func AssertResourceCount(t testing.TestingT, cmdout string, expected ResourceCount) {
+	require.NoError(t, AssertResourceCountE(t, cmdout, expected))
+}
+
+// AssertResourceCountE parses stdout/stderr of apply/plan/destroy commands and returns a ResourceCountMismatch error if
+// the number of affected resources is not exactly the expected number.
+func AssertResourceCountE(t testing.TestingT, cmdout string, expected ResourceCount) error {
+	actual, err := GetResourceCountE(t, cmdout)
+	if err != nil {
+		return err
+	}
+	if *actual != expected {
+		return ResourceCountMismatch{Expected: expected, Actual: *actual}
+	}
+	return nil
+}
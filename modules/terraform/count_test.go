@@ -89,3 +89,38 @@ func runTestGetResourceCountE(t *testing.T, noColor bool) {
 		})
 
 }
+
+func TestGetResourceCountEFromSummaryLines(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		cmdout   string
+		expected ResourceCount
+	}{
+		{"Apply complete! Resources: 2 added, 1 changed, 0 destroyed.", ResourceCount{Add: 2, Change: 1}},
+		{"\033[0m\033[1m\033[32mApply complete! Resources: 3 added, 0 changed, 1 destroyed.\033[0m", ResourceCount{Add: 3, Destroy: 1}},
+		{"Apply complete! Resources: 1 imported, 0 added, 0 changed, 0 destroyed.", ResourceCount{}},
+		{"Plan: 1 to import, 2 to add, 0 to change, 0 to destroy.", ResourceCount{Add: 2}},
+		{"Destroy complete! Resources: 4 destroyed.", ResourceCount{Destroy: 4}},
+	}
+
+	for _, testCase := range testCases {
+		cnt, err := GetResourceCountE(t, testCase.cmdout)
+		require.NoError(t, err, testCase.cmdout)
+		assert.Equal(t, testCase.expected, *cnt, testCase.cmdout)
+	}
+}
+
+func TestAssertResourceCountE(t *testing.T) {
+	t.Parallel()
+
+	cmdout := "Apply complete! Resources: 1 added, 2 changed, 0 destroyed."
+	AssertResourceCount(t, cmdout, ResourceCount{Add: 1, Change: 2})
+
+	err := AssertResourceCountE(t, cmdout, ResourceCount{Add: 1})
+	require.Error(t, err)
+	assert.Equal(t, ResourceCountMismatch{Expected: ResourceCount{Add: 1}, Actual: ResourceCount{Add: 1, Change: 2}}, err)
+	assert.EqualError(t, err, "Expected 1 added, 0 changed, 0 destroyed, but got 1 added, 2 changed, 0 destroyed")
+
+	assert.EqualError(t, AssertResourceCountE(t, "Error: Invalid value", ResourceCount{}), getResourceCountErrMessage)
+}
@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"github.com/gruntwork-io/terratest/modules/environment"
+	"github.com/gruntwork-io/terratest/modules/notify"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -19,16 +21,132 @@ func TgDestroyAll(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// DestroyE runs terraform destroy with the given options and return stdout/stderr.
+// DestroyE runs terraform destroy with the given options and return stdout/stderr. If
+// options.AttemptTerraformDestroyRetry is set, errors matching options.RetryableDestroyErrors are retried too. If destroy
+// fails and options.LeakNotification is set, a notification about the leaked resources is sent. If the
+// TERRATEST_SKIP_DESTROY environment variable is set, destroy is skipped (see environment.Overrides). If
+// options.Workspace is set, the workspace is deleted once its resources are destroyed.
 func DestroyE(t testing.TestingT, options *Options) (string, error) {
-	return RunTerraformCommandE(t, options, FormatArgs(options, "destroy", "-auto-approve", "-input=false")...)
+	if skip, err := skipDestroyE(t, options); skip || err != nil {
+		return "", err
+	}
+
+	out, err := RunTerraformCommandE(t, options.destroyOptions(), FormatArgs(options, "destroy", "-auto-approve", "-input=false")...)
+	if err != nil {
+		notifyLeak(t, options, "destroy failed", err)
+		return out, err
+	}
+
+	// The default workspace can't be deleted
+	if options.Workspace != "" && options.Workspace != "default" {
+		if _, err := WorkspaceDeleteE(t, options, options.Workspace); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// LeakNotification configures the notifications sent when the resources created by a test might be leaked, because
+// destroy failed or was skipped. The Notifier can't be serialized, so it must be set again on Options loaded with
+// test_structure.LoadTerraformOptions.
+type LeakNotification struct {
+	Notifier notify.Notifier `json:"-"` // Where to send the notifications, e.g., notify.SlackNotifier or aws.SnsNotifier
+	UniqueID string          // The unique ID the resources are namespaced with, to include in notifications
+	Region   string          // The region the resources are created in, to include in notifications
+}
+
+// NotifyDestroySkipped sends a notification that the resources created with the given options might be leaked, because
+// destroy was skipped, if options.LeakNotification is set. See test_structure.RunTeardownStage for calling this
+// automatically when the teardown stage is skipped.
+func NotifyDestroySkipped(t testing.TestingT, options *Options) {
+	notifyLeak(t, options, "destroy skipped", nil)
+}
+
+// notifyLeak sends a notification about a leak, if options.LeakNotification is set. Errors are logged rather than
+// returned, so they don't hide the error that caused the leak.
+func notifyLeak(t testing.TestingT, options *Options, reason string, cause error) {
+	if options.LeakNotification == nil || options.LeakNotification.Notifier == nil {
+		return
+	}
+
+	leak := notify.Leak{
+		TestName:     t.Name(),
+		UniqueID:     options.LeakNotification.UniqueID,
+		Region:       options.LeakNotification.Region,
+		TemplatePath: options.TerraformDir,
+		Reason:       reason,
+	}
+	if cause != nil {
+		leak.Error = cause.Error()
+	}
+
+	if err := options.LeakNotification.Notifier.NotifyLeak(leak); err != nil {
+		options.Logger.Logf(t, "Failed to send a notification about the leak (%s): %v", reason, err)
+	}
 }
 
-// TgDestroyAllE runs terragrunt destroy with the given options and return stdout.
+// TgDestroyAllE runs terragrunt destroy with the given options and return stdout. If the TERRATEST_SKIP_DESTROY
+// environment variable is set, destroy is skipped (see environment.Overrides).
 func TgDestroyAllE(t testing.TestingT, options *Options) (string, error) {
 	if options.TerraformBinary != "terragrunt" {
 		return "", TgInvalidBinary(options.TerraformBinary)
 	}
+	if skip, err := skipDestroyE(t, options); skip || err != nil {
+		return "", err
+	}
+
+	return RunTerraformCommandE(t, options.destroyOptions(), FormatArgs(options, "run-all", "destroy", "-auto-approve", "-input=false")...)
+}
+
+// destroyOptions returns the options to run destroy with: if options.AttemptTerraformDestroyRetry is set, a copy that
+// retries the errors matching RetryableDestroyErrors (or DefaultRetryableDestroyErrors, if it's nil) as well as
+// RetryableTerraformErrors, up to MaxDestroyRetries times (or MaxRetries, or DefaultMaxRetries if both are zero),
+// waiting TimeBetweenRetries (or DefaultTimeBetweenRetries, if it's zero) in between. Otherwise, the options themselves.
+func (options *Options) destroyOptions() *Options {
+	if !options.AttemptTerraformDestroyRetry {
+		return options
+	}
+
+	retryableDestroyErrors := options.RetryableDestroyErrors
+	if retryableDestroyErrors == nil {
+		retryableDestroyErrors = DefaultRetryableDestroyErrors
+	}
+	retryableErrors := make(map[string]string, len(options.RetryableTerraformErrors)+len(retryableDestroyErrors))
+	for pattern, message := range options.RetryableTerraformErrors {
+		retryableErrors[pattern] = message
+	}
+	for pattern, message := range retryableDestroyErrors {
+		retryableErrors[pattern] = message
+	}
+
+	destroyOptions := *options
+	destroyOptions.RetryableTerraformErrors = retryableErrors
+	if options.MaxDestroyRetries > 0 {
+		destroyOptions.MaxRetries = options.MaxDestroyRetries
+	} else if options.MaxRetries == 0 {
+		destroyOptions.MaxRetries = DefaultMaxRetries
+	}
+	if options.TimeBetweenRetries == 0 {
+		destroyOptions.TimeBetweenRetries = DefaultTimeBetweenRetries
+	}
+	return &destroyOptions
+}
+
+// skipDestroyE returns true if destroy should be skipped because TERRATEST_SKIP_DESTROY is set, in which case a
+// notification about the resources left behind is sent, if options.LeakNotification is set. Only
+// TERRATEST_SKIP_DESTROY is read here, and an invalid value of it is logged and ignored, so that a typo in an
+// environment variable never leaks infrastructure.
+func skipDestroyE(t testing.TestingT, options *Options) (bool, error) {
+	skipDestroy, err := environment.GetSkipDestroyOverrideE()
+	if err != nil {
+		options.Logger.Logf(t, "Running destroy anyway, as %s is invalid: %s", environment.SkipDestroyEnvVar, err)
+		return false, nil
+	}
+	if !skipDestroy {
+		return false, nil
+	}
 
-	return RunTerraformCommandE(t, options, FormatArgs(options, "run-all", "destroy", "-auto-approve", "-input=false")...)
+	options.Logger.Logf(t, "Skipping destroy of %s because %s is set", options.TerraformDir, environment.SkipDestroyEnvVar)
+	NotifyDestroySkipped(t, options)
+	return true, nil
 }
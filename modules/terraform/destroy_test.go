@@ -0,0 +1,169 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/environment"
+	"github.com/gruntwork-io/terratest/modules/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifier struct {
+	leaks []notify.Leak
+}
+
+func (notifier *fakeNotifier) NotifyLeak(leak notify.Leak) error {
+	notifier.leaks = append(notifier.leaks, leak)
+	return nil
+}
+
+func TestDestroyNotifiesLeakOnFailure(t *testing.T) {
+	t.Parallel()
+
+	notifier := &fakeNotifier{}
+	options := &Options{
+		TerraformBinary:  "terraform-binary-that-does-not-exist",
+		TerraformDir:     t.TempDir(),
+		LeakNotification: &LeakNotification{Notifier: notifier, UniqueID: "a1b2c3", Region: "us-east-1"},
+	}
+
+	_, err := DestroyE(t, options)
+	require.Error(t, err)
+
+	require.Len(t, notifier.leaks, 1)
+	leak := notifier.leaks[0]
+	assert.Equal(t, t.Name(), leak.TestName)
+	assert.Equal(t, "a1b2c3", leak.UniqueID)
+	assert.Equal(t, "us-east-1", leak.Region)
+	assert.Equal(t, options.TerraformDir, leak.TemplatePath)
+	assert.Equal(t, "destroy failed", leak.Reason)
+	assert.Contains(t, leak.Error, "terraform-binary-that-does-not-exist")
+}
+
+func TestNotifyDestroySkipped(t *testing.T) {
+	t.Parallel()
+
+	notifier := &fakeNotifier{}
+	NotifyDestroySkipped(t, &Options{TerraformDir: "/tmp/vpc", LeakNotification: &LeakNotification{Notifier: notifier}})
+	require.Len(t, notifier.leaks, 1)
+	assert.Equal(t, "destroy skipped", notifier.leaks[0].Reason)
+	assert.Empty(t, notifier.leaks[0].Error)
+
+	// Without a LeakNotification, nothing happens
+	NotifyDestroySkipped(t, &Options{})
+}
+
+func TestLeakNotificationCanBeSerialized(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{LeakNotification: &LeakNotification{Notifier: notify.SlackNotifier{WebhookURL: "https://hooks.slack.com/services/x"}, UniqueID: "a1b2c3", Region: "us-east-1"}}
+
+	contents, err := json.Marshal(options)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "hooks.slack.com")
+
+	var loaded Options
+	require.NoError(t, json.Unmarshal(contents, &loaded))
+	assert.Equal(t, &LeakNotification{UniqueID: "a1b2c3", Region: "us-east-1"}, loaded.LeakNotification)
+}
+
+func TestDestroySkippedByEnvironment(t *testing.T) {
+	// This test can not run in parallel, since it manipulates env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(environment.SkipDestroyEnvVar, "true")
+
+	notifier := &fakeNotifier{}
+	options := &Options{
+		TerraformBinary:  "terraform-binary-that-does-not-exist",
+		TerraformDir:     t.TempDir(),
+		LeakNotification: &LeakNotification{Notifier: notifier, UniqueID: "a1b2c3"},
+	}
+
+	_, err := DestroyE(t, options)
+	require.NoError(t, err)
+	require.Len(t, notifier.leaks, 1)
+	assert.Equal(t, "destroy skipped", notifier.leaks[0].Reason)
+}
+
+func TestDestroyRunsWithInvalidOverrides(t *testing.T) {
+	// This test can not run in parallel, since it manipulates env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	t.Setenv(environment.SkipDestroyEnvVar, "maybe")
+	t.Setenv(environment.LogLevelEnvVar, "debug")
+
+	notifier := &fakeNotifier{}
+	options := &Options{
+		TerraformBinary:  "terraform-binary-that-does-not-exist",
+		TerraformDir:     t.TempDir(),
+		LeakNotification: &LeakNotification{Notifier: notifier, UniqueID: "a1b2c3"},
+	}
+
+	_, err := DestroyE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "terraform-binary-that-does-not-exist")
+	require.Len(t, notifier.leaks, 1)
+	assert.Equal(t, "destroy failed", notifier.leaks[0].Reason)
+}
+
+func TestDestroyRetriesRetryableDestroyErrors(t *testing.T) {
+	t.Parallel()
+
+	runner := &countingCommandRunner{output: "Error: DependencyViolation: resource sg-123 has a dependent object"}
+	options := &Options{
+		TerraformDir:       t.TempDir(),
+		TimeBetweenRetries: time.Millisecond,
+		CommandRunner:      runner,
+	}
+
+	// Without AttemptTerraformDestroyRetry, destroy errors aren't retried
+	_, err := DestroyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, 1, runner.runs)
+
+	runner.runs = 0
+	options.AttemptTerraformDestroyRetry = true
+	options.MaxDestroyRetries = 2
+	_, err = DestroyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, 3, runner.runs)
+
+	// RetryableDestroyErrors replaces the defaults, but RetryableTerraformErrors are still retried
+	runner.runs = 0
+	options.RetryableDestroyErrors = map[string]string{".*InvalidGroup.InUse.*": "Security group in use"}
+	_, err = DestroyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, 1, runner.runs)
+
+	runner.runs = 0
+	options.RetryableTerraformErrors = map[string]string{".*DependencyViolation.*": "Dependency violation"}
+	_, err = DestroyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, 3, runner.runs)
+	assert.Equal(t, 0, options.MaxRetries)
+}
+
+func TestDestroyRetriesWithOnlyAttemptTerraformDestroyRetry(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{TerraformDir: t.TempDir(), AttemptTerraformDestroyRetry: true}
+	destroyOptions := options.destroyOptions()
+	assert.Equal(t, DefaultMaxRetries, destroyOptions.MaxRetries)
+	assert.Equal(t, DefaultTimeBetweenRetries, destroyOptions.TimeBetweenRetries)
+
+	// A TimeBetweenRetries that is set is kept, so the retries are quick here
+	runner := &countingCommandRunner{output: "Error: DependencyViolation: resource sg-123 has a dependent object"}
+	options = &Options{
+		TerraformDir:                 t.TempDir(),
+		AttemptTerraformDestroyRetry: true,
+		TimeBetweenRetries:           time.Millisecond,
+		CommandRunner:                runner,
+	}
+	_, err := DestroyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, DefaultMaxRetries+1, runner.runs)
+}
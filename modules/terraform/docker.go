@@ -0,0 +1,126 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+)
+
+// DockerOptions configures running Terraform inside a Docker container, so tests use the Terraform (or Terragrunt)
+// and provider versions of a pinned image rather than whatever is installed on the host. The TerraformDir is mounted
+// at the same absolute path inside the container (on Windows, C:\path\to\module is mounted at /c/path/to/module), so
+// any files it references outside that folder (e.g., var files) must be mounted with Volumes.
+type DockerOptions struct {
+	Image        string   // The image to run, e.g. hashicorp/terraform:1.0.9. Its entrypoint is replaced with the TerraformBinary.
+	PassEnvVars  []string // Names of host environment variables to pass into the container. Defaults to DefaultDockerPassEnvVars.
+	Volumes      []string // Extra volumes to bind mount, in the host-path:container-path format of docker run --volume
+	User         string   // Username or UID to run as (e.g., "1000:1000", so files written to TerraformDir are owned by the host user)
+	OtherOptions []string // Custom CLI options that will be passed as-is to docker run
+}
+
+// DefaultDockerPassEnvVars are the host environment variables passed into the container by default: the credentials
+// and settings of the AWS, GCP, and Azure providers, and Terraform's own logging settings.
+var DefaultDockerPassEnvVars = []string{
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"AWS_PROFILE",
+	"AWS_REGION",
+	"AWS_DEFAULT_REGION",
+	"GOOGLE_CREDENTIALS",
+	"GOOGLE_PROJECT",
+	"GOOGLE_REGION",
+	"ARM_CLIENT_ID",
+	"ARM_CLIENT_SECRET",
+	"ARM_SUBSCRIPTION_ID",
+	"ARM_TENANT_ID",
+	"TF_LOG",
+	"TF_LOG_PATH",
+}
+
+// dockerAwsConfigDir is where the host's ~/.aws folder is mounted inside the container.
+const dockerAwsConfigDir = "/terratest/.aws"
+
+// wrapCommandInDocker returns a command that runs the given Terraform command in a container with the given options.
+// Environment variables are passed by name only, so their values (e.g., credentials) don't show up in the logs.
+func wrapCommandInDocker(dockerOptions *DockerOptions, cmd shell.Command) shell.Command {
+	workingDir, err := filepath.Abs(cmd.WorkingDir)
+	if err != nil {
+		workingDir = cmd.WorkingDir
+	}
+
+	env := map[string]string{}
+	for key, value := range cmd.Env {
+		env[key] = value
+	}
+
+	containerWorkingDir := toDockerContainerPath(workingDir)
+	args := []string{"run", "--rm", "--volume", workingDir + ":" + containerWorkingDir, "--workdir", containerWorkingDir}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		awsConfigDir := filepath.Join(home, ".aws")
+		if info, err := os.Stat(awsConfigDir); err == nil && info.IsDir() {
+			args = append(args, "--volume", awsConfigDir+":"+dockerAwsConfigDir+":ro")
+			env["AWS_SHARED_CREDENTIALS_FILE"] = dockerAwsConfigDir + "/credentials"
+			env["AWS_CONFIG_FILE"] = dockerAwsConfigDir + "/config"
+		}
+	}
+
+	for _, volume := range dockerOptions.Volumes {
+		args = append(args, "--volume", volume)
+	}
+
+	if dockerOptions.User != "" {
+		args = append(args, "--user", dockerOptions.User)
+	}
+
+	passEnvVars := dockerOptions.PassEnvVars
+	if passEnvVars == nil {
+		passEnvVars = DefaultDockerPassEnvVars
+	}
+	envVarNames := []string{}
+	for name := range env {
+		envVarNames = append(envVarNames, name)
+	}
+	for _, name := range passEnvVars {
+		if _, isSet := env[name]; !isSet {
+			if _, isSetOnHost := os.LookupEnv(name); isSetOnHost {
+				envVarNames = append(envVarNames, name)
+			}
+		}
+	}
+	sort.Strings(envVarNames)
+	for _, name := range envVarNames {
+		args = append(args, "--env", name)
+	}
+
+	args = append(args, dockerOptions.OtherOptions...)
+	args = append(args, "--entrypoint", cmd.Command, dockerOptions.Image)
+	args = append(args, cmd.Args...)
+
+	return shell.Command{
+		Command:            "docker",
+		Args:               args,
+		WorkingDir:         cmd.WorkingDir,
+		Env:                env,
+		Logger:             cmd.Logger,
+		OutputMaxLines:     cmd.OutputMaxLines,
+		OutputKeepPatterns: cmd.OutputKeepPatterns,
+		OutputFile:         cmd.OutputFile,
+	}
+}
+
+// toDockerContainerPath returns the path at which the given absolute host path is mounted inside a Linux container. On
+// Unix this is the host path itself. On Windows, the drive letter becomes the first folder, so C:\foo\bar becomes
+// /c/foo/bar.
+func toDockerContainerPath(hostPath string) string {
+	volume := filepath.VolumeName(hostPath)
+	containerPath := filepath.ToSlash(strings.TrimPrefix(hostPath, volume))
+	if volume == "" {
+		return containerPath
+	}
+	return "/" + strings.ToLower(strings.TrimSuffix(volume, ":")) + containerPath
+}
@@ -0,0 +1,137 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapCommandInDocker(t *testing.T) {
+	// These tests can not run in parallel, since they manipulate env vars
+	// DO NOT ADD THIS: t.Parallel()
+
+	home := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(home, ".aws"), 0700))
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	defer os.Setenv("HOME", originalHome)
+	originalUserProfile := os.Getenv("USERPROFILE")
+	os.Setenv("USERPROFILE", home)
+	defer os.Setenv("USERPROFILE", originalUserProfile)
+	os.Setenv("TERRATEST_DOCKER_TEST_PASS", "secret")
+	defer os.Unsetenv("TERRATEST_DOCKER_TEST_PASS")
+
+	workingDir := filepath.Join(home, "fixture")
+	containerWorkingDir := toDockerContainerPath(workingDir)
+	cmd := shell.Command{
+		Command:    "terraform",
+		Args:       []string{"apply", "-auto-approve"},
+		WorkingDir: workingDir,
+		Env:        map[string]string{"TF_VAR_name": "test"},
+	}
+	dockerOptions := &DockerOptions{
+		Image:        "hashicorp/terraform:1.0.9",
+		PassEnvVars:  []string{"TERRATEST_DOCKER_TEST_PASS", "TERRATEST_DOCKER_TEST_UNSET"},
+		User:         "1000:1000",
+		OtherOptions: []string{"--network", "host"},
+	}
+
+	wrapped := wrapCommandInDocker(dockerOptions, cmd)
+	assert.Equal(t, "docker", wrapped.Command)
+	assert.Equal(t, []string{
+		"run", "--rm",
+		"--volume", workingDir + ":" + containerWorkingDir,
+		"--workdir", containerWorkingDir,
+		"--volume", filepath.Join(home, ".aws") + ":/terratest/.aws:ro",
+		"--user", "1000:1000",
+		"--env", "AWS_CONFIG_FILE",
+		"--env", "AWS_SHARED_CREDENTIALS_FILE",
+		"--env", "TERRATEST_DOCKER_TEST_PASS",
+		"--env", "TF_VAR_name",
+		"--network", "host",
+		"--entrypoint", "terraform", "hashicorp/terraform:1.0.9",
+		"apply", "-auto-approve",
+	}, wrapped.Args)
+	assert.Equal(t, map[string]string{
+		"TF_VAR_name":                 "test",
+		"AWS_SHARED_CREDENTIALS_FILE": "/terratest/.aws/credentials",
+		"AWS_CONFIG_FILE":             "/terratest/.aws/config",
+	}, wrapped.Env)
+	assert.Equal(t, workingDir, wrapped.WorkingDir)
+}
+
+func TestToDockerContainerPath(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		assert.Equal(t, "/c/Users/test/module", toDockerContainerPath(`C:\Users\test\module`))
+		assert.Equal(t, "/d/module", toDockerContainerPath(`D:\module`))
+	} else {
+		assert.Equal(t, "/home/test/module", toDockerContainerPath("/home/test/module"))
+	}
+}
+
+func TestGenerateCommandWithoutDocker(t *testing.T) {
+	t.Parallel()
+
+	cmd := generateCommand(&Options{TerraformBinary: "terraform", TerraformDir: "/tmp/fixture"}, "plan")
+	assert.Equal(t, "terraform", cmd.Command)
+	assert.Equal(t, []string{"plan"}, cmd.Args)
+}
+
+func TestGenerateCommandWithOutputLimits(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformBinary:          "terraform",
+		TerraformDir:             "/tmp/fixture",
+		OutputMaxLines:           1000,
+		OutputFile:               "/tmp/fixture/plan.log",
+		RetryableTerraformErrors: map[string]string{".*timeout.*": "Timeout", ".*connection reset.*": "Reset"},
+	}
+
+	cmd := generateCommand(options, "plan")
+	assert.Equal(t, 1000, cmd.OutputMaxLines)
+	assert.Equal(t, "/tmp/fixture/plan.log", cmd.OutputFile)
+	assert.Equal(t, []string{".*connection reset.*", ".*timeout.*"}, cmd.OutputKeepPatterns)
+
+	options.Docker = &DockerOptions{Image: "hashicorp/terraform:1.0.9"}
+	wrapped := generateCommand(options, "plan")
+	assert.Equal(t, "docker", wrapped.Command)
+	assert.Equal(t, cmd.OutputMaxLines, wrapped.OutputMaxLines)
+	assert.Equal(t, cmd.OutputFile, wrapped.OutputFile)
+	assert.Equal(t, cmd.OutputKeepPatterns, wrapped.OutputKeepPatterns)
+}
+
+func TestGenerateCommandWithEnvVarsFunc(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	options := &Options{
+		TerraformBinary: "terraform",
+		EnvVars:         map[string]string{"TF_LOG": "DEBUG", "AWS_SESSION_TOKEN": "stale"},
+		EnvVarsFunc: func() (map[string]string, error) {
+			calls++
+			return map[string]string{"AWS_SESSION_TOKEN": fmt.Sprintf("fresh%d", calls)}, nil
+		},
+	}
+
+	cmd, err := generateCommandE(options, "destroy")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"TF_LOG": "DEBUG", "AWS_SESSION_TOKEN": "fresh1"}, cmd.Env)
+
+	cmd, err = generateCommandE(options, "destroy")
+	require.NoError(t, err)
+	assert.Equal(t, "fresh2", cmd.Env["AWS_SESSION_TOKEN"])
+	assert.Equal(t, "stale", options.EnvVars["AWS_SESSION_TOKEN"])
+
+	options.EnvVarsFunc = func() (map[string]string, error) { return nil, fmt.Errorf("no credentials") }
+	_, err = generateCommandE(options, "destroy")
+	assert.Error(t, err)
+}
@@ -1,8 +1,11 @@
 package terraform
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // TgInvalidBinary occurs when a terragrunt function is called and the TerraformBinary is
@@ -101,3 +104,130 @@ type WorkspaceDoesNotExist string
 func (err WorkspaceDoesNotExist) Error() string {
 	return fmt.Sprintf("The workspace %q does not exist.", string(err))
 }
+
+// OutputAssertionFailed is returned when an assertion on a terraform output fails. The error message includes all the
+// outputs of the Terraform code to make debugging easier, with the values of sensitive outputs redacted. If the
+// assertion failed because of another error (e.g., OutputKeyNotFound), it is available as Underlying.
+type OutputAssertionFailed struct {
+	Key        string
+	Message    string
+	Outputs    map[string]interface{}
+	Underlying error
+}
+
+func (err OutputAssertionFailed) Error() string {
+	// Don't escape HTML characters, so that placeholders such as <sensitive> are readable
+	allOutputs := &bytes.Buffer{}
+	encoder := json.NewEncoder(allOutputs)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(err.Outputs); encodeErr != nil {
+		return fmt.Sprintf("Assertion on output %q failed: %s\nAll outputs: %v", err.Key, err.Message, err.Outputs)
+	}
+	return fmt.Sprintf("Assertion on output %q failed: %s\nAll outputs:\n%s", err.Key, err.Message, strings.TrimSpace(allOutputs.String()))
+}
+
+// Unwrap returns the underlying error, so that it can be checked with errors.As.
+func (err OutputAssertionFailed) Unwrap() error {
+	return err.Underlying
+}
+
+// ResourceCountMismatch is returned when the number of resources affected by an apply/plan/destroy command is not the
+// expected number.
+type ResourceCountMismatch struct {
+	Expected ResourceCount
+	Actual   ResourceCount
+}
+
+func (err ResourceCountMismatch) Error() string {
+	return fmt.Sprintf(
+		"Expected %d added, %d changed, %d destroyed, but got %d added, %d changed, %d destroyed",
+		err.Expected.Add, err.Expected.Change, err.Expected.Destroy,
+		err.Actual.Add, err.Actual.Change, err.Actual.Destroy,
+	)
+}
+
+// InvalidOptions is returned when the Options for running Terraform contain mistakes, before any command runs.
+type InvalidOptions struct {
+	Problems []string
+}
+
+func (err InvalidOptions) Error() string {
+	return fmt.Sprintf("Invalid Terraform options:\n  - %s", strings.Join(err.Problems, "\n  - "))
+}
+
+// VarsConflict is returned when layers of variables that must not override each other set the same variable to
+// different values.
+type VarsConflict struct {
+	Conflicts []VarOverride
+}
+
+func (err VarsConflict) Error() string {
+	conflicts := []string{}
+	for _, conflict := range err.Conflicts {
+		conflicts = append(conflicts, conflict.String())
+	}
+	return fmt.Sprintf("Conflicting variables:\n  - %s", strings.Join(conflicts, "\n  - "))
+}
+
+// NoInstanceTypes is returned when falling back between instance types without any instance types to pick from.
+type NoInstanceTypes string
+
+func (varName NoInstanceTypes) Error() string {
+	return fmt.Sprintf("No instance types were given for variable %s", string(varName))
+}
+
+// ReservedModuleArgument is returned when an input or variable of a ModuleFixture has the name of a meta-argument of
+// module blocks, such as count or source, which can't be passed to the module under test.
+type ReservedModuleArgument string
+
+func (name ReservedModuleArgument) Error() string {
+	return fmt.Sprintf("%s is a meta-argument of module blocks and can't be used as an input of the module under test", string(name))
+}
+
+// UnsupportedTerraformVersion is returned when the version of the TerraformBinary doesn't satisfy the RequiredVersion
+// of the options.
+type UnsupportedTerraformVersion struct {
+	Binary          string
+	Version         string
+	RequiredVersion string
+}
+
+func (err UnsupportedTerraformVersion) Error() string {
+	return fmt.Sprintf("%s is version %s, but version %s is required", err.Binary, err.Version, err.RequiredVersion)
+}
+
+// ResourceNotFoundInState is returned when looking up a resource that isn't in the Terraform state by its address.
+type ResourceNotFoundInState string
+
+func (address ResourceNotFoundInState) Error() string {
+	return fmt.Sprintf("Resource %s was not found in the Terraform state", string(address))
+}
+
+// NotIdempotent is returned when a plan right after an apply still has changes. Changes lists the resources the plan
+// would change, which is empty if only outputs would change or the resources couldn't be determined.
+type NotIdempotent struct {
+	Changes PlannedChanges
+}
+
+func (err NotIdempotent) Error() string {
+	resources := []string{}
+	for _, group := range []struct {
+		action    string
+		addresses []string
+	}{
+		{"create", err.Changes.Create},
+		{"update", err.Changes.Update},
+		{"replace", err.Changes.Replace},
+		{"delete", err.Changes.Delete},
+	} {
+		for _, address := range group.addresses {
+			resources = append(resources, fmt.Sprintf("%s (%s)", address, group.action))
+		}
+	}
+
+	if len(resources) == 0 {
+		return "terraform configuration not idempotent"
+	}
+	return fmt.Sprintf("terraform configuration not idempotent, a second plan would change:\n  - %s", strings.Join(resources, "\n  - "))
+}
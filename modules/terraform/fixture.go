@@ -0,0 +1,304 @@
+package terraform
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// FixtureModuleName is the name of the module block that calls the module under test in a generated fixture.
+const FixtureModuleName = "under_test"
+
+// moduleMetaArguments are the arguments of module blocks that Terraform handles itself, rather than passing them to the
+// module, so they can't be used as names of inputs of the module under test.
+var moduleMetaArguments = []string{"count", "depends_on", "for_each", "providers", "source", "version"}
+
+// ModuleFixture describes a minimal root module that calls a single module, for testing simple modules without
+// maintaining a test fixture folder for each of them.
+type ModuleFixture struct {
+	Source    string                            // The source of the module under test. Local paths are relative to the current working directory.
+	Version   string                            // The version constraint of the module under test, for modules from a registry
+	Inputs    map[string]interface{}            // The input variables to pass to the module under test
+	Variables []string                          // The inputs of the module under test to pass through from variables of the fixture with the same names
+	Outputs   []string                          // The outputs of the module under test to pass through as outputs of the fixture
+	Providers map[string]map[string]interface{} // The configuration of each provider block to add, keyed by provider name (e.g., "aws": {"region": "us-east-1"})
+}
+
+// GenerateModuleFixture writes a root module for the given fixture into a new temp folder and returns the path of that
+// folder, for use as the TerraformDir of Options.
+func GenerateModuleFixture(t testing.TestingT, fixture ModuleFixture) string {
+	dir, err := GenerateModuleFixtureE(t, fixture)
+	require.NoError(t, err)
+	return dir
+}
+
+// GenerateModuleFixtureE writes a root module for the given fixture into a new temp folder and returns the path of that
+// folder, for use as the TerraformDir of Options. Returns a ReservedModuleArgument error if any of the inputs or
+// variables is named after a meta-argument of module blocks, such as count.
+func GenerateModuleFixtureE(t testing.TestingT, fixture ModuleFixture) (string, error) {
+	return generateModuleFixtureE(t, fixture, nil)
+}
+
+// generateModuleFixtureE writes a root module for the given fixture into a new temp folder, like
+// GenerateModuleFixtureE, declaring the variables in hclVariables with type any (see formatModuleFixture).
+func generateModuleFixtureE(t testing.TestingT, fixture ModuleFixture, hclVariables map[string]bool) (string, error) {
+	if err := checkModuleFixtureNames(fixture); err != nil {
+		return "", err
+	}
+
+	dir, err := ioutil.TempDir("", "terratest-fixture-")
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeModuleFixture(dir, fixture, hclVariables); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	logger.Logf(t, "Generated fixture for module %s in %s", fixture.Source, dir)
+	return dir, nil
+}
+
+// WithModuleFixture generates a root module for the given fixture and returns a copy of the given Options that runs
+// Terraform in it. Every variable in the Vars of the Options is passed through to the module under test, so the usual
+// Options-based flow (InitAndApply, Destroy, Output, ...) works on modules referenced by a git URL or a registry
+// address (with Version as the version constraint) just like on a local folder:
+//
+//	options := terraform.WithModuleFixture(t, terraform.ModuleFixture{
+//		Source:  "terraform-aws-modules/vpc/aws",
+//		Version: "~> 3.0",
+//		Outputs: []string{"vpc_id"},
+//	}, &terraform.Options{Vars: map[string]interface{}{"cidr": "10.0.0.0/16"}})
+//	defer os.RemoveAll(options.TerraformDir)
+//	defer terraform.Destroy(t, options)
+//	terraform.InitAndApply(t, options)
+func WithModuleFixture(t testing.TestingT, fixture ModuleFixture, originalOptions *Options) *Options {
+	options, err := WithModuleFixtureE(t, fixture, originalOptions)
+	require.NoError(t, err)
+	return options
+}
+
+// WithModuleFixtureE generates a root module for the given fixture and returns a copy of the given Options that runs
+// Terraform in it. Every variable in the Vars and VarFiles of the Options is passed through to the module under test,
+// and relative VarFiles are made absolute, so they're still found from the fixture. Variables with values other than
+// strings, such as lists and maps, are declared with type any, so that Terraform parses their -var arguments as HCL
+// rather than as strings.
+func WithModuleFixtureE(t testing.TestingT, fixture ModuleFixture, originalOptions *Options) (*Options, error) {
+	options, err := originalOptions.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	hclVariables := map[string]bool{}
+	for name, value := range options.Vars {
+		fixture.Variables = append(fixture.Variables, name)
+		if !isStringValue(value) {
+			hclVariables[name] = true
+		}
+	}
+
+	// Terraform runs in TerraformDir, so relative var files are relative to it, and it's about to move to the fixture
+	for i, varFile := range options.VarFiles {
+		if !filepath.IsAbs(varFile) {
+			varFile = filepath.Join(originalOptions.TerraformDir, varFile)
+		}
+		absVarFile, err := filepath.Abs(varFile)
+		if err != nil {
+			return nil, err
+		}
+		options.VarFiles[i] = absVarFile
+
+		// Variables set in var files are read as HCL whatever their type, so they're declared without one
+		varFileVars := map[string]interface{}{}
+		if err := GetAllVariablesFromVarFileE(t, absVarFile, &varFileVars); err != nil {
+			return nil, err
+		}
+		for name := range varFileVars {
+			fixture.Variables = append(fixture.Variables, name)
+		}
+	}
+
+	dir, err := generateModuleFixtureE(t, fixture, hclVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	options.TerraformDir = dir
+	return options, nil
+}
+
+// checkModuleFixtureNames returns a ReservedModuleArgument error if any of the inputs or variables of the given fixture
+// is named after a meta-argument of module blocks.
+func checkModuleFixtureNames(fixture ModuleFixture) error {
+	for _, name := range moduleMetaArguments {
+		if _, isInput := fixture.Inputs[name]; isInput || containsString(fixture.Variables, name) {
+			return ReservedModuleArgument(name)
+		}
+	}
+	return nil
+}
+
+func writeModuleFixture(dir string, fixture ModuleFixture, hclVariables map[string]bool) error {
+	source, err := fixtureModuleSource(dir, fixture.Source)
+	if err != nil {
+		return err
+	}
+
+	contents, err := formatModuleFixture(fixture, source, hclVariables)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, "main.tf"), contents, 0644)
+}
+
+// fixtureModuleSource returns the source to use for the module under test in a fixture generated in the given folder.
+// Local paths are made relative to that folder, since Terraform only treats paths starting with ./ or ../ as local.
+func fixtureModuleSource(fixtureDir string, source string) (string, error) {
+	if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") && !filepath.IsAbs(source) {
+		return source, nil
+	}
+
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		return "", err
+	}
+
+	relSource, err := filepath.Rel(fixtureDir, absSource)
+	if err != nil {
+		// E.g., the module and the temp folder are on different drives on Windows
+		return filepath.ToSlash(absSource), nil
+	}
+
+	relSource = filepath.ToSlash(relSource)
+	if !strings.HasPrefix(relSource, "../") {
+		relSource = "./" + relSource
+	}
+	return relSource, nil
+}
+
+// formatModuleFixture returns the HCL of a root module with the given provider blocks, a variable for each of the given
+// variables, a call to the module under test with the given source, and an output for each of the given outputs. The
+// variables in hclVariables are declared with type any, since Terraform reads -var arguments of untyped variables as
+// strings, while the rest are left untyped, since Terraform reads -var arguments of variables of type any as HCL.
+func formatModuleFixture(fixture ModuleFixture, source string, hclVariables map[string]bool) ([]byte, error) {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	providerNames := []string{}
+	for name := range fixture.Providers {
+		providerNames = append(providerNames, name)
+	}
+	sort.Strings(providerNames)
+
+	for _, name := range providerNames {
+		provider := body.AppendNewBlock("provider", []string{name})
+		if err := setAttributeValues(provider.Body(), fixture.Providers[name]); err != nil {
+			return nil, err
+		}
+		body.AppendNewline()
+	}
+
+	// Inputs take precedence over variables with the same name
+	variableNames := []string{}
+	for _, name := range fixture.Variables {
+		if _, isInput := fixture.Inputs[name]; !isInput && !containsString(variableNames, name) {
+			variableNames = append(variableNames, name)
+		}
+	}
+	sort.Strings(variableNames)
+
+	for _, name := range variableNames {
+		variable := body.AppendNewBlock("variable", []string{name})
+		if hclVariables[name] {
+			variable.Body().SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "any"}})
+		}
+		body.AppendNewline()
+	}
+
+	module := body.AppendNewBlock("module", []string{FixtureModuleName})
+	module.Body().SetAttributeValue("source", cty.StringVal(source))
+	if fixture.Version != "" {
+		module.Body().SetAttributeValue("version", cty.StringVal(fixture.Version))
+	}
+	if len(fixture.Inputs) > 0 || len(variableNames) > 0 {
+		module.Body().AppendNewline()
+	}
+	if err := setAttributeValues(module.Body(), fixture.Inputs); err != nil {
+		return nil, err
+	}
+	for _, name := range variableNames {
+		module.Body().SetAttributeTraversal(name, hcl.Traversal{
+			hcl.TraverseRoot{Name: "var"},
+			hcl.TraverseAttr{Name: name},
+		})
+	}
+
+	for _, name := range fixture.Outputs {
+		body.AppendNewline()
+		output := body.AppendNewBlock("output", []string{name})
+		output.Body().SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: FixtureModuleName},
+			hcl.TraverseAttr{Name: name},
+		})
+	}
+
+	return hclwrite.Format(file.Bytes()), nil
+}
+
+// setAttributeValues sets an attribute on the given body for each of the given values, in alphabetical order.
+func setAttributeValues(body *hclwrite.Body, values map[string]interface{}) error {
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := toCtyValue(values[name])
+		if err != nil {
+			return err
+		}
+		body.SetAttributeValue(name, value)
+	}
+	return nil
+}
+
+// toCtyValue converts the given Go value to a cty value by way of JSON, so any value that can be passed to Terraform
+// as a JSON variable can be written as HCL, with strings escaped as needed.
+func toCtyValue(value interface{}) (cty.Value, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	ctyType, err := ctyjson.ImpliedType(jsonBytes)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(jsonBytes, ctyType)
+}
+
+func containsString(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
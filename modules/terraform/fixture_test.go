@@ -0,0 +1,200 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModuleFixture(t *testing.T) {
+	t.Parallel()
+
+	dir := GenerateModuleFixture(t, ModuleFixture{
+		Source: "../../test/fixtures/terraform-output",
+		Inputs: map[string]interface{}{
+			"name":      `my "quoted" ${name}`,
+			"instances": 3,
+			"tags":      map[string]string{"Team": "infra"},
+			"zones":     []string{"us-east-1a", "us-east-1b"},
+		},
+		Outputs:   []string{"bool", "string"},
+		Providers: map[string]map[string]interface{}{"aws": {"region": "us-east-1"}},
+	})
+	defer os.RemoveAll(dir)
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+
+	_, diags := hclparse.NewParser().ParseHCL(contents, "main.tf")
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	expectedSource, err := fixtureModuleSource(dir, "../../test/fixtures/terraform-output")
+	require.NoError(t, err)
+
+	assert.Equal(t, `provider "aws" {
+  region = "us-east-1"
+}
+
+module "under_test" {
+  source = "`+expectedSource+`"
+
+  instances = 3
+  name      = "my \"quoted\" $${name}"
+  tags = {
+    Team = "infra"
+  }
+  zones = ["us-east-1a", "us-east-1b"]
+}
+
+output "bool" {
+  value = module.under_test.bool
+}
+
+output "string" {
+  value = module.under_test.string
+}
+`, string(contents))
+}
+
+func TestFixtureModuleSource(t *testing.T) {
+	t.Parallel()
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	fixtureDir := filepath.Join(cwd, "fixture")
+
+	testCases := []struct {
+		source   string
+		expected string
+	}{
+		{"./modules/vpc", "../modules/vpc"},
+		{"../modules/vpc", "../../modules/vpc"},
+		{"./fixture/nested", "./nested"},
+		{"terraform-aws-modules/vpc/aws", "terraform-aws-modules/vpc/aws"},
+		{"git::https://github.com/gruntwork-io/terratest.git//examples/terraform-hello-world-example?ref=v0.38.0", "git::https://github.com/gruntwork-io/terratest.git//examples/terraform-hello-world-example?ref=v0.38.0"},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := fixtureModuleSource(fixtureDir, testCase.source)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, actual, "Source: %s", testCase.source)
+	}
+}
+
+func TestGenerateModuleFixtureUnserializableInput(t *testing.T) {
+	t.Parallel()
+
+	_, err := GenerateModuleFixtureE(t, ModuleFixture{
+		Source: "terraform-aws-modules/vpc/aws",
+		Inputs: map[string]interface{}{"callback": func() {}},
+	})
+	assert.Error(t, err)
+}
+
+func TestWithModuleFixturePassesVarsThrough(t *testing.T) {
+	t.Parallel()
+
+	originalOptions := &Options{
+		TerraformDir: "../../test/fixtures/terraform-output",
+		Vars:         map[string]interface{}{"name": "test", "azs": []string{"us-east-1a", "us-east-1b"}, "cidr": "10.0.0.0/16"},
+	}
+
+	options := WithModuleFixture(t, ModuleFixture{
+		Source:  "terraform-aws-modules/vpc/aws",
+		Version: "~> 3.0",
+		Inputs:  map[string]interface{}{"cidr": "10.1.0.0/16"},
+		Outputs: []string{"vpc_id"},
+	}, originalOptions)
+	defer os.RemoveAll(options.TerraformDir)
+
+	assert.Equal(t, "../../test/fixtures/terraform-output", originalOptions.TerraformDir)
+	assert.Equal(t, originalOptions.Vars, options.Vars)
+
+	contents, err := ioutil.ReadFile(filepath.Join(options.TerraformDir, "main.tf"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `variable "azs" {
+  type = any
+}
+
+variable "name" {
+}
+
+module "under_test" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 3.0"
+
+  cidr = "10.1.0.0/16"
+  azs  = var.azs
+  name = var.name
+}
+
+output "vpc_id" {
+  value = module.under_test.vpc_id
+}
+`, string(contents))
+}
+
+type fixtureTestEnvironment string
+
+func TestWithModuleFixturePassesStringTypesAndVarFilesThrough(t *testing.T) {
+	t.Parallel()
+
+	terraformDir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(terraformDir, "test.tfvars"), []byte(`tags = {Team = "platform"}`), 0644))
+
+	name := "test"
+	originalOptions := &Options{
+		TerraformDir: terraformDir,
+		Vars:         map[string]interface{}{"env": fixtureTestEnvironment("dev"), "name": &name},
+		VarFiles:     []string{"test.tfvars"},
+	}
+
+	options := WithModuleFixture(t, ModuleFixture{Source: "terraform-aws-modules/vpc/aws"}, originalOptions)
+	defer os.RemoveAll(options.TerraformDir)
+
+	assert.Equal(t, []string{"test.tfvars"}, originalOptions.VarFiles)
+	assert.Equal(t, []string{filepath.Join(terraformDir, "test.tfvars")}, options.VarFiles)
+	require.NoError(t, options.Validate())
+
+	contents, err := ioutil.ReadFile(filepath.Join(options.TerraformDir, "main.tf"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `variable "env" {
+}
+
+variable "name" {
+}
+
+variable "tags" {
+}
+
+module "under_test" {
+  source = "terraform-aws-modules/vpc/aws"
+
+  env  = var.env
+  name = var.name
+  tags = var.tags
+}
+`, string(contents))
+}
+
+func TestGenerateModuleFixtureReservedNames(t *testing.T) {
+	t.Parallel()
+
+	_, err := GenerateModuleFixtureE(t, ModuleFixture{
+		Source: "terraform-aws-modules/vpc/aws",
+		Inputs: map[string]interface{}{"count": 3},
+	})
+	assert.Equal(t, ReservedModuleArgument("count"), err)
+
+	_, err = WithModuleFixtureE(t, ModuleFixture{Source: "terraform-aws-modules/vpc/aws"}, &Options{
+		Vars: map[string]interface{}{"for_each": []string{"a"}},
+	})
+	assert.Equal(t, ReservedModuleArgument("for_each"), err)
+}
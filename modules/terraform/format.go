@@ -1,8 +1,10 @@
 package terraform
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -36,6 +38,18 @@ var TerraformCommandsWithPlanFileSupport = []string{
 	"graph",
 }
 
+// TerraformCommandsWithTargetSupport is a list of all the Terraform commands that can be limited to a subset of the
+// resources with -target
+var TerraformCommandsWithTargetSupport = []string{
+	"plan",
+	"plan-all",
+	"apply",
+	"apply-all",
+	"destroy",
+	"destroy-all",
+	"refresh",
+}
+
 // FormatArgs converts the inputs to a format palatable to terraform. This includes converting the given vars to the
 // format the Terraform CLI expects (-var key=value).
 func FormatArgs(options *Options, args ...string) []string {
@@ -49,18 +63,23 @@ func FormatArgs(options *Options, args ...string) []string {
 	}
 	lockSupported := collections.ListContains(TerraformCommandsWithLockSupport, commandType)
 	planFileSupported := collections.ListContains(TerraformCommandsWithPlanFileSupport, commandType)
+	targetSupported := collections.ListContains(TerraformCommandsWithTargetSupport, commandType)
 
-	// Include -var and -var-file flags unless we're running 'apply' with a plan file
+	// Include -var, -var-file and -target flags unless we're running 'apply' with a plan file
 	includeVars := !(commandType == "apply" && len(options.PlanFilePath) > 0)
 
 	terraformArgs = append(terraformArgs, args...)
 
 	if includeVars {
-		terraformArgs = append(terraformArgs, FormatTerraformVarsAsArgs(options.Vars)...)
+		// Terraform gives precedence to the variables that come last on the command line, so pass the var files first,
+		// so that Vars override the values in them
 		terraformArgs = append(terraformArgs, FormatTerraformArgs("-var-file", options.VarFiles)...)
+		terraformArgs = append(terraformArgs, FormatTerraformVarsAsArgs(options.Vars)...)
 	}
 
-	terraformArgs = append(terraformArgs, FormatTerraformArgs("-target", options.Targets)...)
+	if targetSupported && includeVars {
+		terraformArgs = append(terraformArgs, FormatTerraformTargetsAsArgs(options.Targets)...)
+	}
 
 	if options.NoColor {
 		terraformArgs = append(terraformArgs, "-no-color")
@@ -98,6 +117,16 @@ func FormatTerraformVarsAsArgs(vars map[string]interface{}) []string {
 	return formatTerraformArgs(vars, "-var", true)
 }
 
+// FormatTerraformTargetsAsArgs formats the given resource addresses as command-line args for Terraform (e.g. of the
+// format -target=aws_instance.example).
+func FormatTerraformTargetsAsArgs(targets []string) []string {
+	var args []string
+	for _, target := range targets {
+		args = append(args, fmt.Sprintf("-target=%s", target))
+	}
+	return args
+}
+
 // FormatTerraformLockAsArgs formats the lock and lock-timeout variables
 // -lock, -lock-timeout
 func FormatTerraformLockAsArgs(lockCheck bool, lockTimeout string) []string {
@@ -130,9 +159,20 @@ func FormatTerraformArgs(argName string, args []string) []string {
 }
 
 // FormatTerraformBackendConfigAsArgs formats the given variables as backend config args for Terraform (e.g. of the
-// format -backend-config=key=value).
+// format -backend-config=key=value). A key with a nil value is taken to be the path of a backend config file, and is
+// formatted as -backend-config=path.
 func FormatTerraformBackendConfigAsArgs(vars map[string]interface{}) []string {
-	return formatTerraformArgs(vars, "-backend-config", false)
+	var args []string
+
+	for key, value := range vars {
+		if value == nil {
+			args = append(args, fmt.Sprintf("-backend-config=%s", key))
+		} else {
+			args = append(args, formatTerraformArgs(map[string]interface{}{key: value}, "-backend-config", false)...)
+		}
+	}
+
+	return args
 }
 
 // Format the given vars into 'Terraform' format, with each var being prefixed with the given prefix. If
@@ -154,26 +194,76 @@ func formatTerraformArgs(vars map[string]interface{}, prefix string, useSpaceAsS
 	return args
 }
 
+// ToHclString converts the given Go value to the HCL syntax the Terraform CLI (0.12 and above) expects for the value of
+// a -var argument, e.g. to build a `-var 'name=[...]'` argument by hand. Slices become lists, maps with string keys and
+// structs (using their JSON field names) become objects, pointers are replaced by the value they point to, nested
+// strings are quoted and escaped (including ${ and %{ sequences, so they are not interpreted as template expressions),
+// and a top-level string is returned as is, since that's how Terraform reads string variables.
+func ToHclString(value interface{}) string {
+	return toHclString(value, false)
+}
+
+// isStringValue returns true if the given value is a string, of a named string type (e.g. type Environment string), or a
+// non-nil pointer to one, which are the values toHclString returns as is at the top level, rather than as HCL.
+func isStringValue(value interface{}) bool {
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr && !reflectValue.IsNil() {
+		reflectValue = reflectValue.Elem()
+	}
+	return reflectValue.Kind() == reflect.String
+}
+
 // Terraform allows you to pass in command-line variables using HCL syntax (e.g. -var foo=[1,2,3]). Unfortunately,
 // while their golang hcl library can convert an HCL string to a Go type, they don't seem to offer a library to convert
 // arbitrary Go types to an HCL string. Therefore, this method is a simple implementation that correctly handles
-// ints, booleans, lists, and maps. Everything else is forced into a string using Sprintf. Hopefully, this approach is
-// good enough for the type of variables we deal with in Terratest.
+// ints, booleans, lists, maps, and structs. Everything else is forced into a string using Sprintf. Hopefully, this
+// approach is good enough for the type of variables we deal with in Terratest.
 func toHclString(value interface{}, isNested bool) string {
 	// Ideally, we'd use a type switch here to identify slices and maps, but we can't do that, because Go doesn't
 	// support generics, and the type switch only matches concrete types. So we could match []interface{}, but if
 	// a user passes in []string{}, that would NOT match (the same logic applies to maps). Therefore, we have to
 	// use reflection and manually convert into []interface{} and map[string]interface{}.
 
+	// Pointers (e.g. from aws.String) are formatted as the value they point to, or null if they're nil
+	if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return primitiveToHclString(nil, isNested)
+		}
+		return toHclString(reflectValue.Elem().Interface(), isNested)
+	}
+
 	if slice, isSlice := tryToConvertToGenericSlice(value); isSlice {
 		return sliceToHclString(slice)
 	} else if m, isMap := tryToConvertToGenericMap(value); isMap {
 		return mapToHclString(m)
+	} else if m, isStruct := tryToConvertStructToGenericMap(value); isStruct {
+		return mapToHclString(m)
 	} else {
 		return primitiveToHclString(value, isNested)
 	}
 }
 
+// Try to convert the given struct to a generic map with the JSON field names of the struct as keys. Return the map and
+// true if the underlying value was a struct that can be converted and an empty map and false otherwise.
+func tryToConvertStructToGenericMap(value interface{}) (map[string]interface{}, bool) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Struct {
+		return map[string]interface{}{}, false
+	}
+
+	jsonBytes, err := json.Marshal(reflectValue.Interface())
+	if err != nil {
+		return map[string]interface{}{}, false
+	}
+
+	genericMap := map[string]interface{}{}
+	if err := json.Unmarshal(jsonBytes, &genericMap); err != nil {
+		return map[string]interface{}{}, false
+	}
+
+	return genericMap, true
+}
+
 // Try to convert the given value to a generic slice. Return the slice and true if the underlying value itself was a
 // slice and an empty slice and false if it wasn't. This is necessary because Go is a shitty language that doesn't
 // have generics, nor useful utility methods built-in. For more info, see: http://stackoverflow.com/a/12754757/483528
@@ -232,8 +322,14 @@ func sliceToHclString(slice []interface{}) string {
 func mapToHclString(m map[string]interface{}) string {
 	keyValuePairs := []string{}
 
-	for key, value := range m {
-		keyValuePair := fmt.Sprintf(`"%s" = %s`, key, toHclString(value, true))
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyValuePair := fmt.Sprintf(`%s = %s`, quoteHclString(key), toHclString(m[key], true))
 		keyValuePairs = append(keyValuePairs, keyValuePair)
 	}
 
@@ -247,20 +343,40 @@ func primitiveToHclString(value interface{}, isNested bool) string {
 		return "null"
 	}
 
-	switch v := value.(type) {
+	// Switch on the kind rather than the type, so named types (e.g. type Environment string) are formatted like their
+	// underlying type
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
 
-	case bool:
-		return strconv.FormatBool(v)
+	case reflect.Bool:
+		return strconv.FormatBool(reflectValue.Bool())
 
-	case string:
+	case reflect.String:
 		// If string is nested in a larger data structure (e.g. list of string, map of string), ensure value is quoted
 		if isNested {
-			return fmt.Sprintf("\"%v\"", v)
+			return quoteHclString(reflectValue.String())
 		}
 
-		return fmt.Sprintf("%v", v)
+		return reflectValue.String()
 
 	default:
-		return fmt.Sprintf("%v", v)
+		return fmt.Sprintf("%v", value)
 	}
 }
+
+// hclStringEscaper escapes the characters that can't appear as is in a quoted HCL string, including the ${ and %{
+// sequences that would otherwise start a template expression.
+var hclStringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// Convert a string to a quoted HCL string, escaped as needed.
+func quoteHclString(value string) string {
+	return `"` + hclStringEscaper.Replace(value) + `"`
+}
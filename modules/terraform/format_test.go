@@ -75,6 +75,24 @@ func TestFormatTerraformVarsAsArgs(t *testing.T) {
 	}
 }
 
+func TestFormatTerraformBackendConfigAsArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		vars     map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{}, nil},
+		{map[string]interface{}{"bucket": "my-state"}, []string{"-backend-config=bucket=my-state"}},
+		{map[string]interface{}{"encrypt": true}, []string{"-backend-config=encrypt=true"}},
+		{map[string]interface{}{"backend.hcl": nil}, []string{"-backend-config=backend.hcl"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, FormatTerraformBackendConfigAsArgs(testCase.vars))
+	}
+}
+
 func TestPrimitiveToHclString(t *testing.T) {
 	t.Parallel()
 
@@ -198,6 +216,69 @@ func TestToHclString(t *testing.T) {
 	}
 }
 
+type hclTestEnvironment string
+
+func TestToHclStringFormatsPointersAndNamedTypes(t *testing.T) {
+	t.Parallel()
+
+	name := "foo"
+	count := 3
+	var nilName *string
+
+	testCases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{&name, "foo"},
+		{&count, "3"},
+		{nilName, "null"},
+		{[]*string{&name, nilName}, "[\"foo\", null]"},
+		{map[string]*int{"count": &count}, "{\"count\" = 3}"},
+		{hclTestEnvironment("stage"), "stage"},
+		{[]hclTestEnvironment{"stage", "prod"}, "[\"stage\", \"prod\"]"},
+	}
+
+	for _, testCase := range testCases {
+		actual := toHclString(testCase.value, false)
+		assert.Equal(t, testCase.expected, actual, "Value: %v", testCase.value)
+	}
+}
+
+func TestToHclStringEscapesAndEncodesStructs(t *testing.T) {
+	t.Parallel()
+
+	type subnet struct {
+		Name    string            `json:"name"`
+		Cidr    string            `json:"cidr_block"`
+		Public  bool              `json:"public"`
+		Tags    map[string]string `json:"tags,omitempty"`
+		ignored string
+	}
+
+	testCases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{`say "hi"`, `say "hi"`},
+		{[]string{`say "hi"`, `C:\temp`}, `["say \"hi\"", "C:\\temp"]`},
+		{[]string{"${var.foo}", "%{if true}"}, `["$${var.foo}", "%%{if true}"]`},
+		{[]string{"line1\nline2"}, `["line1\nline2"]`},
+		{map[string]string{`odd "key"`: "value"}, `{"odd \"key\"" = "value"}`},
+		{map[string]int{"b": 2, "a": 1}, `{"a" = 1, "b" = 2}`},
+		{subnet{Name: "public", Cidr: "10.0.0.0/24", Public: true}, `{"cidr_block" = "10.0.0.0/24", "name" = "public", "public" = true}`},
+		{&subnet{Name: "private", Tags: map[string]string{"Tier": "app"}}, `{"cidr_block" = "", "name" = "private", "public" = false, "tags" = {"Tier" = "app"}}`},
+		{[]subnet{{Name: "a"}}, `[{"cidr_block" = "", "name" = "a", "public" = false}]`},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, ToHclString(testCase.value), "Value: %v", testCase.value)
+	}
+
+	assert.Equal(t, []string{"-var", `subnets=[{"cidr_block" = "10.0.1.0/24", "name" = "a", "public" = false}]`}, FormatTerraformVarsAsArgs(map[string]interface{}{
+		"subnets": []subnet{{Name: "a", Cidr: "10.0.1.0/24"}},
+	}))
+}
+
 func TestTryToConvertToGenericSlice(t *testing.T) {
 	t.Parallel()
 
@@ -278,3 +359,40 @@ func TestFormatArgsAppliesLockCorrectly(t *testing.T) {
 		assert.Equal(t, testCase.expected, FormatArgs(&Options{}, testCase.command...))
 	}
 }
+
+func TestFormatArgsPassesVarFiles(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		Vars:     map[string]interface{}{"foo": "bar"},
+		VarFiles: []string{"common.tfvars", "test.tfvars"},
+	}
+
+	for _, command := range []string{"plan", "apply", "destroy"} {
+		args := FormatArgs(options, command)
+		assert.Equal(t, []string{command, "-var-file", "common.tfvars", "-var-file", "test.tfvars", "-var", "foo=bar"}, args[:7])
+	}
+
+	// A plan file already has the variables baked into it
+	options.PlanFilePath = "plan.out"
+	assert.Equal(t, []string{"apply", "-lock=false", "plan.out"}, FormatArgs(options, "apply"))
+}
+
+func TestFormatArgsPassesTargets(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{Targets: []string{"aws_instance.example", "module.vpc"}}
+
+	for _, command := range []string{"plan", "apply", "destroy"} {
+		args := FormatArgs(options, command)
+		assert.Equal(t, []string{command, "-target=aws_instance.example", "-target=module.vpc"}, args[:3])
+	}
+	assert.Equal(t, []string{"run-all", "apply", "-target=aws_instance.example", "-target=module.vpc", "-lock=false"}, FormatArgs(options, "run-all", "apply"))
+
+	// Commands that operate on the whole configuration reject -target
+	assert.Equal(t, []string{"validate"}, FormatArgs(options, "validate"))
+
+	// A plan file is already limited to the targets it was created with
+	options.PlanFilePath = "plan.out"
+	assert.Equal(t, []string{"apply", "-lock=false", "plan.out"}, FormatArgs(options, "apply"))
+}
@@ -0,0 +1,68 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	ttesting "github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exitCodeError is the error of a command that exited with the given code.
+type exitCodeError int
+
+func (code exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", int(code))
+}
+
+func (code exitCodeError) ExitCode() int {
+	return int(code)
+}
+
+// notIdempotentCommandRunner is a CommandRunner for a configuration that replaces a resource on every apply: plan
+// always has changes, and show returns a plan with the replacement. It records the arguments of every plan and show.
+type notIdempotentCommandRunner struct {
+	plans [][]string
+	shows [][]string
+}
+
+func (runner *notIdempotentCommandRunner) RunCommandAndGetOutputE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	if command.Args[0] == "plan" {
+		runner.plans = append(runner.plans, command.Args)
+		return "Plan: 1 to add, 0 to change, 1 to destroy.", exitCodeError(TerraformPlanChangesPresentExitCode)
+	}
+	return "Apply complete! Resources: 1 added, 0 changed, 1 destroyed.", nil
+}
+
+func (runner *notIdempotentCommandRunner) RunCommandAndGetStdOutE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	runner.shows = append(runner.shows, command.Args)
+	return `{"format_version": "0.2", "resource_changes": [{"address": "null_resource.test", "mode": "managed", "type": "null_resource", "name": "test", "change": {"actions": ["delete", "create"]}}]}`, nil
+}
+
+func (runner *notIdempotentCommandRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, string, error) {
+	out, err := runner.RunCommandAndGetOutputE(ctx, t, command)
+	return out, "", err
+}
+
+func TestApplyAndIdempotentListsChangedResources(t *testing.T) {
+	t.Parallel()
+
+	runner := &notIdempotentCommandRunner{}
+	options := &Options{TerraformDir: ".", CommandRunner: runner}
+
+	out, err := ApplyAndIdempotentE(t, options)
+	require.NotEmpty(t, out)
+	assert.Equal(t, NotIdempotent{Changes: PlannedChanges{Create: []string{}, Update: []string{}, Replace: []string{"null_resource.test"}, Delete: []string{}}}, err)
+	assert.EqualError(t, err, "terraform configuration not idempotent, a second plan would change:\n  - null_resource.test (replace)")
+
+	// The changes are listed from the plan that detected them
+	require.Len(t, runner.plans, 1)
+	require.Len(t, runner.shows, 1)
+	planFile := strings.TrimPrefix(runner.plans[0][len(runner.plans[0])-1], "-out=")
+	assert.Equal(t, planFile, runner.shows[0][len(runner.shows[0])-1])
+	assert.Empty(t, options.PlanFilePath)
+}
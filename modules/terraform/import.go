@@ -0,0 +1,23 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// Import runs terraform import with the given options to bring the existing infrastructure object with the given ID
+// under the management of the resource at the given address (e.g. aws_s3_bucket.logs), and returns stdout/stderr.
+// This will fail the test if there is an error in the command.
+func Import(t testing.TestingT, options *Options, address string, id string) string {
+	out, err := ImportE(t, options, address, id)
+	require.NoError(t, err)
+	return out
+}
+
+// ImportE runs terraform import with the given options to bring the existing infrastructure object with the given ID
+// under the management of the resource at the given address (e.g. aws_s3_bucket.logs), and returns stdout/stderr.
+func ImportE(t testing.TestingT, options *Options, address string, id string) (string, error) {
+	// Terraform stops parsing flags at the first positional argument, so the address and ID must come last
+	args := append(FormatArgs(options, "import", "-input=false"), address, id)
+	return RunTerraformCommandE(t, options, args...)
+}
@@ -0,0 +1,23 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPassesAddressAndIdLast(t *testing.T) {
+	t.Parallel()
+
+	runner := newRecordingCommandRunner()
+	options := &Options{
+		TerraformDir:  ".",
+		Vars:          map[string]interface{}{"name": "test"},
+		CommandRunner: runner,
+	}
+
+	_, err := ImportE(t, options, "aws_s3_bucket.logs", "my-logs-bucket")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"import", "-input=false", "-var", "name=test", "-lock=false", "aws_s3_bucket.logs", "my-logs-bucket"}, runner.commands["import"].Args)
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
-// Init calls terraform init and return stdout/stderr.
+// Init calls terraform init and return stdout/stderr. If options.RequiredVersion is set, it first checks the version of
+// the TerraformBinary against it. If options.Workspace is set, it then selects that workspace, creating it if it
+// doesn't exist.
 func Init(t testing.TestingT, options *Options) string {
 	out, err := InitE(t, options)
 	if err != nil {
@@ -15,8 +17,34 @@ func Init(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// InitE calls terraform init and return stdout/stderr.
+// InitE calls terraform init and return stdout/stderr. If options.RequiredVersion is set, it first checks the version
+// of the TerraformBinary against it. If options.Workspace is set, it then selects that workspace, creating it if it
+// doesn't exist.
 func InitE(t testing.TestingT, options *Options) (string, error) {
+	if err := CheckRequiredVersionE(t, options); err != nil {
+		return "", err
+	}
+
+	args := formatInitArgs(options)
+
+	// Parallel inits that download providers into the same plugin cache corrupt it, so take turns
+	unlock, err := lockPluginCacheE(t, options)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	out, err := RunTerraformCommandE(t, options, args...)
+	if err != nil || options.Workspace == "" {
+		return out, err
+	}
+
+	_, err = WorkspaceSelectOrNewE(t, options, options.Workspace)
+	return out, err
+}
+
+// formatInitArgs returns the args of the terraform init command for the given options.
+func formatInitArgs(options *Options) []string {
 	args := []string{"init", fmt.Sprintf("-upgrade=%t", options.Upgrade)}
 
 	// Append reconfigure option if specified
@@ -27,8 +55,10 @@ func InitE(t testing.TestingT, options *Options) (string, error) {
 	if options.MigrateState {
 		args = append(args, "-migrate-state", "-force-copy")
 	}
+	if options.NoGet {
+		args = append(args, "-get=false")
+	}
 
 	args = append(args, FormatTerraformBackendConfigAsArgs(options.BackendConfig)...)
-	args = append(args, FormatTerraformPluginDirAsArgs(options.PluginDir)...)
-	return RunTerraformCommandE(t, options, args...)
+	return append(args, FormatTerraformPluginDirAsArgs(options.PluginDir)...)
 }
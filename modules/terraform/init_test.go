@@ -132,3 +132,18 @@ func TestInitBackendMigration(t *testing.T) {
 	_, err = InitE(t, options)
 	assert.NoError(t, err, "Backend initialization with changed configuration should success with -migrate-state option")
 }
+
+func TestFormatInitArgs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"init", "-upgrade=false"}, formatInitArgs(&Options{}))
+
+	options := &Options{
+		Upgrade:       true,
+		Reconfigure:   true,
+		NoGet:         true,
+		BackendConfig: map[string]interface{}{"bucket": "my-bucket"},
+		PluginDir:     "/tmp/plugins",
+	}
+	assert.Equal(t, []string{"init", "-upgrade=true", "-reconfigure", "-get=false", "-backend-config=bucket=my-bucket", "-plugin-dir=/tmp/plugins"}, formatInitArgs(options))
+}
@@ -0,0 +1,77 @@
+package terraform
+
+import (
+	"regexp"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// InstanceTypeCapacityErrors are regexps matching the errors that AWS returns when an instance type can't be launched
+// right now or at all in the chosen Availability Zone, e.g., due to a hardware shortage. Retrying with the same
+// instance type typically doesn't help, but another instance type often works.
+var InstanceTypeCapacityErrors = []string{
+	"InsufficientInstanceCapacity",
+	"Unsupported: Your requested instance type",
+	"is not supported in your requested Availability Zone",
+	"InstanceTypeNotSupported",
+}
+
+// IsInstanceTypeCapacityError returns true if the given output or error of a Terraform command matches one of the
+// InstanceTypeCapacityErrors, in which case it's worth trying again with another instance type (see
+// InitAndApplyWithInstanceTypeFallbackE).
+func IsInstanceTypeCapacityError(output string) bool {
+	for _, pattern := range InstanceTypeCapacityErrors {
+		if regexp.MustCompile(pattern).MatchString(output) {
+			return true
+		}
+	}
+	return false
+}
+
+// InitAndApplyWithInstanceTypeFallback runs terraform init and apply with the given options, setting the variable
+// with the given name to the first of the given instance types. If apply fails because that instance type has no
+// capacity or isn't supported (see IsInstanceTypeCapacityError), apply runs again with the next instance type, and so
+// on, so that hardware shortages don't fail the test. The variable is set in options.Vars, so that a later destroy
+// uses the same instance type. Returns the instance type that worked, and fails the test if none of them did.
+func InitAndApplyWithInstanceTypeFallback(t testing.TestingT, options *Options, varName string, instanceTypes []string) string {
+	instanceType, err := InitAndApplyWithInstanceTypeFallbackE(t, options, varName, instanceTypes)
+	require.NoError(t, err)
+	return instanceType
+}
+
+// InitAndApplyWithInstanceTypeFallbackE runs terraform init and apply with the given options, falling back to the next
+// of the given instance types for the variable with the given name whenever apply fails due to an instance type
+// capacity error. Returns the instance type that worked, or the error of the last apply if none of them did.
+func InitAndApplyWithInstanceTypeFallbackE(t testing.TestingT, options *Options, varName string, instanceTypes []string) (string, error) {
+	if len(instanceTypes) == 0 {
+		return "", NoInstanceTypes(varName)
+	}
+
+	if _, err := InitE(t, options); err != nil {
+		return "", err
+	}
+
+	var err error
+	for i, instanceType := range instanceTypes {
+		// Replace options.Vars rather than writing to it, as the same map is often shared by the options of several
+		// tests running in parallel
+		WithVars(map[string]interface{}{varName: instanceType})(options)
+
+		var out string
+		out, err = ApplyE(t, options)
+		if err == nil {
+			return instanceType, nil
+		}
+
+		if !IsInstanceTypeCapacityError(out) && !IsInstanceTypeCapacityError(err.Error()) {
+			return "", err
+		}
+		if i < len(instanceTypes)-1 {
+			options.Logger.Logf(t, "Instance type %s is not available. Applying again with %s = %s.", instanceType, varName, instanceTypes[i+1])
+		}
+	}
+
+	return "", err
+}
@@ -0,0 +1,94 @@
+package terraform
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	ttesting "github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// unavailableInstanceTypesRunner is a CommandRunner that fails apply with the given output for the instance types in
+// unavailable, and records the arguments of every apply.
+type unavailableInstanceTypesRunner struct {
+	unavailable map[string]string
+	applies     []string
+}
+
+func (runner *unavailableInstanceTypesRunner) RunCommandAndGetOutputE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	if command.Args[0] != "apply" {
+		return "", nil
+	}
+
+	args := strings.Join(command.Args, " ")
+	runner.applies = append(runner.applies, args)
+	for instanceType, output := range runner.unavailable {
+		if strings.Contains(args, "instance_type="+instanceType) {
+			return output, errors.New("exit status 1")
+		}
+	}
+	return "Apply complete!", nil
+}
+
+func (runner *unavailableInstanceTypesRunner) RunCommandAndGetStdOutE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	return runner.RunCommandAndGetOutputE(ctx, t, command)
+}
+
+func (runner *unavailableInstanceTypesRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, string, error) {
+	output, err := runner.RunCommandAndGetOutputE(ctx, t, command)
+	return output, "", err
+}
+
+func TestInitAndApplyWithInstanceTypeFallback(t *testing.T) {
+	t.Parallel()
+
+	runner := &unavailableInstanceTypesRunner{unavailable: map[string]string{
+		"m5.large":  "Error: InsufficientInstanceCapacity: We currently do not have sufficient m5.large capacity",
+		"m5a.large": "Error: Unsupported: Your requested instance type (m5a.large) is not supported in your requested Availability Zone (us-east-1e)",
+	}}
+	sharedVars := map[string]interface{}{"name": "test"}
+	options := &Options{TerraformDir: ".", Vars: sharedVars, CommandRunner: runner}
+
+	instanceType := InitAndApplyWithInstanceTypeFallback(t, options, "instance_type", []string{"m5.large", "m5a.large", "m4.large", "t3.large"})
+
+	assert.Equal(t, "m4.large", instanceType)
+	assert.Equal(t, map[string]interface{}{"name": "test", "instance_type": "m4.large"}, options.Vars)
+	assert.Equal(t, map[string]interface{}{"name": "test"}, sharedVars)
+	assert.Len(t, runner.applies, 3)
+}
+
+func TestInitAndApplyWithInstanceTypeFallbackOtherError(t *testing.T) {
+	t.Parallel()
+
+	runner := &unavailableInstanceTypesRunner{unavailable: map[string]string{
+		"m5.large": "Error: UnauthorizedOperation",
+	}}
+	options := &Options{TerraformDir: ".", CommandRunner: runner}
+
+	_, err := InitAndApplyWithInstanceTypeFallbackE(t, options, "instance_type", []string{"m5.large", "m4.large"})
+
+	require.Error(t, err)
+	assert.Len(t, runner.applies, 1)
+}
+
+func TestInitAndApplyWithInstanceTypeFallbackNoneAvailable(t *testing.T) {
+	t.Parallel()
+
+	runner := &unavailableInstanceTypesRunner{unavailable: map[string]string{
+		"m5.large": "Error: InsufficientInstanceCapacity",
+		"m4.large": "Error: InsufficientInstanceCapacity",
+	}}
+	options := &Options{TerraformDir: ".", CommandRunner: runner}
+
+	_, err := InitAndApplyWithInstanceTypeFallbackE(t, options, "instance_type", []string{"m5.large", "m4.large"})
+	require.Error(t, err)
+	assert.Len(t, runner.applies, 2)
+
+	_, err = InitAndApplyWithInstanceTypeFallbackE(t, options, "instance_type", nil)
+	assert.Equal(t, NoInstanceTypes("instance_type"), err)
+}
@@ -0,0 +1,63 @@
+package terraform
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// interruptHandler catches SIGINT and SIGTERM while it's active, instead of letting them kill the test binary right
+// away, so that the resources a test deployed can be destroyed before it exits.
+type interruptHandler struct {
+	signals  chan os.Signal
+	done     chan struct{}
+	mutex    sync.Mutex
+	received os.Signal
+}
+
+// handleInterrupts starts catching SIGINT and SIGTERM. Call Stop once the resources are destroyed.
+func handleInterrupts(t testing.TestingT) *interruptHandler {
+	handler := &interruptHandler{signals: make(chan os.Signal, 1), done: make(chan struct{})}
+	signal.Notify(handler.signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-handler.signals:
+			logger.Logf(t, "Received %s. Destroying the resources of %s before exiting.", sig, t.Name())
+			handler.mutex.Lock()
+			handler.received = sig
+			handler.mutex.Unlock()
+		case <-handler.done:
+		}
+	}()
+
+	return handler
+}
+
+// Received returns the signal that was caught, or nil if there was none.
+func (handler *interruptHandler) Received() os.Signal {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	return handler.received
+}
+
+// Stop stops catching signals. If one was caught, it's sent again, so that the test binary exits as it would have
+// without the handler (once no other handler is catching it).
+func (handler *interruptHandler) Stop() {
+	signal.Stop(handler.signals)
+	close(handler.done)
+
+	sig := handler.Received()
+	if sig == nil {
+		return
+	}
+	if process, err := os.FindProcess(os.Getpid()); err == nil && process.Signal(sig) == nil {
+		return
+	}
+	// Signals can't be sent on Windows
+	os.Exit(1)
+}
@@ -0,0 +1,26 @@
+package terraform
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterruptHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := handleInterrupts(t)
+	assert.Nil(t, handler.Received())
+
+	// Deliver the signal straight to the handler, so the test binary isn't interrupted
+	handler.signals <- os.Interrupt
+	assert.Eventually(t, func() bool { return handler.Received() == os.Interrupt }, time.Second, time.Millisecond)
+
+	// Forget the signal, so that Stop doesn't send it again
+	handler.mutex.Lock()
+	handler.received = nil
+	handler.mutex.Unlock()
+	handler.Stop()
+}
@@ -0,0 +1,119 @@
+package terraform
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// Option sets one or more fields of Options. Options can be combined with NewOptions and CloneWith so large test suites
+// can define shared defaults once and tweak them per test without mutating a shared Options struct:
+//
+//	defaults := terraform.NewOptions("../examples/vpc", terraform.WithVars(map[string]interface{}{"region": region}), terraform.WithRetry(3, 5*time.Second, nil))
+//	options := terraform.CloneWith(t, defaults, terraform.WithVars(map[string]interface{}{"name": uniqueName}))
+//
+// Options that set maps or slices add to the existing values rather than replacing them, and never modify the maps or
+// slices of the Options they're applied to, so they're safe to apply to copies that share them with the original.
+type Option func(options *Options)
+
+// NewOptions returns Options for the Terraform code in the given folder with the given Options applied in order.
+func NewOptions(terraformDir string, opts ...Option) *Options {
+	options := &Options{TerraformDir: terraformDir}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// CloneWith makes a copy of the given Options with the given Options applied in order, leaving the original untouched.
+// This will fail the test if there are any errors in the cloning process.
+func CloneWith(t testing.TestingT, originalOptions *Options, opts ...Option) *Options {
+	newOptions, err := CloneWithE(t, originalOptions, opts...)
+	require.NoError(t, err)
+	return newOptions
+}
+
+// CloneWithE makes a copy of the given Options with the given Options applied in order, leaving the original untouched.
+func CloneWithE(t testing.TestingT, originalOptions *Options, opts ...Option) (*Options, error) {
+	newOptions, err := originalOptions.Clone()
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(newOptions)
+	}
+	return newOptions, nil
+}
+
+// WithVars adds the given vars to Vars, overriding any vars with the same names.
+func WithVars(vars map[string]interface{}) Option {
+	return func(options *Options) {
+		merged := make(map[string]interface{}, len(options.Vars)+len(vars))
+		for name, value := range options.Vars {
+			merged[name] = value
+		}
+		for name, value := range vars {
+			merged[name] = value
+		}
+		options.Vars = merged
+	}
+}
+
+// WithVarFiles adds the given var files to VarFiles.
+func WithVarFiles(varFiles ...string) Option {
+	return func(options *Options) {
+		options.VarFiles = appendStrings(options.VarFiles, varFiles)
+	}
+}
+
+// WithTargets adds the given resource addresses to Targets.
+func WithTargets(targets ...string) Option {
+	return func(options *Options) {
+		options.Targets = appendStrings(options.Targets, targets)
+	}
+}
+
+// WithEnvVars adds the given environment variables to EnvVars, overriding any with the same names.
+func WithEnvVars(envVars map[string]string) Option {
+	return func(options *Options) {
+		merged := make(map[string]string, len(options.EnvVars)+len(envVars))
+		for name, value := range options.EnvVars {
+			merged[name] = value
+		}
+		for name, value := range envVars {
+			merged[name] = value
+		}
+		options.EnvVars = merged
+	}
+}
+
+// WithRetry sets MaxRetries and TimeBetweenRetries, and adds the given retryable errors to RetryableTerraformErrors. If
+// retryableErrors is nil, DefaultRetryableTerraformErrors is added instead.
+func WithRetry(maxRetries int, timeBetweenRetries time.Duration, retryableErrors map[string]string) Option {
+	if retryableErrors == nil {
+		retryableErrors = DefaultRetryableTerraformErrors
+	}
+
+	return func(options *Options) {
+		merged := make(map[string]string, len(options.RetryableTerraformErrors)+len(retryableErrors))
+		for pattern, message := range options.RetryableTerraformErrors {
+			merged[pattern] = message
+		}
+		for pattern, message := range retryableErrors {
+			merged[pattern] = message
+		}
+		options.RetryableTerraformErrors = merged
+		options.MaxRetries = maxRetries
+		options.TimeBetweenRetries = timeBetweenRetries
+	}
+}
+
+// appendStrings returns a new slice with the given values appended to the given slice, so the given slice is never
+// modified through a shared backing array.
+func appendStrings(slice []string, values []string) []string {
+	result := make([]string, 0, len(slice)+len(values))
+	result = append(result, slice...)
+	return append(result, values...)
+}
@@ -0,0 +1,48 @@
+package terraform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptionsAndCloneWith(t *testing.T) {
+	t.Parallel()
+
+	defaults := NewOptions(
+		"../../test/fixtures/terraform-basic-configuration",
+		WithVars(map[string]interface{}{"region": "us-east-1", "cnt": 1}),
+		WithVarFiles("common.tfvars"),
+		WithEnvVars(map[string]string{"TF_LOG": "INFO"}),
+		WithRetry(3, 5*time.Second, map[string]string{"timeout": "Transient timeout"}),
+	)
+	assert.Equal(t, "../../test/fixtures/terraform-basic-configuration", defaults.TerraformDir)
+	assert.Equal(t, map[string]interface{}{"region": "us-east-1", "cnt": 1}, defaults.Vars)
+	assert.Equal(t, 3, defaults.MaxRetries)
+	assert.Equal(t, 5*time.Second, defaults.TimeBetweenRetries)
+	assert.Equal(t, map[string]string{"timeout": "Transient timeout"}, defaults.RetryableTerraformErrors)
+
+	options := CloneWith(t, defaults,
+		WithVars(map[string]interface{}{"cnt": 2}),
+		WithVarFiles("test.tfvars"),
+		WithTargets("null_resource.test"),
+		WithEnvVars(map[string]string{"TF_LOG": "DEBUG"}),
+		WithRetry(1, time.Second, nil),
+	)
+	assert.Equal(t, map[string]interface{}{"region": "us-east-1", "cnt": 2}, options.Vars)
+	assert.Equal(t, []string{"common.tfvars", "test.tfvars"}, options.VarFiles)
+	assert.Equal(t, []string{"null_resource.test"}, options.Targets)
+	assert.Equal(t, map[string]string{"TF_LOG": "DEBUG"}, options.EnvVars)
+	assert.Equal(t, 1, options.MaxRetries)
+	assert.Contains(t, options.RetryableTerraformErrors, "timeout")
+	assert.Contains(t, options.RetryableTerraformErrors, ".*Error installing provider.*")
+
+	// The shared defaults must be left untouched
+	assert.Equal(t, map[string]interface{}{"region": "us-east-1", "cnt": 1}, defaults.Vars)
+	assert.Equal(t, []string{"common.tfvars"}, defaults.VarFiles)
+	assert.Empty(t, defaults.Targets)
+	assert.Equal(t, map[string]string{"TF_LOG": "INFO"}, defaults.EnvVars)
+	assert.Equal(t, 3, defaults.MaxRetries)
+	assert.Equal(t, map[string]string{"timeout": "Transient timeout"}, defaults.RetryableTerraformErrors)
+}
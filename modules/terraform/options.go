@@ -1,12 +1,20 @@
 package terraform
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"time"
 
+	"github.com/gruntwork-io/terratest/modules/environment"
+	"github.com/gruntwork-io/terratest/modules/files"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/ssh"
 	"github.com/gruntwork-io/terratest/modules/testing"
-	"github.com/jinzhu/copier"
+	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/require"
 )
 
@@ -34,59 +42,315 @@ var (
 		// See https://github.com/terraform-providers/terraform-provider-aws/issues/12449 for an example.
 		".*Provider produced inconsistent result after apply.*": "Provider eventual consistency error.",
 	}
+
+	// DefaultRetryableDestroyErrors are the errors that destroy retries when Options.AttemptTerraformDestroyRetry is
+	// set and Options.RetryableDestroyErrors is nil. Destroy often fails transiently because the cloud provider hasn't
+	// caught up with the deletion of a resource that another one depends on, or because deleting many resources at once
+	// gets throttled.
+	DefaultRetryableDestroyErrors = map[string]string{
+		".*DependencyViolation.*":       "Resource still has dependencies that are being deleted.",
+		".*has a dependent object.*":    "Resource still has dependencies that are being deleted.",
+		".*is currently in use.*":       "Resource is still in use by a resource that is being deleted.",
+		".*ResourceInUseException.*":    "Resource is still in use by a resource that is being deleted.",
+		".*RequestLimitExceeded.*":      "Request was throttled.",
+		".*Throttling.*":                "Request was throttled.",
+		".*TooManyRequestsException.*":  "Request was throttled.",
+		".*timeout while waiting for.*": "Timed out waiting for a resource to be deleted.",
+	}
 )
 
 // Options for running Terraform commands
 type Options struct {
-	TerraformBinary string // Name of the binary that will be used
+	TerraformBinary string // Name or path of the binary that will be used, e.g. a pinned Terraform release or tofu for OpenTofu
 	TerraformDir    string // The path to the folder where the Terraform code is defined.
+	RequiredVersion string // If set, a version constraint (e.g. "= 1.5.7" or ">= 1.3, < 2.0") that Init checks the version of the TerraformBinary against
 
 	// The vars to pass to Terraform commands using the -var option. Note that terraform does not support passing `null`
-	// as a variable value through the command line. That is, if you use `map[string]interface{}{"foo": nil}` as `Vars`,
-	// this will translate to the string literal `"null"` being assigned to the variable `foo`. However, nulls in
+	// as a variable value through the command line, so `map[string]interface{}{"foo": nil}` as `Vars` is rejected by
+	// Validate (it would otherwise assign the string literal `"null"` to the variable `foo`). However, nulls in
 	// lists and maps/objects are supported. E.g., the following var will be set as expected (`{ bar = null }`:
 	// map[string]interface{}{
 	//     "foo": map[string]interface{}{"bar": nil},
 	// }
 	Vars map[string]interface{}
 
-	VarFiles                 []string               // The var file paths to pass to Terraform commands using -var-file option.
+	VarFiles                 []string               // The var file paths to pass to Terraform commands using -var-file option. Vars override the values in these files, and later files override earlier ones.
 	Targets                  []string               // The target resources to pass to the terraform command with -target
 	Lock                     bool                   // The lock option to pass to the terraform command with -lock
 	LockTimeout              string                 // The lock timeout option to pass to the terraform command with -lock-timeout
-	EnvVars                  map[string]string      // Environment variables to set when running Terraform
-	BackendConfig            map[string]interface{} // The vars to pass to the terraform init command for extra configuration for the backend
+	EnvVars                  map[string]string      // Environment variables to set when running Terraform (e.g. TF_LOG, AWS_PROFILE or TF_VAR_xxx), on top of those of the current process
+	BackendConfig            map[string]interface{} // The vars to pass to the terraform init command for extra configuration for the backend (e.g. the bucket and dynamodb_table of an S3 backend). A key with a nil value is passed as the path of a backend config file.
 	RetryableTerraformErrors map[string]string      // If Terraform apply fails with one of these (transient) errors, retry. The keys are a regexp to match against the error and the message is what to display to a user if that error is matched.
-	MaxRetries               int                    // Maximum number of times to retry errors matching RetryableTerraformErrors
+	MaxRetries               int                    // Maximum number of times to retry errors matching RetryableTerraformErrors. Overridden by the TERRATEST_MAX_RETRIES environment variable.
 	TimeBetweenRetries       time.Duration          // The amount of time to wait between retries
 	Upgrade                  bool                   // Whether the -upgrade flag of the terraform init command should be set to true or not
 	Reconfigure              bool                   // Set the -reconfigure flag to the terraform init command
 	MigrateState             bool                   // Set the -migrate-state and -force-copy (suppress 'yes' answer prompt) flag to the terraform init command
+	NoGet                    bool                   // Set the -get=false flag to the terraform init command, so that it uses the modules already in .terraform/modules instead of downloading them
 	NoColor                  bool                   // Whether the -no-color flag will be set for any Terraform command or not
 	SshAgent                 *ssh.SshAgent          // Overrides local SSH agent with the given in-process agent
 	NoStderr                 bool                   // Disable stderr redirection
 	OutputMaxLineSize        int                    // The max size of one line in stdout and stderr (in bytes)
-	Logger                   *logger.Logger         // Set a non-default logger that should be used. See the logger package for more info.
-	Parallelism              int                    // Set the parallelism setting for Terraform
+	OutputMaxLines           int                    // If greater than zero, only keep the first and last OutputMaxLines/2 lines of output (plus lines matching RetryableTerraformErrors) in memory. See shell.Command for details.
+	OutputFile               string                 // If set, append the full output of each command (and retry) to this file, after a header line naming the command
+	Logger                   *logger.Logger         // Set a non-default logger that should be used. Each line of Terraform output is logged as soon as it is written. See the logger package for more info.
+	Parallelism              int                    // Set the parallelism setting for Terraform plan, apply and destroy commands
 	PlanFilePath             string                 // The path to output a plan file to (for the plan command) or read one from (for the apply command)
 	PluginDir                string                 // The path of downloaded plugins to pass to the terraform init command (-plugin-dir)
+	Docker                   *DockerOptions         // If set, run Terraform inside a Docker container instead of on the host. See DockerOptions for more info.
+	Workspace                string                 // If set, Init selects the workspace with this name (creating it if needed) and Destroy deletes it afterwards, to isolate test runs that share a backend
+
+	// If set, destroy retries the errors matching RetryableDestroyErrors as well as RetryableTerraformErrors, up to
+	// MaxDestroyRetries times. Destroy fails transiently (e.g. with a DependencyViolation while the cloud provider is
+	// still deleting a resource that another one depends on) more often than other commands.
+	AttemptTerraformDestroyRetry bool
+	RetryableDestroyErrors       map[string]string // The errors destroy retries if AttemptTerraformDestroyRetry is set, with the same format as RetryableTerraformErrors. Defaults to DefaultRetryableDestroyErrors.
+	MaxDestroyRetries            int               // The maximum number of times destroy retries if AttemptTerraformDestroyRetry is set. Defaults to MaxRetries, or DefaultMaxRetries if that's zero too (in which case a zero TimeBetweenRetries defaults to DefaultTimeBetweenRetries as well). Overridden by the TERRATEST_MAX_RETRIES environment variable.
+
+	// If set, called right before every Terraform command, including retries, for environment variables to set on top
+	// of EnvVars. Use it for values that expire during long tests, such as the credentials returned by
+	// aws.NewRefreshingCredentialsEnvVars, so that e.g. a destroy at the end of a long test doesn't fail with
+	// ExpiredToken. Functions can't be serialized, so this isn't saved by test_structure.SaveTerraformOptions.
+	EnvVarsFunc func() (map[string]string, error) `json:"-"`
+
+	// If set, a notification is sent when destroy fails (or is skipped, see test_structure.RunTeardownStage), so
+	// that the resources the test leaked get cleaned up right away.
+	LeakNotification *LeakNotification
+
+	// If set, errors of failed Terraform commands are reported in this format, so they show up inline on pull requests
+	// in CI. Defaults to the value of the TERRATEST_CI_ANNOTATIONS environment variable. See AnnotationFormat.
+	AnnotationFormat AnnotationFormat
+
+	// If set, Terraform commands are run with this context: once it's done, the running command is killed and no more
+	// retries are made. Use it to enforce a global deadline, or to abort all the Terraform commands of a test when one
+	// of its validations fails fatally. Contexts can't be serialized, so this isn't saved by
+	// test_structure.SaveTerraformOptions.
+	Context context.Context `json:"-"`
+
+	// If set, Terraform commands are run with this CommandRunner instead of on the shell. Set it to a fake, such as
+	// fakes.TerraformRunner, to unit test code that calls the functions in this package without running Terraform or
+	// having any cloud credentials. This isn't saved by test_structure.SaveTerraformOptions.
+	CommandRunner CommandRunner `json:"-"`
 }
 
-// Clone makes a deep copy of most fields on the Options object and returns it.
+// getCommandRunner returns the CommandRunner set on the options, or one that runs commands on the shell if there is
+// none.
+func (options *Options) getCommandRunner() CommandRunner {
+	if options.CommandRunner == nil {
+		return shellCommandRunner{}
+	}
+	return options.CommandRunner
+}
+
+// getMaxRetriesE returns the MaxRetries of the options, unless the TERRATEST_MAX_RETRIES environment variable overrides
+// it (see environment.Overrides).
+func (options *Options) getMaxRetriesE(t testing.TestingT) (int, error) {
+	maxRetries, err := environment.GetMaxRetriesOverrideE()
+	if err != nil {
+		return 0, err
+	}
+	if maxRetries != nil {
+		return *maxRetries, nil
+	}
+	return options.MaxRetries, nil
+}
+
+// getContext returns the Context set on the options, or a context that is never done if there is none.
+func (options *Options) getContext() context.Context {
+	if options.Context == nil {
+		return context.Background()
+	}
+	return options.Context
+}
+
+// Clone makes a deep copy of most fields on the Options object and returns it, so that the copy can be changed (e.g., by
+// a parallel subtest) without affecting the original. Maps and slices are copied recursively, including the values of
+// Vars and BackendConfig.
 //
 // NOTE: options.SshAgent and options.Logger CANNOT be deep copied (e.g., the SshAgent struct contains channels and
 // listeners that can't be meaningfully copied), so the original values are retained.
 func (options *Options) Clone() (*Options, error) {
-	newOptions := &Options{}
-	if err := copier.Copy(newOptions, options); err != nil {
-		return nil, err
+	newOptions := *options
+
+	newOptions.Vars = deepCopyMap(options.Vars)
+	newOptions.BackendConfig = deepCopyMap(options.BackendConfig)
+	newOptions.VarFiles = copyStrings(options.VarFiles)
+	newOptions.Targets = copyStrings(options.Targets)
+	newOptions.EnvVars = copyStringMap(options.EnvVars)
+	newOptions.RetryableTerraformErrors = copyStringMap(options.RetryableTerraformErrors)
+	newOptions.RetryableDestroyErrors = copyStringMap(options.RetryableDestroyErrors)
+
+	if options.Docker != nil {
+		docker := *options.Docker
+		docker.PassEnvVars = copyStrings(options.Docker.PassEnvVars)
+		docker.Volumes = copyStrings(options.Docker.Volumes)
+		docker.OtherOptions = copyStrings(options.Docker.OtherOptions)
+		newOptions.Docker = &docker
+	}
+
+	return &newOptions, nil
+}
+
+// deepCopyMap returns a copy of the given map in which all nested maps and slices are copied too.
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(m)).Interface().(map[string]interface{})
+}
+
+// deepCopyValue returns a copy of the given value in which all maps and slices, at any depth, are copied. Pointers and
+// other values are copied as is.
+func deepCopyValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(deepCopyValue(value.Elem()))
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(value.Index(i)))
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+func copyStrings(slice []string) []string {
+	if slice == nil {
+		return nil
+	}
+	return append([]string{}, slice...)
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
 	}
-	return newOptions, nil
+	copied := make(map[string]string, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
 }
 
+// Validate checks the options for mistakes that would otherwise only show up as cryptic errors partway through running
+// Terraform, and returns an InvalidOptions error describing all of them. It's called before every Terraform command.
+func (options *Options) Validate() error {
+	problems := []string{}
+
+	// An empty TerraformDir means the current working directory
+	if options.TerraformDir != "" && !files.IsExistingDir(options.TerraformDir) {
+		problems = append(problems, fmt.Sprintf("TerraformDir %s does not exist or is not a folder", options.TerraformDir))
+	}
+
+	varNames := []string{}
+	for name := range options.Vars {
+		varNames = append(varNames, name)
+	}
+	sort.Strings(varNames)
+	for _, name := range varNames {
+		if isNilValue(options.Vars[name]) {
+			problems = append(problems, fmt.Sprintf("Vars[%q] is nil, but Terraform can't take null as a -var value and would set the variable to the string \"null\" instead. Leave the variable out to use its default.", name))
+		}
+	}
+
+	for _, varFile := range options.VarFiles {
+		// Terraform runs in TerraformDir, so relative paths are relative to it
+		path := varFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(options.TerraformDir, path)
+		}
+		if !files.IsExistingFile(path) {
+			problems = append(problems, fmt.Sprintf("Var file %s does not exist", path))
+		}
+	}
+
+	if options.MaxRetries < 0 {
+		problems = append(problems, fmt.Sprintf("MaxRetries is %d, but can't be negative", options.MaxRetries))
+	}
+	if options.TimeBetweenRetries < 0 {
+		problems = append(problems, fmt.Sprintf("TimeBetweenRetries is %s, but can't be negative", options.TimeBetweenRetries))
+	}
+	if options.MaxRetries > 0 && len(options.RetryableTerraformErrors) == 0 {
+		problems = append(problems, fmt.Sprintf("MaxRetries is %d, but RetryableTerraformErrors is empty, so no error would ever be retried. Use WithDefaultRetryableErrors to retry common transient errors.", options.MaxRetries))
+	}
+
+	if options.MaxDestroyRetries < 0 {
+		problems = append(problems, fmt.Sprintf("MaxDestroyRetries is %d, but can't be negative", options.MaxDestroyRetries))
+	}
+
+	problems = append(problems, validateRetryableErrors("RetryableTerraformErrors", options.RetryableTerraformErrors)...)
+	problems = append(problems, validateRetryableErrors("RetryableDestroyErrors", options.RetryableDestroyErrors)...)
+
+	if options.RequiredVersion != "" {
+		if _, err := version.NewConstraint(options.RequiredVersion); err != nil {
+			problems = append(problems, fmt.Sprintf("RequiredVersion %q is not a valid version constraint: %s", options.RequiredVersion, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return InvalidOptions{Problems: problems}
+	}
+	return nil
+}
+
+// isNilValue returns true if the given value is nil or a nil pointer, both of which toHclString formats as null.
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	reflectValue := reflect.ValueOf(value)
+	return reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil()
+}
+
+// validateRetryableErrors returns a problem for each key of the given retryable errors that isn't a valid regular
+// expression, in a stable order.
+func validateRetryableErrors(fieldName string, retryableErrors map[string]string) []string {
+	patterns := []string{}
+	for pattern := range retryableErrors {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	problems := []string{}
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("%s contains an invalid regular expression %q: %s", fieldName, pattern, err))
+		}
+	}
+	return problems
+}
+
+// The retry configuration WithDefaultRetryableErrors sets on Options that don't have one yet. These defaults are
+// arbitrary, but have worked well in practice across Gruntwork modules.
+const (
+	DefaultMaxRetries         = 3
+	DefaultTimeBetweenRetries = 5 * time.Second
+)
+
 // WithDefaultRetryableErrors makes a copy of the Options object and returns an updated object with sensible defaults
 // for retryable errors. The included retryable errors are typical errors that most terraform modules encounter during
-// testing, and are known to self resolve upon retrying.
+// testing, and are known to self resolve upon retrying. MaxRetries and TimeBetweenRetries are set to
+// DefaultMaxRetries and DefaultTimeBetweenRetries, unless they are already set, so they can be tuned either way.
 // This will fail the test if there are any errors in the cloning process.
 func WithDefaultRetryableErrors(t testing.TestingT, originalOptions *Options) *Options {
 	newOptions, err := originalOptions.Clone()
@@ -99,10 +363,12 @@ func WithDefaultRetryableErrors(t testing.TestingT, originalOptions *Options) *O
 		newOptions.RetryableTerraformErrors[k] = v
 	}
 
-	// These defaults for retry configuration are arbitrary, but have worked well in practice across Gruntwork
-	// modules.
-	newOptions.MaxRetries = 3
-	newOptions.TimeBetweenRetries = 5 * time.Second
+	if newOptions.MaxRetries == 0 {
+		newOptions.MaxRetries = DefaultMaxRetries
+	}
+	if newOptions.TimeBetweenRetries == 0 {
+		newOptions.TimeBetweenRetries = DefaultTimeBetweenRetries
+	}
 
 	return newOptions
 }
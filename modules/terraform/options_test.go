@@ -0,0 +1,152 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOptions(t *testing.T) {
+	t.Parallel()
+
+	validOptions := &Options{
+		TerraformDir: "../../test/fixtures/terraform-basic-configuration",
+		Vars:         map[string]interface{}{"cnt": 1, "tags": map[string]interface{}{"Name": nil}},
+	}
+	assert.NoError(t, validOptions.Validate())
+	assert.NoError(t, (&Options{}).Validate())
+	assert.NoError(t, WithDefaultRetryableErrors(t, validOptions).Validate())
+
+	invalidOptions := &Options{
+		TerraformDir:             "../../test/fixtures/does-not-exist",
+		Vars:                     map[string]interface{}{"cnt": 1, "name": nil},
+		VarFiles:                 []string{"missing.tfvars"},
+		MaxRetries:               3,
+		TimeBetweenRetries:       -time.Second,
+		RetryableTerraformErrors: map[string]string{},
+	}
+	err := invalidOptions.Validate()
+	require.Error(t, err)
+	require.IsType(t, InvalidOptions{}, err)
+	problems := err.(InvalidOptions).Problems
+	require.Len(t, problems, 5)
+	assert.Contains(t, problems[0], "TerraformDir ../../test/fixtures/does-not-exist does not exist")
+	assert.Contains(t, problems[1], `Vars["name"] is nil`)
+	assert.Contains(t, problems[2], "missing.tfvars does not exist")
+	assert.Contains(t, problems[3], "TimeBetweenRetries is -1s")
+	assert.Contains(t, problems[4], "RetryableTerraformErrors is empty")
+
+	var nilName *string
+	err = (&Options{Vars: map[string]interface{}{"name": nilName}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Vars["name"] is nil`)
+
+	err = (&Options{MaxRetries: 1, RetryableTerraformErrors: map[string]string{"(unclosed": "Invalid"}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid regular expression "(unclosed"`)
+
+	err = (&Options{AttemptTerraformDestroyRetry: true, MaxDestroyRetries: -1, RetryableDestroyErrors: map[string]string{"(unclosed": "Invalid"}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MaxDestroyRetries is -1")
+	assert.Contains(t, err.Error(), `RetryableDestroyErrors contains an invalid regular expression "(unclosed"`)
+
+	err = (&Options{RequiredVersion: "not a version"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RequiredVersion")
+}
+
+func TestWithDefaultRetryableErrorsKeepsRetrySettings(t *testing.T) {
+	t.Parallel()
+
+	defaults := WithDefaultRetryableErrors(t, &Options{})
+	assert.Equal(t, DefaultMaxRetries, defaults.MaxRetries)
+	assert.Equal(t, DefaultTimeBetweenRetries, defaults.TimeBetweenRetries)
+
+	tuned := WithDefaultRetryableErrors(t, &Options{MaxRetries: 10, TimeBetweenRetries: 30 * time.Second})
+	assert.Equal(t, 10, tuned.MaxRetries)
+	assert.Equal(t, 30*time.Second, tuned.TimeBetweenRetries)
+	assert.Contains(t, tuned.RetryableTerraformErrors, ".*Error installing provider.*")
+}
+
+func TestRunTerraformCommandEValidatesOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := RunTerraformCommandE(t, &Options{TerraformDir: "../../test/fixtures/does-not-exist"}, "plan")
+	require.Error(t, err)
+	assert.IsType(t, InvalidOptions{}, err)
+}
+
+func TestCloneIsDeepCopy(t *testing.T) {
+	t.Parallel()
+
+	original := &Options{
+		TerraformDir: "/tmp/module",
+		Vars: map[string]interface{}{
+			"tags":  map[string]interface{}{"Name": "original"},
+			"zones": []interface{}{"us-east-1a"},
+			"names": []string{"a"},
+		},
+		VarFiles:                 []string{"a.tfvars"},
+		Targets:                  []string{"null_resource.a"},
+		EnvVars:                  map[string]string{"TF_LOG": "INFO"},
+		BackendConfig:            map[string]interface{}{"bucket": "original"},
+		RetryableTerraformErrors: map[string]string{"timeout": "Timeout"},
+		Docker:                   &DockerOptions{Image: "hashicorp/terraform:1.0.9", Volumes: []string{"/a:/a"}},
+	}
+
+	clone, err := original.Clone()
+	require.NoError(t, err)
+	assert.Equal(t, original, clone)
+
+	clone.Vars["tags"].(map[string]interface{})["Name"] = "clone"
+	clone.Vars["zones"].([]interface{})[0] = "us-east-1b"
+	clone.Vars["names"].([]string)[0] = "b"
+	clone.Vars["new"] = "var"
+	clone.VarFiles[0] = "b.tfvars"
+	clone.Targets = append(clone.Targets[:0], "null_resource.b")
+	clone.EnvVars["TF_LOG"] = "DEBUG"
+	clone.BackendConfig["bucket"] = "clone"
+	clone.RetryableTerraformErrors["other"] = "Other"
+	clone.Docker.Image = "hashicorp/terraform:1.1.0"
+	clone.Docker.Volumes[0] = "/b:/b"
+
+	assert.Equal(t, map[string]interface{}{
+		"tags":  map[string]interface{}{"Name": "original"},
+		"zones": []interface{}{"us-east-1a"},
+		"names": []string{"a"},
+	}, original.Vars)
+	assert.Equal(t, []string{"a.tfvars"}, original.VarFiles)
+	assert.Equal(t, []string{"null_resource.a"}, original.Targets)
+	assert.Equal(t, map[string]string{"TF_LOG": "INFO"}, original.EnvVars)
+	assert.Equal(t, map[string]interface{}{"bucket": "original"}, original.BackendConfig)
+	assert.Equal(t, map[string]string{"timeout": "Timeout"}, original.RetryableTerraformErrors)
+	assert.Equal(t, &DockerOptions{Image: "hashicorp/terraform:1.0.9", Volumes: []string{"/a:/a"}}, original.Docker)
+}
+
+func TestCloneKeepsNilFields(t *testing.T) {
+	t.Parallel()
+
+	clone, err := (&Options{Vars: map[string]interface{}{"nothing": nil}}).Clone()
+	require.NoError(t, err)
+	assert.Equal(t, &Options{Vars: map[string]interface{}{"nothing": nil}}, clone)
+}
+
+func TestOptionsCanBeSerializedWithEnvVarsFunc(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir: "/tmp/vpc",
+		EnvVarsFunc:  func() (map[string]string, error) { return nil, nil },
+	}
+
+	contents, err := json.Marshal(options)
+	require.NoError(t, err)
+
+	var loaded Options
+	require.NoError(t, json.Unmarshal(contents, &loaded))
+	assert.Equal(t, "/tmp/vpc", loaded.TerraformDir)
+	assert.Nil(t, loaded.EnvVarsFunc)
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -26,7 +27,7 @@ func Output(t testing.TestingT, options *Options, key string) string {
 func OutputE(t testing.TestingT, options *Options, key string) (string, error) {
 	var val interface{}
 	err := OutputStructE(t, options, key, &val)
-	return fmt.Sprintf("%v", val), err
+	return formatOutputValue(val), err
 }
 
 // OutputRequired calls terraform output for the given variable and return its value. If the value is empty, fail the test.
@@ -50,6 +51,46 @@ func OutputRequiredE(t testing.TestingT, options *Options, key string) (string,
 	return out, nil
 }
 
+// GetSensitiveOutput calls terraform output for the given variable and returns its string value representation, like
+// Output, but without ever logging the value. The value is also masked (see logger.MaskValue) in everything logged
+// afterwards, such as the arguments of Terraform commands that it's passed to, so it's safe to use for outputs
+// marked sensitive, such as generated passwords.
+func GetSensitiveOutput(t testing.TestingT, options *Options, key string) string {
+	out, err := GetSensitiveOutputE(t, options, key)
+	require.NoError(t, err)
+	return out
+}
+
+// GetSensitiveOutputE calls terraform output for the given variable and returns its string value representation, like
+// OutputE, but without ever logging the value. The value is also masked (see logger.MaskValue) in everything logged
+// afterwards.
+func GetSensitiveOutputE(t testing.TestingT, options *Options, key string) (string, error) {
+	quietOptions, err := options.Clone()
+	if err != nil {
+		return "", err
+	}
+	quietOptions.Logger = logger.Discard
+	quietOptions.OutputFile = ""
+
+	var val interface{}
+	if err := OutputStructE(t, quietOptions, key, &val); err != nil {
+		return "", err
+	}
+
+	out := formatOutputValue(val)
+	logger.MaskValue(out)
+	return out, nil
+}
+
+// formatOutputValue returns the string representation of a value decoded from the JSON of terraform output. Numbers
+// are formatted the way Terraform shows them (e.g., 1000000 rather than 1e+06).
+func formatOutputValue(value interface{}) string {
+	if number, isNumber := value.(float64); isNumber {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
 // parseListOfMaps takes a list of maps and parses the types.
 // It is mainly a wrapper for parseMap to support lists.
 func parseListOfMaps(l []interface{}) ([]map[string]interface{}, error) {
@@ -219,7 +260,7 @@ func parseListOutputTerraform(outputList []interface{}, key string) ([]string, e
 	list := []string{}
 
 	for _, item := range outputList {
-		list = append(list, fmt.Sprintf("%v", item))
+		list = append(list, formatOutputValue(item))
 	}
 
 	return list, nil
@@ -248,7 +289,7 @@ func OutputMapE(t testing.TestingT, options *Options, key string) (map[string]st
 
 	resultMap := make(map[string]string)
 	for k, v := range outputMap {
-		resultMap[k] = fmt.Sprintf("%v", v)
+		resultMap[k] = formatOutputValue(v)
 	}
 	return resultMap, nil
 }
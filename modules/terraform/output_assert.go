@@ -0,0 +1,285 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// sensitiveOutputPlaceholder replaces the value of sensitive outputs in assertion failure messages.
+const sensitiveOutputPlaceholder = "<sensitive>"
+
+// outputsForAssertion holds the values of all the terraform outputs along with which of them are marked sensitive.
+type outputsForAssertion struct {
+	values    map[string]interface{}
+	sensitive map[string]bool
+}
+
+// AssertOutputEquals checks that the given output is equal to the expected value, failing the test if it is not.
+func AssertOutputEquals(t testing.TestingT, options *Options, key string, expected interface{}) {
+	err := AssertOutputEqualsE(t, options, key, expected)
+	require.NoError(t, err)
+}
+
+// AssertOutputEqualsE checks that the given output is equal to the expected value, returning an error if it is not.
+// The expected value is compared against the JSON representation of the output, so e.g. 3 and 3.0 are equal.
+func AssertOutputEqualsE(t testing.TestingT, options *Options, key string, expected interface{}) error {
+	outputs, err := getOutputsForAssertionE(t, options)
+	if err != nil {
+		return err
+	}
+	return checkOutputEquals(outputs, key, expected)
+}
+
+// AssertOutputContains checks that the given string output contains expected as a substring, or that the given list
+// output contains expected as an item, failing the test if it does not.
+func AssertOutputContains(t testing.TestingT, options *Options, key string, expected interface{}) {
+	err := AssertOutputContainsE(t, options, key, expected)
+	require.NoError(t, err)
+}
+
+// AssertOutputContainsE checks that the given string output contains expected as a substring, or that the given list
+// output contains expected as an item, returning an error if it does not.
+func AssertOutputContainsE(t testing.TestingT, options *Options, key string, expected interface{}) error {
+	outputs, err := getOutputsForAssertionE(t, options)
+	if err != nil {
+		return err
+	}
+	return checkOutputContains(outputs, key, expected)
+}
+
+// AssertOutputMapHasKeys checks that the given output is a map containing all the expected keys, failing the test if
+// it is not.
+func AssertOutputMapHasKeys(t testing.TestingT, options *Options, key string, expectedKeys []string) {
+	err := AssertOutputMapHasKeysE(t, options, key, expectedKeys)
+	require.NoError(t, err)
+}
+
+// AssertOutputMapHasKeysE checks that the given output is a map containing all the expected keys, returning an error
+// if it is not.
+func AssertOutputMapHasKeysE(t testing.TestingT, options *Options, key string, expectedKeys []string) error {
+	outputs, err := getOutputsForAssertionE(t, options)
+	if err != nil {
+		return err
+	}
+	return checkOutputMapHasKeys(outputs, key, expectedKeys)
+}
+
+// AssertOutputListLength checks that the given output is a list of the expected length, failing the test if it is not.
+func AssertOutputListLength(t testing.TestingT, options *Options, key string, expectedLength int) {
+	err := AssertOutputListLengthE(t, options, key, expectedLength)
+	require.NoError(t, err)
+}
+
+// AssertOutputListLengthE checks that the given output is a list of the expected length, returning an error if it is
+// not.
+func AssertOutputListLengthE(t testing.TestingT, options *Options, key string, expectedLength int) error {
+	outputs, err := getOutputsForAssertionE(t, options)
+	if err != nil {
+		return err
+	}
+	return checkOutputListLength(outputs, key, expectedLength)
+}
+
+// getOutputsForAssertionE calls terraform output and returns the values of all the outputs, along with which of them
+// are marked sensitive so that they can be redacted from failure messages. The outputs are read without logging them,
+// like GetSensitiveOutputE does, as they include the values of the sensitive ones.
+func getOutputsForAssertionE(t testing.TestingT, options *Options) (*outputsForAssertion, error) {
+	quietOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	quietOptions.Logger = logger.Discard
+	quietOptions.OutputFile = ""
+
+	out, err := OutputJsonE(t, quietOptions, "")
+	if err != nil {
+		return nil, err
+	}
+	return parseOutputsForAssertion(out)
+}
+
+func parseOutputsForAssertion(outputJson string) (*outputsForAssertion, error) {
+	rawOutputs := map[string]struct {
+		Sensitive bool        `json:"sensitive"`
+		Value     interface{} `json:"value"`
+	}{}
+	if err := json.Unmarshal([]byte(outputJson), &rawOutputs); err != nil {
+		return nil, err
+	}
+
+	outputs := &outputsForAssertion{values: map[string]interface{}{}, sensitive: map[string]bool{}}
+	for key, rawOutput := range rawOutputs {
+		outputs.values[key] = rawOutput.Value
+		if rawOutput.Sensitive {
+			outputs.sensitive[key] = true
+		}
+	}
+	return outputs, nil
+}
+
+func checkOutputEquals(outputs *outputsForAssertion, key string, expected interface{}) error {
+	actual, hasKey := outputs.values[key]
+	if !hasKey {
+		return outputs.newAssertionError(key, OutputKeyNotFound(key))
+	}
+
+	normalizedExpected, err := normalizeOutputValue(expected)
+	if err != nil {
+		return outputs.newAssertionFailure(key, fmt.Sprintf("cannot compare expected value: %s", err))
+	}
+
+	if !reflect.DeepEqual(normalizedExpected, actual) {
+		message := fmt.Sprintf("expected %s but got %s", outputs.format(key, normalizedExpected), outputs.format(key, actual))
+		return outputs.newAssertionFailure(key, message)
+	}
+	return nil
+}
+
+func checkOutputContains(outputs *outputsForAssertion, key string, expected interface{}) error {
+	actual, hasKey := outputs.values[key]
+	if !hasKey {
+		return outputs.newAssertionError(key, OutputKeyNotFound(key))
+	}
+
+	normalizedExpected, err := normalizeOutputValue(expected)
+	if err != nil {
+		return outputs.newAssertionFailure(key, fmt.Sprintf("cannot compare expected value: %s", err))
+	}
+
+	switch actualValue := actual.(type) {
+	case string:
+		expectedStr, isString := normalizedExpected.(string)
+		if !isString {
+			message := fmt.Sprintf("output is a string, so the expected value must also be a string, but got %s", outputs.format(key, normalizedExpected))
+			return outputs.newAssertionFailure(key, message)
+		}
+		if !strings.Contains(actualValue, expectedStr) {
+			message := fmt.Sprintf("expected %s to contain %s", outputs.format(key, actualValue), outputs.format(key, expectedStr))
+			return outputs.newAssertionFailure(key, message)
+		}
+		return nil
+	case []interface{}:
+		for _, item := range actualValue {
+			if reflect.DeepEqual(normalizedExpected, item) {
+				return nil
+			}
+		}
+		message := fmt.Sprintf("expected %s to contain %s", outputs.format(key, actualValue), outputs.format(key, normalizedExpected))
+		return outputs.newAssertionFailure(key, message)
+	default:
+		return outputs.newAssertionError(key, UnexpectedOutputType{Key: key, ExpectedType: "string or list", ActualType: outputTypeName(actual)})
+	}
+}
+
+func checkOutputMapHasKeys(outputs *outputsForAssertion, key string, expectedKeys []string) error {
+	actual, hasKey := outputs.values[key]
+	if !hasKey {
+		return outputs.newAssertionError(key, OutputKeyNotFound(key))
+	}
+
+	actualMap, isMap := actual.(map[string]interface{})
+	if !isMap {
+		return outputs.newAssertionError(key, OutputValueNotMap{Value: outputs.redact(key, actual)})
+	}
+
+	missingKeys := []string{}
+	for _, expectedKey := range expectedKeys {
+		if _, hasExpectedKey := actualMap[expectedKey]; !hasExpectedKey {
+			missingKeys = append(missingKeys, expectedKey)
+		}
+	}
+
+	if len(missingKeys) > 0 {
+		sort.Strings(missingKeys)
+		return outputs.newAssertionFailure(key, fmt.Sprintf("map is missing keys %v", missingKeys))
+	}
+	return nil
+}
+
+func checkOutputListLength(outputs *outputsForAssertion, key string, expectedLength int) error {
+	actual, hasKey := outputs.values[key]
+	if !hasKey {
+		return outputs.newAssertionError(key, OutputKeyNotFound(key))
+	}
+
+	actualList, isList := actual.([]interface{})
+	if !isList {
+		return outputs.newAssertionError(key, OutputValueNotList{Value: outputs.redact(key, actual)})
+	}
+
+	if len(actualList) != expectedLength {
+		message := fmt.Sprintf("expected a list of length %d but got length %d", expectedLength, len(actualList))
+		return outputs.newAssertionFailure(key, message)
+	}
+	return nil
+}
+
+// newAssertionFailure returns an OutputAssertionFailed error with the given message and all the outputs, with
+// sensitive values redacted.
+func (outputs *outputsForAssertion) newAssertionFailure(key string, message string) OutputAssertionFailed {
+	return OutputAssertionFailed{Key: key, Message: message, Outputs: outputs.redacted()}
+}
+
+// newAssertionError returns an OutputAssertionFailed error caused by the given underlying error.
+func (outputs *outputsForAssertion) newAssertionError(key string, underlying error) OutputAssertionFailed {
+	return OutputAssertionFailed{Key: key, Message: underlying.Error(), Outputs: outputs.redacted(), Underlying: underlying}
+}
+
+func (outputs *outputsForAssertion) redacted() map[string]interface{} {
+	redactedValues := map[string]interface{}{}
+	for key, value := range outputs.values {
+		redactedValues[key] = outputs.redact(key, value)
+	}
+	return redactedValues
+}
+
+func (outputs *outputsForAssertion) redact(key string, value interface{}) interface{} {
+	if outputs.sensitive[key] {
+		return sensitiveOutputPlaceholder
+	}
+	return value
+}
+
+// format returns the JSON representation of a value related to the given output, for use in failure messages. Values
+// related to sensitive outputs are redacted.
+func (outputs *outputsForAssertion) format(key string, value interface{}) string {
+	if outputs.sensitive[key] {
+		return sensitiveOutputPlaceholder
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(jsonBytes)
+}
+
+// normalizeOutputValue round trips the given value through JSON so that it has the same Go types (e.g., float64 for
+// numbers, []interface{} for lists) as the values parsed from `terraform output -json`.
+func normalizeOutputValue(value interface{}) (interface{}, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(jsonBytes, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// outputTypeName returns the name of the type of the given output value, which is nil for outputs that are null.
+func outputTypeName(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return reflect.TypeOf(value).String()
+}
@@ -0,0 +1,254 @@
+package terraform
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const outputAssertTestJson = `{
+	"string": {"sensitive": false, "type": "string", "value": "This is a string."},
+	"number": {"sensitive": false, "type": "number", "value": 3},
+	"bool": {"sensitive": false, "type": "bool", "value": true},
+	"list": {"sensitive": false, "type": ["list", "string"], "value": ["a", "b", "c"]},
+	"numbers": {"sensitive": false, "type": ["list", "number"], "value": [1, 2.5, 3]},
+	"objects": {"sensitive": false, "type": ["list", ["object", {"name": "string"}]], "value": [{"name": "foo"}, {"name": "bar"}]},
+	"map": {"sensitive": false, "type": ["map", "string"], "value": {"foo": "bar", "baz": "qux"}},
+	"null": {"sensitive": false, "type": "string", "value": null},
+	"password": {"sensitive": true, "type": "string", "value": "correct-horse-battery-staple"}
+}`
+
+func getOutputAssertTestOutputs(t *testing.T) *outputsForAssertion {
+	outputs, err := parseOutputsForAssertion(outputAssertTestJson)
+	require.NoError(t, err)
+	return outputs
+}
+
+func TestCheckOutputEquals(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	assert.NoError(t, checkOutputEquals(outputs, "string", "This is a string."))
+	assert.NoError(t, checkOutputEquals(outputs, "number", 3))
+	assert.NoError(t, checkOutputEquals(outputs, "number", 3.0))
+	assert.NoError(t, checkOutputEquals(outputs, "bool", true))
+	assert.NoError(t, checkOutputEquals(outputs, "list", []string{"a", "b", "c"}))
+	assert.NoError(t, checkOutputEquals(outputs, "map", map[string]string{"foo": "bar", "baz": "qux"}))
+	assert.NoError(t, checkOutputEquals(outputs, "null", nil))
+
+	assert.Error(t, checkOutputEquals(outputs, "string", "Another string."))
+	assert.Error(t, checkOutputEquals(outputs, "number", "3"))
+	assert.Error(t, checkOutputEquals(outputs, "list", []string{"a", "b"}))
+}
+
+func TestCheckOutputContains(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	assert.NoError(t, checkOutputContains(outputs, "string", "a string"))
+	assert.NoError(t, checkOutputContains(outputs, "list", "b"))
+	assert.NoError(t, checkOutputContains(outputs, "numbers", 3))
+	assert.NoError(t, checkOutputContains(outputs, "numbers", 3.0))
+	assert.NoError(t, checkOutputContains(outputs, "numbers", 2.5))
+	assert.NoError(t, checkOutputContains(outputs, "objects", map[string]string{"name": "bar"}))
+	assert.NoError(t, checkOutputContains(outputs, "objects", struct {
+		Name string `json:"name"`
+	}{Name: "foo"}))
+
+	assert.Error(t, checkOutputContains(outputs, "string", "not there"))
+	assert.Error(t, checkOutputContains(outputs, "string", 3))
+	assert.Error(t, checkOutputContains(outputs, "list", "d"))
+	assert.Error(t, checkOutputContains(outputs, "numbers", "3"))
+	assert.Error(t, checkOutputContains(outputs, "objects", map[string]string{"name": "baz"}))
+
+	err := checkOutputContains(outputs, "map", "foo")
+	var unexpectedTypeErr UnexpectedOutputType
+	assert.True(t, errors.As(err, &unexpectedTypeErr))
+
+	err = checkOutputContains(outputs, "null", "foo")
+	require.True(t, errors.As(err, &unexpectedTypeErr))
+	assert.Equal(t, "null", unexpectedTypeErr.ActualType)
+}
+
+func TestCheckOutputMapHasKeys(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	assert.NoError(t, checkOutputMapHasKeys(outputs, "map", []string{"foo", "baz"}))
+	assert.Error(t, checkOutputMapHasKeys(outputs, "map", []string{"foo", "missing"}))
+
+	err := checkOutputMapHasKeys(outputs, "list", []string{"foo"})
+	var notMapErr OutputValueNotMap
+	assert.True(t, errors.As(err, &notMapErr))
+}
+
+func TestCheckOutputListLength(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	assert.NoError(t, checkOutputListLength(outputs, "list", 3))
+	assert.Error(t, checkOutputListLength(outputs, "list", 2))
+
+	err := checkOutputListLength(outputs, "map", 2)
+	var notListErr OutputValueNotList
+	assert.True(t, errors.As(err, &notListErr))
+}
+
+func TestCheckOutputMissingKey(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	checks := []error{
+		checkOutputEquals(outputs, "missing", "value"),
+		checkOutputContains(outputs, "missing", "value"),
+		checkOutputMapHasKeys(outputs, "missing", []string{"key"}),
+		checkOutputListLength(outputs, "missing", 1),
+	}
+	for _, err := range checks {
+		var notFoundErr OutputKeyNotFound
+		require.True(t, errors.As(err, &notFoundErr))
+		assert.Equal(t, OutputKeyNotFound("missing"), notFoundErr)
+	}
+}
+
+func TestCheckOutputUnserializableExpectedValue(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	for _, err := range []error{
+		checkOutputEquals(outputs, "string", make(chan int)),
+		checkOutputContains(outputs, "list", func() {}),
+	} {
+		var assertionErr OutputAssertionFailed
+		require.True(t, errors.As(err, &assertionErr))
+		assert.Contains(t, assertionErr.Message, "cannot compare expected value")
+		assert.Contains(t, err.Error(), `"This is a string."`)
+	}
+}
+
+func TestOutputAssertionFailedIncludesAllOutputs(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	err := checkOutputEquals(outputs, "string", "Another string.")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"list"`)
+	assert.Contains(t, err.Error(), `"This is a string."`)
+}
+
+func TestOutputAssertionFailedRedactsSensitiveOutputs(t *testing.T) {
+	t.Parallel()
+
+	outputs := getOutputAssertTestOutputs(t)
+
+	for _, err := range []error{
+		checkOutputEquals(outputs, "string", "Another string."),
+		checkOutputEquals(outputs, "password", "wrong-password"),
+		checkOutputContains(outputs, "password", "wrong"),
+		checkOutputListLength(outputs, "password", 1),
+	} {
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "correct-horse-battery-staple")
+		assert.NotContains(t, err.Error(), "wrong")
+		assert.Contains(t, err.Error(), sensitiveOutputPlaceholder)
+	}
+
+	assert.NoError(t, checkOutputEquals(outputs, "password", "correct-horse-battery-staple"))
+}
+
+func TestAssertOutputEqualsE(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-output-assert", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	InitAndApply(t, options)
+
+	assert.NoError(t, AssertOutputEqualsE(t, options, "string", "This is a string."))
+	assert.NoError(t, AssertOutputEqualsE(t, options, "number", 3))
+	assert.NoError(t, AssertOutputEqualsE(t, options, "list", []string{"Sirius", "Rigel", "Betelgeuse"}))
+	assert.NoError(t, AssertOutputEqualsE(t, options, "password", "correct-horse-battery-staple"))
+
+	err = AssertOutputEqualsE(t, options, "password", "wrong-password")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "correct-horse-battery-staple")
+
+	err = AssertOutputEqualsE(t, options, "missing", "value")
+	var notFoundErr OutputKeyNotFound
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestAssertOutputContainsE(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-output-assert", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	InitAndApply(t, options)
+
+	assert.NoError(t, AssertOutputContainsE(t, options, "string", "a string"))
+	assert.NoError(t, AssertOutputContainsE(t, options, "list", "Rigel"))
+	assert.Error(t, AssertOutputContainsE(t, options, "list", "Vega"))
+
+	err = AssertOutputContainsE(t, options, "missing", "value")
+	var notFoundErr OutputKeyNotFound
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestAssertOutputMapHasKeysE(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-output-assert", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	InitAndApply(t, options)
+
+	assert.NoError(t, AssertOutputMapHasKeysE(t, options, "map", []string{"Gemini", "Scorpio"}))
+	assert.Error(t, AssertOutputMapHasKeysE(t, options, "map", []string{"Virgo"}))
+
+	err = AssertOutputMapHasKeysE(t, options, "missing", []string{"Gemini"})
+	var notFoundErr OutputKeyNotFound
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestAssertOutputListLengthE(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-output-assert", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	InitAndApply(t, options)
+
+	assert.NoError(t, AssertOutputListLengthE(t, options, "list", 3))
+	assert.Error(t, AssertOutputListLengthE(t, options, "list", 4))
+
+	err = AssertOutputListLengthE(t, options, "missing", 3)
+	var notFoundErr OutputKeyNotFound
+	assert.True(t, errors.As(err, &notFoundErr))
+}
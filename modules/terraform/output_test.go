@@ -1,10 +1,15 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/shell"
+	ttesting "github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -433,3 +438,58 @@ func TestOutputsForKeysError(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+// stdoutCommandRunner is a CommandRunner that logs and returns the given stdout for every command, like running the
+// command on the shell would.
+type stdoutCommandRunner string
+
+func (stdout stdoutCommandRunner) RunCommandAndGetOutputE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	return stdout.RunCommandAndGetStdOutE(ctx, t, command)
+}
+
+func (stdout stdoutCommandRunner) RunCommandAndGetStdOutE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, error) {
+	command.Logger.Logf(t, "Running command %s with args %s", command.Command, command.Args)
+	command.Logger.Logf(t, "%s", string(stdout))
+	return string(stdout), nil
+}
+
+func (stdout stdoutCommandRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t ttesting.TestingT, command shell.Command) (string, string, error) {
+	out, err := stdout.RunCommandAndGetStdOutE(ctx, t, command)
+	return out, "", err
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Logf(t ttesting.TestingT, format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func TestGetSensitiveOutput(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The output is masked in the messages of every logger, so don't log concurrently with other tests.
+
+	password := "TestGetSensitiveOutput-p4ssw0rd"
+	recorder := &recordingLogger{}
+	options := &Options{
+		TerraformDir:  ".",
+		Logger:        logger.New(recorder),
+		CommandRunner: stdoutCommandRunner(`"` + password + `"`),
+	}
+
+	require.Equal(t, password, GetSensitiveOutput(t, options, "password"))
+	require.Empty(t, recorder.messages)
+
+	options.Logger.Logf(t, "Running command with args [-var password=%s]", password)
+	require.Equal(t, []string{"Running command with args [-var password=" + logger.MaskedValuePlaceholder + "]"}, recorder.messages)
+}
+
+func TestOutputFormatsNumbersLikeTerraform(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1000000", Output(t, &Options{TerraformDir: ".", CommandRunner: stdoutCommandRunner(`1000000`)}, "number"))
+	assert.Equal(t, "3.14", Output(t, &Options{TerraformDir: ".", CommandRunner: stdoutCommandRunner(`3.14`)}, "number"))
+	assert.Equal(t, []string{"10000000", "a"}, OutputList(t, &Options{TerraformDir: ".", CommandRunner: stdoutCommandRunner(`[10000000, "a"]`)}, "list"))
+	assert.Equal(t, map[string]string{"port": "65535000"}, OutputMap(t, &Options{TerraformDir: ".", CommandRunner: stdoutCommandRunner(`{"port": 65535000}`)}, "map"))
+}
@@ -96,6 +96,63 @@ func InitAndPlanAndShowWithStructE(t testing.TestingT, options *Options) (*PlanS
 	return parsePlanJson(jsonOut)
 }
 
+// PlanAndShowWithStruct runs terraform plan and then terraform show with the given options, and parses the json result
+// into a go struct, so that you can assert on the resources terraform will create, update, replace, or delete (see
+// PlanStruct.Changes) without applying. Unlike InitAndPlanAndShowWithStruct, it doesn't run init, and if
+// options.PlanFilePath is not set, the plan is saved to a temporary file that is removed before returning. This will
+// fail the test if there is an error in the command.
+func PlanAndShowWithStruct(t testing.TestingT, options *Options) *PlanStruct {
+	plan, err := PlanAndShowWithStructE(t, options)
+	require.NoError(t, err)
+	return plan
+}
+
+// PlanAndShowWithStructE runs terraform plan and then terraform show with the given options, and parses the json
+// result into a go struct. If options.PlanFilePath is not set, the plan is saved to a temporary file that is removed
+// before returning.
+func PlanAndShowWithStructE(t testing.TestingT, options *Options) (*PlanStruct, error) {
+	options, cleanup, err := withPlanFileE(options)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	if _, err := PlanE(t, options); err != nil {
+		return nil, err
+	}
+	jsonOut, err := ShowE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return parsePlanJson(jsonOut)
+}
+
+// withPlanFileE returns the given options if their PlanFilePath is set. Otherwise, it returns a copy of them with the
+// PlanFilePath set to a new temporary file, which the returned function removes.
+func withPlanFileE(options *Options) (*Options, func(), error) {
+	if options.PlanFilePath != "" {
+		return options, func() {}, nil
+	}
+
+	tmpFile, err := ioutil.TempFile("", "terratest-plan-file-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.Remove(tmpFile.Name()) }
+	if err := tmpFile.Close(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	planOptions, err := options.Clone()
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	planOptions.PlanFilePath = tmpFile.Name()
+	return planOptions, cleanup, nil
+}
+
 // InitAndPlanWithExitCode runs terraform init and plan with the given options and returns exitcode for the plan command.
 // This will fail the test if there is an error in the command.
 func InitAndPlanWithExitCode(t testing.TestingT, options *Options) int {
@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/gruntwork-io/terratest/modules/testing"
 	tfjson "github.com/hashicorp/terraform-json"
@@ -25,6 +26,20 @@ type PlanStruct struct {
 	// A map that maps full resource addresses (e.g., module.foo.null_resource.test) to the planned actions terraform
 	// will take on that resource.
 	ResourceChangesMap map[string]*tfjson.ResourceChange
+
+	// The full addresses of the resources terraform will create, update, replace, or delete, to assert on the plan
+	// without having to interpret the actions of each resource change.
+	Changes PlannedChanges
+}
+
+// PlannedChanges lists the full resource addresses (e.g., module.foo.null_resource.test) of a plan by the action
+// terraform will take on them, each in alphabetical order. Resources without changes and data sources that are only
+// read are not listed.
+type PlannedChanges struct {
+	Create  []string
+	Update  []string
+	Replace []string // Resources that will be deleted and created again, either before or after the deletion
+	Delete  []string
 }
 
 // parsePlanJson takes in the json string representation of the terraform plan and returns a go struct representation
@@ -38,9 +53,36 @@ func parsePlanJson(jsonStr string) (*PlanStruct, error) {
 
 	plan.ResourcePlannedValuesMap = parsePlannedValues(plan)
 	plan.ResourceChangesMap = parseResourceChanges(plan)
+	plan.Changes = parsePlannedChanges(plan)
 	return plan, nil
 }
 
+// parsePlannedChanges groups the addresses of the resource changes of the plan by action.
+func parsePlannedChanges(plan *PlanStruct) PlannedChanges {
+	changes := PlannedChanges{Create: []string{}, Update: []string{}, Replace: []string{}, Delete: []string{}}
+	for address, change := range plan.ResourceChangesMap {
+		if change.Change == nil {
+			continue
+		}
+		actions := change.Change.Actions
+		switch {
+		case actions.Create():
+			changes.Create = append(changes.Create, address)
+		case actions.Update():
+			changes.Update = append(changes.Update, address)
+		case actions.Replace():
+			changes.Replace = append(changes.Replace, address)
+		case actions.Delete():
+			changes.Delete = append(changes.Delete, address)
+		}
+	}
+	sort.Strings(changes.Create)
+	sort.Strings(changes.Update)
+	sort.Strings(changes.Replace)
+	sort.Strings(changes.Delete)
+	return changes
+}
+
 // parseResourceChanges takes a plan and returns a map that maps resource addresses to the planned changes for that
 // resource. If there are no changes, this returns an empty map instead of erroring.
 func parseResourceChanges(plan *PlanStruct) map[string]*tfjson.ResourceChange {
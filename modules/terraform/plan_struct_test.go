@@ -79,3 +79,41 @@ func TestResourceChangesJson(t *testing.T) {
 	assert.Equal(t, barChanges.Change.After.(map[string]interface{})["triggers"].(map[string]interface{})["foo_id"].(string), "424881806176056736")
 
 }
+
+const plannedChangesJson = `{
+  "format_version": "0.2",
+  "resource_changes": [
+    {"address": "null_resource.new", "mode": "managed", "type": "null_resource", "name": "new", "change": {"actions": ["create"]}},
+    {"address": "module.foo.null_resource.tags", "mode": "managed", "type": "null_resource", "name": "tags", "change": {"actions": ["update"]}},
+    {"address": "null_resource.trigger", "mode": "managed", "type": "null_resource", "name": "trigger", "change": {"actions": ["delete", "create"]}},
+    {"address": "null_resource.zero_downtime", "mode": "managed", "type": "null_resource", "name": "zero_downtime", "change": {"actions": ["create", "delete"]}},
+    {"address": "null_resource.old", "mode": "managed", "type": "null_resource", "name": "old", "change": {"actions": ["delete"]}},
+    {"address": "null_resource.same", "mode": "managed", "type": "null_resource", "name": "same", "change": {"actions": ["no-op"]}},
+    {"address": "data.null_data_source.read", "mode": "data", "type": "null_data_source", "name": "read", "change": {"actions": ["read"]}}
+  ]
+}`
+
+func TestPlannedChanges(t *testing.T) {
+	t.Parallel()
+
+	plan, err := parsePlanJson(plannedChangesJson)
+	require.NoError(t, err)
+
+	assert.Equal(t, PlannedChanges{
+		Create:  []string{"null_resource.new"},
+		Update:  []string{"module.foo.null_resource.tags"},
+		Replace: []string{"null_resource.trigger", "null_resource.zero_downtime"},
+		Delete:  []string{"null_resource.old"},
+	}, plan.Changes)
+}
+
+func TestPlanAndShowWithStruct(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{TerraformDir: ".", CommandRunner: stdoutCommandRunner(plannedChangesJson)}
+
+	plan := PlanAndShowWithStruct(t, options)
+
+	assert.Equal(t, []string{"null_resource.new"}, plan.Changes.Create)
+	assert.Empty(t, options.PlanFilePath)
+}
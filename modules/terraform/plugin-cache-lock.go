@@ -0,0 +1,61 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	// pluginCacheDirEnvVar is the environment variable that tells Terraform to download providers into a shared cache.
+	pluginCacheDirEnvVar = "TF_PLUGIN_CACHE_DIR"
+
+	// pluginCacheLockFileName is the name of the lock file that terraform init holds in the plugin cache.
+	pluginCacheLockFileName = ".terratest-init.lock"
+)
+
+// lockPluginCacheE waits for an exclusive lock on the plugin cache that Terraform is configured to use through the
+// TF_PLUGIN_CACHE_DIR environment variable (in EnvVars or in the environment of the test), if any, and returns a
+// function that releases it. Terraform doesn't lock the cache itself, so concurrent inits from parallel tests (even
+// across the test binaries of different packages) can corrupt it and fail with checksum errors. Commands run in
+// Docker are not locked, as the cache folder is in the container.
+func lockPluginCacheE(t testing.TestingT, options *Options) (func(), error) {
+	cacheDir := getPluginCacheDir(options)
+	if cacheDir == "" || options.Docker != nil {
+		return func() {}, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, err
+	}
+
+	lockFile, err := os.OpenFile(filepath.Join(cacheDir, pluginCacheLockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	options.Logger.Logf(t, "Waiting for lock on plugin cache %s", cacheDir)
+	if err := lockFileExclusive(lockFile); err != nil {
+		lockFile.Close()
+		return nil, err
+	}
+
+	return func() {
+		unlockFile(lockFile)
+		lockFile.Close()
+	}, nil
+}
+
+// getPluginCacheDir returns the plugin cache folder that Terraform will use with the given options, or an empty string
+// if there is none. A relative folder is relative to TerraformDir, where Terraform runs.
+func getPluginCacheDir(options *Options) string {
+	cacheDir, isSet := options.EnvVars[pluginCacheDirEnvVar]
+	if !isSet {
+		cacheDir = os.Getenv(pluginCacheDirEnvVar)
+	}
+	if cacheDir == "" || filepath.IsAbs(cacheDir) {
+		return cacheDir
+	}
+	return filepath.Join(options.TerraformDir, cacheDir)
+}
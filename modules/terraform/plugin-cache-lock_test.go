@@ -0,0 +1,57 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockPluginCacheIsExclusive(t *testing.T) {
+	t.Parallel()
+
+	cacheDir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	options := &Options{EnvVars: map[string]string{pluginCacheDirEnvVar: cacheDir}}
+
+	unlock, err := lockPluginCacheE(t, options)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(cacheDir, pluginCacheLockFileName))
+
+	locked := make(chan struct{})
+	go func() {
+		unlockAgain, err := lockPluginCacheE(t, options)
+		if err == nil {
+			unlockAgain()
+		}
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("Expected the second lock to wait for the first one to be released")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the second lock to be taken once the first one was released")
+	}
+}
+
+func TestGetPluginCacheDir(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/tmp/plugin-cache", getPluginCacheDir(&Options{EnvVars: map[string]string{pluginCacheDirEnvVar: "/tmp/plugin-cache"}}))
+	assert.Equal(t, filepath.Join("fixture", "plugin-cache"), getPluginCacheDir(&Options{TerraformDir: "fixture", EnvVars: map[string]string{pluginCacheDirEnvVar: "plugin-cache"}}))
+	assert.Equal(t, "", getPluginCacheDir(&Options{EnvVars: map[string]string{pluginCacheDirEnvVar: ""}}))
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package terraform
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFileExclusive blocks until it holds an exclusive lock on the given file. The lock is released when the file is
+// closed, including when the process exits.
+func lockFileExclusive(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_EX)
+}
+
+// unlockFile releases the lock held on the given file.
+func unlockFile(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_UN)
+}
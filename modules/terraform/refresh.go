@@ -0,0 +1,20 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// Refresh runs terraform refresh with the given options to update the state to match the real infrastructure, and
+// returns stdout/stderr. This will fail the test if there is an error in the command.
+func Refresh(t testing.TestingT, options *Options) string {
+	out, err := RefreshE(t, options)
+	require.NoError(t, err)
+	return out
+}
+
+// RefreshE runs terraform refresh with the given options to update the state to match the real infrastructure, and
+// returns stdout/stderr.
+func RefreshE(t testing.TestingT, options *Options) (string, error) {
+	return RunTerraformCommandE(t, options, FormatArgs(options, "refresh", "-input=false")...)
+}
@@ -0,0 +1,24 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshPassesVarsAndTargets(t *testing.T) {
+	t.Parallel()
+
+	runner := newRecordingCommandRunner()
+	options := &Options{
+		TerraformDir:  ".",
+		Vars:          map[string]interface{}{"name": "test"},
+		Targets:       []string{"aws_instance.example"},
+		CommandRunner: runner,
+	}
+
+	_, err := RefreshE(t, options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refresh", "-input=false", "-var", "name=test", "-target=aws_instance.example", "-lock=false"}, runner.commands["refresh"].Args)
+}
@@ -0,0 +1,144 @@
+package terraform
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+)
+
+// StatePull runs terraform state pull with the given options and returns the current state as JSON. This works for
+// both local and remote backends. This will fail the test if there is an error in the command.
+func StatePull(t testing.TestingT, options *Options) string {
+	out, err := StatePullE(t, options)
+	require.NoError(t, err)
+	return out
+}
+
+// StatePullE runs terraform state pull with the given options and returns the current state as JSON. This works for
+// both local and remote backends.
+func StatePullE(t testing.TestingT, options *Options) (string, error) {
+	return RunTerraformCommandAndGetStdoutE(t, options, "state", "pull")
+}
+
+// StatePush runs terraform state push with the given options to replace the current state with the state file at the
+// given path. This will fail the test if there is an error in the command.
+func StatePush(t testing.TestingT, options *Options, stateFilePath string) string {
+	out, err := StatePushE(t, options, stateFilePath)
+	require.NoError(t, err)
+	return out
+}
+
+// StatePushE runs terraform state push with the given options to replace the current state with the state file at the
+// given path. Terraform refuses to push a state with a different lineage or an older serial than the current one.
+func StatePushE(t testing.TestingT, options *Options, stateFilePath string) (string, error) {
+	return RunTerraformCommandE(t, options, "state", "push", stateFilePath)
+}
+
+// StateList runs terraform state list with the given options and returns the addresses of the resources in the
+// current state, e.g. to check that a resource was removed by a targeted destroy. Any addresses given filter the
+// resources, as with the CLI. This will fail the test if there is an error in the command.
+func StateList(t testing.TestingT, options *Options, addresses ...string) []string {
+	resources, err := StateListE(t, options, addresses...)
+	require.NoError(t, err)
+	return resources
+}
+
+// StateListE runs terraform state list with the given options and returns the addresses of the resources in the
+// current state, e.g. to check that a resource was removed by a targeted destroy. Any addresses given filter the
+// resources, as with the CLI.
+func StateListE(t testing.TestingT, options *Options, addresses ...string) ([]string, error) {
+	out, err := RunTerraformCommandAndGetStdoutE(t, options, append([]string{"state", "list"}, addresses...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseStateList(out), nil
+}
+
+// parseStateList returns the resource addresses in the given output of terraform state list, one per line.
+func parseStateList(out string) []string {
+	resources := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if address := strings.TrimSpace(line); address != "" {
+			resources = append(resources, address)
+		}
+	}
+	return resources
+}
+
+// StateShow runs terraform state show with the given options and returns the human-readable attributes of the resource
+// at the given address. Use GetStateResource to assert on the attributes. This will fail the test if there is an error
+// in the command.
+func StateShow(t testing.TestingT, options *Options, address string) string {
+	out, err := StateShowE(t, options, address)
+	require.NoError(t, err)
+	return out
+}
+
+// StateShowE runs terraform state show with the given options and returns the human-readable attributes of the
+// resource at the given address. Use GetStateResourceE to assert on the attributes.
+func StateShowE(t testing.TestingT, options *Options, address string) (string, error) {
+	return RunTerraformCommandAndGetStdoutE(t, options, "state", "show", "-no-color", address)
+}
+
+// GetStateResource runs terraform show with the given options and returns the resource at the given full address
+// (including the modules it's nested in) in the current state. This will fail the test if there is an error, including
+// if the resource isn't in the state.
+func GetStateResource(t testing.TestingT, options *Options, address string) *tfjson.StateResource {
+	resource, err := GetStateResourceE(t, options, address)
+	require.NoError(t, err)
+	return resource
+}
+
+// GetStateResourceE runs terraform show with the given options and returns the resource at the given full address
+// (including the modules it's nested in) in the current state, or a ResourceNotFoundInState error if it isn't there.
+func GetStateResourceE(t testing.TestingT, options *Options, address string) (*tfjson.StateResource, error) {
+	resources, err := GetStateResourcesE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resource, exists := resources[address]
+	if !exists {
+		return nil, ResourceNotFoundInState(address)
+	}
+	return resource, nil
+}
+
+// GetStateResources runs terraform show with the given options and returns the resources in the current state, keyed
+// by their full address (including the modules they're nested in). This will fail the test if there is an error.
+func GetStateResources(t testing.TestingT, options *Options) map[string]*tfjson.StateResource {
+	resources, err := GetStateResourcesE(t, options)
+	require.NoError(t, err)
+	return resources
+}
+
+// GetStateResourcesE runs terraform show with the given options and returns the resources in the current state, keyed
+// by their full address (including the modules they're nested in).
+func GetStateResourcesE(t testing.TestingT, options *Options) (map[string]*tfjson.StateResource, error) {
+	stateOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	// Show the state, not a plan
+	stateOptions.PlanFilePath = ""
+
+	out, err := ShowE(t, stateOptions)
+	if err != nil {
+		return nil, err
+	}
+	return parseStateResources(out)
+}
+
+func parseStateResources(stateJson string) (map[string]*tfjson.StateResource, error) {
+	state := &tfjson.State{}
+	if err := json.Unmarshal([]byte(stateJson), state); err != nil {
+		return nil, err
+	}
+	// An empty state has no values
+	if state.Values == nil || state.Values.RootModule == nil {
+		return map[string]*tfjson.StateResource{}, nil
+	}
+	return parseModulePlannedValues(state.Values.RootModule), nil
+}
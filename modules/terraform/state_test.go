@@ -0,0 +1,66 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStateResources(t *testing.T) {
+	t.Parallel()
+
+	resources, err := parseStateResources(`{
+  "format_version": "0.2",
+  "terraform_version": "1.0.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "values": {"id": "i-0abc"}}
+      ],
+      "child_modules": [
+        {
+          "address": "module.bucket",
+          "resources": [
+            {"address": "module.bucket.aws_s3_bucket.this", "mode": "managed", "type": "aws_s3_bucket", "name": "this", "values": {"id": "my-bucket"}}
+          ]
+        }
+      ]
+    }
+  }
+}`)
+	require.NoError(t, err)
+
+	require.Len(t, resources, 2)
+	assert.Equal(t, "i-0abc", resources["aws_instance.web"].AttributeValues["id"])
+	assert.Equal(t, "aws_s3_bucket", resources["module.bucket.aws_s3_bucket.this"].Type)
+
+	empty, err := parseStateResources(`{"format_version": "0.2"}`)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParseStateList(t *testing.T) {
+	t.Parallel()
+
+	resources := parseStateList("aws_instance.web\nmodule.bucket.aws_s3_bucket.this\n\n")
+	assert.Equal(t, []string{"aws_instance.web", "module.bucket.aws_s3_bucket.this"}, resources)
+
+	assert.Empty(t, parseStateList(""))
+}
+
+func TestGetStateResourceNotFound(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:  ".",
+		CommandRunner: stdoutCommandRunner(`{"format_version": "0.2", "values": {"root_module": {"resources": [{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web"}]}}}`),
+	}
+
+	resource, err := GetStateResourceE(t, options, "aws_instance.web")
+	require.NoError(t, err)
+	assert.Equal(t, "web", resource.Name)
+
+	_, err = GetStateResourceE(t, options, "aws_instance.db")
+	assert.Equal(t, ResourceNotFoundInState("aws_instance.db"), err)
+}
@@ -0,0 +1,23 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// Taint runs terraform taint with the given options to mark the resource at the given address (e.g.
+// aws_instance.example) for replacement on the next apply, and returns stdout/stderr. This will fail the test if there
+// is an error in the command.
+func Taint(t testing.TestingT, options *Options, address string) string {
+	out, err := TaintE(t, options, address)
+	require.NoError(t, err)
+	return out
+}
+
+// TaintE runs terraform taint with the given options to mark the resource at the given address (e.g.
+// aws_instance.example) for replacement on the next apply, and returns stdout/stderr.
+func TaintE(t testing.TestingT, options *Options, address string) (string, error) {
+	// We manually construct the args here instead of using `FormatArgs`, because taint doesn't accept variables.
+	args := append([]string{"taint"}, FormatTerraformLockAsArgs(options.Lock, options.LockTimeout)...)
+	return RunTerraformCommandE(t, options, append(args, address)...)
+}
@@ -0,0 +1,24 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaintDoesNotPassVars(t *testing.T) {
+	t.Parallel()
+
+	runner := newRecordingCommandRunner()
+	options := &Options{
+		TerraformDir:  ".",
+		Vars:          map[string]interface{}{"name": "test"},
+		LockTimeout:   "5m",
+		CommandRunner: runner,
+	}
+
+	_, err := TaintE(t, options, "aws_instance.example")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"taint", "-lock=false", "-lock-timeout=5m", "aws_instance.example"}, runner.commands["taint"].Args)
+}
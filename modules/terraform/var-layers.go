@@ -0,0 +1,118 @@
+package terraform
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// VarLayer is a named set of variables, such as the defaults of a test suite, the defaults of an environment, or the
+// overrides of a single test. The name is only used to report which layers set the same variable.
+type VarLayer struct {
+	Name string
+	Vars map[string]interface{}
+}
+
+// VarOverride describes a variable set by more than one layer, where the value of the later layer wins.
+type VarOverride struct {
+	Name            string
+	Layer           string
+	Value           interface{}
+	OverriddenLayer string
+	OverriddenValue interface{}
+}
+
+func (override VarOverride) String() string {
+	return fmt.Sprintf("%s = %v from %s overrides %v from %s", override.Name, override.Value, override.Layer, override.OverriddenValue, override.OverriddenLayer)
+}
+
+// LayerVars merges the variables of the given layers into a new map, for use as the Vars of Options. Layers are
+// applied in order, so a variable set by a later layer overrides the same variable set by an earlier one:
+//
+//	vars := terraform.LayerVars(t,
+//		terraform.VarLayer{Name: "suite defaults", Vars: suiteDefaults},
+//		terraform.VarLayer{Name: "staging", Vars: stagingDefaults},
+//		terraform.VarLayer{Name: t.Name(), Vars: map[string]interface{}{"instance_count": 3}},
+//	)
+//
+// Every override is logged, so you can tell where the value of each variable came from.
+func LayerVars(t testing.TestingT, layers ...VarLayer) map[string]interface{} {
+	vars, overrides := LayerVarsAndGetOverrides(layers...)
+	for _, override := range overrides {
+		logger.Logf(t, "Variable %s", override)
+	}
+	return vars
+}
+
+// LayerVarsAndGetOverrides merges the variables of the given layers into a new map like LayerVars, and returns every
+// variable that a later layer overrode, in the order the overrides were applied.
+func LayerVarsAndGetOverrides(layers ...VarLayer) (map[string]interface{}, []VarOverride) {
+	vars := map[string]interface{}{}
+	setBy := map[string]string{}
+	overrides := []VarOverride{}
+
+	for i, layer := range layers {
+		layerName := layer.Name
+		if layerName == "" {
+			layerName = fmt.Sprintf("layer %d", i)
+		}
+
+		layerVars := deepCopyMap(layer.Vars)
+		for _, name := range sortedKeys(layerVars) {
+			if previousLayer, isSet := setBy[name]; isSet {
+				overrides = append(overrides, VarOverride{
+					Name:            name,
+					Layer:           layerName,
+					Value:           layerVars[name],
+					OverriddenLayer: previousLayer,
+					OverriddenValue: vars[name],
+				})
+			}
+			vars[name] = layerVars[name]
+			setBy[name] = layerName
+		}
+	}
+
+	return vars, overrides
+}
+
+// MergeVars merges the variables of the given layers into a new map, failing the test if two layers set the same
+// variable to different values. Use it for layers that are meant to be disjoint, such as variables shared by several
+// modules, where an override is a mistake rather than intended.
+func MergeVars(t testing.TestingT, layers ...VarLayer) map[string]interface{} {
+	vars, err := MergeVarsE(layers...)
+	require.NoError(t, err)
+	return vars
+}
+
+// MergeVarsE merges the variables of the given layers into a new map, returning a VarsConflict error if two layers set
+// the same variable to different values.
+func MergeVarsE(layers ...VarLayer) (map[string]interface{}, error) {
+	vars, overrides := LayerVarsAndGetOverrides(layers...)
+
+	conflicts := []VarOverride{}
+	for _, override := range overrides {
+		if !reflect.DeepEqual(override.Value, override.OverriddenValue) {
+			conflicts = append(conflicts, override)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, VarsConflict{Conflicts: conflicts}
+	}
+
+	return vars, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayerVarsAndGetOverrides(t *testing.T) {
+	t.Parallel()
+
+	defaults := map[string]interface{}{"region": "us-east-1", "instance_count": 1, "tags": map[string]interface{}{"Team": "infra"}}
+	staging := map[string]interface{}{"region": "eu-west-1"}
+
+	vars, overrides := LayerVarsAndGetOverrides(
+		VarLayer{Name: "defaults", Vars: defaults},
+		VarLayer{Name: "staging", Vars: staging},
+		VarLayer{Vars: map[string]interface{}{"instance_count": 3, "region": "eu-west-1"}},
+	)
+
+	assert.Equal(t, map[string]interface{}{
+		"region":         "eu-west-1",
+		"instance_count": 3,
+		"tags":           map[string]interface{}{"Team": "infra"},
+	}, vars)
+	assert.Equal(t, []VarOverride{
+		{Name: "region", Layer: "staging", Value: "eu-west-1", OverriddenLayer: "defaults", OverriddenValue: "us-east-1"},
+		{Name: "instance_count", Layer: "layer 2", Value: 3, OverriddenLayer: "defaults", OverriddenValue: 1},
+		{Name: "region", Layer: "layer 2", Value: "eu-west-1", OverriddenLayer: "staging", OverriddenValue: "eu-west-1"},
+	}, overrides)
+
+	// The layers are copied, so changing the result doesn't change the defaults shared by other tests
+	vars["tags"].(map[string]interface{})["Team"] = "changed"
+	assert.Equal(t, "infra", defaults["tags"].(map[string]interface{})["Team"])
+}
+
+func TestLayerVars(t *testing.T) {
+	t.Parallel()
+
+	vars := LayerVars(t,
+		VarLayer{Name: "defaults", Vars: map[string]interface{}{"region": "us-east-1"}},
+		VarLayer{Name: t.Name(), Vars: map[string]interface{}{"region": "eu-west-1"}},
+	)
+	assert.Equal(t, map[string]interface{}{"region": "eu-west-1"}, vars)
+}
+
+func TestMergeVarsE(t *testing.T) {
+	t.Parallel()
+
+	vars, err := MergeVarsE(
+		VarLayer{Name: "network", Vars: map[string]interface{}{"vpc_id": "vpc-123", "region": "us-east-1"}},
+		VarLayer{Name: "database", Vars: map[string]interface{}{"db_name": "test", "region": "us-east-1"}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"vpc_id": "vpc-123", "db_name": "test", "region": "us-east-1"}, vars)
+
+	_, err = MergeVarsE(
+		VarLayer{Name: "network", Vars: map[string]interface{}{"region": "us-east-1"}},
+		VarLayer{Name: "database", Vars: map[string]interface{}{"region": "eu-west-1"}},
+	)
+	require.Error(t, err)
+	assert.Equal(t, "Conflicting variables:\n  - region = eu-west-1 from database overrides us-east-1 from network", err.Error())
+}
@@ -0,0 +1,72 @@
+package terraform
+
+import (
+	"encoding/json"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/require"
+)
+
+// GetVersion runs terraform version with the given options and returns the version of the TerraformBinary, e.g. 1.5.7.
+// This will fail the test if there is an error.
+func GetVersion(t testing.TestingT, options *Options) string {
+	out, err := GetVersionE(t, options)
+	require.NoError(t, err)
+	return out
+}
+
+// GetVersionE runs terraform version with the given options and returns the version of the TerraformBinary, e.g.
+// 1.5.7. OpenTofu reports its own version the same way.
+func GetVersionE(t testing.TestingT, options *Options) (string, error) {
+	out, err := RunTerraformCommandAndGetStdoutE(t, options, "version", "-json")
+	if err != nil {
+		return "", err
+	}
+	return parseVersion(out)
+}
+
+func parseVersion(versionJson string) (string, error) {
+	var versionInfo struct {
+		TerraformVersion string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal([]byte(versionJson), &versionInfo); err != nil {
+		return "", err
+	}
+	return versionInfo.TerraformVersion, nil
+}
+
+// CheckRequiredVersion fails the test if the version of the TerraformBinary doesn't satisfy options.RequiredVersion.
+// It does nothing if RequiredVersion isn't set.
+func CheckRequiredVersion(t testing.TestingT, options *Options) {
+	require.NoError(t, CheckRequiredVersionE(t, options))
+}
+
+// CheckRequiredVersionE returns an UnsupportedTerraformVersion error if the version of the TerraformBinary doesn't
+// satisfy options.RequiredVersion. It does nothing if RequiredVersion isn't set.
+func CheckRequiredVersionE(t testing.TestingT, options *Options) error {
+	if options.RequiredVersion == "" {
+		return nil
+	}
+
+	actualVersion, err := GetVersionE(t, options)
+	if err != nil {
+		return err
+	}
+	return checkVersionConstraint(options.TerraformBinary, actualVersion, options.RequiredVersion)
+}
+
+func checkVersionConstraint(binary string, actualVersion string, requiredVersion string) error {
+	constraint, err := version.NewConstraint(requiredVersion)
+	if err != nil {
+		return err
+	}
+	parsedVersion, err := version.NewVersion(actualVersion)
+	if err != nil {
+		return err
+	}
+	if !constraint.Check(parsedVersion) {
+		return UnsupportedTerraformVersion{Binary: binary, Version: actualVersion, RequiredVersion: requiredVersion}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	version, err := parseVersion(`{"terraform_version": "1.5.7", "platform": "linux_amd64", "provider_selections": {}, "terraform_outdated": false}`)
+	require.NoError(t, err)
+	assert.Equal(t, "1.5.7", version)
+
+	_, err = parseVersion("Terraform v1.5.7")
+	assert.Error(t, err)
+}
+
+func TestCheckVersionConstraint(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkVersionConstraint("terraform", "1.5.7", "= 1.5.7"))
+	assert.NoError(t, checkVersionConstraint("tofu", "1.6.0", ">= 1.6, < 2.0"))
+
+	err := checkVersionConstraint("terraform", "1.2.0", ">= 1.3")
+	require.Error(t, err)
+	assert.Equal(t, UnsupportedTerraformVersion{Binary: "terraform", Version: "1.2.0", RequiredVersion: ">= 1.3"}, err)
+
+	assert.Error(t, checkVersionConstraint("terraform", "not a version", ">= 1.3"))
+}
+
+func TestCheckRequiredVersionSkippedWithoutConstraint(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckRequiredVersionE(t, &Options{TerraformBinary: "binary-that-does-not-exist"}))
+}
@@ -42,7 +42,8 @@ func WorkspaceSelectOrNewE(t testing.TestingT, options *Options, name string) (s
 }
 
 func isExistingWorkspace(out string, name string) bool {
-	workspaces := strings.Split(out, "\n")
+	// Terraform ends lines with \r\n on Windows
+	workspaces := strings.Split(strings.ReplaceAll(out, "\r\n", "\n"), "\n")
 	for _, ws := range workspaces {
 		if nameMatchesWorkspace(name, ws) {
 			return true
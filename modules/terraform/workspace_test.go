@@ -81,6 +81,25 @@ func TestWorkspaceApply(t *testing.T) {
 	assert.Contains(t, out, "Hello, Terratest")
 }
 
+func TestWorkspaceOption(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-workspace", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Workspace:    "isolated",
+	}
+
+	out := InitAndApply(t, options)
+	assert.Contains(t, out, "Hello, isolated")
+
+	Destroy(t, options)
+	out = RunTerraformCommand(t, options, "workspace", "list")
+	assert.False(t, isExistingWorkspace(out, "isolated"))
+}
+
 func TestIsExistingWorkspace(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,50 @@
+package test_structure
+
+import (
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// DestroyAndAssertAwsResourcesDestroyed runs terraform destroy with the given options, and then checks with AWS that
+// each resource that was in the state before destroy no longer exists in the given region, failing the test if any of
+// them survived. See aws.AssertResourcesDestroyed for the resource types that are checked.
+func DestroyAndAssertAwsResourcesDestroyed(t testing.TestingT, terraformOptions *terraform.Options, region string) string {
+	out, err := DestroyAndAssertAwsResourcesDestroyedE(t, terraformOptions, region)
+	require.NoError(t, err)
+	return out
+}
+
+// DestroyAndAssertAwsResourcesDestroyedE runs terraform destroy with the given options, and then checks with AWS that
+// each resource that was in the state before destroy no longer exists in the given region, returning an
+// aws.ResourcesNotDestroyedError if any of them survived.
+func DestroyAndAssertAwsResourcesDestroyedE(t testing.TestingT, terraformOptions *terraform.Options, region string) (string, error) {
+	resources, err := terraform.GetStateResourcesE(t, terraformOptions)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := terraform.DestroyE(t, terraformOptions)
+	if err != nil {
+		return out, err
+	}
+
+	return out, aws.AssertResourcesDestroyedE(t, region, destroyedResourcesFromState(resources))
+}
+
+// destroyedResourcesFromState converts the managed resources among the given Terraform state resources, which are keyed
+// by address, into the form that aws.AssertResourcesDestroyed takes. Data sources are skipped.
+func destroyedResourcesFromState(resources map[string]*tfjson.StateResource) []aws.DestroyedResource {
+	destroyed := []aws.DestroyedResource{}
+	for address, resource := range resources {
+		if resource.Mode != tfjson.ManagedResourceMode {
+			continue
+		}
+		id, _ := resource.AttributeValues["id"].(string)
+		destroyed = append(destroyed, aws.DestroyedResource{Address: address, Type: resource.Type, ID: id})
+	}
+	return destroyed
+}
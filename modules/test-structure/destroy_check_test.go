@@ -0,0 +1,21 @@
+package test_structure
+
+import (
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+)
+
+func TestDestroyedResourcesFromState(t *testing.T) {
+	t.Parallel()
+
+	resources := map[string]*tfjson.StateResource{
+		"aws_instance.web":    {Mode: tfjson.ManagedResourceMode, Type: "aws_instance", AttributeValues: map[string]interface{}{"id": "i-0abc"}},
+		"data.aws_ami.ubuntu": {Mode: tfjson.DataResourceMode, Type: "aws_ami", AttributeValues: map[string]interface{}{"id": "ami-0abc"}},
+	}
+
+	assert.Equal(t, []aws.DestroyedResource{{Address: "aws_instance.web", Type: "aws_instance", ID: "i-0abc"}}, destroyedResourcesFromState(resources))
+}
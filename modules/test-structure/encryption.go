@@ -0,0 +1,167 @@
+package test_structure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// TestDataPassphraseEnvVar is the environment variable with a passphrase to encrypt saved test data with, if no
+// Encryptor is set with SetTestDataEncryptor. Since test stages often run in separate go test invocations, this is the
+// easiest way to make sure all of them can read each other's data.
+const TestDataPassphraseEnvVar = "TERRATEST_TEST_DATA_PASSPHRASE"
+
+// Encryptor encrypts and decrypts the test data saved between test stages, which can include key material and
+// secrets. See NewPassphraseEncryptor and aws.KmsEncryptor.
+type Encryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+var (
+	encryptorMutex sync.Mutex
+	encryptor      Encryptor
+)
+
+// SetTestDataEncryptor sets the process-wide Encryptor that all test data and Terraform state saved by this package is
+// encrypted with. Pass nil, the default, to encrypt with the passphrase in TERRATEST_TEST_DATA_PASSPHRASE if set, or to
+// save test data unencrypted otherwise. Data saved unencrypted can still be loaded after setting an Encryptor.
+func SetTestDataEncryptor(newEncryptor Encryptor) {
+	encryptorMutex.Lock()
+	defer encryptorMutex.Unlock()
+	encryptor = newEncryptor
+}
+
+// getTestDataEncryptor returns the Encryptor set with SetTestDataEncryptor, or a passphrase Encryptor if
+// TERRATEST_TEST_DATA_PASSPHRASE is set, or nil otherwise.
+func getTestDataEncryptor() Encryptor {
+	encryptorMutex.Lock()
+	defer encryptorMutex.Unlock()
+
+	if encryptor != nil {
+		return encryptor
+	}
+	if passphrase := os.Getenv(TestDataPassphraseEnvVar); passphrase != "" {
+		return NewPassphraseEncryptor(passphrase)
+	}
+	return nil
+}
+
+// encryptedTestData is the format of encrypted test data files. It's JSON, so that it can be told apart from
+// unencrypted test data.
+type encryptedTestData struct {
+	EncryptedTestData []byte `json:"encrypted_test_data"`
+}
+
+// encodeTestData encrypts the given test data, if there is an Encryptor.
+func encodeTestData(data []byte) ([]byte, error) {
+	encryptor := getTestDataEncryptor()
+	if encryptor == nil {
+		return data, nil
+	}
+
+	ciphertext, err := encryptor.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encryptedTestData{EncryptedTestData: ciphertext})
+}
+
+// readTestDataFile reads the test data in the given file, decrypting it if it was encrypted.
+func readTestDataFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted encryptedTestData
+	if err := json.Unmarshal(data, &encrypted); err != nil || encrypted.EncryptedTestData == nil {
+		// Not encrypted
+		return data, nil
+	}
+
+	encryptor := getTestDataEncryptor()
+	if encryptor == nil {
+		return nil, TestDataEncrypted{Path: path}
+	}
+	return encryptor.Decrypt(encrypted.EncryptedTestData)
+}
+
+const (
+	passphraseSaltSize = 16
+	// These are the parameters recommended for interactive logins by the scrypt package
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+// passphraseEncryptor encrypts with AES-256-GCM, using a key derived from a passphrase with scrypt and a random salt.
+type passphraseEncryptor struct {
+	passphrase string
+}
+
+// NewPassphraseEncryptor returns an Encryptor that encrypts with AES-256-GCM, using a key derived from the given
+// passphrase.
+func NewPassphraseEncryptor(passphrase string) Encryptor {
+	return passphraseEncryptor{passphrase: passphrase}
+}
+
+// Encrypt encrypts the given plaintext. The result contains the salt and nonce followed by the ciphertext.
+func (encryptor passphraseEncryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, passphraseSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := encryptor.newGCM(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	result := append(salt, nonce...)
+	return gcm.Seal(result, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts ciphertext returned by Encrypt.
+func (encryptor passphraseEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < passphraseSaltSize {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	gcm, err := encryptor.newGCM(ciphertext[:passphraseSaltSize])
+	if err != nil {
+		return nil, err
+	}
+
+	rest := ciphertext[passphraseSaltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil)
+}
+
+func (encryptor passphraseEncryptor) newGCM(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(encryptor.passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
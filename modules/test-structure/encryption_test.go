@@ -0,0 +1,60 @@
+package test_structure
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassphraseEncryptor(t *testing.T) {
+	t.Parallel()
+
+	encryptor := NewPassphraseEncryptor("correct horse battery staple")
+	ciphertext, err := encryptor.Encrypt([]byte("secret"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "secret")
+
+	plaintext, err := encryptor.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	_, err = NewPassphraseEncryptor("wrong").Decrypt(ciphertext)
+	assert.Error(t, err)
+}
+
+func TestSaveAndLoadEncryptedTestData(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// The Encryptor and environment variables are process-wide.
+
+	testFolder := t.TempDir()
+	path := formatNamedTestDataPath(testFolder, "password")
+
+	os.Setenv(TestDataPassphraseEnvVar, "correct horse battery staple")
+	SaveString(t, testFolder, "password", "hunter2")
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "hunter2")
+	assert.True(t, IsTestDataPresent(t, path))
+	assert.Equal(t, "hunter2", LoadString(t, testFolder, "password"))
+
+	// Without the passphrase, the data can't be loaded, but is still considered present
+	os.Unsetenv(TestDataPassphraseEnvVar)
+	assert.True(t, IsTestDataPresent(t, path))
+	_, err = readTestDataFile(path)
+	assert.IsType(t, TestDataEncrypted{}, err)
+
+	// Unencrypted data can still be loaded with an Encryptor set
+	SaveString(t, testFolder, "username", "admin")
+	SetTestDataEncryptor(NewPassphraseEncryptor("another passphrase"))
+	defer SetTestDataEncryptor(nil)
+	assert.Equal(t, "admin", LoadString(t, testFolder, "username"))
+
+	contents, err = readTestDataFile(filepath.Join(testFolder, ".test-data", "username.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `"admin"`, string(contents))
+}
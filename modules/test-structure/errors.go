@@ -0,0 +1,33 @@
+package test_structure
+
+import "fmt"
+
+// FixtureNotFound is an error that occurs when no artifacts were saved by RegisterFixture for a region and unique ID.
+type FixtureNotFound struct {
+	Region   string
+	UniqueId string
+	Path     string
+}
+
+func (err FixtureNotFound) Error() string {
+	return fmt.Sprintf("No fixture with unique ID %s was registered in region %s (expected TerraformOptions at %s). Check the region and the value of %s.", err.UniqueId, err.Region, err.Path, FIXTURES_DIR_ENV_VAR)
+}
+
+// FixtureTerraformDirNotFound is an error that occurs when the Terraform folder of a registered fixture no longer exists.
+type FixtureTerraformDirNotFound struct {
+	UniqueId     string
+	TerraformDir string
+}
+
+func (err FixtureTerraformDirNotFound) Error() string {
+	return fmt.Sprintf("The Terraform folder %s of fixture %s no longer exists, so its state can't be read to destroy it.", err.TerraformDir, err.UniqueId)
+}
+
+// TestDataEncrypted is an error that occurs when test data was saved encrypted, but no Encryptor is set to decrypt it.
+type TestDataEncrypted struct {
+	Path string
+}
+
+func (err TestDataEncrypted) Error() string {
+	return fmt.Sprintf("The test data at %s is encrypted, but no Encryptor is set to decrypt it. Call SetTestDataEncryptor or set %s.", err.Path, TestDataPassphraseEnvVar)
+}
@@ -0,0 +1,184 @@
+package test_structure
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// FIXTURES_DIR_ENV_VAR is the environment variable that sets the folder in which RegisterFixture saves the artifacts
+// of each test run. It defaults to a terratest-fixtures folder in the system temp folder.
+const FIXTURES_DIR_ENV_VAR = "TERRATEST_FIXTURES_DIR"
+
+// FormatFixtureFolder returns the folder in which the artifacts of the test run with the given region and unique ID
+// are saved.
+func FormatFixtureFolder(region string, uniqueId string) string {
+	root := os.Getenv(FIXTURES_DIR_ENV_VAR)
+	if root == "" {
+		root = filepath.Join(os.TempDir(), "terratest-fixtures")
+	}
+	return filepath.Join(root, region, uniqueId)
+}
+
+// RegisterFixture saves the TerraformOptions of a test run under its region and unique ID (e.g., from
+// random.UniqueId), along with a copy of its TerraformDir and, once the module has been initialized, its Terraform
+// state, so that if the test fails to clean up after itself, everything it deployed can be destroyed later with
+// DestroyByUniqueId or the terratest_destroy command, even from another machine or after the TerraformDir is gone.
+// Call it after InitAndApply, so that the saved state covers the deployed resources (calling it again updates the
+// saved state), and call CleanupFixture once the test has destroyed its resources.
+func RegisterFixture(t testing.TestingT, region string, uniqueId string, terraformOptions *terraform.Options) {
+	require.NoError(t, RegisterFixtureE(t, region, uniqueId, terraformOptions))
+}
+
+// RegisterFixtureE saves the TerraformOptions of a test run under its region and unique ID, along with a copy of its
+// TerraformDir and, once the module has been initialized, its Terraform state. See RegisterFixture.
+func RegisterFixtureE(t testing.TestingT, region string, uniqueId string, terraformOptions *terraform.Options) error {
+	folder := FormatFixtureFolder(region, uniqueId)
+	SaveTerraformOptions(t, folder, terraformOptions)
+
+	if files.IsExistingDir(terraformOptions.TerraformDir) {
+		moduleFolder := formatFixtureModulePath(folder)
+		if err := os.RemoveAll(moduleFolder); err != nil {
+			return err
+		}
+		if err := copyFixtureModule(terraformOptions.TerraformDir, moduleFolder); err != nil {
+			return err
+		}
+	}
+
+	if files.IsExistingDir(filepath.Join(terraformOptions.TerraformDir, ".terraform")) {
+		if err := SaveTerraformStateE(t, folder, terraformOptions); err != nil {
+			return err
+		}
+	}
+
+	logger.Logf(t, "Registered fixture %s in region %s. If this test leaks resources, destroy them with: terratest_destroy %s %s", uniqueId, region, region, uniqueId)
+	return nil
+}
+
+// CleanupFixture removes the artifacts saved by RegisterFixture for the given region and unique ID.
+func CleanupFixture(t testing.TestingT, region string, uniqueId string) {
+	require.NoError(t, CleanupFixtureE(t, region, uniqueId))
+}
+
+// CleanupFixtureE removes the artifacts saved by RegisterFixture for the given region and unique ID.
+func CleanupFixtureE(t testing.TestingT, region string, uniqueId string) error {
+	folder := FormatFixtureFolder(region, uniqueId)
+	logger.Logf(t, "Cleaning up fixture artifacts in %s", folder)
+	return os.RemoveAll(folder)
+}
+
+// DestroyByUniqueId loads the TerraformOptions that RegisterFixture saved for the given region and unique ID, runs
+// terraform destroy with them, and then removes the saved artifacts. If there's any error, fail the test.
+func DestroyByUniqueId(t testing.TestingT, region string, uniqueId string) {
+	require.NoError(t, DestroyByUniqueIdE(t, region, uniqueId))
+}
+
+// DestroyByUniqueIdE loads the TerraformOptions that RegisterFixture saved for the given region and unique ID, runs
+// terraform destroy with them, and then removes the saved artifacts. If RegisterFixture saved the state, destroy runs
+// in a fresh copy of the saved module, into which the saved state is restored, unless its backend already has a state
+// (e.g., a remote backend). Otherwise, the state is read from the backend configured in the saved TerraformDir, which
+// must still exist. Note that local modules outside of the TerraformDir (e.g., source = "../modules/vpc") aren't part of
+// the saved copy, so they must still be at the same relative paths.
+func DestroyByUniqueIdE(t testing.TestingT, region string, uniqueId string) error {
+	folder := FormatFixtureFolder(region, uniqueId)
+	path := formatTerraformOptionsPath(folder)
+	if !files.FileExists(path) {
+		return FixtureNotFound{Region: region, UniqueId: uniqueId, Path: path}
+	}
+
+	bytes, err := readTestDataFile(path)
+	if err != nil {
+		return err
+	}
+	var terraformOptions terraform.Options
+	if err := json.Unmarshal(bytes, &terraformOptions); err != nil {
+		return err
+	}
+
+	// Make sure the AWS provider targets the region the fixture was deployed to, unless the test set it explicitly
+	if terraformOptions.EnvVars == nil {
+		terraformOptions.EnvVars = map[string]string{}
+	}
+	if _, hasRegion := terraformOptions.EnvVars["AWS_DEFAULT_REGION"]; !hasRegion {
+		terraformOptions.EnvVars["AWS_DEFAULT_REGION"] = region
+	}
+
+	moduleFolder := formatFixtureModulePath(folder)
+	if IsTerraformStateSaved(t, folder) && files.IsExistingDir(moduleFolder) {
+		tmpFolder, err := ioutil.TempDir("", "terratest-destroy-"+uniqueId+"-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpFolder)
+
+		if err := files.CopyFolderContents(moduleFolder, tmpFolder); err != nil {
+			return err
+		}
+		terraformOptions.TerraformDir = tmpFolder
+
+		if err := restoreFixtureStateE(t, folder, &terraformOptions); err != nil {
+			return err
+		}
+	} else if !files.IsExistingDir(terraformOptions.TerraformDir) {
+		return FixtureTerraformDirNotFound{UniqueId: uniqueId, TerraformDir: terraformOptions.TerraformDir}
+	}
+
+	logger.Logf(t, "Destroying fixture %s in region %s from %s", uniqueId, region, terraformOptions.TerraformDir)
+	if _, err := terraform.DestroyE(t, &terraformOptions); err != nil {
+		return err
+	}
+
+	return CleanupFixtureE(t, region, uniqueId)
+}
+
+// restoreFixtureStateE initializes the Terraform module in terraformOptions and restores the state saved in the given
+// fixture folder into it, unless its backend already has a state.
+func restoreFixtureStateE(t testing.TestingT, folder string, terraformOptions *terraform.Options) error {
+	if _, err := terraform.InitE(t, terraformOptions); err != nil {
+		return err
+	}
+
+	state, err := terraform.StatePullE(t, terraformOptions)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(state) != "" {
+		logger.Logf(t, "Using the Terraform state in the backend of %s rather than the saved state", terraformOptions.TerraformDir)
+		return nil
+	}
+
+	return RestoreTerraformStateE(t, folder, terraformOptions)
+}
+
+// copyFixtureModule copies the Terraform files in the given TerraformDir to the given folder, leaving out the state and
+// hidden files and folders such as .terraform, except for the dependency lock file.
+func copyFixtureModule(terraformDir string, moduleFolder string) error {
+	if err := os.MkdirAll(moduleFolder, 0777); err != nil {
+		return err
+	}
+	return files.CopyFolderContentsWithFilter(terraformDir, moduleFolder, func(path string) bool {
+		relPath, err := filepath.Rel(terraformDir, path)
+		if err != nil {
+			return false
+		}
+		if filepath.Base(relPath) == ".terraform.lock.hcl" {
+			return true
+		}
+		return !files.PathContainsHiddenFileOrFolder(relPath) && !files.PathContainsTerraformState(relPath)
+	})
+}
+
+// formatFixtureModulePath formats the path of the copy of the TerraformDir that RegisterFixture saves in the given
+// fixture folder.
+func formatFixtureModulePath(folder string) string {
+	return filepath.Join(folder, "module")
+}
@@ -0,0 +1,73 @@
+package test_structure
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestroyByUniqueId(t *testing.T) {
+	t.Parallel()
+
+	region := "us-east-1"
+	uniqueId := random.UniqueId()
+
+	terraformOptions := &terraform.Options{
+		TerraformDir: CopyTerraformFolderToTemp(t, "../../", "test/fixtures/terraform-null"),
+	}
+	RegisterFixture(t, region, uniqueId, terraformOptions)
+	defer CleanupFixture(t, region, uniqueId)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	DestroyByUniqueId(t, region, uniqueId)
+	assert.False(t, files.FileExists(FormatFixtureFolder(region, uniqueId)))
+}
+
+func TestDestroyByUniqueIdAfterTerraformDirIsGone(t *testing.T) {
+	t.Parallel()
+
+	region := "us-east-1"
+	uniqueId := random.UniqueId()
+
+	terraformOptions := &terraform.Options{
+		TerraformDir: CopyTerraformFolderToTemp(t, "../../", "test/fixtures/terraform-null"),
+	}
+	terraform.InitAndApply(t, terraformOptions)
+	RegisterFixture(t, region, uniqueId, terraformOptions)
+	defer CleanupFixture(t, region, uniqueId)
+	assert.True(t, IsTerraformStateSaved(t, FormatFixtureFolder(region, uniqueId)))
+
+	// Simulate a cleaned up CI workspace, which takes the local state with it
+	require.NoError(t, os.RemoveAll(terraformOptions.TerraformDir))
+
+	DestroyByUniqueId(t, region, uniqueId)
+	assert.False(t, files.FileExists(FormatFixtureFolder(region, uniqueId)))
+}
+
+func TestDestroyByUniqueIdNotRegistered(t *testing.T) {
+	t.Parallel()
+
+	err := DestroyByUniqueIdE(t, "us-east-1", random.UniqueId())
+	_, isNotFoundErr := err.(FixtureNotFound)
+	assert.True(t, isNotFoundErr, "unexpected error, got %v", err)
+}
+
+func TestDestroyByUniqueIdMissingTerraformDir(t *testing.T) {
+	t.Parallel()
+
+	region := "us-east-1"
+	uniqueId := random.UniqueId()
+
+	RegisterFixture(t, region, uniqueId, &terraform.Options{TerraformDir: "/folder/that/does/not/exist"})
+	defer CleanupFixture(t, region, uniqueId)
+
+	err := DestroyByUniqueIdE(t, region, uniqueId)
+	require.Error(t, err)
+	assert.Equal(t, FixtureTerraformDirNotFound{UniqueId: uniqueId, TerraformDir: "/folder/that/does/not/exist"}, err)
+}
@@ -24,6 +24,12 @@ func SaveTerraformOptions(t testing.TestingT, testFolder string, terraformOption
 	SaveTestData(t, formatTerraformOptionsPath(testFolder), terraformOptions)
 }
 
+// SaveTerraformOptionsE serializes and saves TerraformOptions into the given folder. This allows you to create TerraformOptions during setup
+// and to reuse that TerraformOptions later during validation and teardown.
+func SaveTerraformOptionsE(t testing.TestingT, testFolder string, terraformOptions *terraform.Options) error {
+	return SaveTestDataE(t, formatTerraformOptionsPath(testFolder), terraformOptions)
+}
+
 // LoadTerraformOptions loads and unserializes TerraformOptions from the given folder. This allows you to reuse a TerraformOptions that was
 // created during an earlier setup step in later validation and teardown steps.
 func LoadTerraformOptions(t testing.TestingT, testFolder string) *terraform.Options {
@@ -32,11 +38,102 @@ func LoadTerraformOptions(t testing.TestingT, testFolder string) *terraform.Opti
 	return &terraformOptions
 }
 
+// LoadTerraformOptionsE loads and unserializes TerraformOptions from the given folder. This allows you to reuse a TerraformOptions that was
+// created during an earlier setup step in later validation and teardown steps.
+func LoadTerraformOptionsE(t testing.TestingT, testFolder string) (*terraform.Options, error) {
+	var terraformOptions terraform.Options
+	if err := LoadTestDataE(t, formatTerraformOptionsPath(testFolder), &terraformOptions); err != nil {
+		return nil, err
+	}
+	return &terraformOptions, nil
+}
+
 // formatTerraformOptionsPath formats a path to save TerraformOptions in the given folder.
 func formatTerraformOptionsPath(testFolder string) string {
 	return FormatTestDataPath(testFolder, "TerraformOptions.json")
 }
 
+// SaveTerraformState pulls the current state of the Terraform module in terraformOptions (from either a local or a
+// remote backend) and saves it into the given folder. Together with RestoreTerraformState, this allows you to apply
+// once and then iterate on your validation code against the same resources, without re-provisioning them each time.
+func SaveTerraformState(t testing.TestingT, testFolder string, terraformOptions *terraform.Options) {
+	require.NoError(t, SaveTerraformStateE(t, testFolder, terraformOptions))
+}
+
+// SaveTerraformStateE pulls the current state of the Terraform module in terraformOptions (from either a local or a
+// remote backend) and saves it into the given folder.
+func SaveTerraformStateE(t testing.TestingT, testFolder string, terraformOptions *terraform.Options) error {
+	path := formatTerraformStatePath(testFolder)
+	logger.Logf(t, "Storing Terraform state in %s so it can be restored later", path)
+
+	state, err := terraform.StatePullE(t, terraformOptions)
+	if err != nil {
+		return err
+	}
+
+	parentDir := filepath.Dir(path)
+	if err := os.MkdirAll(parentDir, 0777); err != nil {
+		return fmt.Errorf("Failed to create folder %s: %v", parentDir, err)
+	}
+
+	// The state can contain secrets, so encrypt it like the rest of the test data
+	encoded, err := encodeTestData([]byte(state))
+	if err != nil {
+		return fmt.Errorf("Failed to encrypt Terraform state: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, encoded, 0600); err != nil {
+		return fmt.Errorf("Failed to save Terraform state to %s: %v", path, err)
+	}
+	return nil
+}
+
+// RestoreTerraformState pushes the Terraform state saved by SaveTerraformState in the given folder to the backend of
+// the Terraform module in terraformOptions, which must already be initialized.
+func RestoreTerraformState(t testing.TestingT, testFolder string, terraformOptions *terraform.Options) {
+	require.NoError(t, RestoreTerraformStateE(t, testFolder, terraformOptions))
+}
+
+// RestoreTerraformStateE pushes the Terraform state saved by SaveTerraformState in the given folder to the backend of
+// the Terraform module in terraformOptions, which must already be initialized.
+func RestoreTerraformStateE(t testing.TestingT, testFolder string, terraformOptions *terraform.Options) error {
+	path := formatTerraformStatePath(testFolder)
+	if !IsTerraformStateSaved(t, testFolder) {
+		return fmt.Errorf("No Terraform state found at %s. Call SaveTerraformState first.", path)
+	}
+
+	state, err := readTestDataFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to load Terraform state from %s: %v", path, err)
+	}
+
+	// terraform state push needs the plain state in a file
+	stateFile, err := ioutil.TempFile("", "terratest-state-*.tfstate")
+	if err != nil {
+		return fmt.Errorf("Failed to create a temp file for the Terraform state: %v", err)
+	}
+	defer os.Remove(stateFile.Name())
+	_, err = stateFile.Write(state)
+	stateFile.Close()
+	if err != nil {
+		return fmt.Errorf("Failed to write the Terraform state to %s: %v", stateFile.Name(), err)
+	}
+
+	logger.Logf(t, "Restoring Terraform state from %s", path)
+	_, err = terraform.StatePushE(t, terraformOptions, stateFile.Name())
+	return err
+}
+
+// IsTerraformStateSaved returns true if SaveTerraformState has saved a Terraform state in the given folder.
+func IsTerraformStateSaved(t testing.TestingT, testFolder string) bool {
+	return files.FileExists(formatTerraformStatePath(testFolder))
+}
+
+// formatTerraformStatePath formats a path to save the Terraform state in the given folder.
+func formatTerraformStatePath(testFolder string) string {
+	return FormatTestDataPath(testFolder, "terraform.tfstate")
+}
+
 // SavePackerOptions serializes and saves PackerOptions into the given folder. This allows you to create PackerOptions during setup
 // and to reuse that PackerOptions later during validation and teardown.
 func SavePackerOptions(t testing.TestingT, testFolder string, packerOptions *packer.Options) {
@@ -183,113 +280,175 @@ func FormatTestDataPath(testFolder string, filename string) string {
 }
 
 // SaveTestData serializes and saves a value used at test time to the given path. This allows you to create some sort of test data
-// (e.g., TerraformOptions) during setup and to reuse this data later during validation and teardown.
+// (e.g., TerraformOptions) during setup and to reuse this data later during validation and teardown. If an Encryptor
+// is set (see SetTestDataEncryptor), the data is encrypted, and isn't logged.
 func SaveTestData(t testing.TestingT, path string, value interface{}) {
+	if err := SaveTestDataE(t, path, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// SaveTestDataE serializes and saves a value used at test time to the given path. This allows you to create some sort of test data
+// (e.g., TerraformOptions) during setup and to reuse this data later during validation and teardown. If an Encryptor
+// is set (see SetTestDataEncryptor), the data is encrypted, and isn't logged.
+func SaveTestDataE(t testing.TestingT, path string, value interface{}) error {
 	logger.Logf(t, "Storing test data in %s so it can be reused later", path)
 
-	if IsTestDataPresent(t, path) {
-		logger.Logf(t, "[WARNING] The named test data at path %s is non-empty. Save operation will overwrite existing value with \"%v\".\n.", path, value)
+	encrypt := getTestDataEncryptor() != nil
+	present, err := IsTestDataPresentE(t, path)
+	if err != nil {
+		return err
+	}
+	if present {
+		if encrypt {
+			logger.Logf(t, "[WARNING] The named test data at path %s is non-empty. Save operation will overwrite existing value.", path)
+		} else {
+			logger.Logf(t, "[WARNING] The named test data at path %s is non-empty. Save operation will overwrite existing value with \"%v\".\n.", path, value)
+		}
 	}
 
 	bytes, err := json.Marshal(value)
 	if err != nil {
-		t.Fatalf("Failed to convert value %s to JSON: %v", path, err)
+		return fmt.Errorf("Failed to convert value %s to JSON: %v", path, err)
 	}
 
-	logger.Logf(t, "Marshalled JSON: %s", string(bytes))
+	if !encrypt {
+		logger.Logf(t, "Marshalled JSON: %s", string(bytes))
+	}
+
+	bytes, err = encodeTestData(bytes)
+	if err != nil {
+		return fmt.Errorf("Failed to encrypt value %s: %v", path, err)
+	}
 
 	parentDir := filepath.Dir(path)
 	if err := os.MkdirAll(parentDir, 0777); err != nil {
-		t.Fatalf("Failed to create folder %s: %v", parentDir, err)
+		return fmt.Errorf("Failed to create folder %s: %v", parentDir, err)
 	}
 
 	if err := ioutil.WriteFile(path, bytes, 0644); err != nil {
-		t.Fatalf("Failed to save value %s: %v", path, err)
+		return fmt.Errorf("Failed to save value %s: %v", path, err)
 	}
+
+	return nil
 }
 
 // LoadTestData loads and unserializes a value stored at the given path. The value should be a pointer to a struct into which the
 // value will be deserialized. This allows you to reuse some sort of test data (e.g., TerraformOptions) from earlier
 // setup steps in later validation and teardown steps.
 func LoadTestData(t testing.TestingT, path string, value interface{}) {
+	if err := LoadTestDataE(t, path, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// LoadTestDataE loads and unserializes a value stored at the given path. The value should be a pointer to a struct into which the
+// value will be deserialized. This allows you to reuse some sort of test data (e.g., TerraformOptions) from earlier
+// setup steps in later validation and teardown steps.
+func LoadTestDataE(t testing.TestingT, path string, value interface{}) error {
 	logger.Logf(t, "Loading test data from %s", path)
 
-	bytes, err := ioutil.ReadFile(path)
+	bytes, err := readTestDataFile(path)
 	if err != nil {
-		t.Fatalf("Failed to load value from %s: %v", path, err)
+		return fmt.Errorf("Failed to load value from %s: %v", path, err)
 	}
 
 	if err := json.Unmarshal(bytes, value); err != nil {
-		t.Fatalf("Failed to parse JSON for value %s: %v", path, err)
+		return fmt.Errorf("Failed to parse JSON for value %s: %v", path, err)
 	}
+
+	return nil
 }
 
 // IsTestDataPresent returns true if a file exists at $path and the test data there is non-empty.
 func IsTestDataPresent(t testing.TestingT, path string) bool {
+	present, err := IsTestDataPresentE(t, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return present
+}
+
+// IsTestDataPresentE returns true if a file exists at $path and the test data there is non-empty.
+func IsTestDataPresentE(t testing.TestingT, path string) (bool, error) {
 	exists, err := files.FileExistsE(path)
 	if err != nil {
-		t.Fatalf("Failed to load test data from %s due to unexpected error: %v", path, err)
+		return false, fmt.Errorf("Failed to load test data from %s due to unexpected error: %v", path, err)
 	}
 	if !exists {
-		return false
+		return false, nil
 	}
 
-	bytes, err := ioutil.ReadFile(path)
-
+	bytes, err := readTestDataFile(path)
+	if _, isEncrypted := err.(TestDataEncrypted); isEncrypted {
+		// Without the Encryptor the data can't be checked, so assume it isn't empty
+		return true, nil
+	}
 	if err != nil {
-		t.Fatalf("Failed to load test data from %s due to unexpected error: %v", path, err)
+		return false, fmt.Errorf("Failed to load test data from %s due to unexpected error: %v", path, err)
 	}
 
-	if isEmptyJSON(t, bytes) {
-		return false
+	isEmpty, err := isEmptyJSONE(bytes)
+	if err != nil {
+		return false, err
 	}
 
-	return true
+	return !isEmpty, nil
 }
 
 // isEmptyJSON returns true if the given bytes are empty, or in a valid JSON format that can reasonably be considered empty.
-// The types used are based on the type possibilities listed at https://golang.org/src/encoding/json/decode.go?s=4062:4110#L51
+// This will fail the test if the bytes are not valid JSON.
 func isEmptyJSON(t testing.TestingT, bytes []byte) bool {
+	isEmpty, err := isEmptyJSONE(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return isEmpty
+}
+
+// isEmptyJSONE returns true if the given bytes are empty, or in a valid JSON format that can reasonably be considered empty.
+// The types used are based on the type possibilities listed at https://golang.org/src/encoding/json/decode.go?s=4062:4110#L51
+func isEmptyJSONE(bytes []byte) (bool, error) {
 	var value interface{}
 
 	if len(bytes) == 0 {
-		return true
+		return true, nil
 	}
 
 	if err := json.Unmarshal(bytes, &value); err != nil {
-		t.Fatalf("Failed to parse JSON while testing whether it is empty: %v", err)
+		return false, fmt.Errorf("Failed to parse JSON while testing whether it is empty: %v", err)
 	}
 
 	if value == nil {
-		return true
+		return true, nil
 	}
 
 	valueBool, ok := value.(bool)
 	if ok && !valueBool {
-		return true
+		return true, nil
 	}
 
 	valueFloat64, ok := value.(float64)
 	if ok && valueFloat64 == 0 {
-		return true
+		return true, nil
 	}
 
 	valueString, ok := value.(string)
 	if ok && valueString == "" {
-		return true
+		return true, nil
 	}
 
 	valueSlice, ok := value.([]interface{})
 	if ok && len(valueSlice) == 0 {
-		return true
+		return true, nil
 	}
 
 	valueMap, ok := value.(map[string]interface{})
 	if ok && len(valueMap) == 0 {
-		return true
+		return true, nil
 	}
 
-	return false
+	return false, nil
 }
 
 // CleanupTestData cleans up the test data at the given path.
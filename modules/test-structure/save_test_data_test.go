@@ -49,6 +49,31 @@ func TestSaveAndLoadTestData(t *testing.T) {
 	assert.False(t, files.FileExists(tmpFile.Name()))
 }
 
+func TestLoadTestDataEMissingFile(t *testing.T) {
+	t.Parallel()
+
+	actualData := testData{}
+	err := LoadTestDataE(t, "/file/that/does/not/exist", &actualData)
+	assert.Error(t, err)
+}
+
+func TestIsTestDataPresentEInvalidJson(t *testing.T) {
+	t.Parallel()
+
+	tmpFile, err := ioutil.TempFile("", "is-test-data-present-invalid-json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer CleanupTestData(t, tmpFile.Name())
+
+	if err := ioutil.WriteFile(tmpFile.Name(), []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+
+	_, err = IsTestDataPresentE(t, tmpFile.Name())
+	assert.Error(t, err)
+}
+
 func TestIsEmptyJson(t *testing.T) {
 	t.Parallel()
 
@@ -107,6 +132,27 @@ func TestSaveAndLoadTerraformOptions(t *testing.T) {
 	assert.Equal(t, expectedData, actualData)
 }
 
+func TestSaveAndRestoreTerraformState(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+	assert.False(t, IsTerraformStateSaved(t, tmpFolder))
+
+	appliedOptions := &terraform.Options{
+		TerraformDir: CopyTerraformFolderToTemp(t, "../../", "test/fixtures/terraform-output"),
+	}
+	terraform.InitAndApply(t, appliedOptions)
+	SaveTerraformState(t, tmpFolder, appliedOptions)
+	assert.True(t, IsTerraformStateSaved(t, tmpFolder))
+
+	restoredOptions := &terraform.Options{
+		TerraformDir: CopyTerraformFolderToTemp(t, "../../", "test/fixtures/terraform-output"),
+	}
+	terraform.Init(t, restoredOptions)
+	RestoreTerraformState(t, tmpFolder, restoredOptions)
+	assert.Equal(t, "This is a string.", terraform.Output(t, restoredOptions, "string"))
+}
+
 func TestSaveAndLoadAmiId(t *testing.T) {
 	t.Parallel()
 
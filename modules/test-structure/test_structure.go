@@ -31,6 +31,17 @@ func RunTestStage(t testing.TestingT, stageName string, stage func()) {
 	}
 }
 
+// RunTeardownStage executes the given teardown stage, which destroys the resources created with the given
+// terraformOptions, like RunTestStage. If the stage is skipped, a notification that the resources are not destroyed is
+// sent, if terraformOptions.LeakNotification is set.
+func RunTeardownStage(t testing.TestingT, stageName string, terraformOptions *terraform.Options, stage func()) {
+	envVarName := fmt.Sprintf("%s%s", SKIP_STAGE_ENV_VAR_PREFIX, stageName)
+	if os.Getenv(envVarName) != "" {
+		terraform.NotifyDestroySkipped(t, terraformOptions)
+	}
+	RunTestStage(t, stageName, stage)
+}
+
 // SkipStageEnvVarSet returns true if an environment variable is set instructing Terratest to skip a test stage. This can be an easy way
 // to tell if the tests are running in a local dev environment vs a CI server.
 func SkipStageEnvVarSet() bool {
@@ -132,11 +143,15 @@ func CopyTerraformFolderToDest(t testing.TestingT, rootFolder string, terraformM
 	return tmpTestFolder
 }
 
+// cleanName returns the last part of the given (sub)test name, with any characters that are not allowed in file names
+// on Windows replaced, so it can be used as a folder name on every OS.
 func cleanName(originalName string) string {
 	parts := strings.Split(originalName, "/")
-	return parts[len(parts)-1]
+	return invalidFileNameCharacters.Replace(parts[len(parts)-1])
 }
 
+var invalidFileNameCharacters = strings.NewReplacer(`<`, "_", `>`, "_", `:`, "_", `"`, "_", `\`, "_", `|`, "_", `?`, "_", `*`, "_")
+
 // ValidateAllTerraformModules automatically finds all folders specified in RootDir that contain .tf files and runs
 // InitAndValidate in all of them.
 // Filters down to only those paths passed in ValidationOptions.IncludeDirs, if passed.
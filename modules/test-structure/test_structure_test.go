@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/notify"
+	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +24,14 @@ func TestCopySubtestToTempFolder(t *testing.T) {
 	})
 }
 
+func TestCleanName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "TestFoo", cleanName("TestFoo"))
+	assert.Equal(t, "Subtest", cleanName("TestFoo/Subtest"))
+	assert.Equal(t, "region_us-east-1_size_t3.micro_", cleanName(`TestFoo/region:us-east-1|size"t3.micro"`))
+}
+
 // TestValidateAllTerraformModulesSucceedsOnValidTerraform points at a simple text fixture Terraform module that is
 // known to be valid
 func TestValidateAllTerraformModulesSucceedsOnValidTerraform(t *testing.T) {
@@ -144,3 +154,34 @@ func TestValidateTerragruntModulesOnTerratestByInclusion(t *testing.T) {
 
 	ValidateAllTerraformModules(t, opts)
 }
+
+type fakeNotifier struct {
+	leaks []notify.Leak
+}
+
+func (notifier *fakeNotifier) NotifyLeak(leak notify.Leak) error {
+	notifier.leaks = append(notifier.leaks, leak)
+	return nil
+}
+
+func TestRunTeardownStageNotifiesWhenSkipped(t *testing.T) {
+	// DO NOT ADD THIS: t.Parallel()
+	// This test sets an environment variable.
+
+	notifier := &fakeNotifier{}
+	options := &terraform.Options{TerraformDir: "/tmp/vpc", LeakNotification: &terraform.LeakNotification{Notifier: notifier}}
+
+	ran := false
+	RunTeardownStage(t, "teardown_notify_test", options, func() { ran = true })
+	assert.True(t, ran)
+	assert.Empty(t, notifier.leaks)
+
+	os.Setenv("SKIP_teardown_notify_test", "true")
+	defer os.Unsetenv("SKIP_teardown_notify_test")
+
+	ran = false
+	RunTeardownStage(t, "teardown_notify_test", options, func() { ran = true })
+	assert.False(t, ran)
+	require.Len(t, notifier.leaks, 1)
+	assert.Equal(t, "destroy skipped", notifier.leaks[0].Reason)
+}
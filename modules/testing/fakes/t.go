@@ -0,0 +1,99 @@
+// Package fakes contains in-memory fakes of the things Terratest talks to, so that code built on top of Terratest
+// (e.g., the helpers shared by a test suite) can be unit tested offline, without running Terraform or having any cloud
+// credentials.
+package fakes
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// T is an in-memory implementation of testing.TestingT that records failures instead of reporting them to go test, so
+// you can check that your code fails the test when it should. Like testing.T, FailNow (and so Fatal and Fatalf) stops
+// the goroutine that calls it, so call the code under test with Run.
+type T struct {
+	name     string
+	mutex    sync.Mutex
+	failed   bool
+	messages []string
+}
+
+// NewT creates a T with the given test name.
+func NewT(name string) *T {
+	return &T{name: name}
+}
+
+// Run calls f with t in a new goroutine and waits for it to return, or to be stopped by FailNow. It returns true if
+// t didn't fail.
+func (t *T) Run(f func(t *T)) bool {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(t)
+	}()
+	<-done
+	return !t.Failed()
+}
+
+// Failed returns true if the test was marked as failed.
+func (t *T) Failed() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.failed
+}
+
+// Messages returns the messages passed to Error, Errorf, Fatal, and Fatalf, in order.
+func (t *T) Messages() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]string{}, t.messages...)
+}
+
+// Fail marks the test as failed but continues execution.
+func (t *T) Fail() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.failed = true
+}
+
+// FailNow marks the test as failed and stops the calling goroutine.
+func (t *T) FailNow() {
+	t.Fail()
+	runtime.Goexit()
+}
+
+// Fatal records the given message and calls FailNow.
+func (t *T) Fatal(args ...interface{}) {
+	t.record(fmt.Sprint(args...))
+	t.FailNow()
+}
+
+// Fatalf records the given formatted message and calls FailNow.
+func (t *T) Fatalf(format string, args ...interface{}) {
+	t.record(fmt.Sprintf(format, args...))
+	t.FailNow()
+}
+
+// Error records the given message and calls Fail.
+func (t *T) Error(args ...interface{}) {
+	t.record(fmt.Sprint(args...))
+	t.Fail()
+}
+
+// Errorf records the given formatted message and calls Fail.
+func (t *T) Errorf(format string, args ...interface{}) {
+	t.record(fmt.Sprintf(format, args...))
+	t.Fail()
+}
+
+// Name returns the name of the test.
+func (t *T) Name() string {
+	return t.name
+}
+
+func (t *T) record(message string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.messages = append(t.messages, message)
+}
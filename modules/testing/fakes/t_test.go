@@ -0,0 +1,39 @@
+package fakes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTFatalStopsExecution(t *testing.T) {
+	t.Parallel()
+
+	fakeT := NewT("TestFake")
+	reachedEnd := false
+	passed := fakeT.Run(func(fakeT *T) {
+		fakeT.Fatal(errors.New("boom"))
+		reachedEnd = true
+	})
+
+	assert.False(t, passed)
+	assert.False(t, reachedEnd)
+	assert.Equal(t, []string{"boom"}, fakeT.Messages())
+}
+
+func TestTWithNonErrorVariant(t *testing.T) {
+	t.Parallel()
+
+	runner := NewTerraformRunner().On("apply", Response{Output: "Error: Invalid reference", ExitCode: 1})
+	options := &terraform.Options{TerraformDir: ".", CommandRunner: runner}
+
+	fakeT := NewT("TestFake")
+	passed := fakeT.Run(func(fakeT *T) {
+		terraform.Apply(fakeT, options)
+	})
+
+	assert.False(t, passed)
+	assert.Equal(t, "TestFake", fakeT.Name())
+}
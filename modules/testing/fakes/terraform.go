@@ -0,0 +1,134 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// Response is the canned result of a command run by a TerraformRunner.
+type Response struct {
+	Output   string // The stdout and stderr of the command, interleaved
+	Stdout   string // The stdout of the command. Defaults to Output.
+	Stderr   string // The stderr of the command
+	ExitCode int    // If not zero, the command fails with an ExitError with this code
+}
+
+// ExitError is the error returned for a Response with a non-zero ExitCode.
+type ExitError struct {
+	Code int
+}
+
+func (err ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", err.Code)
+}
+
+// ExitCode returns the exit code of the command.
+func (err ExitError) ExitCode() int {
+	return err.Code
+}
+
+// TerraformRunner is a terraform.CommandRunner that returns canned responses instead of running Terraform. Set it as
+// the CommandRunner of the terraform.Options to unit test code that calls the terraform package, including its output
+// parsing and its retries on RetryableTerraformErrors:
+//
+//	runner := fakes.NewTerraformRunner().
+//		On("apply", fakes.Response{Output: "Error: RequestLimitExceeded", ExitCode: 1}, fakes.Response{Output: "Apply complete!"}).
+//		On("output", fakes.Response{Stdout: `{"url": {"value": "http://example.com"}}`})
+//	options := &terraform.Options{TerraformDir: ".", CommandRunner: runner}
+type TerraformRunner struct {
+	mutex     sync.Mutex
+	responses map[string][]Response
+	calls     [][]string
+}
+
+// NewTerraformRunner creates a TerraformRunner without any responses.
+func NewTerraformRunner() *TerraformRunner {
+	return &TerraformRunner{responses: map[string][]Response{}}
+}
+
+// On sets the responses to the Terraform subcommand with the given name (the first argument of the command, e.g.
+// "apply" or "output"). Each run of the subcommand returns the next response, and the last one is repeated once they
+// run out. Subcommands without responses succeed with no output.
+func (runner *TerraformRunner) On(subcommand string, responses ...Response) *TerraformRunner {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	runner.responses[subcommand] = responses
+	return runner
+}
+
+// Calls returns the arguments of every command that was run, in order.
+func (runner *TerraformRunner) Calls() [][]string {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	return append([][]string{}, runner.calls...)
+}
+
+// CallCount returns how many times the Terraform subcommand with the given name was run.
+func (runner *TerraformRunner) CallCount(subcommand string) int {
+	count := 0
+	for _, args := range runner.Calls() {
+		if len(args) > 0 && args[0] == subcommand {
+			count++
+		}
+	}
+	return count
+}
+
+// RunCommandAndGetOutputE implements terraform.CommandRunner.
+func (runner *TerraformRunner) RunCommandAndGetOutputE(ctx context.Context, t testing.TestingT, command shell.Command) (string, error) {
+	response := runner.run(command)
+	return response.Output, response.err()
+}
+
+// RunCommandAndGetStdOutE implements terraform.CommandRunner.
+func (runner *TerraformRunner) RunCommandAndGetStdOutE(ctx context.Context, t testing.TestingT, command shell.Command) (string, error) {
+	response := runner.run(command)
+	if response.Stdout == "" {
+		return response.Output, response.err()
+	}
+	return response.Stdout, response.err()
+}
+
+// RunCommandAndGetStdOutErrE implements terraform.CommandRunner.
+func (runner *TerraformRunner) RunCommandAndGetStdOutErrE(ctx context.Context, t testing.TestingT, command shell.Command) (string, string, error) {
+	response := runner.run(command)
+	if response.Stdout == "" {
+		return response.Output, response.Stderr, response.err()
+	}
+	return response.Stdout, response.Stderr, response.err()
+}
+
+// run records the given command and returns the next response for it.
+func (runner *TerraformRunner) run(command shell.Command) Response {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+
+	runner.calls = append(runner.calls, command.Args)
+	if len(command.Args) == 0 {
+		return Response{}
+	}
+
+	responses := runner.responses[command.Args[0]]
+	if len(responses) == 0 {
+		return Response{}
+	}
+	response := responses[0]
+	if len(responses) > 1 {
+		runner.responses[command.Args[0]] = responses[1:]
+	}
+	return response
+}
+
+func (response Response) err() error {
+	if response.ExitCode == 0 {
+		return nil
+	}
+	return ExitError{Code: response.ExitCode}
+}
+
+var _ terraform.CommandRunner = &TerraformRunner{}
@@ -0,0 +1,80 @@
+package fakes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformRunnerRetriesRetryableErrors(t *testing.T) {
+	t.Parallel()
+
+	runner := NewTerraformRunner().
+		On("apply", Response{Output: "Error: RequestLimitExceeded", ExitCode: 1}, Response{Output: "Apply complete!"})
+	options := &terraform.Options{
+		TerraformDir:             ".",
+		CommandRunner:            runner,
+		MaxRetries:               2,
+		TimeBetweenRetries:       time.Millisecond,
+		RetryableTerraformErrors: map[string]string{"RequestLimitExceeded": "Throttled by AWS"},
+	}
+
+	out, report, err := terraform.ApplyWithRetryReportE(t, options)
+	require.NoError(t, err)
+	assert.Equal(t, "Apply complete!", out)
+	assert.Equal(t, []string{"Throttled by AWS"}, report.MatchedMessages())
+	assert.Equal(t, 2, runner.CallCount("apply"))
+}
+
+func TestTerraformRunnerFailsOnOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	runner := NewTerraformRunner().On("apply", Response{Output: "Error: Invalid reference", ExitCode: 1})
+	options := &terraform.Options{
+		TerraformDir:             ".",
+		CommandRunner:            runner,
+		MaxRetries:               2,
+		RetryableTerraformErrors: map[string]string{"RequestLimitExceeded": "Throttled by AWS"},
+	}
+
+	_, err := terraform.ApplyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, 1, runner.CallCount("apply"))
+}
+
+func TestTerraformRunnerOutputParsing(t *testing.T) {
+	t.Parallel()
+
+	runner := NewTerraformRunner().On("output", Response{Stdout: `{"us-east-1": "vpc-123", "us-west-2": "vpc-456"}`})
+	options := &terraform.Options{TerraformDir: ".", CommandRunner: runner}
+
+	vpcs, err := terraform.OutputMapE(t, options, "vpc_ids")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"us-east-1": "vpc-123", "us-west-2": "vpc-456"}, vpcs)
+	assert.Equal(t, "vpc_ids", runner.Calls()[0][len(runner.Calls()[0])-1])
+}
+
+func TestTerraformRunnerExitCode(t *testing.T) {
+	t.Parallel()
+
+	runner := NewTerraformRunner().On("plan", Response{ExitCode: 2})
+	options := &terraform.Options{TerraformDir: ".", CommandRunner: runner}
+
+	exitCode, err := terraform.PlanExitCodeE(t, options)
+	require.NoError(t, err)
+	assert.Equal(t, 2, exitCode)
+}
+
+func TestTerraformRunnerStillValidatesOptions(t *testing.T) {
+	t.Parallel()
+
+	runner := NewTerraformRunner()
+	options := &terraform.Options{TerraformDir: "/folder/that/does/not/exist", CommandRunner: runner}
+
+	_, err := terraform.ApplyE(t, options)
+	require.Error(t, err)
+	assert.Empty(t, runner.Calls())
+}
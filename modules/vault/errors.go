@@ -0,0 +1,25 @@
+package vault
+
+import "fmt"
+
+// UnexpectedSealStatus is an error that occurs when a Vault server is not in the expected seal state.
+type UnexpectedSealStatus struct {
+	Addr          string
+	ExpectedState string
+	Status        SealStatus
+}
+
+func (err UnexpectedSealStatus) Error() string {
+	return fmt.Sprintf("Expected Vault server at %s to be %s, but initialized = %t and sealed = %t (unseal progress %d/%d)", err.Addr, err.ExpectedState, err.Status.Initialized, err.Status.Sealed, err.Status.Progress, err.Status.Threshold)
+}
+
+// UnexpectedResponse is an error that occurs when the Vault HTTP API returns a non-200 response.
+type UnexpectedResponse struct {
+	Url    string
+	Status int
+	Body   string
+}
+
+func (err UnexpectedResponse) Error() string {
+	return fmt.Sprintf("Unexpected response from %s. Response status: %d. Response body:\n%s", err.Url, err.Status, err.Body)
+}
@@ -0,0 +1,114 @@
+// Package vault allows to check the health of Vault clusters through the Vault HTTP API.
+package vault
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// SealStatus is the seal status of a Vault server, as returned by /v1/sys/seal-status.
+type SealStatus struct {
+	Type        string `json:"type"`
+	Initialized bool   `json:"initialized"`
+	Sealed      bool   `json:"sealed"`
+	Threshold   int    `json:"t"`
+	Shares      int    `json:"n"`
+	Progress    int    `json:"progress"`
+	Version     string `json:"version"`
+	ClusterName string `json:"cluster_name"`
+}
+
+// GetSealStatus returns the seal status of the Vault server at the given address (e.g. https://1.2.3.4:8200).
+func GetSealStatus(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config) *SealStatus {
+	status, err := GetSealStatusE(t, vaultAddr, tlsConfig)
+	require.NoError(t, err)
+	return status
+}
+
+// GetSealStatusE returns the seal status of the Vault server at the given address (e.g. https://1.2.3.4:8200).
+func GetSealStatusE(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config) (*SealStatus, error) {
+	url := fmt.Sprintf("%s/v1/sys/seal-status", vaultAddr)
+	statusCode, body, err := http_helper.HttpGetE(t, url, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != 200 {
+		return nil, UnexpectedResponse{Url: url, Status: statusCode, Body: body}
+	}
+
+	status := &SealStatus{}
+	if err := json.Unmarshal([]byte(body), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// WaitForInitialized waits until the Vault server at the given address is initialized, failing the test if that does
+// not happen after the given number of retries.
+func WaitForInitialized(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration) {
+	err := WaitForInitializedE(t, vaultAddr, tlsConfig, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForInitializedE waits until the Vault server at the given address is initialized, returning an error if that
+// does not happen after the given number of retries.
+func WaitForInitializedE(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration) error {
+	return waitForSealStatusE(t, vaultAddr, tlsConfig, "initialized", retries, sleepBetweenRetries, func(status *SealStatus) bool {
+		return status.Initialized
+	})
+}
+
+// WaitForUnsealed waits until the Vault server at the given address is initialized and unsealed, failing the test if
+// that does not happen after the given number of retries.
+func WaitForUnsealed(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration) {
+	err := WaitForUnsealedE(t, vaultAddr, tlsConfig, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForUnsealedE waits until the Vault server at the given address is initialized and unsealed, returning an error
+// if that does not happen after the given number of retries.
+func WaitForUnsealedE(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration) error {
+	return waitForSealStatusE(t, vaultAddr, tlsConfig, "unsealed", retries, sleepBetweenRetries, func(status *SealStatus) bool {
+		return status.Initialized && !status.Sealed
+	})
+}
+
+// WaitForSealed waits until the Vault server at the given address is sealed, failing the test if that does not happen
+// after the given number of retries.
+func WaitForSealed(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration) {
+	err := WaitForSealedE(t, vaultAddr, tlsConfig, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForSealedE waits until the Vault server at the given address is sealed, returning an error if that does not
+// happen after the given number of retries.
+func WaitForSealedE(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration) error {
+	return waitForSealStatusE(t, vaultAddr, tlsConfig, "sealed", retries, sleepBetweenRetries, func(status *SealStatus) bool {
+		return status.Sealed
+	})
+}
+
+func waitForSealStatusE(t testing.TestingT, vaultAddr string, tlsConfig *tls.Config, expectedState string, retries int, sleepBetweenRetries time.Duration, isExpectedState func(*SealStatus) bool) error {
+	description := fmt.Sprintf("Waiting for Vault server at %s to be %s", vaultAddr, expectedState)
+	_, err := retry.DoWithRetryE(t, description, retries, sleepBetweenRetries, func() (string, error) {
+		status, err := GetSealStatusE(t, vaultAddr, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+		if !isExpectedState(status) {
+			return "", UnexpectedSealStatus{Addr: vaultAddr, ExpectedState: expectedState, Status: *status}
+		}
+
+		logger.Logf(t, "Vault server at %s is %s", vaultAddr, expectedState)
+		return "", nil
+	})
+	return err
+}
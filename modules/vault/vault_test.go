@@ -0,0 +1,45 @@
+package vault
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeVaultServer(initialized bool, sealed bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"type": "shamir", "initialized": %t, "sealed": %t, "t": 3, "n": 5, "progress": 1, "version": "1.8.4"}`, initialized, sealed)
+	}))
+}
+
+func TestGetSealStatus(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeVaultServer(true, true)
+	defer server.Close()
+
+	status := GetSealStatus(t, server.URL, nil)
+	assert.True(t, status.Initialized)
+	assert.True(t, status.Sealed)
+	assert.Equal(t, 3, status.Threshold)
+	assert.Equal(t, 5, status.Shares)
+	assert.Equal(t, "1.8.4", status.Version)
+}
+
+func TestWaitForUnsealed(t *testing.T) {
+	t.Parallel()
+
+	unsealedServer := newFakeVaultServer(true, false)
+	defer unsealedServer.Close()
+	sealedServer := newFakeVaultServer(true, true)
+	defer sealedServer.Close()
+
+	assert.NoError(t, WaitForUnsealedE(t, unsealedServer.URL, nil, 1, time.Millisecond))
+	assert.Error(t, WaitForUnsealedE(t, sealedServer.URL, nil, 1, time.Millisecond))
+	assert.NoError(t, WaitForSealedE(t, sealedServer.URL, nil, 1, time.Millisecond))
+	assert.NoError(t, WaitForInitializedE(t, sealedServer.URL, nil, 1, time.Millisecond))
+}
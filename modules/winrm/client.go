@@ -0,0 +1,100 @@
+package winrm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/masterzen/winrm"
+)
+
+const (
+	// operationTimeout is how long the server waits for output before answering a Receive request with a timeout
+	// fault, in which case the request is simply sent again.
+	operationTimeout = 60 * time.Second
+
+	maxEnvelopeSize = 153600
+)
+
+// newClient returns a WinRM client for the given host. The client authenticates with NTLM if the host asks for it,
+// which is the default configuration of the WinRM service, and with Basic auth otherwise.
+func newClient(host Host) (*winrm.Client, error) {
+	// Leave room for the server to answer a Receive request just before the operation timeout
+	endpoint := winrm.NewEndpoint(host.Hostname, host.getPort(), host.UseHttps, host.Insecure, nil, nil, nil, operationTimeout+30*time.Second)
+
+	parameters := winrm.NewParameters(fmt.Sprintf("PT%dS", int(operationTimeout.Seconds())), "en-US", maxEnvelopeSize)
+	parameters.TransportDecorator = func() winrm.Transporter { return &winrm.ClientNTLM{} }
+
+	return winrm.NewClientWithParameters(endpoint, host.Username, host.Password, parameters)
+}
+
+// runCommand runs the given command line in a new cmd shell on the given host, writes the given chunks to its stdin
+// and then closes it, and returns the stdout, stderr, and exit code of the command.
+func runCommand(host Host, commandLine string, stdin [][]byte) (string, string, int, error) {
+	client, err := newClient(host)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	shell, err := client.CreateShell()
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	stdout, stderr, exitCode, err := runCommandInShell(shell, commandLine, stdin)
+	if closeErr := shell.Close(); err == nil {
+		err = closeErr
+	}
+	return stdout, stderr, exitCode, err
+}
+
+func runCommandInShell(shell *winrm.Shell, commandLine string, stdin [][]byte) (string, string, int, error) {
+	command, err := shell.Execute(commandLine)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	var stdoutErr, stderrErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, stdoutErr = io.Copy(&stdout, command.Stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		_, stderrErr = io.Copy(&stderr, command.Stderr)
+	}()
+
+	if err := writeInput(command, stdin); err != nil {
+		// Terminate the command, which may otherwise wait for the rest of its input forever. The output of a
+		// terminated command is never completed, so don't wait for it.
+		command.Close()
+		command.Wait()
+		return "", "", 0, err
+	}
+
+	command.Wait()
+	wg.Wait()
+
+	for _, err := range []error{stdoutErr, stderrErr} {
+		if err != nil {
+			return stdout.String(), stderr.String(), 0, err
+		}
+	}
+	return stdout.String(), stderr.String(), command.ExitCode(), nil
+}
+
+// writeInput writes each of the given chunks to the stdin of the given command in a request of its own, and then
+// closes stdin so that commands reading it don't wait forever.
+func writeInput(command *winrm.Command, stdin [][]byte) error {
+	for _, chunk := range stdin {
+		if _, err := command.Stdin.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return command.Stdin.Close()
+}
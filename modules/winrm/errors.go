@@ -0,0 +1,15 @@
+package winrm
+
+import "fmt"
+
+// CommandFailed is an error that occurs when a command exits with a non-zero exit code.
+type CommandFailed struct {
+	Command  string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+func (err CommandFailed) Error() string {
+	return fmt.Sprintf("Command %q exited with code %d.\nstdout:\n%s\nstderr:\n%s", err.Command, err.ExitCode, err.Stdout, err.Stderr)
+}
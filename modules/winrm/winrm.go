@@ -0,0 +1,227 @@
+// Package winrm allows to run commands and PowerShell scripts on, and copy files to and from, Windows hosts over
+// WinRM, the way the ssh package does for Linux hosts. It authenticates with NTLM, which the WinRM service accepts by
+// default, or with Basic auth if the host only offers that. Messages are not encrypted at the WinRM level, so either
+// set UseHttps, or allow unencrypted traffic over HTTP on the host, e.g. with:
+//
+//	winrm set winrm/config/service '@{AllowUnencrypted="true"}'
+package winrm
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	defaultHttpPort  = 5985
+	defaultHttpsPort = 5986
+
+	// copyChunkSize is the number of bytes of a file sent per request when copying it. Each chunk is base64 encoded
+	// twice, once as a line of input of the copy script and once more in the request, which keeps it at about 85 KiB,
+	// well within the maximum envelope size.
+	copyChunkSize = 48 * 1024
+)
+
+// Host is a remote host reachable over WinRM.
+type Host struct {
+	Hostname string
+	Port     int // Defaults to 5986 if UseHttps is set, and to 5985 otherwise
+	Username string
+	Password string
+	UseHttps bool
+	Insecure bool // Skip verification of the certificate of the host, e.g., for the self-signed certificate of a fresh AMI
+}
+
+func (host Host) getPort() int {
+	if host.Port != 0 {
+		return host.Port
+	}
+	if host.UseHttps {
+		return defaultHttpsPort
+	}
+	return defaultHttpPort
+}
+
+// CheckWinRmConnection checks that you can connect via WinRM to the given host and fail the test if the connection fails.
+func CheckWinRmConnection(t testing.TestingT, host Host) {
+	err := CheckWinRmConnectionE(t, host)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckWinRmConnectionE checks that you can connect via WinRM to the given host and return an error if the connection
+// fails.
+func CheckWinRmConnectionE(t testing.TestingT, host Host) error {
+	_, err := RunCommandE(t, host, "echo Hello, World")
+	return err
+}
+
+// CheckWinRmConnectionWithRetry attempts to connect via WinRM until the given number of retries is exceeded, and fails
+// the test if the connection never succeeds.
+func CheckWinRmConnectionWithRetry(t testing.TestingT, host Host, retries int, sleepBetweenRetries time.Duration) {
+	err := CheckWinRmConnectionWithRetryE(t, host, retries, sleepBetweenRetries)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckWinRmConnectionWithRetryE attempts to connect via WinRM until the given number of retries is exceeded, and
+// returns an error if the connection never succeeds.
+func CheckWinRmConnectionWithRetryE(t testing.TestingT, host Host, retries int, sleepBetweenRetries time.Duration) error {
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Checking WinRM connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
+		return "", CheckWinRmConnectionE(t, host)
+	})
+	return err
+}
+
+// RunCommand runs the given command with cmd.exe on the given host and returns its stdout. Fails the test if the
+// command exits with a non-zero exit code.
+func RunCommand(t testing.TestingT, host Host, command string) string {
+	out, err := RunCommandE(t, host, command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// RunCommandE runs the given command with cmd.exe on the given host and returns its stdout. Returns a CommandFailed
+// error if the command exits with a non-zero exit code.
+func RunCommandE(t testing.TestingT, host Host, command string) (string, error) {
+	logger.Logf(t, "Running command %s on %s over WinRM", command, host.Hostname)
+	return runCommandWithInput(host, command, command, nil)
+}
+
+// RunPowerShell runs the given PowerShell script on the given host and returns its stdout. Fails the test if the script
+// exits with a non-zero exit code.
+func RunPowerShell(t testing.TestingT, host Host, script string) string {
+	out, err := RunPowerShellE(t, host, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// RunPowerShellE runs the given PowerShell script on the given host and returns its stdout. Returns a CommandFailed
+// error if the script exits with a non-zero exit code.
+func RunPowerShellE(t testing.TestingT, host Host, script string) (string, error) {
+	logger.Logf(t, "Running PowerShell script %s on %s over WinRM", script, host.Hostname)
+	return runCommandWithInput(host, formatPowerShellCommand(script), script, nil)
+}
+
+// RunPowerShellWithRetry runs the given PowerShell script on the given host until it succeeds or the given number of
+// retries is exceeded, and returns its stdout. Fails the test if the script never succeeds.
+func RunPowerShellWithRetry(t testing.TestingT, host Host, script string, retries int, sleepBetweenRetries time.Duration) string {
+	out, err := RunPowerShellWithRetryE(t, host, script, retries, sleepBetweenRetries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// RunPowerShellWithRetryE runs the given PowerShell script on the given host until it succeeds or the given number of
+// retries is exceeded, and returns its stdout.
+func RunPowerShellWithRetryE(t testing.TestingT, host Host, script string, retries int, sleepBetweenRetries time.Duration) (string, error) {
+	return retry.DoWithRetryE(t, fmt.Sprintf("Running PowerShell script on %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
+		return RunPowerShellE(t, host, script)
+	})
+}
+
+// CopyFileTo writes the given contents to the file at the given path on the given host, replacing the file if it
+// already exists. Fails the test on error.
+func CopyFileTo(t testing.TestingT, host Host, remotePath string, contents []byte) {
+	err := CopyFileToE(t, host, remotePath, contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CopyFileToE writes the given contents to the file at the given path on the given host, replacing the file if it
+// already exists.
+func CopyFileToE(t testing.TestingT, host Host, remotePath string, contents []byte) error {
+	logger.Logf(t, "Copying %d bytes to %s on %s over WinRM", len(contents), remotePath, host.Hostname)
+
+	script := formatCopyScript(remotePath)
+	_, err := runCommandWithInput(host, formatPowerShellCommand(script), script, formatCopyInput(contents))
+	return err
+}
+
+// FetchContentsOfFile returns the contents of the file at the given path on the given host. Fails the test on error.
+func FetchContentsOfFile(t testing.TestingT, host Host, remotePath string) []byte {
+	contents, err := FetchContentsOfFileE(t, host, remotePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+// FetchContentsOfFileE returns the contents of the file at the given path on the given host.
+func FetchContentsOfFileE(t testing.TestingT, host Host, remotePath string) ([]byte, error) {
+	logger.Logf(t, "Fetching %s from %s over WinRM", remotePath, host.Hostname)
+
+	script := fmt.Sprintf("[Convert]::ToBase64String([IO.File]::ReadAllBytes(%s))", quotePowerShellString(remotePath))
+	out, err := runCommandWithInput(host, formatPowerShellCommand(script), script, nil)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(out))
+}
+
+// runCommandWithInput runs the given command line on the given host, writes the given chunks to its stdin, and returns
+// its stdout, using description to identify the command in errors.
+func runCommandWithInput(host Host, commandLine string, description string, stdin [][]byte) (string, error) {
+	stdout, stderr, exitCode, err := runCommand(host, commandLine, stdin)
+	if err != nil {
+		return stdout, err
+	}
+	if exitCode != 0 {
+		return stdout, CommandFailed{Command: description, ExitCode: exitCode, Stdout: stdout, Stderr: stderr}
+	}
+	return stdout, nil
+}
+
+// formatPowerShellCommand returns a command line that runs the given script with PowerShell. The script is passed
+// encoded, so it needs no escaping for cmd.exe.
+func formatPowerShellCommand(script string) string {
+	encoded := utf16.Encode([]rune(script))
+	scriptBytes := make([]byte, 0, len(encoded)*2)
+	for _, unit := range encoded {
+		scriptBytes = append(scriptBytes, byte(unit), byte(unit>>8))
+	}
+	return fmt.Sprintf("powershell.exe -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand %s", base64.StdEncoding.EncodeToString(scriptBytes))
+}
+
+// formatCopyScript returns a PowerShell script that writes the file at the given path from the base64 encoded lines
+// it reads from stdin, so that a file of any size is copied with a single command.
+func formatCopyScript(remotePath string) string {
+	return fmt.Sprintf("$file = [IO.File]::Create(%s); try { while (($line = [Console]::In.ReadLine()) -ne $null) { $bytes = [Convert]::FromBase64String($line); $file.Write($bytes, 0, $bytes.Length) } } finally { $file.Close() }", quotePowerShellString(remotePath))
+}
+
+// formatCopyInput returns the stdin of the script returned by formatCopyScript for the given contents, one base64
+// encoded line per chunk of copyChunkSize bytes.
+func formatCopyInput(contents []byte) [][]byte {
+	var lines [][]byte
+	for start := 0; start < len(contents); start += copyChunkSize {
+		chunk := base64.StdEncoding.EncodeToString(contents[start:min(start+copyChunkSize, len(contents))])
+		lines = append(lines, []byte(chunk+"\r\n"))
+	}
+	return lines
+}
+
+// quotePowerShellString returns the given value as a single-quoted PowerShell string literal.
+func quotePowerShellString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
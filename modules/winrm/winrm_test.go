@@ -0,0 +1,239 @@
+package winrm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	actionCreate  = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Create"
+	actionDelete  = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Delete"
+	actionCommand = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Command"
+	actionSend    = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Send"
+	actionReceive = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Receive"
+	actionSignal  = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Signal"
+
+	timeoutFault = `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd"><s:Body><s:Fault><s:Code><s:Value>s:Receiver</s:Value><s:Subcode><s:Value>w:TimedOut</s:Value></s:Subcode></s:Code><s:Reason><s:Text xml:lang="en-US">The WS-Management service cannot complete the operation within the time specified in OperationTimeout.</s:Text></s:Reason></s:Fault></s:Body></s:Envelope>`
+)
+
+var (
+	commandPattern  = regexp.MustCompile(`<rsp:Command><!\[CDATA\[(.*)\]\]></rsp:Command>`)
+	commandIdAttr   = regexp.MustCompile(`CommandId="([^"]+)"`)
+	streamPattern   = regexp.MustCompile(`<rsp:Stream[^>]*(?:/>|>([^<]*)</rsp:Stream>)`)
+	streamEndedAttr = regexp.MustCompile(`<rsp:Stream[^>]*End="true"`)
+)
+
+// fakeWinRmServer answers WS-Management requests, running each command with the given handler once its stdin is
+// closed. The handler gets the command line and stdin of the command, and returns its stdout and exit code.
+type fakeWinRmServer struct {
+	handler     func(commandLine string, stdin string) (string, int)
+	mutex       sync.Mutex
+	commands    map[string]*fakeCommand
+	timeouts    int  // The number of Receive requests to answer with a timeout fault before sending output
+	failDeletes bool // Whether to answer requests to delete a shell with an error
+	shells      int
+	deletions   int
+}
+
+type fakeCommand struct {
+	commandLine string
+	stdin       bytes.Buffer
+	stdinClosed bool
+}
+
+func (server *fakeWinRmServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != "Administrator" || password != "password" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	request := string(body)
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	switch {
+	case strings.Contains(request, actionCreate):
+		server.shells++
+		fmt.Fprintf(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd" xmlns:x="http://schemas.xmlsoap.org/ws/2004/09/transfer" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing"><s:Body><x:ResourceCreated><a:ReferenceParameters><w:SelectorSet><w:Selector Name="ShellId">shell-%d</w:Selector></w:SelectorSet></a:ReferenceParameters></x:ResourceCreated></s:Body></s:Envelope>`, server.shells)
+	case strings.Contains(request, actionCommand):
+		commandId := fmt.Sprintf("command-%d", len(server.commands))
+		server.commands[commandId] = &fakeCommand{commandLine: commandPattern.FindStringSubmatch(request)[1]}
+		fmt.Fprintf(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell"><s:Body><rsp:CommandResponse><rsp:CommandId>%s</rsp:CommandId></rsp:CommandResponse></s:Body></s:Envelope>`, commandId)
+	case strings.Contains(request, actionSend):
+		command := server.commands[commandIdAttr.FindStringSubmatch(request)[1]]
+		decoded, _ := base64.StdEncoding.DecodeString(streamPattern.FindStringSubmatch(request)[1])
+		command.stdin.Write(decoded)
+		command.stdinClosed = streamEndedAttr.MatchString(request)
+		fmt.Fprint(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body></s:Body></s:Envelope>`)
+	case strings.Contains(request, actionReceive):
+		commandId := commandIdAttr.FindStringSubmatch(request)[1]
+		command := server.commands[commandId]
+		if server.timeouts > 0 || !command.stdinClosed {
+			if command.stdinClosed {
+				server.timeouts--
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, timeoutFault)
+			return
+		}
+		stdout, exitCode := server.handler(command.commandLine, command.stdin.String())
+		fmt.Fprintf(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell"><s:Body><rsp:ReceiveResponse><rsp:Stream Name="stdout" CommandId="%s">%s</rsp:Stream><rsp:Stream Name="stdout" CommandId="%s" End="true"></rsp:Stream><rsp:Stream Name="stderr" CommandId="%s" End="true"></rsp:Stream><rsp:CommandState CommandId="%s" State="http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/Done"><rsp:ExitCode>%d</rsp:ExitCode></rsp:CommandState></rsp:ReceiveResponse></s:Body></s:Envelope>`,
+			commandId, base64.StdEncoding.EncodeToString([]byte(stdout)), commandId, commandId, commandId, exitCode)
+	case strings.Contains(request, actionSignal):
+		fmt.Fprint(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body></s:Body></s:Envelope>`)
+	case strings.Contains(request, actionDelete):
+		server.deletions++
+		if server.failDeletes {
+			http.Error(w, "the shell could not be deleted", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body></s:Body></s:Envelope>`)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func startFakeWinRmServer(t *testing.T, handler func(commandLine string, stdin string) (string, int)) (*fakeWinRmServer, Host) {
+	server := &fakeWinRmServer{handler: handler, commands: map[string]*fakeCommand{}}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	hostname, port, err := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return server, Host{Hostname: hostname, Port: portNumber, Username: "Administrator", Password: "password"}
+}
+
+// decodePowerShellCommand returns the script of a command line created by formatPowerShellCommand.
+func decodePowerShellCommand(t *testing.T, commandLine string) string {
+	encoded := commandLine[strings.LastIndex(commandLine, " ")+1:]
+	scriptBytes, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+
+	units := make([]uint16, len(scriptBytes)/2)
+	for i := range units {
+		units[i] = uint16(scriptBytes[2*i]) | uint16(scriptBytes[2*i+1])<<8
+	}
+	return string(utf16.Decode(units))
+}
+
+func TestRunCommand(t *testing.T) {
+	t.Parallel()
+
+	server, host := startFakeWinRmServer(t, func(commandLine string, stdin string) (string, int) {
+		if commandLine == "exit 3" {
+			return "", 3
+		}
+		return commandLine + "\r\n", 0
+	})
+	server.timeouts = 2
+
+	assert.Equal(t, "echo <a & b>\r\n", RunCommand(t, host, "echo <a & b>"))
+	CheckWinRmConnection(t, host)
+
+	_, err := RunCommandE(t, host, "exit 3")
+	require.Error(t, err)
+	assert.Equal(t, 3, err.(CommandFailed).ExitCode)
+	assert.Equal(t, 3, server.shells)
+	assert.Equal(t, 3, server.deletions)
+}
+
+func TestRunCommandFailsIfShellCannotBeDeleted(t *testing.T) {
+	t.Parallel()
+
+	server, host := startFakeWinRmServer(t, func(commandLine string, stdin string) (string, int) { return "", 0 })
+	server.failDeletes = true
+
+	_, err := RunCommandE(t, host, "hostname")
+	require.Error(t, err)
+	assert.Equal(t, 1, server.deletions)
+}
+
+func TestRunPowerShell(t *testing.T) {
+	t.Parallel()
+
+	_, host := startFakeWinRmServer(t, func(commandLine string, stdin string) (string, int) {
+		if !strings.HasPrefix(commandLine, "powershell.exe ") {
+			return "", 1
+		}
+		script := decodePowerShellCommand(t, commandLine)
+		return strings.TrimPrefix(script, "Write-Output "), 0
+	})
+
+	assert.Equal(t, "'héllo wörld'", RunPowerShell(t, host, "Write-Output 'héllo wörld'"))
+}
+
+func TestRunCommandWrongPassword(t *testing.T) {
+	t.Parallel()
+
+	_, host := startFakeWinRmServer(t, func(commandLine string, stdin string) (string, int) { return "", 0 })
+	host.Password = "wrong"
+
+	_, err := RunCommandE(t, host, "hostname")
+	require.Error(t, err)
+}
+
+func TestCopyFileToAndFetchContentsOfFile(t *testing.T) {
+	t.Parallel()
+
+	contents := []byte(strings.Repeat("0123456789", 2*copyChunkSize/10+1))
+	var scripts []string
+	var copied []byte
+	server, host := startFakeWinRmServer(t, func(commandLine string, stdin string) (string, int) {
+		script := decodePowerShellCommand(t, commandLine)
+		scripts = append(scripts, script)
+		if strings.Contains(script, "ReadAllBytes") {
+			return base64.StdEncoding.EncodeToString(copied) + "\r\n", 0
+		}
+		for _, line := range strings.Fields(stdin) {
+			chunk, err := base64.StdEncoding.DecodeString(line)
+			require.NoError(t, err)
+			copied = append(copied, chunk...)
+		}
+		return "", 0
+	})
+
+	CopyFileTo(t, host, `C:\it's\test.txt`, contents)
+	require.Len(t, scripts, 1)
+	assert.Contains(t, scripts[0], `Create('C:\it''s\test.txt')`)
+	assert.Equal(t, contents, copied)
+	assert.Equal(t, 1, server.shells)
+
+	assert.Equal(t, contents, FetchContentsOfFile(t, host, `C:\it's\test.txt`))
+}
+
+func TestFormatCopyInput(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, formatCopyInput(nil), 0)
+	assert.Len(t, formatCopyInput(make([]byte, copyChunkSize)), 1)
+	assert.Len(t, formatCopyInput(make([]byte, copyChunkSize+1)), 2)
+}
+
+func TestHostGetPort(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 5985, Host{}.getPort())
+	assert.Equal(t, 5986, Host{UseHttps: true}.getPort())
+	assert.Equal(t, 1234, Host{Port: 1234, UseHttps: true}.getPort())
+}
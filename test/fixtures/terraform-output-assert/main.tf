@@ -0,0 +1,23 @@
+output "string" {
+  value = "This is a string."
+}
+
+output "number" {
+  value = 3
+}
+
+output "list" {
+  value = ["Sirius", "Rigel", "Betelgeuse"]
+}
+
+output "map" {
+  value = {
+    Gemini  = "Pollux"
+    Scorpio = "Antares"
+  }
+}
+
+output "password" {
+  value     = "correct-horse-battery-staple"
+  sensitive = true
+}